@@ -0,0 +1,115 @@
+package solc
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DecodeABI decodes the ABI encoding data of the parameters params, the reverse of EncodeABI
+//
+// Integers decode as *big.Int, addresses as Address, fixed and dynamic bytes as byte slices, arrays
+// and tuples as []interface{}, bools and strings as themselves
+func DecodeABI(params []ABIParameter, data []byte) ([]interface{}, error) {
+	values, err := decodeTuple(params, data)
+	if err != nil {
+		return nil, fmt.Errorf("solc: %v", err)
+	}
+	return values, nil
+}
+
+// decodeTuple decodes values from their heads at the beginning of data, dynamic ones from their offsets in data
+func decodeTuple(params []ABIParameter, data []byte) ([]interface{}, error) {
+	values := make([]interface{}, len(params))
+	head := 0
+	for i, p := range params {
+		var err error
+		if abiDynamic(p) {
+			var offset int
+			offset, err = abiOffset(data, head)
+			if err == nil {
+				values[i], err = decodeValue(p, data[offset:])
+			}
+			head += 32
+		} else {
+			if head+abiHeadSize(p) > len(data) {
+				err = fmt.Errorf("data too short")
+			} else {
+				values[i], err = decodeValue(p, data[head:])
+			}
+			head += abiHeadSize(p)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", parameterName(p, i), err)
+		}
+	}
+	return values, nil
+}
+
+// abiOffset reads the offset (or length) at i of data, checking it is within data
+func abiOffset(data []byte, i int) (int, error) {
+	if i+32 > len(data) {
+		return 0, fmt.Errorf("data too short")
+	}
+	n := (&big.Int{}).SetBytes(data[i : i+32])
+	if !n.IsInt64() || n.Int64() > int64(len(data)) {
+		return 0, fmt.Errorf("offset %v out of bounds", n)
+	}
+	return int(n.Int64()), nil
+}
+
+func decodeValue(p ABIParameter, data []byte) (interface{}, error) {
+	if elem, size, ok := abiArray(p); ok {
+		if size < 0 {
+			n, err := abiOffset(data, 0)
+			if err != nil {
+				return nil, err
+			}
+			size, data = n, data[32:]
+		}
+		params := make([]ABIParameter, size)
+		for i := range params {
+			params[i] = elem
+		}
+		return decodeTuple(params, data)
+	}
+
+	switch {
+	case p.Type == "tuple":
+		return decodeTuple(p.Components, data)
+	case p.Type == "string", p.Type == "bytes":
+		n, err := abiOffset(data, 0)
+		if err != nil {
+			return nil, err
+		}
+		if 32+n > len(data) {
+			return nil, fmt.Errorf("data too short")
+		}
+		b := append([]byte{}, data[32:32+n]...)
+		if p.Type == "string" {
+			return string(b), nil
+		}
+		return b, nil
+	case p.Type == "address":
+		var a Address
+		copy(a[:], data[12:32])
+		return a, nil
+	case p.Type == "bool":
+		return data[31] == 1, nil
+	case strings.HasPrefix(p.Type, "bytes"):
+		size, err := strconv.Atoi(strings.TrimPrefix(p.Type, "bytes"))
+		if err != nil || size < 1 || size > 32 {
+			return nil, fmt.Errorf("invalid type %v", p.Type)
+		}
+		return append([]byte{}, data[:size]...), nil
+	case strings.HasPrefix(p.Type, "uint"), strings.HasPrefix(p.Type, "int"):
+		n := (&big.Int{}).SetBytes(data[:32])
+		// Negative integers are encoded in two's complement
+		if strings.HasPrefix(p.Type, "int") && data[0]&0x80 != 0 {
+			n.Sub(n, big.NewInt(0).Lsh(big.NewInt(1), 256))
+		}
+		return n, nil
+	}
+	return nil, fmt.Errorf("unsupported type %v", p.Type)
+}
@@ -0,0 +1,44 @@
+package solc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeABI(t *testing.T) {
+	params := []ABIParameter{
+		{Type: "uint256"},
+		{Type: "uint32[]"},
+		{Type: "bytes10"},
+		{Type: "bytes"},
+		{Type: "int8"},
+		{Type: "tuple[]", Components: []ABIParameter{{Name: "to", Type: "address"}, {Name: "memo", Type: "string"}}},
+		{Type: "bool[2]"},
+	}
+	to := Address{0xab}
+	b, err := EncodeABI(params,
+		0x123, []uint32{0x456, 0x789}, []byte("1234567890"), []byte("Hello, world!"), -2,
+		[]interface{}{[]interface{}{to, "one"}, []interface{}{to, ""}}, []bool{true, false},
+	)
+	require.NoError(t, err, "EncodeABI should not error")
+
+	values, err := DecodeABI(params, b)
+	require.NoError(t, err, "DecodeABI should not error")
+	assert.Equal(t, []interface{}{
+		big.NewInt(0x123),
+		[]interface{}{big.NewInt(0x456), big.NewInt(0x789)},
+		[]byte("1234567890"),
+		[]byte("Hello, world!"),
+		big.NewInt(-2),
+		[]interface{}{[]interface{}{to, "one"}, []interface{}{to, ""}},
+		[]interface{}{true, false},
+	}, values, "Decoding should reverse encoding")
+
+	_, err = DecodeABI(params, b[:100])
+	assert.Error(t, err, "Truncated data should error")
+	_, err = DecodeABI([]ABIParameter{{Type: "string"}}, append(make([]byte, 31), 0xff))
+	assert.Error(t, err, "Offsets out of bounds should error")
+}
@@ -0,0 +1,332 @@
+package solc
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EncodeConstructorArgs returns the hex ABI encoding of the constructor arguments args of contract,
+// to append to its creation bytecode or to submit with Etherscan verifications
+//
+// It requires the abi output, see EncodeABI for the accepted argument values
+func EncodeConstructorArgs(contract Contract, args ...interface{}) (string, error) {
+	// Contracts without entries have an empty non nil ABI
+	if contract.ABI == nil {
+		return "", fmt.Errorf("solc: encoding constructor arguments requires the abi output")
+	}
+	entries, err := ParseABI(contract.ABI)
+	if err != nil {
+		return "", err
+	}
+	var inputs []ABIParameter
+	for _, entry := range entries {
+		if entry.Type == "constructor" {
+			inputs = entry.Inputs
+		}
+	}
+	b, err := EncodeABI(inputs, args...)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// EncodeABI returns the ABI encoding of values as the parameters params
+//
+// Integers accept Go integers, *big.Int and decimal or 0x prefixed hex strings, addresses accept Address,
+// [20]byte and hex strings, fixed and dynamic bytes accept byte slices or arrays and hex strings, arrays
+// accept slices and arrays and tuples accept []interface{}, map[string]interface{} by component name
+// and structs by case insensitive field name
+func EncodeABI(params []ABIParameter, values ...interface{}) ([]byte, error) {
+	if len(values) != len(params) {
+		return nil, fmt.Errorf("solc: expected %v values, got %v", len(params), len(values))
+	}
+	rvs := make([]reflect.Value, len(values))
+	for i, v := range values {
+		rvs[i] = reflect.ValueOf(v)
+	}
+	b, err := encodeTuple(params, rvs)
+	if err != nil {
+		return nil, fmt.Errorf("solc: %v", err)
+	}
+	return b, nil
+}
+
+// encodeTuple encodes the heads of values followed by the tails of the dynamic ones
+func encodeTuple(params []ABIParameter, values []reflect.Value) ([]byte, error) {
+	var heads, tails []byte
+	headSize := 0
+	for _, p := range params {
+		headSize += abiHeadSize(p)
+	}
+	for i, p := range params {
+		b, err := encodeValue(p, values[i])
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", parameterName(p, i), err)
+		}
+		if !abiDynamic(p) {
+			heads = append(heads, b...)
+			continue
+		}
+		heads = append(heads, abiUint(uint64(headSize+len(tails)))...)
+		tails = append(tails, b...)
+	}
+	return append(heads, tails...), nil
+}
+
+func parameterName(p ABIParameter, i int) string {
+	if p.Name != "" {
+		return fmt.Sprintf("%v %v", p.Type, p.Name)
+	}
+	return fmt.Sprintf("%v #%v", p.Type, i)
+}
+
+func encodeValue(p ABIParameter, v reflect.Value) ([]byte, error) {
+	for v.IsValid() && v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil, fmt.Errorf("missing value")
+	}
+
+	if elem, size, ok := abiArray(p); ok {
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return nil, fmt.Errorf("cannot encode %v as %v", v.Type(), p.Type)
+		}
+		if size >= 0 && v.Len() != size {
+			return nil, fmt.Errorf("expected %v elements, got %v", size, v.Len())
+		}
+		params := make([]ABIParameter, v.Len())
+		values := make([]reflect.Value, v.Len())
+		for i := range params {
+			params[i], values[i] = elem, v.Index(i)
+		}
+		b, err := encodeTuple(params, values)
+		if err != nil || size >= 0 {
+			return b, err
+		}
+		return append(abiUint(uint64(v.Len())), b...), nil
+	}
+
+	switch {
+	case p.Type == "tuple":
+		return encodeStruct(p, v)
+	case p.Type == "address":
+		if s, ok := v.Interface().(string); ok {
+			a, err := HexToAddress(s)
+			if err != nil {
+				return nil, err
+			}
+			return abiAddress(a), nil
+		}
+		b, err := abiBytes(v)
+		if err != nil || len(b) != 20 {
+			return nil, fmt.Errorf("cannot encode %v as address", v.Type())
+		}
+		return append(make([]byte, 12), b...), nil
+	case p.Type == "bool":
+		if v.Kind() != reflect.Bool {
+			return nil, fmt.Errorf("cannot encode %v as bool", v.Type())
+		}
+		if v.Bool() {
+			return abiUint(1), nil
+		}
+		return abiUint(0), nil
+	case p.Type == "string":
+		if v.Kind() != reflect.String {
+			return nil, fmt.Errorf("cannot encode %v as string", v.Type())
+		}
+		return append(abiUint(uint64(v.Len())), abiPad([]byte(v.String()))...), nil
+	case p.Type == "bytes":
+		b, err := abiBytes(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(abiUint(uint64(len(b))), abiPad(b)...), nil
+	case strings.HasPrefix(p.Type, "bytes"):
+		size, err := strconv.Atoi(strings.TrimPrefix(p.Type, "bytes"))
+		if err != nil || size < 1 || size > 32 {
+			return nil, fmt.Errorf("invalid type %v", p.Type)
+		}
+		b, err := abiBytes(v)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) != size {
+			return nil, fmt.Errorf("expected %v bytes, got %v", size, len(b))
+		}
+		return abiPad(b), nil
+	case strings.HasPrefix(p.Type, "uint"), strings.HasPrefix(p.Type, "int"):
+		return encodeInt(p.Type, v)
+	}
+	return nil, fmt.Errorf("unsupported type %v", p.Type)
+}
+
+// encodeStruct encodes a tuple from a slice, a map or a struct
+func encodeStruct(p ABIParameter, v reflect.Value) ([]byte, error) {
+	values := make([]reflect.Value, len(p.Components))
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Len() != len(p.Components) {
+			return nil, fmt.Errorf("expected %v components, got %v", len(p.Components), v.Len())
+		}
+		for i := range values {
+			values[i] = v.Index(i)
+		}
+	case reflect.Map:
+		for i, c := range p.Components {
+			values[i] = v.MapIndex(reflect.ValueOf(c.Name))
+		}
+	case reflect.Ptr, reflect.Struct:
+		v = reflect.Indirect(v)
+		if !v.IsValid() || v.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("cannot encode nil or non struct pointer as tuple")
+		}
+		for i, c := range p.Components {
+			name := strings.TrimLeft(c.Name, "_")
+			values[i] = v.FieldByNameFunc(func(field string) bool { return strings.EqualFold(field, name) })
+		}
+	default:
+		return nil, fmt.Errorf("cannot encode %v as tuple", v.Type())
+	}
+	return encodeTuple(p.Components, values)
+}
+
+func encodeInt(typ string, v reflect.Value) ([]byte, error) {
+	signed := strings.HasPrefix(typ, "int")
+	bits := 256
+	if size := strings.TrimPrefix(strings.TrimPrefix(typ, "u"), "int"); size != "" {
+		var err error
+		bits, err = strconv.Atoi(size)
+		if err != nil || bits < 8 || bits > 256 || bits%8 != 0 {
+			return nil, fmt.Errorf("invalid type %v", typ)
+		}
+	}
+
+	n := &big.Int{}
+	switch value := v.Interface().(type) {
+	case *big.Int:
+		n.Set(value)
+	case string:
+		if _, ok := n.SetString(value, 0); !ok {
+			return nil, fmt.Errorf("invalid integer %q", value)
+		}
+	default:
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n.SetInt64(v.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			n.SetUint64(v.Uint())
+		default:
+			return nil, fmt.Errorf("cannot encode %v as %v", v.Type(), typ)
+		}
+	}
+
+	low, high := big.NewInt(0), big.NewInt(0).Lsh(big.NewInt(1), uint(bits))
+	if signed {
+		high.Rsh(high, 1)
+		low.Neg(high)
+	}
+	if n.Cmp(low) < 0 || n.Cmp(high) >= 0 {
+		return nil, fmt.Errorf("%v overflows %v", n, typ)
+	}
+	// Negative integers are encoded in two's complement
+	if n.Sign() < 0 {
+		n.Add(n, big.NewInt(0).Lsh(big.NewInt(1), 256))
+	}
+	b := make([]byte, 32)
+	copy(b[32-len(n.Bytes()):], n.Bytes())
+	return b, nil
+}
+
+// abiBytes returns the bytes of a byte slice, a byte array or a hex string
+func abiBytes(v reflect.Value) ([]byte, error) {
+	switch {
+	case v.Kind() == reflect.String:
+		b, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(v.String(), "0x"), "0X"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex %q: %v", v.String(), err)
+		}
+		return b, nil
+	case (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() == reflect.Uint8:
+		b := make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(b), v)
+		return b, nil
+	}
+	return nil, fmt.Errorf("cannot encode %v as bytes", v.Type())
+}
+
+// abiArray returns the element parameter and size of array types, -1 for dynamic arrays
+func abiArray(p ABIParameter) (ABIParameter, int, bool) {
+	if !strings.HasSuffix(p.Type, "]") {
+		return p, 0, false
+	}
+	i := strings.LastIndex(p.Type, "[")
+	elem := p
+	elem.Type = p.Type[:i]
+	size, err := strconv.Atoi(p.Type[i+1 : len(p.Type)-1])
+	if err != nil {
+		size = -1
+	}
+	return elem, size, true
+}
+
+// abiDynamic indicates whether values of p are encoded in the tail of tuples
+func abiDynamic(p ABIParameter) bool {
+	if elem, size, ok := abiArray(p); ok {
+		return size < 0 || abiDynamic(elem)
+	}
+	switch p.Type {
+	case "string", "bytes":
+		return true
+	case "tuple":
+		for _, c := range p.Components {
+			if abiDynamic(c) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// abiHeadSize returns the size of p in the head of tuples, static arrays and tuples are inlined
+func abiHeadSize(p ABIParameter) int {
+	if abiDynamic(p) {
+		return 32
+	}
+	if elem, size, ok := abiArray(p); ok {
+		return size * abiHeadSize(elem)
+	}
+	if p.Type == "tuple" {
+		size := 0
+		for _, c := range p.Components {
+			size += abiHeadSize(c)
+		}
+		return size
+	}
+	return 32
+}
+
+// abiUint returns the ABI encoding of an unsigned integer
+func abiUint(v uint64) []byte {
+	b := make([]byte, 32)
+	binary.BigEndian.PutUint64(b[24:], v)
+	return b
+}
+
+// abiAddress returns the ABI encoding of an address, left padded
+func abiAddress(a Address) []byte {
+	return append(make([]byte, 12), a[:]...)
+}
+
+// abiPad right pads b to a multiple of 32 bytes, as fixed size bytes and the data of dynamic bytes are
+func abiPad(b []byte) []byte {
+	padded := make([]byte, (len(b)+31)/32*32)
+	copy(padded, b)
+	return padded
+}
@@ -0,0 +1,144 @@
+package solc
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const constructorSource = `pragma solidity ^0.6.2;
+pragma experimental ABIEncoderV2;
+contract Deployed {
+    struct Position { address owner; string[] tags; uint128 size; }
+    constructor(int8 a, uint256[][] memory b, string[2] memory c, Position memory d, bool e, bytes3 f) public {}
+}
+contract NoConstructor {}
+`
+
+func TestEncodeConstructorArgs(t *testing.T) {
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err, "Creating compiler should not error")
+	defer solc.Close()
+	out, err := CompileSource(solc, "Deployed.sol", constructorSource)
+	require.NoError(t, err, "Compile should not error")
+	deployed, _ := out.Contract("Deployed.sol", "Deployed")
+
+	type position struct {
+		Owner Address
+		Tags  []string
+		Size  *big.Int
+	}
+	owner, _ := HexToAddress("0x00000000000000000000000000000000000000ff")
+	args, err := EncodeConstructorArgs(
+		*deployed,
+		-2,
+		[][]int{{1, 2}, {3}},
+		[2]string{"one", "two"},
+		position{Owner: owner, Tags: []string{"x"}, Size: big.NewInt(7)},
+		true,
+		"0xabcdef",
+	)
+	require.NoError(t, err, "EncodeConstructorArgs should not error")
+
+	// Matches the encoding of go-ethereum's abi.Pack
+	assert.Equal(
+		t,
+		"fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe"+
+			"00000000000000000000000000000000000000000000000000000000000000c0"+
+			"00000000000000000000000000000000000000000000000000000000000001c0"+
+			"0000000000000000000000000000000000000000000000000000000000000280"+
+			"0000000000000000000000000000000000000000000000000000000000000001"+
+			"abcdef0000000000000000000000000000000000000000000000000000000000"+
+			"0000000000000000000000000000000000000000000000000000000000000002"+
+			"0000000000000000000000000000000000000000000000000000000000000040"+
+			"00000000000000000000000000000000000000000000000000000000000000a0"+
+			"0000000000000000000000000000000000000000000000000000000000000002"+
+			"0000000000000000000000000000000000000000000000000000000000000001"+
+			"0000000000000000000000000000000000000000000000000000000000000002"+
+			"0000000000000000000000000000000000000000000000000000000000000001"+
+			"0000000000000000000000000000000000000000000000000000000000000003"+
+			"0000000000000000000000000000000000000000000000000000000000000040"+
+			"0000000000000000000000000000000000000000000000000000000000000080"+
+			"0000000000000000000000000000000000000000000000000000000000000003"+
+			"6f6e650000000000000000000000000000000000000000000000000000000000"+
+			"0000000000000000000000000000000000000000000000000000000000000003"+
+			"74776f0000000000000000000000000000000000000000000000000000000000"+
+			"00000000000000000000000000000000000000000000000000000000000000ff"+
+			"0000000000000000000000000000000000000000000000000000000000000060"+
+			"0000000000000000000000000000000000000000000000000000000000000007"+
+			"0000000000000000000000000000000000000000000000000000000000000001"+
+			"0000000000000000000000000000000000000000000000000000000000000020"+
+			"0000000000000000000000000000000000000000000000000000000000000001"+
+			"7800000000000000000000000000000000000000000000000000000000000000",
+		args,
+		"Arguments should be ABI encoded",
+	)
+
+	// Tuples may also be given as slices and maps
+	for _, d := range []interface{}{
+		[]interface{}{owner, []string{"x"}, 7},
+		map[string]interface{}{"owner": "0x00000000000000000000000000000000000000ff", "tags": []string{"x"}, "size": "0x7"},
+	} {
+		encoded, err := EncodeConstructorArgs(*deployed, int8(-2), [][]*big.Int{{big.NewInt(1), big.NewInt(2)}, {big.NewInt(3)}}, []string{"one", "two"}, d, true, [3]byte{0xab, 0xcd, 0xef})
+		require.NoError(t, err, "EncodeConstructorArgs should not error")
+		assert.Equal(t, args, encoded, "Tuple values should encode the same")
+	}
+
+	none, _ := out.Contract("Deployed.sol", "NoConstructor")
+	args, err = EncodeConstructorArgs(*none)
+	require.NoError(t, err, "EncodeConstructorArgs should not error")
+	assert.Empty(t, args, "Contracts without constructor should have no arguments")
+
+	_, err = EncodeConstructorArgs(*none, 1)
+	assert.Error(t, err, "Unexpected arguments should error")
+	_, err = EncodeConstructorArgs(Contract{})
+	assert.Error(t, err, "Missing ABI should error")
+}
+
+func TestEncodeABI(t *testing.T) {
+	// Example of the ABI specification
+	b, err := EncodeABI(
+		[]ABIParameter{{Type: "uint256"}, {Type: "uint32[]"}, {Type: "bytes10"}, {Type: "bytes"}},
+		0x123, []uint32{0x456, 0x789}, []byte("1234567890"), []byte("Hello, world!"),
+	)
+	require.NoError(t, err, "EncodeABI should not error")
+	assert.Equal(
+		t,
+		"0000000000000000000000000000000000000000000000000000000000000123"+
+			"0000000000000000000000000000000000000000000000000000000000000080"+
+			"3132333435363738393000000000000000000000000000000000000000000000"+
+			"00000000000000000000000000000000000000000000000000000000000000e0"+
+			"0000000000000000000000000000000000000000000000000000000000000002"+
+			"0000000000000000000000000000000000000000000000000000000000000456"+
+			"0000000000000000000000000000000000000000000000000000000000000789"+
+			"000000000000000000000000000000000000000000000000000000000000000d"+
+			"48656c6c6f2c20776f726c642100000000000000000000000000000000000000",
+		hex.EncodeToString(b),
+		"Values should be ABI encoded",
+	)
+
+	for _, test := range []struct {
+		typ   string
+		value interface{}
+	}{
+		{"uint8", 256},
+		{"uint256", -1},
+		{"int8", 128},
+		{"int8", "-129"},
+		{"bytes4", "0x01"},
+		{"address", "0x01"},
+		{"bool", 1},
+		{"string", []byte("a")},
+		{"uint256[2]", []int{1}},
+		{"fixed128x18", 1},
+		{"uint256", nil},
+	} {
+		_, err := EncodeABI([]ABIParameter{{Name: "x", Type: test.typ}}, test.value)
+		assert.Error(t, err, "Encoding %v as %v should error", test.value, test.typ)
+	}
+	_, err = EncodeABI([]ABIParameter{{Type: "tuple", Components: []ABIParameter{{Name: "a", Type: "uint256"}}}}, map[string]interface{}{})
+	assert.Error(t, err, "Missing tuple component should error")
+}
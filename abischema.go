@@ -0,0 +1,250 @@
+package solc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// JSONSchemaDraft is the JSON Schema dialect of the schemas of ABI payloads
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// OpenRPCVersion is the version of the OpenRPC specification of NewOpenRPC documents
+const OpenRPCVersion = "1.2.6"
+
+// JSONSchema is the subset of JSON Schema describing ABI values
+type JSONSchema struct {
+	Schema      string `json:"$schema,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	// Type is a JSON type or a list of them
+	Type    interface{} `json:"type,omitempty"`
+	Pattern string      `json:"pattern,omitempty"`
+	Minimum *int        `json:"minimum,omitempty"`
+
+	Items       *JSONSchema   `json:"items,omitempty"`
+	PrefixItems []*JSONSchema `json:"prefixItems,omitempty"`
+	MinItems    *int          `json:"minItems,omitempty"`
+	MaxItems    *int          `json:"maxItems,omitempty"`
+
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+}
+
+// Patterns of ABI values encoded as JSON strings, integers are also accepted as JSON numbers
+const (
+	intPattern     = `^-?(0|[1-9][0-9]*|0x[0-9a-fA-F]+)$`
+	uintPattern    = `^(0|[1-9][0-9]*|0x[0-9a-fA-F]+)$`
+	fixedPattern   = `^-?[0-9]+(\.[0-9]+)?$`
+	addressPattern = `^0x[0-9a-fA-F]{40}$`
+	bytesPattern   = `^0x([0-9a-fA-F]{2})*$`
+)
+
+// ParameterSchema returns the JSON Schema of the values of an ABI parameter, as EncodeABI accepts them:
+// integers are JSON numbers or decimal and 0x prefixed hex strings, addresses and bytes 0x prefixed hex
+// strings, and tuples objects by component name (arrays if a component is unnamed)
+func ParameterSchema(p ABIParameter) *JSONSchema {
+	if elem, size, ok := abiArray(p); ok {
+		s := &JSONSchema{Type: "array", Items: ParameterSchema(elem)}
+		if size >= 0 {
+			s.MinItems, s.MaxItems = &size, &size
+		}
+		return s
+	}
+
+	s := &JSONSchema{Description: p.Type}
+	if p.InternalType != "" && p.InternalType != p.Type {
+		s.Description = p.InternalType
+	}
+	switch {
+	case p.Type == "tuple":
+		tuple := tupleSchema(p.Components)
+		tuple.Description = s.Description
+		return tuple
+	case p.Type == "bool":
+		s.Type = "boolean"
+	case p.Type == "string":
+		s.Type = "string"
+	case p.Type == "address":
+		s.Type, s.Pattern = "string", addressPattern
+	case p.Type == "bytes":
+		s.Type, s.Pattern = "string", bytesPattern
+	case p.Type == "function":
+		s.Type, s.Pattern = "string", `^0x[0-9a-fA-F]{48}$`
+	case strings.HasPrefix(p.Type, "bytes"):
+		n, _ := strconv.Atoi(strings.TrimPrefix(p.Type, "bytes"))
+		s.Type, s.Pattern = "string", fmt.Sprintf("^0x[0-9a-fA-F]{%v}$", 2*n)
+	case strings.HasPrefix(p.Type, "uint"):
+		zero := 0
+		s.Type, s.Pattern, s.Minimum = []string{"string", "integer"}, uintPattern, &zero
+	case strings.HasPrefix(p.Type, "int"):
+		s.Type, s.Pattern = []string{"string", "integer"}, intPattern
+	case strings.HasPrefix(p.Type, "fixed"), strings.HasPrefix(p.Type, "ufixed"):
+		s.Type, s.Pattern = []string{"string", "number"}, fixedPattern
+	}
+	return s
+}
+
+// tupleSchema returns the schema of a tuple of params, an object by name if they all have one, an array otherwise
+func tupleSchema(params []ABIParameter) *JSONSchema {
+	named := true
+	for _, p := range params {
+		named = named && p.Name != ""
+	}
+	if !named {
+		n := len(params)
+		s := &JSONSchema{Type: "array", PrefixItems: []*JSONSchema{}, MinItems: &n, MaxItems: &n}
+		for _, p := range params {
+			s.PrefixItems = append(s.PrefixItems, ParameterSchema(p))
+		}
+		return s
+	}
+
+	closed := false
+	s := &JSONSchema{Type: "object", Properties: make(map[string]*JSONSchema), Required: []string{}, AdditionalProperties: &closed}
+	for _, p := range params {
+		s.Properties[p.Name] = ParameterSchema(p)
+		s.Required = append(s.Required, p.Name)
+	}
+	return s
+}
+
+// ABISchema holds the JSON Schemas of the payloads of the entries of an ABI, by signature
+// (e.g. transfer(address,uint256))
+type ABISchema struct {
+	Schema string `json:"$schema"`
+
+	// Constructor is the schema of the constructor arguments, nil for contracts without constructor
+	Constructor *JSONSchema `json:"constructor,omitempty"`
+
+	Functions map[string]FunctionSchema `json:"functions"`
+
+	// Events are the schemas of the parameters of events, indexed ones included
+	Events map[string]*JSONSchema `json:"events"`
+
+	Errors map[string]*JSONSchema `json:"errors"`
+}
+
+// FunctionSchema holds the schemas of the arguments and of the return values of a function
+type FunctionSchema struct {
+	Selector string      `json:"selector"`
+	Inputs   *JSONSchema `json:"inputs"`
+	Outputs  *JSONSchema `json:"outputs"`
+}
+
+// NewABISchema returns the schemas of the payloads of the entries of an ABI (see ParseABI), to validate
+// arguments, return values, events and errors off-chain
+//
+// Payloads are tuples of the parameters of entries, see ParameterSchema
+func NewABISchema(entries []ABIEntry) *ABISchema {
+	schema := &ABISchema{
+		Schema:    JSONSchemaDraft,
+		Functions: make(map[string]FunctionSchema),
+		Events:    make(map[string]*JSONSchema),
+		Errors:    make(map[string]*JSONSchema),
+	}
+	for _, entry := range entries {
+		switch entry.Type {
+		case "constructor":
+			schema.Constructor = tupleSchema(entry.Inputs)
+		case "function":
+			schema.Functions[entry.Signature()] = FunctionSchema{
+				Selector: entry.Selector(),
+				Inputs:   tupleSchema(entry.Inputs),
+				Outputs:  tupleSchema(entry.Outputs),
+			}
+		case "event":
+			schema.Events[entry.Signature()] = tupleSchema(entry.Inputs)
+		case "error":
+			schema.Errors[entry.Signature()] = tupleSchema(entry.Inputs)
+		}
+	}
+	return schema
+}
+
+// OpenRPCDocument is an OpenRPC document describing the functions of a contract as methods
+type OpenRPCDocument struct {
+	OpenRPC    string            `json:"openrpc"`
+	Info       OpenRPCInfo       `json:"info"`
+	Methods    []OpenRPCMethod   `json:"methods"`
+	Components OpenRPCComponents `json:"components"`
+}
+
+type OpenRPCInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OpenRPCMethod is a function, named by its name or by its signature if overloaded
+type OpenRPCMethod struct {
+	Name           string                     `json:"name"`
+	Summary        string                     `json:"summary"`
+	ParamStructure string                     `json:"paramStructure"`
+	Params         []OpenRPCContentDescriptor `json:"params"`
+	Result         OpenRPCContentDescriptor   `json:"result"`
+
+	// Selector and StateMutability are extensions giving the selector and state mutability of the function
+	Selector        string `json:"x-selector"`
+	StateMutability string `json:"x-stateMutability"`
+}
+
+type OpenRPCContentDescriptor struct {
+	Name     string      `json:"name"`
+	Required bool        `json:"required,omitempty"`
+	Schema   *JSONSchema `json:"schema"`
+}
+
+// OpenRPCComponents hold the schemas of events and errors, by "event <signature>" and "error <signature>"
+type OpenRPCComponents struct {
+	Schemas map[string]*JSONSchema `json:"schemas"`
+}
+
+// NewOpenRPC returns an OpenRPC document of the functions of an ABI (see ParseABI), titled title at version
+// (e.g. the contract name and a release), in ABI order
+//
+// Params are by position, named after the parameters or argN if unnamed, results are the tuple of
+// return values (see ParameterSchema)
+func NewOpenRPC(title, version string, entries []ABIEntry) *OpenRPCDocument {
+	doc := &OpenRPCDocument{
+		OpenRPC:    OpenRPCVersion,
+		Info:       OpenRPCInfo{Title: title, Version: version},
+		Methods:    []OpenRPCMethod{},
+		Components: OpenRPCComponents{Schemas: make(map[string]*JSONSchema)},
+	}
+	overloads := make(map[string]int)
+	for _, entry := range entries {
+		if entry.Type == "function" {
+			overloads[entry.Name]++
+		}
+	}
+	for _, entry := range entries {
+		switch entry.Type {
+		case "function":
+			method := OpenRPCMethod{
+				Name:            entry.Name,
+				Summary:         entry.Signature(),
+				ParamStructure:  "by-position",
+				Params:          []OpenRPCContentDescriptor{},
+				Result:          OpenRPCContentDescriptor{Name: "result", Schema: tupleSchema(entry.Outputs)},
+				Selector:        entry.Selector(),
+				StateMutability: canonicalMutability(entry),
+			}
+			if overloads[entry.Name] > 1 {
+				method.Name = entry.Signature()
+			}
+			for i, input := range entry.Inputs {
+				name := input.Name
+				if name == "" {
+					name = fmt.Sprintf("arg%v", i)
+				}
+				method.Params = append(method.Params, OpenRPCContentDescriptor{Name: name, Required: true, Schema: ParameterSchema(input)})
+			}
+			doc.Methods = append(doc.Methods, method)
+		case "event", "error":
+			doc.Components.Schemas[entry.Type+" "+entry.Signature()] = tupleSchema(entry.Inputs)
+		}
+	}
+	return doc
+}
@@ -0,0 +1,82 @@
+package solc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestABISchema(t *testing.T) {
+	entries, err := ParseABI([]json.RawMessage{
+		json.RawMessage(`{"type": "constructor", "inputs": [{"name": "owner", "type": "address"}]}`),
+		json.RawMessage(`{"type": "function", "name": "transfer", "inputs": [{"name": "to", "type": "address"}, {"name": "value", "type": "uint256"}], "outputs": [{"name": "", "type": "bool"}], "stateMutability": "nonpayable"}`),
+		json.RawMessage(`{"type": "function", "name": "transfer", "inputs": [{"name": "", "type": "bytes32[2]"}], "outputs": [], "stateMutability": "payable"}`),
+		json.RawMessage(`{"type": "event", "name": "Transfer", "inputs": [{"name": "from", "type": "address", "indexed": true}, {"name": "value", "type": "int8"}]}`),
+		json.RawMessage(`{"type": "error", "name": "Unauthorized", "inputs": [{"name": "user", "type": "tuple", "internalType": "struct User", "components": [{"name": "a", "type": "address"}, {"name": "b", "type": "bytes"}]}]}`),
+	})
+	require.NoError(t, err, "ParseABI should not error")
+
+	schema := NewABISchema(entries)
+	assert.Equal(t, JSONSchemaDraft, schema.Schema)
+	assert.Equal(t, []string{"owner"}, schema.Constructor.Required)
+	require.Len(t, schema.Functions, 2)
+	transfer := schema.Functions["transfer(address,uint256)"]
+	assert.Equal(t, "0xa9059cbb", transfer.Selector)
+	b, err := json.Marshal(transfer.Inputs)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"to": {"description": "address", "type": "string", "pattern": "^0x[0-9a-fA-F]{40}$"},
+			"value": {"description": "uint256", "type": ["string", "integer"], "pattern": "^(0|[1-9][0-9]*|0x[0-9a-fA-F]+)$", "minimum": 0}
+		},
+		"required": ["to", "value"],
+		"additionalProperties": false
+	}`, string(b))
+	assert.Equal(t, "array", transfer.Outputs.Type, "Unnamed parameters should be positional")
+	assert.Equal(t, "boolean", transfer.Outputs.PrefixItems[0].Type)
+
+	fixed := schema.Functions["transfer(bytes32[2])"].Inputs.PrefixItems[0]
+	assert.Equal(t, 2, *fixed.MinItems)
+	assert.Equal(t, 2, *fixed.MaxItems)
+	assert.Equal(t, "^0x[0-9a-fA-F]{64}$", fixed.Items.Pattern)
+
+	assert.Equal(t, intPattern, schema.Events["Transfer(address,int8)"].Properties["value"].Pattern, "Indexed and non indexed parameters should be included")
+	user := schema.Errors["Unauthorized((address,bytes))"].Properties["user"]
+	assert.Equal(t, "struct User", user.Description)
+	assert.Equal(t, bytesPattern, user.Properties["b"].Pattern)
+}
+
+func TestOpenRPC(t *testing.T) {
+	entries, err := ParseABI([]json.RawMessage{
+		json.RawMessage(`{"type": "function", "name": "transfer", "inputs": [{"name": "to", "type": "address"}, {"name": "", "type": "uint256"}], "outputs": [{"name": "ok", "type": "bool"}], "stateMutability": "nonpayable"}`),
+		json.RawMessage(`{"type": "function", "name": "transfer", "inputs": [], "outputs": []}`),
+		json.RawMessage(`{"type": "function", "name": "balance", "inputs": [], "outputs": [{"name": "", "type": "uint256"}], "constant": true}`),
+		json.RawMessage(`{"type": "event", "name": "Transfer", "inputs": [{"name": "from", "type": "address", "indexed": true}]}`),
+	})
+	require.NoError(t, err, "ParseABI should not error")
+
+	doc := NewOpenRPC("Token", "1.0.0", entries)
+	assert.Equal(t, OpenRPCVersion, doc.OpenRPC)
+	assert.Equal(t, OpenRPCInfo{Title: "Token", Version: "1.0.0"}, doc.Info)
+	require.Len(t, doc.Methods, 3)
+
+	transfer := doc.Methods[0]
+	assert.Equal(t, "transfer(address,uint256)", transfer.Name, "Overloaded functions should be named by signature")
+	assert.Equal(t, "by-position", transfer.ParamStructure)
+	require.Len(t, transfer.Params, 2)
+	assert.Equal(t, "to", transfer.Params[0].Name)
+	assert.Equal(t, "arg1", transfer.Params[1].Name, "Unnamed parameters should be named by position")
+	assert.Equal(t, []string{"ok"}, transfer.Result.Schema.Required)
+	assert.Equal(t, "0xa9059cbb", transfer.Selector)
+
+	balance := doc.Methods[2]
+	assert.Equal(t, "balance", balance.Name)
+	assert.Equal(t, "view", balance.StateMutability, "Legacy constant functions should be views")
+	assert.Contains(t, doc.Components.Schemas, "event Transfer(address)")
+
+	_, err = json.Marshal(doc)
+	assert.NoError(t, err, "Marshalling document should not error")
+}
@@ -0,0 +1,177 @@
+package solc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nmvalera/solc-go/ast"
+)
+
+// Analyzer is a static analysis of compiled contracts, run by Analyze and after compilations (see WithAnalyzers)
+type Analyzer interface {
+	Name() string
+
+	// Run returns the findings of the contract declared by node in unit and compiled as contract,
+	// the Analyzer and Contract of findings are set by the caller
+	Run(unit *ast.Unit, node *ast.Contract, contract *Contract) []Finding
+}
+
+// NewAnalyzer returns an analyzer named name running run
+func NewAnalyzer(name string, run func(unit *ast.Unit, node *ast.Contract, contract *Contract) []Finding) Analyzer {
+	return &funcAnalyzer{name: name, run: run}
+}
+
+type funcAnalyzer struct {
+	name string
+	run  func(unit *ast.Unit, node *ast.Contract, contract *Contract) []Finding
+}
+
+func (a *funcAnalyzer) Name() string {
+	return a.name
+}
+
+func (a *funcAnalyzer) Run(unit *ast.Unit, node *ast.Contract, contract *Contract) []Finding {
+	return a.run(unit, node, contract)
+}
+
+// Analyze runs analyzers on the contracts of out, findings are sorted by contract then analyzer
+//
+// Analyzers require the ast output, contracts of sources without AST are not analyzed
+func Analyze(out *Output, analyzers ...Analyzer) ([]Finding, error) {
+	var files []string
+	for file := range out.Contracts {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	var findings []Finding
+	for _, file := range files {
+		source, ok := out.Sources[file]
+		if !ok || len(source.AST) == 0 {
+			continue
+		}
+		unit, err := ast.Parse(source.AST)
+		if err != nil {
+			return nil, fmt.Errorf("solc: could not parse AST of %q: %w", file, err)
+		}
+
+		var names []string
+		for name := range out.Contracts[file] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			node := unit.Contract(name)
+			if node == nil {
+				continue
+			}
+			contract := out.Contracts[file][name]
+			for _, analyzer := range analyzers {
+				for _, finding := range analyzer.Run(unit, node, &contract) {
+					finding.Analyzer = analyzer.Name()
+					finding.Contract = ContractID{File: file, Name: name}
+					findings = append(findings, finding)
+				}
+			}
+		}
+	}
+	return findings, nil
+}
+
+// NodeLocation returns the location of an AST node of unit, for findings
+func NodeLocation(unit *ast.Unit, n *ast.Node) SourceLocation {
+	r := n.Range()
+	return SourceLocation{File: unit.AbsolutePath, Start: r.Start, End: r.Start + r.Length}
+}
+
+// withAST returns input, or a copy of it selecting the ast output of every source if it does not
+func withAST(input *Input) *Input {
+	selection := input.Settings.OutputSelection
+	for file := range input.Sources {
+		if !selection.Selected(file, "", "ast") {
+			c := *input
+			c.Settings.OutputSelection = make(OutputSelection)
+			for file, contracts := range selection {
+				for contract, outputs := range contracts {
+					c.Settings.OutputSelection.Select(file, contract, outputs...)
+				}
+			}
+			c.Settings.OutputSelection.Select("*", "", "ast")
+			return &c
+		}
+	}
+	return input
+}
+
+// analyze returns a copy of out with the findings of the configured analyzers, outputs may be shared by caches
+func (o *options) analyze(out *Output) (*Output, error) {
+	findings, err := Analyze(out, o.analyzers...)
+	if err != nil {
+		return nil, err
+	}
+	analyzed := *out
+	analyzed.Findings = findings
+	return &analyzed, nil
+}
+
+// DefaultAnalyzers are the built-in analyzers
+var DefaultAnalyzers = []Analyzer{TxOriginAnalyzer, ConstructorDelegatecallAnalyzer, FloatingPragmaAnalyzer}
+
+// TxOriginAnalyzer reports reads of tx.origin, which is unsafe for authorization
+var TxOriginAnalyzer = NewAnalyzer("tx-origin", func(unit *ast.Unit, node *ast.Contract, contract *Contract) []Finding {
+	var findings []Finding
+	for _, n := range node.Find("MemberAccess") {
+		if expr := n.Child("expression"); n.StringAttr("memberName") == "origin" && expr != nil && expr.Name() == "tx" && expr.NodeType == "Identifier" {
+			findings = append(findings, Finding{
+				Severity:       SeverityWarning,
+				Message:        "tx.origin is unsafe for authorization, use msg.sender",
+				SourceLocation: NodeLocation(unit, n),
+			})
+		}
+	}
+	return findings
+})
+
+// ConstructorDelegatecallAnalyzer reports delegatecalls made by constructors, which run code that can
+// alter the storage of the contract before it is deployed
+var ConstructorDelegatecallAnalyzer = NewAnalyzer("constructor-delegatecall", func(unit *ast.Unit, node *ast.Contract, contract *Contract) []Finding {
+	var findings []Finding
+	for _, call := range node.ExternalCalls() {
+		if call.Kind != "delegatecall" {
+			continue
+		}
+		if f := call.Ancestor("FunctionDefinition"); f != nil && (f.StringAttr("kind") == "constructor" || f.BoolAttr("isConstructor")) {
+			findings = append(findings, Finding{
+				Severity:       SeverityWarning,
+				Message:        "delegatecall in constructor",
+				SourceLocation: NodeLocation(unit, call.Node),
+			})
+		}
+	}
+	return findings
+})
+
+// FloatingPragmaAnalyzer reports version pragmas accepting several compiler versions, it reports a unit
+// once on its first contract
+var FloatingPragmaAnalyzer = NewAnalyzer("floating-pragma", func(unit *ast.Unit, node *ast.Contract, contract *Contract) []Finding {
+	if contracts := unit.Contracts(); len(contracts) == 0 || contracts[0].ID != node.ID {
+		return nil
+	}
+	var findings []Finding
+	for _, n := range unit.ChildList("nodes") {
+		var literals []string
+		_ = n.Attr("literals", &literals)
+		if n.NodeType != "PragmaDirective" || len(literals) == 0 || literals[0] != "solidity" {
+			continue
+		}
+		if strings.ContainsAny(strings.Join(literals[1:], ""), "^~<>*|-xX") {
+			findings = append(findings, Finding{
+				Severity:       SeverityInformation,
+				Message:        "floating pragma, lock the compiler version",
+				SourceLocation: NodeLocation(unit, n),
+			})
+		}
+	}
+	return findings
+})
@@ -0,0 +1,68 @@
+package solc
+
+import (
+	"testing"
+
+	"github.com/nmvalera/solc-go/ast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const analyzedSource = `pragma solidity ^0.6.2;
+contract Proxy {
+    constructor(address impl) public { impl.delegatecall(""); }
+}
+contract Owned {
+    address owner;
+    function set() public { require(tx.origin == owner); owner = msg.sender; }
+}
+`
+
+func TestAnalyzers(t *testing.T) {
+	var analyzed []string
+	bytecode := NewAnalyzer("bytecode", func(unit *ast.Unit, node *ast.Contract, contract *Contract) []Finding {
+		analyzed = append(analyzed, node.Name)
+		if contract.EVM.DeployedBytecode.Object == "" {
+			return []Finding{{Severity: SeverityError, Message: "no bytecode"}}
+		}
+		return nil
+	})
+
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js", WithAnalyzers(DefaultAnalyzers...), WithAnalyzers(bytecode))
+	require.NoError(t, err, "Creating compiler should not error")
+	defer solc.Close()
+
+	in := NewInput().AddSource("A.sol", analyzedSource)
+	out, err := solc.Compile(in)
+	require.NoError(t, err, "Compile should not error")
+	assert.False(t, in.Settings.OutputSelection.Selected("A.sol", "", "ast"), "Input should not be modified")
+	assert.Equal(t, []string{"Owned", "Proxy"}, analyzed, "Contracts should be analyzed in order")
+
+	var kinds []string
+	for _, f := range out.Findings {
+		kinds = append(kinds, f.Contract.String()+" "+f.Analyzer)
+	}
+	assert.Equal(
+		t,
+		[]string{"A.sol:Owned tx-origin", "A.sol:Proxy constructor-delegatecall", "A.sol:Proxy floating-pragma"},
+		kinds,
+		"Findings should be sorted by contract then analyzer",
+	)
+	origin := out.Findings[0]
+	assert.Equal(t, SeverityWarning, origin.Severity)
+	assert.Equal(t, "A.sol", origin.SourceLocation.File)
+	assert.Equal(t, "tx.origin", analyzedSource[origin.SourceLocation.Start:origin.SourceLocation.End], "Location should point at the node")
+
+	// Locked pragmas are not reported
+	findings, err := Analyze(out, bytecode)
+	require.NoError(t, err, "Analyze should not error")
+	assert.Empty(t, findings, "Contracts with bytecode should not be reported")
+	out, err = CompileSource(solc, "B.sol", "pragma solidity =0.6.2; contract B {}")
+	require.NoError(t, err, "Compile should not error")
+	assert.Empty(t, out.Findings, "Locked pragma should not be reported")
+
+	// Outputs without AST are not analyzed
+	findings, err = Analyze(&Output{Contracts: map[string]map[string]Contract{"A.sol": {"A": {}}}}, bytecode)
+	require.NoError(t, err, "Analyze should not error")
+	assert.Empty(t, findings, "Contracts without AST should not be analyzed")
+}
@@ -0,0 +1,101 @@
+package solc
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nmvalera/solc-go/types"
+)
+
+// ArtifactFormat is the Hardhat artifact format emitted by this package
+const ArtifactFormat = "hh-sol-artifact-1"
+
+// Artifact is a Hardhat compatible contract artifact (artifacts/<sourceName>/<contractName>.json)
+type Artifact struct {
+	Format                 string                                `json:"_format"`
+	ContractName           string                                `json:"contractName"`
+	SourceName             string                                `json:"sourceName"`
+	ABI                    []json.RawMessage                     `json:"abi"`
+	Bytecode               string                                `json:"bytecode"`
+	DeployedBytecode       string                                `json:"deployedBytecode"`
+	LinkReferences         map[string]map[string][]LinkReference `json:"linkReferences"`
+	DeployedLinkReferences map[string]map[string][]LinkReference `json:"deployedLinkReferences"`
+}
+
+// NewArtifacts returns the artifacts of the contracts of output, sorted by source and contract name
+func NewArtifacts(output *Output) []*Artifact {
+	var artifacts []*Artifact
+	for sourceName, contracts := range output.Contracts {
+		for contractName, contract := range contracts {
+			artifacts = append(artifacts, &Artifact{
+				Format:                 ArtifactFormat,
+				ContractName:           contractName,
+				SourceName:             sourceName,
+				ABI:                    nonNilABI(contract.ABI),
+				Bytecode:               "0x" + contract.EVM.Bytecode.Object,
+				DeployedBytecode:       "0x" + contract.EVM.DeployedBytecode.Object,
+				LinkReferences:         nonNilLinkReferences(contract.EVM.Bytecode.LinkReferences),
+				DeployedLinkReferences: nonNilLinkReferences(contract.EVM.DeployedBytecode.LinkReferences),
+			})
+		}
+	}
+	sort.Slice(artifacts, func(i, j int) bool {
+		if artifacts[i].SourceName != artifacts[j].SourceName {
+			return artifacts[i].SourceName < artifacts[j].SourceName
+		}
+		return artifacts[i].ContractName < artifacts[j].ContractName
+	})
+	return artifacts
+}
+
+func nonNilABI(abi []json.RawMessage) []json.RawMessage {
+	if abi == nil {
+		return []json.RawMessage{}
+	}
+	return abi
+}
+
+func nonNilLinkReferences(refs map[string]map[string][]LinkReference) map[string]map[string][]LinkReference {
+	if refs == nil {
+		return map[string]map[string][]LinkReference{}
+	}
+	return refs
+}
+
+// ID returns the fully qualified name of the contract of the artifact
+func (a *Artifact) ID() ContractID {
+	return ContractID{File: a.SourceName, Name: a.ContractName}
+}
+
+// Path returns the slash separated path WriteFile writes the artifact to, relative to the artifacts directory
+func (a *Artifact) Path() string {
+	return strings.TrimPrefix(path.Join(NormalizeSourceName(a.SourceName), a.ContractName+".json"), "/")
+}
+
+// WriteFile writes the artifact into <artifactsDir>/<sourceName>/<contractName>.json and returns the file path
+//
+// The source name is normalized (see NormalizeSourceName) so artifacts are laid out alike on every platform
+func (a *Artifact) WriteFile(artifactsDir string) (string, error) {
+	file := filepath.Join(artifactsDir, filepath.FromSlash(a.Path()))
+	err := os.MkdirAll(filepath.Dir(file), 0755)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := types.MarshalDeterministic(a)
+	if err != nil {
+		return "", err
+	}
+
+	err = ioutil.WriteFile(file, b, 0644)
+	if err != nil {
+		return "", err
+	}
+
+	return file, nil
+}
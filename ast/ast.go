@@ -0,0 +1,232 @@
+package ast
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Node is a generic node of the solc compact JSON AST
+//
+// Common fields are decoded eagerly, any other attribute can be read with Attr
+type Node struct {
+	ID       int
+	NodeType string
+	Src      string
+
+	Parent   *Node
+	Children []*Node
+
+	attrs  map[string]json.RawMessage
+	fields map[string][]*Node
+}
+
+// Unit is the root SourceUnit node of a source file
+type Unit struct {
+	*Node
+	AbsolutePath string
+}
+
+// SourceRange is a decoded "start:length:file" src attribute
+type SourceRange struct {
+	Start  int
+	Length int
+	File   int
+}
+
+// Parse decodes the compact JSON AST of a single source as found in Output.Sources[name].AST
+func Parse(b []byte) (*Unit, error) {
+	root := &Node{}
+	err := json.Unmarshal(b, root)
+	if err != nil {
+		return nil, err
+	}
+
+	if root.NodeType != "SourceUnit" {
+		return nil, fmt.Errorf("ast: expected SourceUnit root node but got %q", root.NodeType)
+	}
+
+	return &Unit{
+		Node:         root,
+		AbsolutePath: root.StringAttr("absolutePath"),
+	}, nil
+}
+
+func (n *Node) UnmarshalJSON(b []byte) error {
+	attrs := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &attrs)
+	if err != nil {
+		return err
+	}
+
+	n.attrs = attrs
+	n.fields = make(map[string][]*Node)
+	n.NodeType = n.StringAttr("nodeType")
+	n.Src = n.StringAttr("src")
+	_ = n.Attr("id", &n.ID)
+
+	// Any attribute holding a node (or a list of nodes) is a child
+	for key, raw := range attrs {
+		children, ok := decodeChildren(raw)
+		if !ok {
+			continue
+		}
+		for _, child := range children {
+			child.Parent = n
+		}
+		n.fields[key] = children
+		n.Children = append(n.Children, children...)
+	}
+
+	// Keep children in source order so walks are deterministic
+	sort.SliceStable(n.Children, func(i, j int) bool {
+		ri, rj := n.Children[i].Range(), n.Children[j].Range()
+		if ri.Start != rj.Start {
+			return ri.Start < rj.Start
+		}
+		return n.Children[i].ID < n.Children[j].ID
+	})
+
+	return nil
+}
+
+func decodeChildren(raw json.RawMessage) ([]*Node, bool) {
+	trimmed := strings.TrimSpace(string(raw))
+	switch {
+	case strings.HasPrefix(trimmed, "{"):
+		if !strings.Contains(trimmed, `"nodeType"`) {
+			return nil, false
+		}
+		child := &Node{}
+		if json.Unmarshal(raw, child) != nil || child.NodeType == "" {
+			return nil, false
+		}
+		return []*Node{child}, true
+	case strings.HasPrefix(trimmed, "["):
+		if !strings.Contains(trimmed, `"nodeType"`) {
+			return nil, false
+		}
+		var items []json.RawMessage
+		if json.Unmarshal(raw, &items) != nil {
+			return nil, false
+		}
+		var children []*Node
+		for _, item := range items {
+			nodes, ok := decodeChildren(item)
+			if ok {
+				children = append(children, nodes...)
+			}
+		}
+		return children, len(children) > 0
+	default:
+		return nil, false
+	}
+}
+
+// Attr decodes the raw attribute name into v
+func (n *Node) Attr(name string, v interface{}) error {
+	raw, ok := n.attrs[name]
+	if !ok {
+		return fmt.Errorf("ast: node %v has no attribute %q", n.ID, name)
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// Child returns the node held by attribute name or nil
+func (n *Node) Child(name string) *Node {
+	if n == nil {
+		return nil
+	}
+	if children := n.fields[name]; len(children) > 0 {
+		return children[0]
+	}
+	return nil
+}
+
+// ChildList returns the nodes held by the list attribute name
+func (n *Node) ChildList(name string) []*Node {
+	return n.fields[name]
+}
+
+// HasAttr indicates whether the node carries attribute name
+func (n *Node) HasAttr(name string) bool {
+	_, ok := n.attrs[name]
+	return ok
+}
+
+// StringAttr returns a string attribute or "" if missing or not a string
+func (n *Node) StringAttr(name string) string {
+	var s string
+	_ = n.Attr(name, &s)
+	return s
+}
+
+// BoolAttr returns a boolean attribute or false if missing or not a boolean
+func (n *Node) BoolAttr(name string) bool {
+	var b bool
+	_ = n.Attr(name, &b)
+	return b
+}
+
+// Name returns the name attribute of the node
+func (n *Node) Name() string {
+	return n.StringAttr("name")
+}
+
+// TypeString returns typeDescriptions.typeString of the node
+func (n *Node) TypeString() string {
+	var desc struct {
+		TypeString string `json:"typeString"`
+	}
+	_ = n.Attr("typeDescriptions", &desc)
+	return desc.TypeString
+}
+
+// Range decodes the src attribute of the node
+func (n *Node) Range() SourceRange {
+	r := SourceRange{Start: -1, Length: -1, File: -1}
+	parts := strings.Split(n.Src, ":")
+	if len(parts) != 3 {
+		return r
+	}
+	r.Start, _ = strconv.Atoi(parts[0])
+	r.Length, _ = strconv.Atoi(parts[1])
+	r.File, _ = strconv.Atoi(parts[2])
+	return r
+}
+
+// Walk traverses the node and its descendants depth-first
+//
+// Descendants of a node are skipped when fn returns false
+func (n *Node) Walk(fn func(*Node) bool) {
+	if !fn(n) {
+		return
+	}
+	for _, child := range n.Children {
+		child.Walk(fn)
+	}
+}
+
+// Find returns all descendants of the node (including itself) of the given node type
+func (n *Node) Find(nodeType string) []*Node {
+	var nodes []*Node
+	n.Walk(func(node *Node) bool {
+		if node.NodeType == nodeType {
+			nodes = append(nodes, node)
+		}
+		return true
+	})
+	return nodes
+}
+
+// Ancestor returns the closest ancestor of the given node type or nil
+func (n *Node) Ancestor(nodeType string) *Node {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p.NodeType == nodeType {
+			return p
+		}
+	}
+	return nil
+}
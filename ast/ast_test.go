@@ -0,0 +1,69 @@
+package ast
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadUnit(t *testing.T, file string) *Unit {
+	b, err := ioutil.ReadFile(file)
+	require.NoError(t, err, "Reading AST fixture should not error")
+	unit, err := Parse(b)
+	require.NoError(t, err, "Parsing valid AST should not error")
+	return unit
+}
+
+func TestQueries(t *testing.T) {
+	unit := loadUnit(t, "./testdata/Token.json")
+	assert.Equal(t, "Token.sol", unit.AbsolutePath, "Absolute path should be correct")
+
+	// Contracts
+	var names []string
+	for _, c := range unit.Contracts() {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, []string{"Base", "Mid", "Token"}, names, "Contracts should be listed in source order")
+
+	// Inheritance
+	graph := NewInheritanceGraph(unit)
+	token := unit.Contract("Token")
+	require.NotNil(t, token, "Token contract should be found")
+	var linearized []string
+	for _, c := range graph.Linearized(token) {
+		linearized = append(linearized, c.Name)
+	}
+	assert.Equal(t, []string{"Token", "Mid", "Base"}, linearized, "Linearization should be correct")
+	require.Len(t, graph.Bases(token), 1, "Token should have a single direct base")
+	assert.Equal(t, "Mid", graph.Bases(token)[0].Name, "Token should inherit from Mid")
+	require.Len(t, graph.Derived(unit.Contract("Base")), 1, "Base should have a single direct derived contract")
+	assert.Equal(t, "Mid", graph.Derived(unit.Contract("Base"))[0].Name, "Mid should derive from Base")
+
+	// Functions by selector
+	f := token.FunctionBySelector("0x901717d1")
+	require.NotNil(t, f, "Function should be found by selector")
+	assert.Equal(t, "one", f.Name, "Function name should be correct")
+	assert.Len(t, unit.FunctionsBySelector("901717D1"), 2, "Base and overriding function should match selector")
+	assert.Nil(t, token.FunctionBySelector("deadbeef"), "Unknown selector should not match")
+
+	// State variables
+	vars := unit.Contract("Mid").StateVariables()
+	require.Len(t, vars, 2, "Mid should declare 2 state variables")
+	assert.Equal(t, "owner", vars[0].Name)
+	assert.Equal(t, "public", vars[0].Visibility)
+	assert.Equal(t, "address", vars[0].Type)
+	assert.Equal(t, "balances", vars[1].Name)
+	assert.Equal(t, "private", vars[1].Visibility)
+	assert.Equal(t, "mapping(address => uint256)", vars[1].Type)
+
+	// External calls
+	var kinds []string
+	for _, call := range token.ExternalCalls() {
+		kinds = append(kinds, call.Kind)
+	}
+	assert.Equal(t, []string{"call", "delegatecall", "external", "transfer"}, kinds, "External calls should be found in source order")
+	assert.Equal(t, "one", token.ExternalCalls()[2].Function, "External call function should be correct")
+	assert.Equal(t, 0, token.ExternalCalls()[0].Range().File, "External call location should be decoded")
+}
@@ -0,0 +1,494 @@
+package ast
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Interface is the external interface of a contract, inherited members included
+type Interface struct {
+	Name string
+
+	// License is the SPDX license identifier of the contract source unit
+	License string
+
+	// Pragmas are the pragma directives of the contract source unit, e.g. "solidity ^0.8.4"
+	Pragmas []string
+
+	// Imports are the paths of the source units declaring contracts and types referenced by the interface
+	Imports []string
+
+	Documentation string
+	Members       []*InterfaceMember
+}
+
+// InterfaceMember is a declaration of an interface
+type InterfaceMember struct {
+	// Kind is one of "struct", "enum", "type", "error", "event" or "function"
+	Kind string
+	Name string
+
+	// Documentation is the NatSpec of the declaration, with @inheritdoc tags resolved
+	Documentation string
+
+	// Declaration is the Solidity source of the member, without documentation
+	Declaration string
+
+	// Node is the declaration the member is extracted from, a state variable for getters
+	Node *Node
+}
+
+// Interface extracts the interface of c from the AST
+//
+// The interface declares the external and public functions, public state variable getters, events and errors
+// of c and its bases, along with the structs, enums and user defined value types of the hierarchy they use.
+// NatSpec comments are carried over. Constructors, fallback and receive functions are left out.
+//
+// The interface is named after c with an "I" prefix, and written to Solidity with String
+func (g *InheritanceGraph) Interface(c *Contract) *Interface {
+	e := &extractor{
+		g:       g,
+		scopes:  make(map[int]bool),
+		types:   make(map[int]bool),
+		imports: make(map[string]bool),
+	}
+	linearized := g.Linearized(c)
+	for _, base := range linearized {
+		e.scopes[base.ID] = true
+	}
+
+	// Walk bases first so members keep the position of their first declaration
+	var members []*InterfaceMember
+	index := make(map[string]int)
+	for k := len(linearized) - 1; k >= 0; k-- {
+		for _, n := range linearized[k].ChildList("nodes") {
+			key, m := e.member(n)
+			if m == nil {
+				continue
+			}
+			if i, ok := index[key]; ok {
+				m.Documentation = inheritDoc(m.Documentation, members[i].Documentation)
+				members[i] = m
+				continue
+			}
+			m.Documentation = inheritDoc(m.Documentation, "")
+			index[key] = len(members)
+			members = append(members, m)
+		}
+	}
+
+	// Group members by kind, types being needed by the others
+	members = append(e.typeMembers(linearized), members...)
+	sort.SliceStable(members, func(i, j int) bool {
+		return memberOrder[members[i].Kind] < memberOrder[members[j].Kind]
+	})
+
+	i := &Interface{
+		Name:          "I" + c.Name,
+		Documentation: inheritDoc(documentation(c.Node), ""),
+		Members:       members,
+	}
+	if unit := c.Ancestor("SourceUnit"); unit != nil {
+		i.License = unit.StringAttr("license")
+		for _, n := range unit.ChildList("nodes") {
+			if n.NodeType == "PragmaDirective" {
+				if p := pragma(n); p != "" {
+					i.Pragmas = append(i.Pragmas, p)
+				}
+			}
+		}
+	}
+	for path := range e.imports {
+		i.Imports = append(i.Imports, path)
+	}
+	sort.Strings(i.Imports)
+
+	return i
+}
+
+var memberOrder = map[string]int{"error": 1, "event": 2, "function": 3}
+
+// String returns the Solidity source of the interface
+func (i *Interface) String() string {
+	var b strings.Builder
+	if i.License != "" {
+		fmt.Fprintf(&b, "// SPDX-License-Identifier: %v\n", i.License)
+	}
+	for _, p := range i.Pragmas {
+		fmt.Fprintf(&b, "pragma %v;\n", p)
+	}
+	if len(i.Imports) > 0 {
+		b.WriteString("\n")
+		for _, path := range i.Imports {
+			fmt.Fprintf(&b, "import %q;\n", path)
+		}
+	}
+	if b.Len() > 0 {
+		b.WriteString("\n")
+	}
+
+	writeDoc(&b, i.Documentation, "")
+	fmt.Fprintf(&b, "interface %v {\n", i.Name)
+	for k, m := range i.Members {
+		if k > 0 {
+			b.WriteString("\n")
+		}
+		writeDoc(&b, m.Documentation, "    ")
+		for _, line := range strings.Split(m.Declaration, "\n") {
+			b.WriteString("    " + line + "\n")
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func writeDoc(b *strings.Builder, doc, indent string) {
+	if doc == "" {
+		return
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		b.WriteString(strings.TrimRight(indent+"/// "+line, " ") + "\n")
+	}
+}
+
+// extractor renders the members of an interface
+type extractor struct {
+	g *InheritanceGraph
+
+	// scopes are the ids of the contracts of the hierarchy, types declared in them are copied in the interface
+	scopes map[int]bool
+
+	// types are the ids of the referenced type declarations to copy
+	types map[int]bool
+
+	imports map[string]bool
+}
+
+// member returns the interface member extracted from n and the key identifying overrides, nil if n is not external
+func (e *extractor) member(n *Node) (string, *InterfaceMember) {
+	m := &InterfaceMember{Name: n.Name(), Documentation: documentation(n), Node: n}
+	switch n.NodeType {
+	case "FunctionDefinition":
+		visibility := n.StringAttr("visibility")
+		if n.StringAttr("kind") != "function" || (visibility != "public" && visibility != "external") {
+			return "", nil
+		}
+		m.Kind, m.Declaration = "function", e.function(n)
+		return "function:" + selector(n, m.Name, parameterTypes(n.Child("parameters"))), m
+	case "VariableDeclaration":
+		if !n.BoolAttr("stateVariable") || n.StringAttr("visibility") != "public" {
+			return "", nil
+		}
+		declaration, keys := e.getter(n)
+		m.Kind, m.Declaration = "function", declaration
+		return "function:" + selector(n, m.Name, keys), m
+	case "EventDefinition":
+		m.Kind = "event"
+		m.Declaration = "event " + m.Name + "(" + e.parameters(n.Child("parameters"), "", true) + ")"
+		if n.BoolAttr("anonymous") {
+			m.Declaration += " anonymous"
+		}
+		m.Declaration += ";"
+		return "event:" + signature(m.Name, parameterTypes(n.Child("parameters"))), m
+	case "ErrorDefinition":
+		m.Kind = "error"
+		m.Declaration = "error " + m.Name + "(" + e.parameters(n.Child("parameters"), "", false) + ");"
+		return "error:" + signature(m.Name, parameterTypes(n.Child("parameters"))), m
+	}
+	return "", nil
+}
+
+func (e *extractor) function(n *Node) string {
+	s := "function " + n.Name() + "(" + e.parameters(n.Child("parameters"), "calldata", false) + ") external"
+	if mutability := n.StringAttr("stateMutability"); mutability != "" && mutability != "nonpayable" {
+		s += " " + mutability
+	}
+	if returns := e.parameters(n.Child("returnParameters"), "memory", false); returns != "" {
+		s += " returns (" + returns + ")"
+	}
+	return s + ";"
+}
+
+// getter renders the getter of a public state variable and returns the canonical types of its parameters
+func (e *extractor) getter(n *Node) (string, []string) {
+	var params, keys []string
+	typ := n.Child("typeName")
+loop:
+	for typ != nil {
+		switch typ.NodeType {
+		case "Mapping":
+			key := typ.Child("keyType")
+			params = append(params, e.typeName(key)+location(key, "calldata"))
+			keys = append(keys, canonicalType(key.TypeString()))
+			typ = typ.Child("valueType")
+		case "ArrayTypeName":
+			params = append(params, "uint256")
+			keys = append(keys, "uint256")
+			typ = typ.Child("baseType")
+		default:
+			break loop
+		}
+	}
+
+	// Getters of structs return their members, except mappings and arrays
+	var returns []string
+	if decl := e.declaration(typ); decl != nil && decl.NodeType == "StructDefinition" {
+		for _, member := range decl.ChildList("members") {
+			t := member.Child("typeName")
+			if t == nil || t.NodeType == "Mapping" || t.NodeType == "ArrayTypeName" {
+				continue
+			}
+			returns = append(returns, e.typeName(t)+location(member, "memory")+" "+member.Name())
+		}
+	} else if typ != nil {
+		returns = append(returns, e.typeName(typ)+location(typ, "memory"))
+	}
+
+	s := "function " + n.Name() + "(" + strings.Join(params, ", ") + ") external view"
+	if len(returns) > 0 {
+		s += " returns (" + strings.Join(returns, ", ") + ")"
+	}
+	return s + ";", keys
+}
+
+// parameters renders a parameter list, reference types are given loc (none for events and errors)
+func (e *extractor) parameters(list *Node, loc string, event bool) string {
+	if list == nil {
+		return ""
+	}
+	var params []string
+	for _, p := range list.ChildList("parameters") {
+		s := e.typeName(p.Child("typeName"))
+		if loc != "" {
+			s += location(p, loc)
+		}
+		if event && p.BoolAttr("indexed") {
+			s += " indexed"
+		}
+		if name := p.Name(); name != "" {
+			s += " " + name
+		}
+		params = append(params, s)
+	}
+	return strings.Join(params, ", ")
+}
+
+var arrayLength = regexp.MustCompile(`\[(\d+)\]`)
+
+// typeName renders a type name as written in the source, types of the hierarchy are referenced unqualified
+func (e *extractor) typeName(n *Node) string {
+	if n == nil {
+		return ""
+	}
+	switch n.NodeType {
+	case "ElementaryTypeName":
+		name := n.StringAttr("name")
+		if name == "address" && n.StringAttr("stateMutability") == "payable" {
+			return "address payable"
+		}
+		return name
+	case "UserDefinedTypeName":
+		return e.userDefined(n)
+	case "ArrayTypeName":
+		length := ""
+		if l := n.Child("length"); l != nil {
+			if l.NodeType == "Literal" {
+				length = l.StringAttr("value")
+			} else if m := arrayLength.FindAllStringSubmatch(n.TypeString(), -1); len(m) > 0 {
+				// Constant expression, its value is the outermost dimension of the type
+				length = m[len(m)-1][1]
+			}
+		}
+		return e.typeName(n.Child("baseType")) + "[" + length + "]"
+	case "Mapping":
+		return "mapping(" + e.typeName(n.Child("keyType")) + " => " + e.typeName(n.Child("valueType")) + ")"
+	}
+	return canonicalType(n.TypeString())
+}
+
+func (e *extractor) userDefined(n *Node) string {
+	written := n.Name()
+	if path := n.Child("pathNode"); path != nil {
+		written = path.Name()
+	}
+
+	decl := e.declaration(n)
+	if decl == nil {
+		return written
+	}
+	if decl.NodeType != "ContractDefinition" {
+		if scope := decl.Ancestor("ContractDefinition"); scope != nil && e.scopes[scope.ID] {
+			e.types[decl.ID] = true
+			return decl.Name()
+		}
+	}
+	if unit := decl.Ancestor("SourceUnit"); unit != nil {
+		e.imports[unit.StringAttr("absolutePath")] = true
+	}
+	return written
+}
+
+// declaration returns the declaration referenced by a user defined type name or nil
+func (e *extractor) declaration(n *Node) *Node {
+	if n == nil || n.NodeType != "UserDefinedTypeName" {
+		return nil
+	}
+	var id int
+	_ = n.Attr("referencedDeclaration", &id)
+	return e.g.declarations[id]
+}
+
+// typeMembers renders the referenced types declared in the hierarchy, bases first
+func (e *extractor) typeMembers(linearized []*Contract) []*InterfaceMember {
+	// Rendering a struct may reference more types
+	rendered := make(map[int]*InterfaceMember)
+	for len(rendered) < len(e.types) {
+		for id := range e.types {
+			if _, ok := rendered[id]; !ok {
+				rendered[id] = e.typeMember(e.g.declarations[id])
+			}
+		}
+	}
+
+	var members []*InterfaceMember
+	for k := len(linearized) - 1; k >= 0; k-- {
+		for _, n := range linearized[k].ChildList("nodes") {
+			if m := rendered[n.ID]; m != nil {
+				members = append(members, m)
+			}
+		}
+	}
+	return members
+}
+
+func (e *extractor) typeMember(n *Node) *InterfaceMember {
+	m := &InterfaceMember{Name: n.Name(), Documentation: inheritDoc(documentation(n), ""), Node: n}
+	switch n.NodeType {
+	case "StructDefinition":
+		lines := []string{"struct " + m.Name + " {"}
+		for _, member := range n.ChildList("members") {
+			lines = append(lines, "    "+e.typeName(member.Child("typeName"))+" "+member.Name()+";")
+		}
+		m.Kind, m.Declaration = "struct", strings.Join(append(lines, "}"), "\n")
+	case "EnumDefinition":
+		var values []string
+		for _, value := range n.ChildList("members") {
+			values = append(values, value.Name())
+		}
+		m.Kind, m.Declaration = "enum", "enum "+m.Name+" { "+strings.Join(values, ", ")+" }"
+	case "UserDefinedValueTypeDefinition":
+		m.Kind, m.Declaration = "type", "type "+m.Name+" is "+e.typeName(n.Child("underlyingType"))+";"
+	}
+	return m
+}
+
+// location returns the data location suffix of n if it is of a reference type
+func location(n *Node, loc string) string {
+	var desc struct {
+		TypeIdentifier string `json:"typeIdentifier"`
+	}
+	_ = n.Attr("typeDescriptions", &desc)
+	for _, prefix := range []string{"t_string", "t_bytes_", "t_array", "t_struct"} {
+		if strings.HasPrefix(desc.TypeIdentifier, prefix) {
+			return " " + loc
+		}
+	}
+	return ""
+}
+
+// selector returns the function selector of n, or its signature for compilers not exposing selectors (<0.6.0)
+func selector(n *Node, name string, types []string) string {
+	if s := n.StringAttr("functionSelector"); s != "" {
+		return s
+	}
+	return signature(name, types)
+}
+
+func signature(name string, types []string) string {
+	return name + "(" + strings.Join(types, ",") + ")"
+}
+
+func parameterTypes(list *Node) []string {
+	if list == nil {
+		return nil
+	}
+	var types []string
+	for _, p := range list.ChildList("parameters") {
+		types = append(types, canonicalType(p.TypeString()))
+	}
+	return types
+}
+
+var locations = strings.NewReplacer(" storage ref", "", " storage pointer", "", " memory", "", " calldata", "", " pointer", "")
+
+// canonicalType strips data locations from a type string
+func canonicalType(typ string) string {
+	return locations.Replace(typ)
+}
+
+// documentation returns the NatSpec of a declaration, without comment markers
+//
+// solc <0.6.3 exposes documentation as a string, later versions as a StructuredDocumentation node
+func documentation(n *Node) string {
+	text := n.StringAttr("documentation")
+	if doc := n.Child("documentation"); doc != nil {
+		text = doc.StringAttr("text")
+	}
+	lines := strings.Split(text, "\n")
+	for k, line := range lines {
+		lines[k] = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "*"))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// inheritDoc replaces @inheritdoc tags of doc by the documentation of the overridden declaration
+//
+// A declaration without documentation inherits the one of the declaration it overrides
+func inheritDoc(doc, base string) string {
+	if doc == "" {
+		return base
+	}
+	var lines []string
+	for _, line := range strings.Split(doc, "\n") {
+		if !strings.HasPrefix(line, "@inheritdoc") {
+			lines = append(lines, line)
+		} else if base != "" {
+			lines = append(lines, base)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// pragma renders a pragma directive from its literals
+func pragma(n *Node) string {
+	var literals []string
+	_ = n.Attr("literals", &literals)
+	if len(literals) == 0 {
+		return ""
+	}
+	if literals[0] != "solidity" {
+		return strings.Join(literals, " ")
+	}
+
+	// Version literals are split on dots, e.g. ">=", "0.6", ".0", "<", "0.8", ".0"
+	s := "solidity "
+	for k, literal := range literals[1:] {
+		if literal == "" {
+			continue
+		}
+		if k > 0 {
+			prev := literals[k]
+			if (isVersionOperator(literal[0]) && !isVersionOperator(prev[len(prev)-1])) || prev == "||" || prev == "-" {
+				s += " "
+			}
+		}
+		s += literal
+	}
+	return s
+}
+
+func isVersionOperator(c byte) bool {
+	return strings.IndexByte("<>=^~|-", c) >= 0
+}
@@ -0,0 +1,122 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterface(t *testing.T) {
+	unit := loadUnit(t, "./testdata/Vault.json")
+	graph := NewInheritanceGraph(unit, loadUnit(t, "./testdata/IERC20Like.json"))
+	vault := unit.Contract("Vault")
+	require.NotNil(t, vault, "Vault contract should be found")
+
+	i := graph.Interface(vault)
+	assert.Equal(t, "IVault", i.Name, "Interface name should be correct")
+	assert.Equal(t, []string{"solidity ^0.6.2", "experimental ABIEncoderV2"}, i.Pragmas, "Pragmas should be carried over")
+	assert.Equal(t, []string{"IERC20Like.sol"}, i.Imports, "Referenced contracts should be imported")
+	var kinds []string
+	for _, m := range i.Members {
+		kinds = append(kinds, m.Kind+" "+m.Name)
+	}
+	assert.Equal(
+		t,
+		[]string{
+			"struct Deposit", "enum State", "event OwnerChanged", "event Deposited",
+			"function owner", "function setOwner", "function deposits", "function flags", "function history",
+			"function state", "function token", "function deposit", "function depositsOf", "function hash",
+		},
+		kinds,
+		"Members should be grouped by kind, bases first, unused types and internal functions excluded",
+	)
+	assert.Equal(t, `pragma solidity ^0.6.2;
+pragma experimental ABIEncoderV2;
+
+import "IERC20Like.sol";
+
+/// @title Vault
+/// @notice Holds deposits of accounts
+interface IVault {
+    struct Deposit {
+        uint256 amount;
+        uint64 time;
+        string memo;
+        uint256[] parts;
+    }
+
+    enum State { Open, Closed }
+
+    /// @notice Emitted when the owner changes
+    event OwnerChanged(address indexed previous, address indexed next);
+
+    event Deposited(address indexed account, Deposit deposit);
+
+    function owner() external view returns (address);
+
+    /// @notice Transfers ownership
+    /// @param next The new owner
+    function setOwner(address next) external;
+
+    function deposits(address) external view returns (uint256 amount, uint64 time, string memory memo);
+
+    function flags(address, uint256) external view returns (bool);
+
+    function history(uint256) external view returns (uint256);
+
+    function state() external view returns (State);
+
+    function token() external view returns (IERC20Like);
+
+    /// @notice Deposits ether
+    function deposit(string calldata memo) external payable;
+
+    /// @notice Returns the deposits of accounts
+    /// @param accounts The accounts
+    /// @return The deposits
+    function depositsOf(address[] calldata accounts) external view returns (Deposit[] memory);
+
+    function hash(bytes calldata data, address payable to) external pure returns (bytes32 h);
+}
+`, i.String(), "Interface source should be correct")
+
+	// Custom errors, user defined value types and structured documentation (solc >=0.8.4)
+	unit = loadUnit(t, "./testdata/Pausable.json")
+	i = NewInheritanceGraph(unit).Interface(unit.Contract("Pausable"))
+	assert.Equal(t, `// SPDX-License-Identifier: MIT
+pragma solidity >=0.8.4 <0.9.0;
+
+/// @title Pausable
+/// @author solc-go
+interface IPausable {
+    type Price is uint128;
+
+    /// @notice Reverted while paused
+    /// @param until End of the pause
+    error Paused(uint256 until);
+
+    error Unauthorized(address caller);
+
+    /// @notice Emitted on pause
+    event PauseSet(address indexed account, Price price);
+
+    /// @notice Pauses the contract
+    /// @dev Only callable by the owner
+    function pause() external;
+
+    /// @notice Current price
+    function price() external view returns (Price);
+
+    /// @notice Names of the
+    /// roles
+    function names() external view returns (string[3] memory);
+}
+`, i.String(), "Interface source should be correct")
+}
+
+func TestInheritDoc(t *testing.T) {
+	assert.Equal(t, "@notice Base", inheritDoc("", "@notice Base"), "Undocumented override should inherit documentation")
+	assert.Equal(t, "@notice Base\n@dev Derived", inheritDoc("@inheritdoc Base\n@dev Derived", "@notice Base"), "@inheritdoc should be replaced")
+	assert.Equal(t, "@dev Derived", inheritDoc("@inheritdoc Base\n@dev Derived", ""), "Unresolved @inheritdoc should be dropped")
+}
@@ -0,0 +1,389 @@
+package ast
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Contract is a ContractDefinition node
+type Contract struct {
+	*Node
+	Name                    string
+	Kind                    string
+	Abstract                bool
+	LinearizedBaseContracts []int
+}
+
+// Function is a FunctionDefinition node
+type Function struct {
+	*Node
+	Name            string
+	Kind            string
+	Visibility      string
+	StateMutability string
+	Selector        string
+}
+
+// StateVariable is a VariableDeclaration node declared at contract level
+type StateVariable struct {
+	*Node
+	Name       string
+	Visibility string
+	Type       string
+	Constant   bool
+	Selector   string
+}
+
+// ExternalCall is a call leaving the current contract
+type ExternalCall struct {
+	*Node
+
+	// Kind is one of "call", "delegatecall", "staticcall", "send", "transfer" or "external"
+	// (high level call to a function of another contract)
+	Kind string
+
+	// Function is the name of the called function for "external" calls
+	Function string
+}
+
+// AssemblyBlock is an InlineAssembly node
+type AssemblyBlock struct {
+	*Node
+
+	// Opcodes are the call, create and selfdestruct opcodes used by the block, in order of first use
+	Opcodes []string
+}
+
+func newContract(n *Node) *Contract {
+	c := &Contract{
+		Node:     n,
+		Name:     n.Name(),
+		Kind:     n.StringAttr("contractKind"),
+		Abstract: n.BoolAttr("abstract"),
+	}
+	_ = n.Attr("linearizedBaseContracts", &c.LinearizedBaseContracts)
+	return c
+}
+
+func newFunction(n *Node) *Function {
+	return &Function{
+		Node:            n,
+		Name:            n.Name(),
+		Kind:            n.StringAttr("kind"),
+		Visibility:      n.StringAttr("visibility"),
+		StateMutability: n.StringAttr("stateMutability"),
+		Selector:        n.StringAttr("functionSelector"),
+	}
+}
+
+func newStateVariable(n *Node) *StateVariable {
+	return &StateVariable{
+		Node:       n,
+		Name:       n.Name(),
+		Visibility: n.StringAttr("visibility"),
+		Type:       n.TypeString(),
+		Constant:   n.BoolAttr("constant"),
+		Selector:   n.StringAttr("functionSelector"),
+	}
+}
+
+// Contracts returns the contracts, interfaces and libraries defined in the unit
+func (u *Unit) Contracts() []*Contract {
+	var contracts []*Contract
+	for _, n := range u.Children {
+		if n.NodeType == "ContractDefinition" {
+			contracts = append(contracts, newContract(n))
+		}
+	}
+	return contracts
+}
+
+// Contract returns the contract with the given name or nil
+func (u *Unit) Contract(name string) *Contract {
+	for _, c := range u.Contracts() {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// Functions returns the functions declared by the contract (inherited functions excluded)
+func (c *Contract) Functions() []*Function {
+	var functions []*Function
+	for _, n := range c.Children {
+		if n.NodeType == "FunctionDefinition" {
+			functions = append(functions, newFunction(n))
+		}
+	}
+	return functions
+}
+
+// StateVariables returns the state variables declared by the contract (inherited variables excluded)
+func (c *Contract) StateVariables() []*StateVariable {
+	var vars []*StateVariable
+	for _, n := range c.Children {
+		if n.NodeType == "VariableDeclaration" && n.BoolAttr("stateVariable") {
+			vars = append(vars, newStateVariable(n))
+		}
+	}
+	return vars
+}
+
+// FunctionBySelector returns the function of the contract matching the 4 bytes selector or nil
+//
+// Selectors are only available on public and external functions (solc >=0.6.0)
+func (c *Contract) FunctionBySelector(selector string) *Function {
+	selector = normalizeSelector(selector)
+	for _, f := range c.Functions() {
+		if f.Selector != "" && f.Selector == selector {
+			return f
+		}
+	}
+	return nil
+}
+
+// ExternalCalls returns the low level calls, ether transfers and calls to other contracts made in the contract
+func (c *Contract) ExternalCalls() []*ExternalCall {
+	var calls []*ExternalCall
+	c.Walk(func(n *Node) bool {
+		if n.NodeType != "FunctionCall" || n.StringAttr("kind") != "functionCall" {
+			return true
+		}
+		if call := externalCall(n); call != nil {
+			calls = append(calls, call)
+		}
+		return true
+	})
+	return calls
+}
+
+func externalCall(n *Node) *ExternalCall {
+	expr := n.Child("expression")
+
+	// Unwrap call options such as {value: ...} and legacy .value(...)/.gas(...) modifiers
+	for expr != nil && expr.NodeType != "MemberAccess" {
+		switch {
+		case expr.NodeType == "FunctionCallOptions":
+			expr = expr.Child("expression")
+		case expr.NodeType == "FunctionCall" && isCallModifier(expr):
+			expr = expr.Child("expression").Child("expression")
+		default:
+			return nil
+		}
+	}
+	if expr == nil {
+		return nil
+	}
+
+	member := expr.StringAttr("memberName")
+	typ := expr.TypeString()
+	switch {
+	case isBareCall(member, typ):
+		return &ExternalCall{Node: n, Kind: member}
+	case (member == "send" || member == "transfer") && strings.HasPrefix(typ, "function (uint256)"):
+		return &ExternalCall{Node: n, Kind: member}
+	case strings.HasPrefix(typ, "function ") && strings.Contains(typ, " external"):
+		return &ExternalCall{Node: n, Kind: "external", Function: member}
+	}
+	return nil
+}
+
+// Selfdestructs returns the calls to selfdestruct (or suicide before solc 0.5.0) made in the contract
+func (c *Contract) Selfdestructs() []*Node {
+	var calls []*Node
+	c.Walk(func(n *Node) bool {
+		if n.NodeType != "FunctionCall" {
+			return true
+		}
+		expr := n.Child("expression")
+		var ref int
+		if expr != nil && expr.NodeType == "Identifier" && expr.Attr("referencedDeclaration", &ref) == nil && ref < 0 {
+			// Builtins have negative ids
+			if name := expr.Name(); name == "selfdestruct" || name == "suicide" {
+				calls = append(calls, n)
+			}
+		}
+		return true
+	})
+	return calls
+}
+
+// AssemblyBlocks returns the inline assembly blocks of the contract
+func (c *Contract) AssemblyBlocks() []*AssemblyBlock {
+	var blocks []*AssemblyBlock
+	for _, n := range c.Find("InlineAssembly") {
+		blocks = append(blocks, &AssemblyBlock{Node: n, Opcodes: assemblyOpcodes(n)})
+	}
+	return blocks
+}
+
+// assemblyOpcodes lists the opcodes of interest of a block
+//
+// solc >=0.6.0 exposes the Yul AST of blocks, earlier versions their source as the operations attribute
+func assemblyOpcodes(n *Node) []string {
+	var names []string
+	if len(n.Find("YulFunctionCall")) > 0 {
+		for _, call := range n.Find("YulFunctionCall") {
+			names = append(names, call.Child("functionName").Name())
+		}
+	} else {
+		for _, m := range assemblyCall.FindAllStringSubmatch(n.StringAttr("operations"), -1) {
+			names = append(names, m[1])
+		}
+	}
+
+	var opcodes []string
+	for _, name := range names {
+		if assemblyOpcode[name] && !contains(opcodes, name) {
+			opcodes = append(opcodes, name)
+		}
+	}
+	return opcodes
+}
+
+var assemblyCall = regexp.MustCompile(`\b([a-z0-9]+)\s*\(`)
+
+var assemblyOpcode = map[string]bool{
+	"call": true, "callcode": true, "delegatecall": true, "staticcall": true,
+	"create": true, "create2": true, "selfdestruct": true, "suicide": true,
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// isCallModifier detects legacy .value(...)/.gas(...) modifiers
+func isCallModifier(n *Node) bool {
+	expr := n.Child("expression")
+	if expr == nil || expr.NodeType != "MemberAccess" {
+		return false
+	}
+	member := expr.StringAttr("memberName")
+	return (member == "value" || member == "gas") && strings.Contains(expr.TypeString(), "returns (function")
+}
+
+func isBareCall(member, typ string) bool {
+	switch member {
+	case "call", "delegatecall", "staticcall", "callcode":
+		return strings.HasPrefix(typ, "function (")
+	}
+	return false
+}
+
+// FunctionsBySelector returns every function of the unit matching the 4 bytes selector
+func (u *Unit) FunctionsBySelector(selector string) []*Function {
+	var functions []*Function
+	for _, c := range u.Contracts() {
+		if f := c.FunctionBySelector(selector); f != nil {
+			functions = append(functions, f)
+		}
+	}
+	return functions
+}
+
+// ExternalCalls returns the external calls of every contract in the unit
+func (u *Unit) ExternalCalls() []*ExternalCall {
+	var calls []*ExternalCall
+	for _, c := range u.Contracts() {
+		calls = append(calls, c.ExternalCalls()...)
+	}
+	return calls
+}
+
+func normalizeSelector(selector string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(selector, "0x"), "0X"))
+}
+
+// InheritanceGraph indexes contracts of several units by AST id
+type InheritanceGraph struct {
+	contracts map[int]*Contract
+	order     []int
+
+	// declarations are the contracts and type definitions of the units, by AST id
+	declarations map[int]*Node
+}
+
+// NewInheritanceGraph builds the inheritance graph of all contracts in units
+//
+// Units should come from the same compilation so AST ids are consistent
+func NewInheritanceGraph(units ...*Unit) *InheritanceGraph {
+	g := &InheritanceGraph{contracts: make(map[int]*Contract), declarations: make(map[int]*Node)}
+	for _, u := range units {
+		u.Walk(func(n *Node) bool {
+			switch n.NodeType {
+			case "ContractDefinition", "StructDefinition", "EnumDefinition", "UserDefinedValueTypeDefinition":
+				g.declarations[n.ID] = n
+			}
+			return true
+		})
+		for _, c := range u.Contracts() {
+			if _, ok := g.contracts[c.ID]; !ok {
+				g.order = append(g.order, c.ID)
+			}
+			g.contracts[c.ID] = c
+		}
+	}
+	return g
+}
+
+// Contract returns the contract with the given AST id or nil
+func (g *InheritanceGraph) Contract(id int) *Contract {
+	return g.contracts[id]
+}
+
+// Contracts returns all contracts of the graph
+func (g *InheritanceGraph) Contracts() []*Contract {
+	contracts := make([]*Contract, 0, len(g.order))
+	for _, id := range g.order {
+		contracts = append(contracts, g.contracts[id])
+	}
+	return contracts
+}
+
+// Linearized returns the C3 linearization of c, starting with c itself and ending with the most base contract
+func (g *InheritanceGraph) Linearized(c *Contract) []*Contract {
+	var contracts []*Contract
+	for _, id := range c.LinearizedBaseContracts {
+		if base, ok := g.contracts[id]; ok {
+			contracts = append(contracts, base)
+		}
+	}
+	return contracts
+}
+
+// Bases returns the contracts c directly inherits from
+func (g *InheritanceGraph) Bases(c *Contract) []*Contract {
+	var bases []*Contract
+	for _, spec := range c.ChildList("baseContracts") {
+		baseName := spec.Child("baseName")
+		if baseName == nil {
+			continue
+		}
+		var id int
+		_ = baseName.Attr("referencedDeclaration", &id)
+		if base, ok := g.contracts[id]; ok {
+			bases = append(bases, base)
+		}
+	}
+	return bases
+}
+
+// Derived returns the contracts directly inheriting from c
+func (g *InheritanceGraph) Derived(c *Contract) []*Contract {
+	var derived []*Contract
+	for _, candidate := range g.Contracts() {
+		for _, base := range g.Bases(candidate) {
+			if base.ID == c.ID {
+				derived = append(derived, candidate)
+				break
+			}
+		}
+	}
+	return derived
+}
@@ -0,0 +1,94 @@
+package solc
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nmvalera/solc-go/types"
+)
+
+// Binary is a soljson emscripten binary file
+type Binary struct {
+	// Version is the long version of the compiler (e.g. 0.6.2+commit.bacdbe57)
+	Version string
+	Path    string
+}
+
+// ListBinaries returns the soljson-v<version>.js binaries of dir sorted by version
+func ListBinaries(dir string) ([]Binary, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var binaries []Binary
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, "soljson-v") || !strings.HasSuffix(name, ".js") {
+			continue
+		}
+		version := strings.TrimSuffix(strings.TrimPrefix(name, "soljson-v"), ".js")
+		if !completeVersion(version) {
+			continue
+		}
+		binaries = append(binaries, Binary{Version: version, Path: filepath.Join(dir, name)})
+	}
+	sort.Slice(binaries, func(i, j int) bool {
+		return versionLess(binaries[i].Version, binaries[j].Version)
+	})
+
+	return binaries, nil
+}
+
+// completeVersion indicates whether version is a complete major.minor.patch version
+func completeVersion(version string) bool {
+	_, err := MatchVersion("*", version)
+	return err == nil
+}
+
+// versionLess indicates whether version v is older than w
+func versionLess(v, w string) bool {
+	c, _ := types.CompareVersions(v, w)
+	return c < 0
+}
+
+// ResolveBinary returns the binary of dir with the highest version satisfying every constraint (see MatchVersion)
+func ResolveBinary(dir string, constraints ...string) (Binary, error) {
+	binaries, err := ListBinaries(dir)
+	if err != nil {
+		return Binary{}, err
+	}
+
+	for i := len(binaries) - 1; i >= 0; i-- {
+		matched, err := types.MatchVersions(constraints, binaries[i].Version)
+		if err != nil {
+			return Binary{}, err
+		}
+		if matched {
+			return binaries[i], nil
+		}
+	}
+	return Binary{}, &NoVersionError{Constraints: constraints, Dir: dir}
+}
+
+// NoVersionError is returned when no compiler binary, or release, satisfies version constraints
+type NoVersionError struct {
+	Constraints []string
+
+	// Dir is the binaries directory searched, empty if releases were
+	Dir string
+}
+
+func (e *NoVersionError) Error() string {
+	if e.Dir == "" {
+		return fmt.Sprintf("solc: no release satisfies %v", strings.Join(e.Constraints, ", "))
+	}
+	return fmt.Sprintf("solc: no compiler in %v satisfies %v", e.Dir, strings.Join(e.Constraints, ", "))
+}
@@ -0,0 +1,32 @@
+package solc
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveBinary(t *testing.T) {
+	binaries, err := ListBinaries(SOLC_BIN_DIR)
+	require.NoError(t, err, "ListBinaries should not error")
+	require.Len(t, binaries, 2)
+	assert.Equal(t, "0.5.9+commit.e560f70d", binaries[0].Version, "Binaries should be sorted by version")
+	assert.Equal(t, filepath.Join(SOLC_BIN_DIR, "soljson-v0.6.2+commit.bacdbe57.js"), binaries[1].Path)
+
+	binary, err := ResolveBinary(SOLC_BIN_DIR)
+	require.NoError(t, err, "ResolveBinary should not error")
+	assert.Equal(t, "0.6.2+commit.bacdbe57", binary.Version, "Highest version should be resolved")
+
+	binary, err = ResolveBinary(SOLC_BIN_DIR, ">=0.5.0", "<0.6.0")
+	require.NoError(t, err, "ResolveBinary should not error")
+	assert.Equal(t, "0.5.9+commit.e560f70d", binary.Version, "Every constraint should be satisfied")
+
+	_, err = ResolveBinary(SOLC_BIN_DIR, "^0.7.0")
+	assert.Equal(t, &NoVersionError{Constraints: []string{"^0.7.0"}, Dir: SOLC_BIN_DIR}, err, "ResolveBinary should error on unsatisfiable constraints")
+
+	binaries, err = ListBinaries("missing")
+	assert.NoError(t, err, "ListBinaries should not error on missing directory")
+	assert.Empty(t, binaries)
+}
@@ -0,0 +1,280 @@
+// Package bind generates Go bindings of compiled contracts, compatible with go-ethereum's accounts/abi/bind
+//
+// Like abigen bindings, generated code depends on go-ethereum (this package does not). Bindings cover
+// deployment and methods, events and errors are not bound
+package bind
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"go/token"
+	"strings"
+	"unicode"
+
+	solc "github.com/nmvalera/solc-go"
+)
+
+// Contract is a contract to generate bindings for
+type Contract struct {
+	Name string
+	ABI  []json.RawMessage
+
+	// Bytecode is the hex creation bytecode, deployment is not bound if it is empty or unlinked
+	Bytecode string
+}
+
+// Generate returns the formatted source of the bindings of contract in package pkg
+func Generate(pkg string, contract Contract) ([]byte, error) {
+	entries, err := solc.ParseABI(contract.ABI)
+	if err != nil {
+		return nil, err
+	}
+	abiJSON, err := json.Marshal(contract.ABI)
+	if err != nil {
+		return nil, err
+	}
+
+	name := camel(contract.Name)
+	g := &generator{}
+	g.printf("// Code generated by solc-go bind. DO NOT EDIT.\n\npackage %v\n\n", pkg)
+	g.printf(header)
+	g.printf("// %vABI is the ABI of the %v contract\nconst %vABI = %q\n\n", name, contract.Name, name, string(abiJSON))
+	g.printf("// %v is a binding of the %v contract\ntype %v struct {\n\tcontract *bind.BoundContract\n}\n\n", name, contract.Name, name)
+	g.printf(`// New%v binds the %v contract deployed at address
+func New%v(address common.Address, backend bind.ContractBackend) (*%v, error) {
+	parsed, err := abi.JSON(strings.NewReader(%vABI))
+	if err != nil {
+		return nil, err
+	}
+	return &%v{contract: bind.NewBoundContract(address, parsed, backend, backend, backend)}, nil
+}
+
+`, name, contract.Name, name, name, name, name)
+
+	if bin := strings.TrimPrefix(contract.Bytecode, "0x"); bin != "" && !strings.Contains(bin, "__") {
+		var ctor solc.ABIEntry
+		for _, entry := range entries {
+			if entry.Type == "constructor" {
+				ctor = entry
+			}
+		}
+		params, args, err := g.params(ctor.Inputs)
+		if err != nil {
+			return nil, fmt.Errorf("constructor: %w", err)
+		}
+		g.printf("// %vBin is the creation bytecode of the %v contract\nconst %vBin = %q\n\n", name, contract.Name, name, "0x"+bin)
+		g.printf(`// Deploy%v deploys a new %v contract
+func Deploy%v(auth *bind.TransactOpts, backend bind.ContractBackend%v) (common.Address, *types.Transaction, *%v, error) {
+	parsed, err := abi.JSON(strings.NewReader(%vABI))
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	address, tx, contract, err := bind.DeployContract(auth, parsed, common.FromHex(%vBin), backend%v)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &%v{contract: contract}, nil
+}
+
+`, name, contract.Name, name, params, name, name, name, args, name)
+	}
+
+	// Overloaded methods are named like go-ethereum does (foo, foo0, foo1...)
+	names := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.Type != "function" {
+			continue
+		}
+		method := entry.Name
+		for i := 0; names[method]; i++ {
+			method = fmt.Sprintf("%v%v", entry.Name, i)
+		}
+		names[method] = true
+
+		err = g.method(name, method, entry)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", entry.Signature(), err)
+		}
+	}
+
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting bindings of %v: %w", contract.Name, err)
+	}
+	return src, nil
+}
+
+const header = `import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Reference imports to suppress errors if they are not otherwise used
+var (
+	_ = big.NewInt
+	_ = abi.ConvertType
+	_ = common.FromHex
+	_ = types.NewTx
+)
+
+`
+
+type generator struct {
+	buf bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// params returns the ", name type" parameter list and ", name" argument list of inputs
+func (g *generator) params(inputs []solc.ABIParameter) (params, args string, err error) {
+	for i, input := range inputs {
+		typ, err := goType(input)
+		if err != nil {
+			return "", "", err
+		}
+		name := paramName(input.Name, "arg", i)
+		params += fmt.Sprintf(", %v %v", name, typ)
+		args += ", " + name
+	}
+	return params, args, nil
+}
+
+func (g *generator) method(contract, method string, entry solc.ABIEntry) error {
+	params, args, err := g.params(entry.Inputs)
+	if err != nil {
+		return err
+	}
+	goName := camel(method)
+
+	if entry.StateMutability != "view" && entry.StateMutability != "pure" {
+		g.printf(`// %v sends a %v transaction
+func (c *%v) %v(opts *bind.TransactOpts%v) (*types.Transaction, error) {
+	return c.contract.Transact(opts, %q%v)
+}
+
+`, goName, entry.Signature(), contract, goName, params, method, args)
+		return nil
+	}
+
+	var types, zeros, results []string
+	for i, output := range entry.Outputs {
+		typ, err := goType(output)
+		if err != nil {
+			return err
+		}
+		types = append(types, typ)
+		zeros = append(zeros, fmt.Sprintf("*new(%v)", typ))
+		results = append(results, fmt.Sprintf("*abi.ConvertType(out[%v], new(%v)).(*%v)", i, typ, typ))
+	}
+	types = append(types, "error")
+	zeros = append(zeros, "err")
+	results = append(results, "nil")
+
+	g.printf(`// %v calls %v
+func (c *%v) %v(opts *bind.CallOpts%v) (%v) {
+	var out []interface{}
+	err := c.contract.Call(opts, &out, %q%v)
+	if err != nil {
+		return %v
+	}
+	return %v
+}
+
+`, goName, entry.Signature(), contract, goName, params, strings.Join(types, ", "), method, args, strings.Join(zeros, ", "), strings.Join(results, ", "))
+	return nil
+}
+
+// goType returns the Go type go-ethereum decodes an ABI type to
+func goType(p solc.ABIParameter) (string, error) {
+	typ := p.Type
+	if strings.HasSuffix(typ, "]") {
+		i := strings.LastIndex(typ, "[")
+		elem := p
+		elem.Type = typ[:i]
+		elemType, err := goType(elem)
+		if err != nil {
+			return "", err
+		}
+		return "[" + typ[i+1:len(typ)-1] + "]" + elemType, nil
+	}
+
+	switch {
+	case typ == "tuple":
+		var fields []string
+		for i, c := range p.Components {
+			fieldType, err := goType(c)
+			if err != nil {
+				return "", err
+			}
+			field := camel(c.Name)
+			if field == "" {
+				field = fmt.Sprintf("Field%v", i)
+			}
+			fields = append(fields, fmt.Sprintf("%v %v `json:\"%v\"`", field, fieldType, c.Name))
+		}
+		return "struct {\n" + strings.Join(fields, "\n") + "\n}", nil
+	case typ == "address":
+		return "common.Address", nil
+	case typ == "bool", typ == "string":
+		return typ, nil
+	case typ == "bytes":
+		return "[]byte", nil
+	case typ == "function":
+		return "[24]byte", nil
+	case strings.HasPrefix(typ, "bytes"):
+		return "[" + strings.TrimPrefix(typ, "bytes") + "]byte", nil
+	case strings.HasPrefix(typ, "uint"), strings.HasPrefix(typ, "int"):
+		switch bits := strings.TrimLeft(typ, "uint"); bits {
+		case "8", "16", "32", "64":
+			return typ, nil
+		default:
+			return "*big.Int", nil
+		}
+	}
+	return "", fmt.Errorf("unsupported ABI type %q", typ)
+}
+
+// camel converts an ABI name to an exported Go identifier
+func camel(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		r := []rune(part)
+		b.WriteString(string(unicode.ToUpper(r[0])) + string(r[1:]))
+	}
+	return b.String()
+}
+
+// reserved are the identifiers used by generated functions
+var reserved = map[string]bool{
+	"abi": true, "bind": true, "common": true, "types": true, "big": true, "strings": true,
+	"opts": true, "auth": true, "backend": true, "c": true, "out": true, "err": true,
+	"parsed": true, "address": true, "tx": true, "contract": true,
+}
+
+// paramName converts an ABI parameter name to a Go identifier not clashing with generated code, prefix<i> if unnamed
+func paramName(name, prefix string, i int) string {
+	if name == "" {
+		return fmt.Sprintf("%v%v", prefix, i)
+	}
+	r := []rune(strings.TrimLeft(name, "_"))
+	if len(r) == 0 {
+		return fmt.Sprintf("%v%v", prefix, i)
+	}
+	name = string(unicode.ToLower(r[0])) + string(r[1:])
+	if token.IsKeyword(name) || reserved[name] {
+		name += "_"
+	}
+	return name
+}
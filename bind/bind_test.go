@@ -0,0 +1,39 @@
+package bind
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const tokenABI = `[
+	{"type": "constructor", "inputs": [{"name": "_supply", "type": "uint256"}]},
+	{"type": "function", "name": "balanceOf", "stateMutability": "view", "inputs": [{"name": "owner", "type": "address"}], "outputs": [{"name": "", "type": "uint256"}]},
+	{"type": "function", "name": "transfer", "stateMutability": "nonpayable", "inputs": [{"name": "to", "type": "address"}, {"name": "value", "type": "uint256"}], "outputs": [{"name": "", "type": "bool"}]},
+	{"type": "function", "name": "transfer", "stateMutability": "nonpayable", "inputs": [{"name": "to", "type": "address"}, {"name": "value", "type": "uint256"}, {"name": "data", "type": "bytes"}], "outputs": []},
+	{"type": "function", "name": "info", "stateMutability": "pure", "inputs": [{"name": "type", "type": "uint8[2]"}], "outputs": [{"name": "", "type": "tuple[]", "components": [{"name": "id", "type": "bytes32"}, {"name": "small", "type": "int64"}]}, {"name": "", "type": "string"}]},
+	{"type": "event", "name": "Transfer", "inputs": [{"name": "from", "type": "address", "indexed": true}]}
+]`
+
+func TestGenerate(t *testing.T) {
+	var abi []json.RawMessage
+	require.NoError(t, json.Unmarshal([]byte(tokenABI), &abi))
+
+	src, err := Generate("contracts", Contract{Name: "Token", ABI: abi, Bytecode: "6080"})
+	require.NoError(t, err, "Generate should not error")
+	code := string(src)
+
+	assert.Contains(t, code, "package contracts")
+	assert.Contains(t, code, "func DeployToken(auth *bind.TransactOpts, backend bind.ContractBackend, supply *big.Int) (common.Address, *types.Transaction, *Token, error)")
+	assert.Contains(t, code, "func (c *Token) BalanceOf(opts *bind.CallOpts, owner common.Address) (*big.Int, error)")
+	assert.Contains(t, code, "func (c *Token) Transfer(opts *bind.TransactOpts, to common.Address, value *big.Int) (*types.Transaction, error)")
+	assert.Contains(t, code, `c.contract.Transact(opts, "transfer0", to, value, data)`, "Overloads should be named like go-ethereum")
+	assert.Contains(t, code, "func (c *Token) Info(opts *bind.CallOpts, type_ [2]uint8) ([]struct {")
+	assert.Contains(t, code, "Small int64")
+
+	src, err = Generate("contracts", Contract{Name: "Lib", ABI: abi, Bytecode: "6080__$1234$__"})
+	require.NoError(t, err, "Generate should not error")
+	assert.NotContains(t, string(src), "DeployLib", "Unlinked contracts should not be deployable")
+}
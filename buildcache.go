@@ -0,0 +1,106 @@
+package solc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/nmvalera/solc-go/types"
+)
+
+// BuildCache is a Cache persisting compilation outputs on disk so unchanged compilation units are not recompiled
+//
+// Entries are keyed by a hash of the compiler version, the sources and the settings of the input
+type BuildCache struct {
+	dir string
+}
+
+// NewBuildCache creates a build cache storing outputs in dir
+func NewBuildCache(dir string) (*BuildCache, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	return &BuildCache{dir: dir}, nil
+}
+
+// Compile returns the cached output for input if any, otherwise it compiles input and caches the output
+func (c *BuildCache) Compile(solc Solc, input *Input) (out *Output, cached bool, err error) {
+	return compileCached(c, solc, input)
+}
+
+// Get returns the output stored under key or nil if there is none
+//
+// Entries written as JSON by earlier versions are still read
+func (c *BuildCache) Get(key string) (*Output, error) {
+	b, err := ioutil.ReadFile(c.path(key))
+	if os.IsNotExist(err) {
+		b, err = ioutil.ReadFile(filepath.Join(c.dir, key+".json"))
+	}
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return types.UnmarshalOutput(b)
+}
+
+// Put stores out under key, binary encoded (see Output.MarshalBinary)
+func (c *BuildCache) Put(key string, out *Output) error {
+	b, err := out.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so concurrent readers never see partial entries
+	tmp, err := ioutil.TempFile(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(b)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+// Clear removes every entry of the cache, JSON entries of earlier versions included
+func (c *BuildCache) Clear() error {
+	var entries []string
+	for _, pattern := range []string{"*.bin", "*.json"} {
+		matches, err := filepath.Glob(filepath.Join(c.dir, pattern))
+		if err != nil {
+			return err
+		}
+		entries = append(entries, matches...)
+	}
+	for _, entry := range entries {
+		err := os.Remove(entry)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *BuildCache) path(key string) string {
+	return filepath.Join(c.dir, key+".bin")
+}
+
+func buildKey(version string, input *Input) string {
+	h := sha256.New()
+	h.Write([]byte(version))
+	h.Write([]byte{0})
+	h.Write([]byte(input.Hash()))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
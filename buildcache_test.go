@@ -0,0 +1,81 @@
+package solc
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSolc is a Solc returning canned outputs without running the compiler
+type fakeSolc struct {
+	version  string
+	compiles int
+	output   *Output
+	err      error
+}
+
+func (s *fakeSolc) License() string { return "" }
+func (s *fakeSolc) Version() string { return s.version }
+func (s *fakeSolc) Close() error    { return nil }
+
+func (s *fakeSolc) HeapStatistics() HeapStatistics { return HeapStatistics{} }
+
+func (s *fakeSolc) Compile(input *Input) (*Output, error) {
+	s.compiles++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.output, nil
+}
+
+func TestBuildCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "solc-build-cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cache, err := NewBuildCache(dir)
+	require.NoError(t, err, "NewBuildCache should not error")
+
+	compiler := &fakeSolc{
+		version: "0.6.2+commit.bacdbe57.Emscripten.clang",
+		output: &Output{
+			Contracts: map[string]map[string]Contract{
+				"One.sol": map[string]Contract{"One": Contract{Metadata: "{}"}},
+			},
+		},
+	}
+	in := &Input{
+		Language: "Solidity",
+		Sources:  map[string]SourceIn{"One.sol": SourceIn{Content: "contract One {}"}},
+	}
+
+	out, cached, err := cache.Compile(compiler, in)
+	require.NoError(t, err, "Compile should not error")
+	assert.False(t, cached, "First compilation should not be cached")
+	assert.Equal(t, "{}", out.Contracts["One.sol"]["One"].Metadata)
+
+	out, cached, err = cache.Compile(compiler, in)
+	require.NoError(t, err, "Compile should not error")
+	assert.True(t, cached, "Second compilation should be cached")
+	assert.Equal(t, "{}", out.Contracts["One.sol"]["One"].Metadata)
+	assert.Equal(t, 1, compiler.compiles, "Compiler should have been called once")
+
+	// Changing settings invalidates the entry
+	in.Settings.Optimizer.Enabled = true
+	_, cached, err = cache.Compile(compiler, in)
+	require.NoError(t, err, "Compile should not error")
+	assert.False(t, cached, "Compilation with new settings should not be cached")
+
+	// Changing compiler version invalidates the entry
+	compiler.version = "0.5.9+commit.e560f70d.Emscripten.clang"
+	_, cached, err = cache.Compile(compiler, in)
+	require.NoError(t, err, "Compile should not error")
+	assert.False(t, cached, "Compilation with new compiler should not be cached")
+
+	require.NoError(t, cache.Clear(), "Clear should not error")
+	_, cached, _ = cache.Compile(compiler, in)
+	assert.False(t, cached, "Compilation after Clear should not be cached")
+}
@@ -0,0 +1,285 @@
+package solc
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// BundleOptions configures the loading of archives and git clones
+type BundleOptions struct {
+	// MaxEntrySize and MaxSize bound the decompressed size of each .sol file and of all of them, archives
+	// exceeding them fail with a *SizeLimitError. DefaultBundleOptions applies if 0
+	MaxEntrySize int64
+	MaxSize      int64
+
+	// StripRoot strips the top-level directory shared by every file whatever its name, by default only
+	// the <repo>-<commit sha> directories of GitHub archives are stripped
+	StripRoot bool
+}
+
+// DefaultBundleOptions are the options of LoadZip, LoadTarGz, LoadTar and LoadGit
+var DefaultBundleOptions = BundleOptions{MaxEntrySize: 4 << 20, MaxSize: 64 << 20}
+
+// LoadZip reads every .sol file of a zip archive with DefaultBundleOptions
+//
+// If all files of the archive live under a single <repo>-<commit sha> directory (as in GitHub archives) it is stripped from source unit names
+func LoadZip(r io.ReaderAt, size int64) (map[string]SourceIn, error) {
+	return LoadZipOptions(r, size, DefaultBundleOptions)
+}
+
+// LoadZipOptions is like LoadZip with options
+func LoadZipOptions(r io.ReaderAt, size int64, opts BundleOptions) (map[string]SourceIn, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	b := newBundle(opts)
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		name, err := bundlePath(f.Name)
+		if err != nil {
+			return nil, err
+		}
+		if path.Ext(name) != ".sol" {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		err = b.read(name, rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return b.sources(), nil
+}
+
+// LoadTarGz reads every .sol file of a gzipped tarball with DefaultBundleOptions
+//
+// If all files of the archive live under a single <repo>-<commit sha> directory (as in GitHub archives) it is stripped from source unit names
+func LoadTarGz(r io.Reader) (map[string]SourceIn, error) {
+	return LoadTarGzOptions(r, DefaultBundleOptions)
+}
+
+// LoadTarGzOptions is like LoadTarGz with options
+func LoadTarGzOptions(r io.Reader, opts BundleOptions) (map[string]SourceIn, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	return LoadTarOptions(gz, opts)
+}
+
+// LoadTar reads every .sol file of an uncompressed tarball with DefaultBundleOptions
+func LoadTar(r io.Reader) (map[string]SourceIn, error) {
+	return LoadTarOptions(r, DefaultBundleOptions)
+}
+
+// LoadTarOptions is like LoadTar with options
+func LoadTarOptions(r io.Reader, opts BundleOptions) (map[string]SourceIn, error) {
+	tr := tar.NewReader(r)
+	b := newBundle(opts)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name, err := bundlePath(hdr.Name)
+		if err != nil {
+			return nil, err
+		}
+		if path.Ext(name) != ".sol" {
+			continue
+		}
+
+		err = b.read(name, tr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return b.sources(), nil
+}
+
+// LoadGit clones a git repository at ref (branch or tag, default branch if empty) and reads every .sol file of it
+// with DefaultBundleOptions
+//
+// The git binary must be available in PATH
+func LoadGit(ctx context.Context, url, ref string) (map[string]SourceIn, error) {
+	return LoadGitOptions(ctx, url, ref, DefaultBundleOptions)
+}
+
+// LoadGitOptions is like LoadGit with options, StripRoot does not apply as clones have no top-level directory
+func LoadGitOptions(ctx context.Context, url, ref string, opts BundleOptions) (map[string]SourceIn, error) {
+	dir, err := ioutil.TempDir("", "solc-git")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	args := []string{"clone", "--quiet", "--depth", "1"}
+	if ref != "" {
+		args = append(args, "--branch", ref)
+	}
+	args = append(args, "--", url, dir)
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("git clone %v failed: %v: %v", url, err, strings.TrimSpace(stderr.String()))
+	}
+
+	b := newBundle(opts)
+	err = filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if p != dir && skipDir(info.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() || filepath.Ext(p) != ".sol" {
+			return nil
+		}
+		name, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return b.read(filepath.ToSlash(name), f)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make(map[string]SourceIn)
+	for name, content := range b.files {
+		sources[name] = SourceIn{Content: content}
+	}
+	return sources, nil
+}
+
+// CompileSources compiles a set of sources (e.g. loaded from a bundle) with the given settings
+func CompileSources(solc Solc, sources map[string]SourceIn, settings Settings) (*Output, error) {
+	return solc.Compile(&Input{
+		Language: "Solidity",
+		Sources:  sources,
+		Settings: settings,
+	})
+}
+
+// bundlePath cleans an archive entry name and rejects entries escaping the archive root
+func bundlePath(name string) (string, error) {
+	clean := path.Clean("/" + strings.ReplaceAll(name, "\\", "/"))[1:]
+	if clean == "" || strings.HasPrefix(name, "/") || strings.Contains("/"+name+"/", "/../") {
+		return "", fmt.Errorf("invalid archive entry %q", name)
+	}
+	return clean, nil
+}
+
+// bundle collects the files of an archive within the size limits of its options
+type bundle struct {
+	opts  BundleOptions
+	files map[string]string
+	size  int64
+}
+
+func newBundle(opts BundleOptions) *bundle {
+	if opts.MaxEntrySize == 0 {
+		opts.MaxEntrySize = DefaultBundleOptions.MaxEntrySize
+	}
+	if opts.MaxSize == 0 {
+		opts.MaxSize = DefaultBundleOptions.MaxSize
+	}
+	return &bundle{opts: opts, files: make(map[string]string)}
+}
+
+// read reads the file name from r, never more than a byte past the limits so archives decompressing
+// into large files are rejected early
+func (b *bundle) read(name string, r io.Reader) error {
+	limit := b.opts.MaxEntrySize
+	if remaining := b.opts.MaxSize - b.size; remaining < limit {
+		limit = remaining
+	}
+	content, err := ioutil.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return err
+	}
+	size := int64(len(content))
+	if size > b.opts.MaxEntrySize {
+		return &SizeLimitError{Kind: "archive entry", Size: int(size), Limit: int(b.opts.MaxEntrySize)}
+	}
+	if b.size+size > b.opts.MaxSize {
+		return &SizeLimitError{Kind: "archive", Size: int(b.size + size), Limit: int(b.opts.MaxSize)}
+	}
+	b.size += size
+	b.files[name] = string(content)
+	return nil
+}
+
+// githubRoot matches the top-level directory of GitHub archives of a commit (e.g. solc-go-4f1c2ab/)
+var githubRoot = regexp.MustCompile(`^[A-Za-z0-9._-]+-[0-9a-f]{7,40}/$`)
+
+// sources returns the sources of the files, stripped of the top-level directory shared by every file if
+// any and stripping is set or matches GitHub archives
+func (b *bundle) sources() map[string]SourceIn {
+	prefix := ""
+	for name := range b.files {
+		i := strings.Index(name, "/")
+		if i < 0 {
+			prefix = ""
+			break
+		}
+		if prefix == "" {
+			prefix = name[:i+1]
+		}
+		if !strings.HasPrefix(name, prefix) {
+			prefix = ""
+			break
+		}
+	}
+	if !b.opts.StripRoot && !githubRoot.MatchString(prefix) {
+		prefix = ""
+	}
+
+	sources := make(map[string]SourceIn)
+	for name, content := range b.files {
+		sources[strings.TrimPrefix(name, prefix)] = SourceIn{Content: content}
+	}
+	return sources
+}
@@ -0,0 +1,135 @@
+package solc
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var bundleFiles = map[string]string{
+	"repo-4f1c2ab/contracts/One.sol": "contract One {}",
+	"repo-4f1c2ab/lib/Two.sol":       "contract Two {}",
+	"repo-4f1c2ab/README.md":         "# Repo",
+}
+
+func zipBundle(t *testing.T, files map[string]string) *bytes.Reader {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestLoadZip(t *testing.T) {
+	r := zipBundle(t, bundleFiles)
+	sources, err := LoadZip(r, r.Size())
+	require.NoError(t, err, "LoadZip should not error")
+	assert.Equal(t, map[string]SourceIn{
+		"contracts/One.sol": SourceIn{Content: "contract One {}"},
+		"lib/Two.sol":       SourceIn{Content: "contract Two {}"},
+	}, sources, "Sources should be loaded without the top-level directory of GitHub archives")
+
+	files := map[string]string{"contracts/One.sol": "contract One {}", "contracts/lib/Two.sol": "contract Two {}"}
+	r = zipBundle(t, files)
+	sources, err = LoadZip(r, r.Size())
+	require.NoError(t, err, "LoadZip should not error")
+	assert.Contains(t, sources, "contracts/One.sol", "Other top-level directories should be kept")
+	sources, err = LoadZipOptions(r, r.Size(), BundleOptions{StripRoot: true})
+	require.NoError(t, err, "LoadZipOptions should not error")
+	assert.Contains(t, sources, "One.sol", "Top-level directories should be stripped if set")
+
+	r = zipBundle(t, map[string]string{"Big.sol": strings.Repeat(" ", 1024)})
+	_, err = LoadZipOptions(r, r.Size(), BundleOptions{MaxEntrySize: 1000})
+	assert.Equal(t, &SizeLimitError{Kind: "archive entry", Size: 1001, Limit: 1000}, err, "Entries over the limit should be rejected")
+	r = zipBundle(t, files)
+	_, err = LoadZipOptions(r, r.Size(), BundleOptions{MaxSize: 20})
+	assert.IsType(t, &SizeLimitError{}, err, "Archives over the limit should be rejected")
+	assert.Equal(t, "archive", err.(*SizeLimitError).Kind)
+}
+
+func TestLoadTarGz(t *testing.T) {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range bundleFiles {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	sources, err := LoadTarGz(buf)
+	require.NoError(t, err, "LoadTarGz should not error")
+	assert.Len(t, sources, 2, "Only Solidity sources should be loaded")
+	assert.Equal(t, "contract Two {}", sources["lib/Two.sol"].Content)
+
+	buf = &bytes.Buffer{}
+	tw = tar.NewWriter(buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "Big.sol", Mode: 0644, Size: 1024, Typeflag: tar.TypeReg}))
+	_, err = tw.Write(make([]byte, 1024))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	_, err = LoadTarOptions(buf, BundleOptions{MaxEntrySize: 1000})
+	assert.IsType(t, &SizeLimitError{}, err, "Entries over the limit should be rejected")
+
+	// Entries escaping the archive are rejected
+	buf = &bytes.Buffer{}
+	tw = tar.NewWriter(buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "../evil.sol", Mode: 0644, Typeflag: tar.TypeReg}))
+	require.NoError(t, tw.Close())
+	_, err = LoadTar(buf)
+	assert.Error(t, err, "LoadTar should reject path traversal")
+}
+
+func TestLoadGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	dir, err := ioutil.TempDir("", "solc-git-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{"contracts/One.sol": "contract One {}", "contracts/lib/Two.sol": "contract Two {}", "README.md": "# Repo"}
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0755))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	for _, args := range [][]string{{"init", "--quiet"}, {"add", "."}, {"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "init"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v should not error: %s", args[0], out)
+	}
+	ctx := context.Background()
+	url := "file://" + filepath.ToSlash(dir)
+
+	sources, err := LoadGit(ctx, url, "")
+	require.NoError(t, err, "LoadGit should not error")
+	assert.Equal(t, map[string]SourceIn{
+		"contracts/One.sol":     SourceIn{Content: "contract One {}"},
+		"contracts/lib/Two.sol": SourceIn{Content: "contract Two {}"},
+	}, sources, "Only Solidity sources should be loaded")
+
+	_, err = LoadGitOptions(ctx, url, "", BundleOptions{MaxEntrySize: 10})
+	assert.Equal(t, &SizeLimitError{Kind: "archive entry", Size: 11, Limit: 10}, err, "Files over the limit should be rejected")
+	_, err = LoadGitOptions(ctx, url, "", BundleOptions{MaxSize: 20})
+	assert.IsType(t, &SizeLimitError{}, err, "Clones over the limit should be rejected")
+	assert.Equal(t, "archive", err.(*SizeLimitError).Kind)
+}
@@ -0,0 +1,143 @@
+package solc
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/nmvalera/solc-go/types"
+)
+
+// Cache stores compilation outputs keyed by a hash of the compiler version and the input
+//
+// BuildCache (disk), MemoryCache and StoreCache implement it
+type Cache interface {
+	// Get returns the output stored under key or nil if there is none
+	Get(key string) (*Output, error)
+
+	// Put stores out under key
+	Put(key string, out *Output) error
+}
+
+// MemoryCache is an in-memory Cache evicting least recently used entries
+//
+// Outputs are shared with callers and must not be modified
+type MemoryCache struct {
+	size int
+
+	mux     sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key string
+	out *Output
+}
+
+// NewMemoryCache creates a cache holding at most size outputs
+func NewMemoryCache(size int) *MemoryCache {
+	return &MemoryCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (c *MemoryCache) Get(key string) (*Output, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, nil
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*memoryEntry).out, nil
+}
+
+func (c *MemoryCache) Put(key string, out *Output) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*memoryEntry).out = out
+		c.order.MoveToFront(elem)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&memoryEntry{key: key, out: out})
+	for c.size > 0 && c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryEntry).key)
+	}
+	return nil
+}
+
+// Len returns the number of outputs in the cache
+func (c *MemoryCache) Len() int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.order.Len()
+}
+
+// Store is a key/value store that a StoreCache persists outputs into
+//
+// It is meant to be implemented by thin adapters over shared stores such as Redis
+type Store interface {
+	// Get returns the value stored under key or nil if there is none
+	Get(key string) ([]byte, error)
+
+	// Set stores value under key
+	Set(key string, value []byte) error
+}
+
+// StoreCache is a Cache persisting binary encoded outputs in a Store (see Output.MarshalBinary)
+//
+// JSON encoded outputs stored by earlier versions are still read
+type StoreCache struct {
+	store  Store
+	prefix string
+}
+
+// NewStoreCache creates a cache storing outputs in store under keys starting with prefix
+func NewStoreCache(store Store, prefix string) *StoreCache {
+	return &StoreCache{store: store, prefix: prefix}
+}
+
+func (c *StoreCache) Get(key string) (*Output, error) {
+	b, err := c.store.Get(c.prefix + key)
+	if err != nil || b == nil {
+		return nil, err
+	}
+
+	return types.UnmarshalOutput(b)
+}
+
+func (c *StoreCache) Put(key string, out *Output) error {
+	b, err := out.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return c.store.Set(c.prefix+key, b)
+}
+
+// compileCached returns the output cached for input if any, otherwise it compiles input and caches the output
+func compileCached(cache Cache, solc Solc, input *Input) (out *Output, cached bool, err error) {
+	key := buildKey(solc.Version(), input)
+
+	out, err = cache.Get(key)
+	if err == nil && out != nil {
+		return out, true, nil
+	}
+
+	out, err = solc.Compile(input)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = cache.Put(key, out)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return out, false, nil
+}
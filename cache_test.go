@@ -0,0 +1,65 @@
+package solc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCache(t *testing.T) {
+	cache := NewMemoryCache(2)
+	one, two, three := &Output{}, &Output{}, &Output{}
+
+	require.NoError(t, cache.Put("one", one), "Put should not error")
+	require.NoError(t, cache.Put("two", two), "Put should not error")
+
+	// Reading one makes two the least recently used entry
+	out, err := cache.Get("one")
+	require.NoError(t, err, "Get should not error")
+	assert.True(t, out == one, "Get should return the stored output")
+
+	require.NoError(t, cache.Put("three", three), "Put should not error")
+	assert.Equal(t, 2, cache.Len(), "Cache should be bounded")
+
+	out, _ = cache.Get("two")
+	assert.Nil(t, out, "Least recently used entry should be evicted")
+	out, _ = cache.Get("one")
+	assert.True(t, out == one, "Recently used entry should be kept")
+	out, _ = cache.Get("three")
+	assert.True(t, out == three, "Last entry should be kept")
+}
+
+type mapStore map[string][]byte
+
+func (s mapStore) Get(key string) ([]byte, error)     { return s[key], nil }
+func (s mapStore) Set(key string, value []byte) error { s[key] = value; return nil }
+
+func TestStoreCache(t *testing.T) {
+	store := mapStore{}
+	cache := NewStoreCache(store, "solc:")
+
+	compiler := &fakeSolc{
+		version: "0.6.2+commit.bacdbe57.Emscripten.clang",
+		output: &Output{
+			Contracts: map[string]map[string]Contract{
+				"One.sol": map[string]Contract{"One": Contract{Metadata: "{}"}},
+			},
+		},
+	}
+	in := &Input{Sources: map[string]SourceIn{"One.sol": SourceIn{Content: "contract One {}"}}}
+
+	_, cached, err := compileCached(cache, compiler, in)
+	require.NoError(t, err, "Compile should not error")
+	assert.False(t, cached, "First compilation should not be cached")
+
+	out, cached, err := compileCached(cache, compiler, in)
+	require.NoError(t, err, "Compile should not error")
+	assert.True(t, cached, "Second compilation should be cached")
+	assert.Equal(t, "{}", out.Contracts["One.sol"]["One"].Metadata)
+	assert.Equal(t, 1, compiler.compiles, "Compiler should have been called once")
+
+	for key := range store {
+		assert.Contains(t, key, "solc:", "Keys should be prefixed")
+	}
+}
@@ -0,0 +1,71 @@
+package solc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+)
+
+// abiTypeOrder orders canonical ABI entries by type
+var abiTypeOrder = map[string]int{"constructor": 0, "fallback": 1, "receive": 2, "function": 3, "event": 4, "error": 5}
+
+// CanonicalizeABI returns a normalized copy of entries and a hex encoded SHA-256 of it
+//
+// Only what defines the interface is kept: parameter names and internal types are dropped,
+// legacy constant and payable flags are turned into a state mutability and entries are sorted
+// by type then signature. ABIs of the same interface thus hash the same whatever the compiler
+// version, parameter names or declaration order
+func CanonicalizeABI(entries []ABIEntry) ([]ABIEntry, string) {
+	canonical := make([]ABIEntry, len(entries))
+	for i, entry := range entries {
+		canonical[i] = ABIEntry{
+			Type:            entry.Type,
+			Name:            entry.Name,
+			Inputs:          canonicalParameters(entry.Inputs),
+			Outputs:         canonicalParameters(entry.Outputs),
+			StateMutability: canonicalMutability(entry),
+			Anonymous:       entry.Anonymous,
+		}
+		if canonical[i].Type == "" {
+			canonical[i].Type = "function"
+		}
+	}
+	sort.SliceStable(canonical, func(i, j int) bool {
+		ei, ej := canonical[i], canonical[j]
+		if ei.Type != ej.Type {
+			return abiTypeOrder[ei.Type] < abiTypeOrder[ej.Type]
+		}
+		return ei.Signature() < ej.Signature()
+	})
+
+	// encoding/json output is stable for structs and the entries are sorted
+	b, _ := json.Marshal(canonical)
+	h := sha256.Sum256(b)
+	return canonical, hex.EncodeToString(h[:])
+}
+
+func canonicalParameters(params []ABIParameter) []ABIParameter {
+	if len(params) == 0 {
+		return nil
+	}
+	canonical := make([]ABIParameter, len(params))
+	for i, p := range params {
+		canonical[i] = ABIParameter{Type: p.Type, Components: canonicalParameters(p.Components), Indexed: p.Indexed}
+	}
+	return canonical
+}
+
+func canonicalMutability(entry ABIEntry) string {
+	switch {
+	case entry.StateMutability != "":
+		return entry.StateMutability
+	case entry.Type == "event" || entry.Type == "error":
+		return ""
+	case entry.Payable:
+		return "payable"
+	case entry.Constant:
+		return "view"
+	}
+	return "nonpayable"
+}
@@ -0,0 +1,45 @@
+package solc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalizeABI(t *testing.T) {
+	current, err := ParseABI([]json.RawMessage{
+		json.RawMessage(`{"type":"event","name":"Set","inputs":[{"name":"value","type":"uint256","internalType":"uint256","indexed":true}]}`),
+		json.RawMessage(`{"type":"function","name":"set","inputs":[{"name":"value","type":"uint256","internalType":"uint256"}],"outputs":[],"stateMutability":"nonpayable"}`),
+		json.RawMessage(`{"type":"function","name":"get","inputs":[],"outputs":[{"name":"","type":"uint256","internalType":"uint256"}],"stateMutability":"view"}`),
+		json.RawMessage(`{"type":"constructor","inputs":[],"stateMutability":"payable"}`),
+	})
+	require.NoError(t, err)
+
+	// Same interface as emitted by solc < 0.5.0, in another order and with other parameter names
+	legacy, err := ParseABI([]json.RawMessage{
+		json.RawMessage(`{"constant":true,"name":"get","inputs":[],"outputs":[{"name":"v","type":"uint256"}]}`),
+		json.RawMessage(`{"type":"constructor","inputs":[],"payable":true}`),
+		json.RawMessage(`{"constant":false,"name":"set","inputs":[{"name":"v","type":"uint256"}],"outputs":[]}`),
+		json.RawMessage(`{"type":"event","name":"Set","inputs":[{"name":"v","type":"uint256","indexed":true}],"anonymous":false}`),
+	})
+	require.NoError(t, err)
+
+	canonical, hash := CanonicalizeABI(current)
+	_, legacyHash := CanonicalizeABI(legacy)
+	assert.Equal(t, hash, legacyHash, "Same interface should hash the same")
+	assert.Len(t, hash, 64)
+
+	var types []string
+	for _, entry := range canonical {
+		types = append(types, entry.Type+" "+entry.Signature()+" "+entry.StateMutability)
+	}
+	assert.Equal(t, []string{"constructor () payable", "function get() view", "function set(uint256) nonpayable", "event Set(uint256) "}, types, "Entries should be sorted by type and signature")
+	assert.Equal(t, "", canonical[1].Outputs[0].Name, "Parameter names should be dropped")
+	assert.Equal(t, "uint256", current[1].Inputs[0].InternalType, "Original entries should not be modified")
+
+	current[2].StateMutability = "pure"
+	_, changed := CanonicalizeABI(current)
+	assert.NotEqual(t, hash, changed, "Interface changes should change the hash")
+}
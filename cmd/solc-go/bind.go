@@ -0,0 +1,123 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	solc "github.com/nmvalera/solc-go"
+	"github.com/nmvalera/solc-go/bind"
+)
+
+func init() {
+	register(&command{
+		name:  "bind",
+		usage: "compile contracts and generate go-ethereum compatible Go bindings",
+		run:   runBind,
+	})
+}
+
+func runBind(e *env, args []string) error {
+	fs := flag.NewFlagSet("bind", flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	var (
+		cf compilerFlags
+		sf settingsFlags
+	)
+	cf.register(fs)
+	sf.register(fs)
+	pkg := fs.String("pkg", "contracts", "package of the generated bindings")
+	out := fs.String("out", ".", "directory bindings are written to, one file per contract")
+	var only stringsFlag
+	fs.Var(&only, "contracts", "contracts to bind, Name or path:Name, comma separated or repeated (defaults to every contract)")
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: solc-go bind [--pkg contracts] [--out dir] [flags] [files|dirs]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	project, err := loadProject(fs, &sf.importFlags, &cf, &sf)
+	if err != nil {
+		return err
+	}
+
+	paths := sourcePaths(fs.Args(), project)
+	sources, err := loadSources(paths, sf.normalization(), sf.resolver(paths...))
+	if err != nil {
+		return err
+	}
+	compiler, err := cf.compiler(sources)
+	if err != nil {
+		return err
+	}
+	defer compiler.Close()
+
+	settings := sf.settings()
+	settings.OutputSelection = solc.SelectAll("abi", "evm.bytecode.object")
+	_, output, err := compile(e, compiler, sources, settings)
+	if err != nil {
+		return err
+	}
+
+	if len(only) > 0 {
+		output = output.Filter(nil, only)
+	}
+
+	// Bindings share a package so contract names must be unique
+	contracts := make(map[string]bind.Contract)
+	found := make(map[string]bool)
+	var names []string
+	for file, fileContracts := range output.Contracts {
+		for name, c := range fileContracts {
+			found[name], found[solc.ContractID{File: file, Name: name}.String()] = true, true
+			if _, ok := contracts[name]; ok {
+				return fmt.Errorf("several contracts named %v, select contracts with --contracts", name)
+			}
+			contracts[name] = bind.Contract{Name: name, ABI: c.ABI, Bytecode: c.EVM.Bytecode.Object}
+			names = append(names, name)
+		}
+	}
+	for _, name := range only {
+		if !found[name] {
+			return fmt.Errorf("contract %v not found", name)
+		}
+	}
+	sort.Strings(names)
+
+	err = os.MkdirAll(*out, 0755)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		src, err := bind.Generate(*pkg, contracts[name])
+		if err != nil {
+			return err
+		}
+		file := filepath.Join(*out, snake(name)+".go")
+		err = ioutil.WriteFile(file, src, 0644)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(e.stdout, "Generated %v\n", file)
+	}
+	return nil
+}
+
+// snake converts a contract name to a snake case file name (e.g. ERC20Token becomes erc20_token)
+func snake(name string) string {
+	r := []rune(name)
+	var b strings.Builder
+	for i, c := range r {
+		if unicode.IsUpper(c) && i > 0 && (unicode.IsLower(r[i-1]) || unicode.IsDigit(r[i-1]) || i+1 < len(r) && unicode.IsLower(r[i+1]) && unicode.IsUpper(r[i-1])) {
+			b.WriteRune('_')
+		}
+		b.WriteRune(unicode.ToLower(c))
+	}
+	return b.String()
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBind(t *testing.T) {
+	dir, err := ioutil.TempDir("", "solc-go-bind")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	source := filepath.Join(dir, "Token.sol")
+	require.NoError(t, ioutil.WriteFile(source, []byte("pragma solidity ^0.6.0; contract ERC20Token { function total() public pure returns (uint) { return 1; } } contract Other {}"), 0644))
+
+	out := filepath.Join(dir, "gen")
+	code, stdout, stderr := runCLI("", "bind", "--soljson", soljson, "--pkg", "tokens", "--out", out, "--contracts", "ERC20Token", source)
+	require.Equal(t, 0, code, "solc-go bind should succeed: %v", stderr)
+	assert.Contains(t, stdout, "erc20_token.go")
+
+	b, err := ioutil.ReadFile(filepath.Join(out, "erc20_token.go"))
+	require.NoError(t, err, "Bindings should be written")
+	assert.Contains(t, string(b), "package tokens")
+	assert.Contains(t, string(b), "func DeployERC20Token(")
+	assert.Contains(t, string(b), "func (c *ERC20Token) Total(opts *bind.CallOpts) (*big.Int, error)")
+	_, err = os.Stat(filepath.Join(out, "other.go"))
+	assert.True(t, os.IsNotExist(err), "Unselected contracts should not be bound")
+
+	code, _, stderr = runCLI("", "bind", "--soljson", soljson, "--out", out, "--contracts", "Missing", source)
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "Missing not found")
+}
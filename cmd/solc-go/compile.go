@@ -0,0 +1,213 @@
+package main
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	solc "github.com/nmvalera/solc-go"
+)
+
+func init() {
+	register(&command{
+		name:  "compile",
+		usage: "compile files and directories into artifacts",
+		run:   runCompile,
+	})
+}
+
+// Artifact formats
+const (
+	formatHardhat      = "hardhat"
+	formatStandardJSON = "standard-json"
+)
+
+func runCompile(e *env, args []string) error {
+	fs := flag.NewFlagSet("compile", flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	var cf compilerFlags
+	cf.register(fs)
+	var sf settingsFlags
+	sf.register(fs)
+	outputDir := fs.String("output-dir", "artifacts", "directory artifacts are written to")
+	format := fs.String("format", formatHardhat, "artifact format: hardhat (artifacts and build-info) or standard-json (output.json)")
+	signKey := fs.String("sign-key", "", "PEM (PKCS #8) private key signing hardhat artifacts, build-info and their provenance")
+	builder := fs.String("builder", "solc-go", "builder ID attested by the provenance of signed builds")
+	profile := fs.Bool("profile", false, "print where compilation time goes, compiling up to 3 more times to time the compiler steps")
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: solc-go compile [flags] [files|dirs]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	project, err := loadProject(fs, &sf.importFlags, &cf, &sf)
+	if err != nil {
+		return err
+	}
+	if set := setFlags(fs); project != nil {
+		if !set["output-dir"] && project.Artifacts.Dir != "" {
+			*outputDir = project.Path(project.Artifacts.Dir)
+		}
+		if !set["format"] && project.Artifacts.Format != "" {
+			*format = project.Artifacts.Format
+		}
+	}
+	if *format != formatHardhat && *format != formatStandardJSON {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	var key crypto.Signer
+	if *signKey != "" {
+		if *format != formatHardhat {
+			return fmt.Errorf("only %v artifacts can be signed", formatHardhat)
+		}
+		key, err = readSigningKey(*signKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	paths := sourcePaths(fs.Args(), project)
+	sources, err := loadSources(paths, sf.normalization(), sf.resolver(paths...))
+	if err != nil {
+		return err
+	}
+
+	compiler, err := cf.compiler(sources)
+	if err != nil {
+		return err
+	}
+	defer compiler.Close()
+	if *profile {
+		compiler = &profilingSolc{Solc: compiler, env: e}
+	}
+
+	input, out, err := compile(e, compiler, sources, sf.settings())
+	if err != nil {
+		return err
+	}
+	if out.Profile != nil {
+		fmt.Fprintf(e.stderr, "Profile: %v\n", out.Profile)
+	}
+
+	count, files, err := writeOutput(*outputDir, *format, compiler.Version(), input, out)
+	if err != nil {
+		return err
+	}
+	if key != nil {
+		err = signOutput(*outputDir, files, key, *builder, compiler.Version(), input, out)
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(e.stdout, "Compiled %v contracts with %v into %v\n", count, solc.LongVersion(compiler.Version()), *outputDir)
+
+	return nil
+}
+
+// profilingSolc profiles compilations (see solc.ProfileCompile)
+type profilingSolc struct {
+	solc.Solc
+	env *env
+}
+
+func (s *profilingSolc) Compile(input *solc.Input) (*solc.Output, error) {
+	return solc.ProfileCompile(s.env.ctx, s.Solc, input)
+}
+
+// writeOutput writes out into dir in format and returns the number of contracts and the files written
+func writeOutput(dir, format, version string, input *solc.Input, out *solc.Output) (int, []string, error) {
+	artifacts := solc.NewArtifacts(out)
+
+	if format == formatStandardJSON {
+		err := os.MkdirAll(dir, 0755)
+		if err != nil {
+			return 0, nil, err
+		}
+		b, err := out.MarshalDeterministic()
+		if err != nil {
+			return 0, nil, err
+		}
+		file := filepath.Join(dir, "output.json")
+		return len(artifacts), []string{file}, ioutil.WriteFile(file, b, 0644)
+	}
+
+	var files []string
+	for _, artifact := range artifacts {
+		file, err := artifact.WriteFile(dir)
+		if err != nil {
+			return 0, nil, err
+		}
+		files = append(files, file)
+	}
+	info, err := solc.NewBuildInfo(version, input, out)
+	if err != nil {
+		return 0, nil, err
+	}
+	file, err := info.WriteFile(dir)
+	if err != nil {
+		return 0, nil, err
+	}
+	return len(artifacts), append(files, file), nil
+}
+
+// provenanceFile is the DSSE envelope of the provenance of signed builds, in the output directory
+const provenanceFile = "provenance.intoto.json"
+
+// signOutput writes the signatures of files, the hardhat artifacts and build-info of out written into dir
+// by writeOutput, and their signed provenance
+func signOutput(dir string, files []string, key crypto.Signer, builder, version string, input *solc.Input, out *solc.Output) error {
+	artifacts := solc.NewArtifacts(out)
+	info, err := solc.NewBuildInfo(version, input, out)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		_, err := solc.WriteSignature(file, key)
+		if err != nil {
+			return err
+		}
+	}
+
+	provenance, err := solc.NewProvenance(builder, info, artifacts)
+	if err != nil {
+		return err
+	}
+	envelope, err := provenance.Sign(key, "")
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, provenanceFile), b, 0644)
+}
+
+// readSigningKey reads a PEM encoded PKCS #8 private key
+func readSigningKey(file string) (crypto.Signer, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block in %v", file)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key %v: %v", file, err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key %v", file)
+	}
+	return signer, nil
+}
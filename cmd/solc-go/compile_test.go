@@ -0,0 +1,120 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	solc "github.com/nmvalera/solc-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "solc-go-compile")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	contracts := filepath.Join(dir, "contracts")
+	require.NoError(t, os.MkdirAll(filepath.Join(contracts, "lib"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(contracts, "One.sol"), []byte(`pragma solidity ^0.5.0; import "./lib/Math.sol"; contract One { function one() public pure returns (uint) { return Math.one(); } }`), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(contracts, "lib", "Math.sol"), []byte(`pragma solidity ^0.5.0; library Math { function one() internal pure returns (uint) { return 1; } }`), 0644))
+
+	binDir, err := filepath.Abs("../../solc-bin")
+	require.NoError(t, err)
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	defer os.Chdir(wd)
+
+	// Version is resolved from the pragmas
+	code, stdout, stderr := runCLI("", "compile", "--bin-dir", binDir, "contracts")
+	require.Equal(t, 0, code, "solc-go compile should succeed: %v", stderr)
+	assert.Contains(t, stdout, "Compiled 2 contracts with 0.5.9+commit.e560f70d into artifacts")
+
+	b, err := ioutil.ReadFile(filepath.Join("artifacts", "contracts", "One.sol", "One.json"))
+	require.NoError(t, err, "Artifact should be written")
+	var artifact map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &artifact))
+	assert.Equal(t, "hh-sol-artifact-1", artifact["_format"])
+	assert.Equal(t, "contracts/One.sol", artifact["sourceName"])
+	infos, _ := filepath.Glob(filepath.Join("artifacts", "build-info", "*.json"))
+	assert.Len(t, infos, 1, "Build info should be written")
+
+	// Files and standard JSON format
+	code, _, stderr = runCLI("", "compile", "--bin-dir", binDir, "-v", "0.5.9", "--optimize", "--runs", "1", "--format", "standard-json", "--output-dir", "out", "contracts/One.sol")
+	require.Equal(t, 0, code, "solc-go compile should succeed: %v", stderr)
+	b, err = ioutil.ReadFile(filepath.Join("out", "output.json"))
+	require.NoError(t, err, "Output should be written")
+	assert.Contains(t, string(b), `"contracts/lib/Math.sol"`, "Imports should be compiled")
+
+	code, _, stderr = runCLI("", "compile", "--bin-dir", binDir, "-v", "0.5.9", "--optimize", "--output-dir", "profiled", "--profile", "contracts/One.sol")
+	require.Equal(t, 0, code, "solc-go compile should succeed: %v", stderr)
+	assert.Contains(t, stderr, "Profile: ", "Profile should be printed")
+	assert.Contains(t, stderr, "optimizer ", "Compiler steps should be timed")
+
+	// Project configuration, overridden by flags
+	require.NoError(t, ioutil.WriteFile("solc-go.yaml", []byte("version: 0.5.9\nsources: [contracts/One.sol]\noptimizer: {enabled: true, runs: 1}\nartifacts: {dir: build, format: standard-json}\n"), 0644))
+	code, stdout, stderr = runCLI("", "compile", "--bin-dir", binDir)
+	require.Equal(t, 0, code, "solc-go compile should succeed: %v", stderr)
+	assert.Contains(t, stdout, "into build")
+	b, err = ioutil.ReadFile(filepath.Join("build", "output.json"))
+	require.NoError(t, err, "Output should be written as configured")
+	code, _, _ = runCLI("", "compile", "--bin-dir", binDir, "--format", "hardhat", "--output-dir", "hh")
+	require.Equal(t, 0, code)
+	_, err = os.Stat(filepath.Join("hh", "contracts", "One.sol", "One.json"))
+	assert.NoError(t, err, "Flags should override the configuration")
+	require.NoError(t, os.Remove("solc-go.yaml"))
+
+	// Signed artifacts and provenance
+	pub, key, _ := ed25519.GenerateKey(rand.Reader)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile("key.pem", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600))
+	code, _, stderr = runCLI("", "compile", "--bin-dir", binDir, "--sign-key", "key.pem", "--builder", "https://ci.example.com", "--output-dir", "signed", "contracts")
+	require.Equal(t, 0, code, "solc-go compile should succeed: %v", stderr)
+	assert.NoError(t, solc.VerifySignature(filepath.Join("signed", "contracts", "One.sol", "One.json"), pub), "Artifacts should be signed")
+	infos, _ = filepath.Glob(filepath.Join("signed", "build-info", "*.json"))
+	require.Len(t, infos, 1)
+	assert.NoError(t, solc.VerifySignature(infos[0], pub), "Build info should be signed")
+	b, err = ioutil.ReadFile(filepath.Join("signed", "provenance.intoto.json"))
+	require.NoError(t, err, "Provenance should be written")
+	envelope := &solc.Envelope{}
+	require.NoError(t, json.Unmarshal(b, envelope))
+	provenance, err := envelope.Verify(pub)
+	require.NoError(t, err, "Provenance should be signed")
+	assert.Equal(t, "https://ci.example.com", provenance.Predicate.RunDetails.Builder.ID)
+	assert.Len(t, provenance.Subject, 3, "Artifacts and build info should be attested")
+	code, _, stderr = runCLI("", "compile", "--bin-dir", binDir, "--sign-key", "key.pem", "--format", "standard-json", "contracts")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "only hardhat artifacts can be signed")
+
+	// Unsatisfiable constraint
+	code, _, stderr = runCLI("", "compile", "--bin-dir", binDir, "-v", "^0.7.0", "contracts")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "no compiler")
+
+	// Version pinned for the project
+	require.NoError(t, ioutil.WriteFile("foundry.toml", []byte("[profile.default]\nsolc = \"0.5.9\"\n"), 0644))
+	code, stdout, stderr = runCLI("", "compile", "--bin-dir", binDir, "contracts/One.sol")
+	require.Equal(t, 0, code, "solc-go compile should succeed: %v", stderr)
+	assert.Contains(t, stdout, "with 0.5.9+commit.e560f70d", "Version of foundry.toml should be used")
+	require.NoError(t, ioutil.WriteFile(".solc-version", []byte("^0.7.0\n"), 0644))
+	code, _, stderr = runCLI("", "compile", "--bin-dir", binDir, "contracts/One.sol")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "no compiler", ".solc-version should take precedence over foundry.toml")
+	require.NoError(t, os.Remove(".solc-version"))
+	require.NoError(t, os.Remove("foundry.toml"))
+
+	// Compilation errors
+	require.NoError(t, ioutil.WriteFile(filepath.Join(contracts, "Broken.sol"), []byte(`pragma solidity ^0.5.0; contract Broken {`), 0644))
+	code, _, stderr = runCLI("", "compile", "--bin-dir", binDir, "contracts/Broken.sol")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "ParserError")
+}
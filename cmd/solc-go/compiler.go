@@ -0,0 +1,385 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	solc "github.com/nmvalera/solc-go"
+)
+
+// BinDirEnv is the environment variable setting the directory of soljson binaries used when --bin-dir is not set
+const BinDirEnv = "SOLC_GO_BIN_DIR"
+
+// DefaultBinDir is the directory of soljson binaries used when neither --bin-dir nor $SOLC_GO_BIN_DIR are set
+const DefaultBinDir = "solc-bin"
+
+const binDirUsage = "directory of soljson-v<version>.js binaries (defaults to $" + BinDirEnv + " or " + DefaultBinDir + ")"
+
+// compilerFlags select the compiler of a command
+type compilerFlags struct {
+	soljson string
+	binDir  string
+	version string
+}
+
+func (f *compilerFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.soljson, "soljson", "", "soljson emscripten binary, overrides version selection")
+	fs.StringVar(&f.binDir, "bin-dir", "", binDirUsage)
+	fs.StringVar(&f.version, "version", "", "compiler version constraint (defaults to the version of "+solc.VersionFile+" or "+solc.FoundryConfigFile+", then to the pragmas of the sources)")
+	fs.StringVar(&f.version, "v", "", "shorthand for --version")
+}
+
+func (f *compilerFlags) dir() string {
+	if f.binDir != "" {
+		return f.binDir
+	}
+	if dir := os.Getenv(BinDirEnv); dir != "" {
+		return dir
+	}
+	return DefaultBinDir
+}
+
+// compiler creates the compiler selected by the flags, the version pinned in the working directory (see
+// solc.PinnedVersion) or else satisfying the pragmas of sources if no version is set
+func (f *compilerFlags) compiler(sources map[string]solc.SourceIn) (solc.Solc, error) {
+	if f.soljson != "" {
+		return solc.NewFromFile(f.soljson)
+	}
+
+	version := f.version
+	if version == "" {
+		pinned, err := solc.PinnedVersion(".")
+		if err != nil {
+			return nil, err
+		}
+		version = pinned
+	}
+	constraints := solc.VersionPragmas(sources)
+	if version != "" {
+		constraints = []string{version}
+	}
+	binary, err := solc.ResolveBinary(f.dir(), constraints...)
+	if err != nil {
+		return nil, err
+	}
+	return solc.NewFromFile(binary.Path)
+}
+
+// importFlags set how the imports of a command are resolved
+type importFlags struct {
+	config       string
+	remappings   remappingsFlag
+	includePaths stringsFlag
+	allowPaths   stringsFlag
+
+	preserveSourceNames bool
+
+	stripBOM             bool
+	normalizeLineEndings bool
+}
+
+func (f *importFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.config, "config", "", "project configuration file (defaults to "+solc.ProjectConfigFile+" if present)")
+	fs.Var(&f.remappings, "remappings", "import remappings (prefix=target), comma separated or repeated")
+	fs.Var(&f.includePaths, "include-path", "directory imports are resolved from when not found in the working directory (defaults to node_modules), comma separated or repeated")
+	fs.Var(&f.allowPaths, "allow-paths", "directory imports may read files from, besides the working directory and include paths, comma separated or repeated")
+	fs.BoolVar(&f.preserveSourceNames, "preserve-source-names", false, "resolve imports and remappings as written, without normalizing backslashes")
+	fs.BoolVar(&f.stripBOM, "strip-bom", false, "remove the UTF-8 byte order mark of sources (changes metadata hashes)")
+	fs.BoolVar(&f.normalizeLineEndings, "normalize-line-endings", false, "replace CRLF line endings of sources by LF (changes metadata hashes)")
+}
+
+// normalization returns the normalization of the contents of the sources loaded
+func (f *importFlags) normalization() solc.SourceNormalization {
+	normalization := solc.NormalizeNone
+	if f.stripBOM {
+		normalization |= solc.StripBOM
+	}
+	if f.normalizeLineEndings {
+		normalization |= solc.NormalizeLineEndings
+	}
+	return normalization
+}
+
+// resolver resolves imports from the working directory then include paths, after remapping
+//
+// Like native solc, imports may read files from the allow paths and from the directories of sources, the
+// files and directories compiled. Imports and remappings are normalized unless source names are preserved
+func (f *importFlags) resolver(sources ...string) solc.ImportResolver {
+	includePaths := f.includePaths
+	if len(includePaths) == 0 {
+		includePaths = []string{"node_modules"}
+	}
+	resolver := solc.NewFSResolver(".", includePaths...).Allow(f.allowPaths...)
+	resolver.Normalization = f.normalization()
+	for _, source := range sources {
+		if info, err := os.Stat(source); err == nil && !info.IsDir() {
+			source = filepath.Dir(source)
+		}
+		resolver.Allow(source)
+	}
+	if f.preserveSourceNames {
+		return solc.RemappingResolver(f.remappings, resolver)
+	}
+	remappings := make([]solc.Remapping, len(f.remappings))
+	for i, r := range f.remappings {
+		remappings[i] = solc.Remapping{
+			Context: solc.NormalizeSourceName(r.Context),
+			Prefix:  solc.NormalizeSourceName(r.Prefix),
+			Target:  solc.NormalizeSourceName(r.Target),
+		}
+	}
+	return solc.NormalizeResolver(solc.RemappingResolver(remappings, resolver))
+}
+
+// settingsFlags set the compilation settings of a command
+type settingsFlags struct {
+	importFlags
+	optimize   bool
+	runs       int
+	evmVersion string
+	viaIR      bool
+
+	// outputSelection is only set by the project configuration
+	outputSelection map[string]map[string][]string
+}
+
+func (f *settingsFlags) register(fs *flag.FlagSet) {
+	f.importFlags.register(fs)
+	fs.BoolVar(&f.optimize, "optimize", false, "enable the optimizer")
+	fs.IntVar(&f.runs, "runs", solc.DefaultOptimizerRuns, "optimizer runs")
+	fs.StringVar(&f.evmVersion, "evm-version", "", "target EVM version")
+	fs.BoolVar(&f.viaIR, "via-ir", false, "compile through the Yul IR pipeline")
+}
+
+// settings returns the settings, selecting the outputs of artifacts unless configured otherwise
+func (f *settingsFlags) settings() solc.Settings {
+	selection := f.outputSelection
+	if selection == nil {
+		selection = map[string]map[string][]string{
+			"*": {
+				"*": {"abi", "evm.bytecode", "evm.deployedBytecode", "evm.methodIdentifiers", "metadata"},
+				"":  {"ast"},
+			},
+		}
+	}
+	return solc.Settings{
+		Optimizer:       solc.Optimizer{Enabled: f.optimize, Runs: f.runs},
+		EVMVersion:      f.evmVersion,
+		ViaIR:           f.viaIR,
+		OutputSelection: selection,
+	}
+}
+
+// loadProject reads the project configuration and fills the flags not set on the command line from it
+//
+// cf and sf may be nil. It returns nil if there is no configuration
+func loadProject(fs *flag.FlagSet, imports *importFlags, cf *compilerFlags, sf *settingsFlags) (*solc.ProjectConfig, error) {
+	file := imports.config
+	if file == "" {
+		file = solc.ProjectConfigFile
+	}
+	project, err := solc.ReadProjectConfig(file)
+	if errors.Is(err, solc.ErrNoProjectConfig) && imports.config == "" {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	set := setFlags(fs)
+	if !set["remappings"] {
+		imports.remappings, _ = project.ParsedRemappings()
+	}
+	if !set["include-path"] {
+		for _, dir := range project.IncludePaths {
+			imports.includePaths = append(imports.includePaths, project.Path(dir))
+		}
+	}
+	if !set["preserve-source-names"] {
+		imports.preserveSourceNames = project.PreserveSourceNames
+	}
+	if !set["strip-bom"] {
+		imports.stripBOM = project.StripBOM
+	}
+	if !set["normalize-line-endings"] {
+		imports.normalizeLineEndings = project.NormalizeLineEndings
+	}
+	if !set["allow-paths"] {
+		for _, dir := range project.AllowPaths {
+			imports.allowPaths = append(imports.allowPaths, project.Path(dir))
+		}
+	}
+	if cf != nil && !set["version"] && !set["v"] {
+		cf.version = project.Version
+	}
+	if sf != nil {
+		settings := project.Settings()
+		if !set["optimize"] {
+			sf.optimize = settings.Optimizer.Enabled
+		}
+		if !set["runs"] {
+			sf.runs = settings.Optimizer.Runs
+		}
+		if !set["evm-version"] {
+			sf.evmVersion = settings.EVMVersion
+		}
+		if !set["via-ir"] {
+			sf.viaIR = settings.ViaIR
+		}
+		sf.outputSelection = settings.OutputSelection
+	}
+
+	return project, nil
+}
+
+// setFlags returns the names of the flags set on the command line
+func setFlags(fs *flag.FlagSet) map[string]bool {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	return set
+}
+
+// sourcePaths returns args, or the sources of the project configuration if args are empty
+func sourcePaths(args []string, project *solc.ProjectConfig) []string {
+	if len(args) > 0 || project == nil {
+		return args
+	}
+	var paths []string
+	for _, p := range project.Sources {
+		paths = append(paths, project.Path(p))
+	}
+	return paths
+}
+
+// compile compiles sources, printing diagnostics and failing on errors
+func compile(e *env, compiler solc.Solc, sources map[string]solc.SourceIn, settings solc.Settings) (*solc.Input, *solc.Output, error) {
+	input := &solc.Input{
+		Language: "Solidity",
+		Sources:  sources,
+		Settings: settings,
+	}
+	out, err := compiler.Compile(input)
+	if err != nil {
+		return nil, nil, err
+	}
+	if printErrors(e, out) {
+		return nil, nil, fmt.Errorf("compilation failed")
+	}
+	return input, out, nil
+}
+
+// loadSources reads the .sol files and directories of paths, normalized, and the files they import
+//
+// Source unit names are slash separated paths relative to the working directory, imported
+// files keep the name they are imported with (before remapping)
+func loadSources(paths []string, normalization solc.SourceNormalization, resolver solc.ImportResolver) (map[string]solc.SourceIn, error) {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	sources := make(map[string]solc.SourceIn)
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		name := filepath.ToSlash(filepath.Clean(p))
+		if !info.IsDir() {
+			content, err := ioutil.ReadFile(p)
+			if err != nil {
+				return nil, err
+			}
+			sources[name] = solc.SourceIn{Content: normalization.Normalize(string(content))}
+			continue
+		}
+
+		dirSources, err := solc.LoadDirNormalized(p, normalization)
+		if err != nil {
+			return nil, err
+		}
+		for rel, source := range dirSources {
+			if name != "." {
+				rel = path.Join(name, rel)
+			}
+			sources[rel] = source
+		}
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no source found in %v", paths)
+	}
+
+	graph, err := solc.DependencyGraph(sources, resolver)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range graph.Nodes {
+		if _, ok := sources[name]; ok {
+			continue
+		}
+		content, err := resolver.Resolve(name)
+		if err != nil {
+			return nil, err
+		}
+		sources[name] = solc.SourceIn{Content: content}
+	}
+
+	return sources, nil
+}
+
+// printErrors prints the diagnostics of out and indicates whether compilation failed
+func printErrors(e *env, out *solc.Output) (failed bool) {
+	for _, err := range out.Errors {
+		msg := err.FormattedMessage
+		if msg == "" {
+			msg = err.Message + "\n"
+		}
+		fmt.Fprint(e.stderr, msg)
+		failed = failed || err.Severity == "error"
+	}
+	return failed
+}
+
+// remappingsFlag collects remappings from repeated flags, each holding comma or space separated remappings
+type remappingsFlag []solc.Remapping
+
+func (f *remappingsFlag) String() string {
+	var s []string
+	for _, r := range *f {
+		s = append(s, r.String())
+	}
+	return strings.Join(s, ",")
+}
+
+func (f *remappingsFlag) Set(value string) error {
+	for _, field := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+		r, err := solc.ParseRemapping(field)
+		if err != nil {
+			return err
+		}
+		*f = append(*f, r)
+	}
+	return nil
+}
+
+// stringsFlag collects values from repeated flags, each holding comma separated values
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*f = append(*f, v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	solc "github.com/nmvalera/solc-go"
+)
+
+func init() {
+	register(&command{
+		name:  "flatten",
+		usage: "inline the imports of a source into a single file",
+		run:   runFlatten,
+	})
+}
+
+func runFlatten(e *env, args []string) error {
+	fs := flag.NewFlagSet("flatten", flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	var imports importFlags
+	imports.register(fs)
+	output := fs.String("output", "", "file the flattened source is written to (defaults to stdout)")
+	fs.StringVar(output, "o", "", "shorthand for --output")
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: solc-go flatten [flags] Contract.sol\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+	if _, err := loadProject(fs, &imports, nil, nil); err != nil {
+		return err
+	}
+
+	entry := filepath.ToSlash(filepath.Clean(fs.Arg(0)))
+	flat, err := solc.Flatten(entry, imports.resolver(entry))
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = fmt.Fprint(e.stdout, flat)
+		return err
+	}
+	return ioutil.WriteFile(*output, []byte(flat), 0644)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlatten(t *testing.T) {
+	dir, err := ioutil.TempDir("", "solc-go-flatten")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "lib", "math"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "One.sol"), []byte("// SPDX-License-Identifier: MIT\npragma solidity ^0.6.0;\nimport \"@math/Math.sol\";\ncontract One {}\n"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "lib", "math", "Math.sol"), []byte("// SPDX-License-Identifier: MIT\npragma solidity ^0.6.0;\nlibrary Math {}\n"), 0644))
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	defer os.Chdir(wd)
+
+	code, stdout, stderr := runCLI("", "flatten", "--remappings", "@math/=lib/math/", "One.sol")
+	require.Equal(t, 0, code, "solc-go flatten should succeed: %v", stderr)
+	assert.Contains(t, stdout, "library Math {}", "Remapped imports should be inlined")
+	assert.NotContains(t, stdout, "import", "Imports should be removed")
+
+	code, _, _ = runCLI("", "flatten", "--remappings", "@math/=lib/math/", "-o", "Flat.sol", "One.sol")
+	require.Equal(t, 0, code)
+	b, err := ioutil.ReadFile("Flat.sol")
+	require.NoError(t, err, "Flattened source should be written")
+	assert.Equal(t, stdout, string(b))
+
+	code, _, stderr = runCLI("", "flatten", "One.sol")
+	assert.Equal(t, 1, code, "Unresolved imports should fail")
+	assert.Contains(t, stderr, "@math/Math.sol")
+
+	ext, err := ioutil.TempDir("", "solc-go-flatten-ext")
+	require.NoError(t, err)
+	defer os.RemoveAll(ext)
+	require.NoError(t, os.MkdirAll(filepath.Join(ext, "math"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(ext, "math", "Math.sol"), []byte("// SPDX-License-Identifier: MIT\npragma solidity ^0.6.0;\nlibrary Math {}\n"), 0644))
+
+	code, _, stderr = runCLI("", "flatten", "--remappings", "@math/="+filepath.ToSlash(ext)+"/math/", "One.sol")
+	assert.Equal(t, 1, code, "Imports out of the working directory should fail")
+	assert.Contains(t, stderr, "not allowed")
+
+	code, stdout, stderr = runCLI("", "flatten", "--remappings", "@math/="+filepath.ToSlash(ext)+"/math/", "--allow-paths", ext, "One.sol")
+	require.Equal(t, 0, code, "Imports from allowed paths should succeed: %v", stderr)
+	assert.Contains(t, stdout, "library Math {}")
+}
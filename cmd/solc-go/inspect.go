@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	solc "github.com/nmvalera/solc-go"
+)
+
+func init() {
+	register(&command{
+		name:  "storage-layout",
+		usage: "print the storage layout of a contract (file:Contract)",
+		run:   runStorageLayout,
+	})
+	register(&command{
+		name:  "selectors",
+		usage: "print the function, event and error selectors of the contracts of a file",
+		run:   runSelectors,
+	})
+}
+
+// inspectFlags are the flags of the commands inspecting the compilation of a single file
+type inspectFlags struct {
+	compilerFlags
+	settingsFlags
+	json bool
+}
+
+func (f *inspectFlags) parse(e *env, name, usage string, args []string) (string, error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	f.compilerFlags.register(fs)
+	f.settingsFlags.register(fs)
+	fs.BoolVar(&f.json, "json", false, "print JSON instead of a table")
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: solc-go %v [flags] %v\n\nFlags:\n", name, usage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return "", err
+	}
+	if _, err := loadProject(fs, &f.importFlags, &f.compilerFlags, &f.settingsFlags); err != nil {
+		return "", err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return "", flag.ErrHelp
+	}
+	return fs.Arg(0), nil
+}
+
+// compile compiles file selecting outputs of contract ("*" for all)
+func (f *inspectFlags) compile(e *env, file, contract string, outputs ...string) (*solc.Output, string, error) {
+	sources, err := loadSources([]string{file}, f.normalization(), f.resolver(file))
+	if err != nil {
+		return nil, "", err
+	}
+	compiler, err := f.compiler(sources)
+	if err != nil {
+		return nil, "", err
+	}
+	defer compiler.Close()
+
+	name := filepath.ToSlash(filepath.Clean(file))
+	settings := f.settings()
+	settings.OutputSelection = solc.SelectContract(name, contract, outputs...)
+	_, out, err := compile(e, compiler, sources, settings)
+	if err != nil {
+		return nil, "", err
+	}
+	return out, name, nil
+}
+
+func runStorageLayout(e *env, args []string) error {
+	var f inspectFlags
+	arg, err := f.parse(e, "storage-layout", "file:Contract", args)
+	if err != nil {
+		return err
+	}
+	id, err := solc.ParseContractID(arg)
+	if err == nil && !id.IsQualified() {
+		err = fmt.Errorf("invalid contract %q, expected file:Contract", arg)
+	}
+	if err != nil {
+		return err
+	}
+	file, contract := id.File, id.Name
+
+	out, name, err := f.compile(e, file, contract, "storageLayout", "transientStorageLayout")
+	if err != nil {
+		return err
+	}
+	c, ok := out.Contract(name, contract)
+	if !ok {
+		return fmt.Errorf("contract %v not found in %v", contract, name)
+	}
+	layout := c.StorageLayout
+	if layout == nil {
+		return fmt.Errorf("no storage layout output, storage layouts require solc >= 0.5.13")
+	}
+
+	if f.json {
+		return printJSON(e, struct {
+			*solc.StorageLayout
+			Transient *solc.StorageLayout `json:"transient,omitempty"`
+		}{layout, c.TransientStorageLayout})
+	}
+	w := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "Name\tType\tSlot\tOffset\tBytes\tContract\tLocation")
+	for _, location := range []solc.StorageLocation{solc.LocationStorage, solc.LocationTransient} {
+		layout := c.Layout(location)
+		if layout == nil {
+			continue
+		}
+		for _, slot := range layout.Storage {
+			label, size := slot.Type, ""
+			if t, ok := layout.Types[slot.Type]; ok {
+				label, size = t.Label, t.NumberOfBytes
+			}
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\n", slot.Label, label, slot.Slot, slot.Offset, size, slot.Contract, location)
+		}
+	}
+	return w.Flush()
+}
+
+// selector is a selector printed by the selectors command
+type selector struct {
+	Contract  string `json:"contract"`
+	Type      string `json:"type"`
+	Signature string `json:"signature"`
+	Selector  string `json:"selector"`
+}
+
+func runSelectors(e *env, args []string) error {
+	var f inspectFlags
+	file, err := f.parse(e, "selectors", "file", args)
+	if err != nil {
+		return err
+	}
+
+	out, name, err := f.compile(e, file, "*", "abi")
+	if err != nil {
+		return err
+	}
+
+	var selectors []selector
+	for contract, c := range out.Contracts[name] {
+		entries, err := solc.ParseABI(c.ABI)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if s := entry.Selector(); s != "" {
+				selectors = append(selectors, selector{Contract: contract, Type: entry.Type, Signature: entry.Signature(), Selector: s})
+			}
+		}
+	}
+	order := map[string]int{"function": 0, "event": 1, "error": 2}
+	sort.Slice(selectors, func(i, j int) bool {
+		si, sj := selectors[i], selectors[j]
+		if si.Contract != sj.Contract {
+			return si.Contract < sj.Contract
+		}
+		if si.Type != sj.Type {
+			return order[si.Type] < order[sj.Type]
+		}
+		return si.Signature < sj.Signature
+	})
+
+	if f.json {
+		return printJSON(e, selectors)
+	}
+	w := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "Contract\tType\tSelector\tSignature")
+	for _, s := range selectors {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\n", s.Contract, s.Type, s.Selector, s.Signature)
+	}
+	return w.Flush()
+}
+
+func printJSON(e *env, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(e.stdout, "%s\n", b)
+	return err
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspect(t *testing.T) {
+	dir, err := ioutil.TempDir("", "solc-go-inspect")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	source := filepath.Join(dir, "Token.sol")
+	require.NoError(t, ioutil.WriteFile(source, []byte(`pragma solidity ^0.6.0;
+contract Token {
+	uint128 total;
+	bool paused;
+	mapping(address => uint256) balances;
+	event Transfer(address indexed from, address indexed to, uint256 value);
+	function transfer(address to, uint256 value) public returns (bool) { emit Transfer(msg.sender, to, value); return true; }
+}`), 0644))
+
+	code, stdout, stderr := runCLI("", "storage-layout", "--soljson", soljson, source+":Token")
+	require.Equal(t, 0, code, "solc-go storage-layout should succeed: %v", stderr)
+	assert.Regexp(t, `total\s+uint128\s+0\s+0\s+16\s+\S+\s+storage`, stdout)
+	assert.Regexp(t, `paused\s+bool\s+0\s+16\s+1`, stdout, "Packed variables should share a slot")
+	assert.Regexp(t, `balances\s+mapping\(address => uint256\)\s+1\s+0\s+32`, stdout)
+
+	code, _, _ = runCLI("", "storage-layout", "--soljson", soljson, source+":Missing")
+	assert.Equal(t, 1, code, "Unknown contract should fail")
+
+	code, stdout, stderr = runCLI("", "selectors", "--soljson", soljson, source)
+	require.Equal(t, 0, code, "solc-go selectors should succeed: %v", stderr)
+	assert.Regexp(t, `Token\s+function\s+0xa9059cbb\s+transfer\(address,uint256\)`, stdout)
+	assert.Regexp(t, `Token\s+event\s+0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef\s+Transfer\(address,address,uint256\)`, stdout)
+
+	code, stdout, _ = runCLI("", "selectors", "--soljson", soljson, "--json", source)
+	require.Equal(t, 0, code)
+	assert.Contains(t, stdout, `"selector": "0xa9059cbb"`)
+}
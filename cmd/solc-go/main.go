@@ -0,0 +1,138 @@
+// Command solc-go compiles solidity using the emscripten compiled solc running in v8
+//
+// Usage:
+//
+//	solc-go --standard-json [--soljson file]  compile standard JSON from stdin to stdout, like solc --standard-json
+//	solc-go --version [--soljson file]        print the compiler version, like solc --version
+//	solc-go <command> [flags] [args]          run a command, see solc-go help
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+
+	solc "github.com/nmvalera/solc-go"
+)
+
+// SoljsonEnv is the environment variable pointing to the soljson file used when --soljson is not set
+const SoljsonEnv = "SOLC_GO_SOLJSON"
+
+// command is a solc-go subcommand
+type command struct {
+	name  string
+	usage string
+	run   func(env *env, args []string) error
+}
+
+var commands = map[string]*command{}
+
+func register(cmd *command) {
+	commands[cmd.name] = cmd
+}
+
+// env is the execution environment of a command
+type env struct {
+	// ctx is canceled on interrupt
+	ctx context.Context
+
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		cancel()
+	}()
+
+	os.Exit(run(os.Args[1:], &env{ctx: ctx, stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}))
+}
+
+func run(args []string, e *env) int {
+	if len(args) > 0 {
+		if cmd, ok := commands[args[0]]; ok {
+			err := cmd.run(e, args[1:])
+			if err == flag.ErrHelp {
+				return 2
+			}
+			if err != nil {
+				fmt.Fprintf(e.stderr, "solc-go %v: %v\n", cmd.name, err)
+				return 1
+			}
+			return 0
+		}
+	}
+
+	fs := flag.NewFlagSet("solc-go", flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	standardJSON := fs.Bool("standard-json", false, "compile standard JSON from stdin to stdout")
+	version := fs.Bool("version", false, "print the compiler version")
+	soljson := fs.String("soljson", "", "soljson emscripten binary (defaults to $"+SoljsonEnv+")")
+	fs.Usage = func() { usage(e.stderr, fs) }
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	switch {
+	case *standardJSON || *version:
+		compiler, err := newCompiler(*soljson)
+		if err != nil {
+			fmt.Fprintf(e.stderr, "solc-go: %v\n", err)
+			return 1
+		}
+		defer compiler.Close()
+
+		if *version {
+			fmt.Fprintf(e.stdout, "solc, the solidity compiler commandline interface\nVersion: %v\n", compiler.Version())
+			return 0
+		}
+		err = solc.ServeStandardJSON(compiler, e.stdin, e.stdout)
+		if err != nil {
+			fmt.Fprintf(e.stderr, "solc-go: %v\n", err)
+			return 1
+		}
+		return 0
+	default:
+		usage(e.stderr, fs)
+		return 2
+	}
+}
+
+func usage(w io.Writer, fs *flag.FlagSet) {
+	fmt.Fprintf(w, "Usage: solc-go [--standard-json | --version] [--soljson file]\n       solc-go <command> [flags] [args]\n\nFlags:\n")
+	fs.PrintDefaults()
+	if len(commands) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\nCommands:\n")
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-16v %v\n", name, commands[name].usage)
+	}
+}
+
+// newCompiler creates a compiler from soljson, or from the file set in the environment
+func newCompiler(soljson string) (solc.Solc, error) {
+	if soljson == "" {
+		soljson = os.Getenv(SoljsonEnv)
+	}
+	if soljson == "" {
+		return nil, fmt.Errorf("no compiler, set --soljson or $%v", SoljsonEnv)
+	}
+	return solc.NewFromFile(soljson)
+}
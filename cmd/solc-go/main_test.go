@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const soljson = "../../solc-bin/soljson-v0.6.2+commit.bacdbe57.js"
+
+func runCLI(stdin string, args ...string) (code int, stdout, stderr string) {
+	var out, errOut bytes.Buffer
+	code = run(args, &env{ctx: context.Background(), stdin: strings.NewReader(stdin), stdout: &out, stderr: &errOut})
+	return code, out.String(), errOut.String()
+}
+
+func TestStandardJSON(t *testing.T) {
+	input := `{"language": "Solidity", "sources": {"One.sol": {"content": "pragma solidity ^0.6.1; contract One {}"}}, "settings": {"outputSelection": {"*": {"*": ["evm.bytecode.object"]}}}}`
+	code, stdout, stderr := runCLI(input, "--standard-json", "--soljson", soljson)
+	require.Equal(t, 0, code, "solc-go --standard-json should succeed: %v", stderr)
+	assert.True(t, strings.HasSuffix(stdout, "\n"), "Output should end with a newline")
+
+	var out map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(stdout), &out), "Output should be standard JSON")
+	assert.Contains(t, out, "contracts")
+
+	// Invalid inputs are reported by the compiler in the output
+	code, stdout, _ = runCLI("{", "--standard-json", "--soljson", soljson)
+	require.Equal(t, 0, code)
+	assert.Contains(t, stdout, "JSONError")
+}
+
+func TestVersion(t *testing.T) {
+	code, stdout, _ := runCLI("", "--version", "--soljson", soljson)
+	require.Equal(t, 0, code)
+	assert.Contains(t, stdout, "Version: 0.6.2+commit.bacdbe57.Emscripten.clang")
+
+	code, _, stderr := runCLI("", "--version")
+	assert.Equal(t, 1, code, "Missing compiler should fail")
+	assert.Contains(t, stderr, "no compiler")
+
+	code, _, _ = runCLI("")
+	assert.Equal(t, 2, code, "No mode should print usage")
+}
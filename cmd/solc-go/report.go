@@ -0,0 +1,368 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	solc "github.com/nmvalera/solc-go"
+)
+
+func init() {
+	register(&command{
+		name:  "gas",
+		usage: "print the gas estimates of contracts",
+		run:   runGas,
+	})
+	register(&command{
+		name:  "calls",
+		usage: "print the external calls, delegatecalls, selfdestructs and assembly blocks of contracts",
+		run:   runCalls,
+	})
+	register(&command{
+		name:  "size",
+		usage: "print the bytecode sizes of contracts against the EIP-170 limit",
+		run:   runSize,
+	})
+	register(&command{
+		name:  "explain",
+		usage: "print the compiler, settings, size and gas of contracts, to audit builds or find why bytecode differs",
+		run:   runExplain,
+	})
+	register(&command{
+		name:  "decoders",
+		usage: "print the table of event topics and error selectors to their ABI entries, as JSON or Go source",
+		run:   runDecoders,
+	})
+}
+
+// Report formats
+const (
+	formatTable    = "table"
+	formatMarkdown = "markdown"
+	formatJSON     = "json"
+)
+
+// reportFlags are the flags of the commands reporting on the compilation of files and directories
+type reportFlags struct {
+	compilerFlags
+	settingsFlags
+	format string
+
+	// paths are the files and directories compiled
+	paths []string
+}
+
+func (f *reportFlags) parse(e *env, fs *flag.FlagSet, args []string) error {
+	fs.SetOutput(e.stderr)
+	f.compilerFlags.register(fs)
+	f.settingsFlags.register(fs)
+	fs.StringVar(&f.format, "format", formatTable, "output format: table, markdown or json")
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: solc-go %v [flags] [files|dirs]\n\nFlags:\n", fs.Name())
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	project, err := loadProject(fs, &f.importFlags, &f.compilerFlags, &f.settingsFlags)
+	if err != nil {
+		return err
+	}
+	f.paths = sourcePaths(fs.Args(), project)
+	switch f.format {
+	case formatTable, formatMarkdown, formatJSON:
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q", f.format)
+	}
+}
+
+func (f *reportFlags) compile(e *env, selection solc.OutputSelection) (*solc.Input, *solc.Output, error) {
+	_, in, out, err := f.compileVersion(e, selection)
+	return in, out, err
+}
+
+// compileVersion compiles like compile and also returns the version of the compiler
+func (f *reportFlags) compileVersion(e *env, selection solc.OutputSelection) (string, *solc.Input, *solc.Output, error) {
+	sources, err := loadSources(f.paths, f.normalization(), f.resolver(f.paths...))
+	if err != nil {
+		return "", nil, nil, err
+	}
+	compiler, err := f.compiler(sources)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	defer compiler.Close()
+
+	settings := f.settings()
+	settings.OutputSelection = selection
+	in, out, err := compile(e, compiler, sources, settings)
+	return compiler.Version(), in, out, err
+}
+
+func runSize(e *env, args []string) error {
+	fs := flag.NewFlagSet("size", flag.ContinueOnError)
+	var f reportFlags
+	check := fs.Bool("check", false, "fail if a contract exceeds the maximum size")
+	maxSize := fs.Int("max-size", solc.MaxCodeSize, "maximum deployed bytecode size checked by --check (creation bytecode may be twice as large, EIP-3860)")
+	if err := f.parse(e, fs, args); err != nil {
+		return err
+	}
+
+	_, out, err := f.compile(e, solc.SelectAll("evm.bytecode.object", "evm.deployedBytecode.object"))
+	if err != nil {
+		return err
+	}
+	report := solc.NewSizeReport(out)
+
+	if f.format == formatJSON {
+		err = printJSON(e, report)
+	} else {
+		rows := make([][]string, len(report))
+		for i, s := range report {
+			rows[i] = []string{s.Contract, strconv.Itoa(s.Size), strconv.Itoa(s.InitSize), strconv.Itoa(*maxSize - s.Size)}
+		}
+		err = renderTable(e.stdout, f.format, []string{"Contract", "Size (B)", "Init size (B)", "Margin (B)"}, rows)
+	}
+	if err != nil || !*check {
+		return err
+	}
+
+	var oversized []string
+	for _, s := range report {
+		if s.Size > *maxSize || s.InitSize > 2**maxSize {
+			oversized = append(oversized, s.Contract)
+		}
+	}
+	if len(oversized) > 0 {
+		return fmt.Errorf("contracts exceeding %v bytes: %v", *maxSize, strings.Join(oversized, ", "))
+	}
+	return nil
+}
+
+func runGas(e *env, args []string) error {
+	fs := flag.NewFlagSet("gas", flag.ContinueOnError)
+	var f reportFlags
+	check := fs.Uint64("check", 0, "fail if a function estimate is unbounded or exceeds this gas (0 disables the check)")
+	if err := f.parse(e, fs, args); err != nil {
+		return err
+	}
+
+	_, out, err := f.compile(e, solc.SelectAll("evm.gasEstimates"))
+	if err != nil {
+		return err
+	}
+	report := solc.NewGasReport(out)
+
+	if f.format == formatJSON {
+		err = printJSON(e, report)
+	} else {
+		var rows [][]string
+		for _, c := range report {
+			rows = append(rows, []string{c.Contract, "deployment", c.Deployment})
+			for _, fn := range c.Functions {
+				rows = append(rows, []string{c.Contract, fn.Signature, fn.Gas})
+			}
+		}
+		err = renderTable(e.stdout, f.format, []string{"Contract", "Function", "Gas"}, rows)
+	}
+	if err != nil || *check == 0 {
+		return err
+	}
+
+	var exceeding []string
+	for _, c := range report {
+		for _, fn := range c.Functions {
+			if fn.Exceeds(*check) {
+				exceeding = append(exceeding, fmt.Sprintf("%v.%v (%v)", c.Contract, fn.Signature, fn.Gas))
+			}
+		}
+	}
+	if len(exceeding) > 0 {
+		return fmt.Errorf("functions exceeding %v gas: %v", *check, strings.Join(exceeding, ", "))
+	}
+	return nil
+}
+
+func runCalls(e *env, args []string) error {
+	fs := flag.NewFlagSet("calls", flag.ContinueOnError)
+	var f reportFlags
+	if err := f.parse(e, fs, args); err != nil {
+		return err
+	}
+
+	in, out, err := f.compile(e, solc.OutputSelection{}.Select("*", "", "ast"))
+	if err != nil {
+		return err
+	}
+	report, err := solc.NewCallReport(out)
+	if err != nil {
+		return err
+	}
+
+	if f.format == formatJSON {
+		return printJSON(e, report)
+	}
+	var rows [][]string
+	for _, c := range report {
+		for _, call := range c.Calls {
+			target := call.Function
+			if call.Kind == "assembly" {
+				target = strings.Join(call.Opcodes, ", ")
+			}
+			loc := call.SourceLocation
+			rows = append(rows, []string{c.Contract, call.Caller, call.Kind, target, fmt.Sprintf("%v:%v", loc.File, lineOf(in.Sources[loc.File].Content, loc.Start))})
+		}
+	}
+	return renderTable(e.stdout, f.format, []string{"Contract", "Caller", "Kind", "Target", "Location"}, rows)
+}
+
+func runExplain(e *env, args []string) error {
+	fs := flag.NewFlagSet("explain", flag.ContinueOnError)
+	var f reportFlags
+	if err := f.parse(e, fs, args); err != nil {
+		return err
+	}
+
+	version, in, out, err := f.compileVersion(e, solc.SelectAll("metadata", "evm.bytecode.object", "evm.deployedBytecode.object", "evm.gasEstimates"))
+	if err != nil {
+		return err
+	}
+	report, err := solc.NewExplainReport(version, in, out)
+	if err != nil {
+		return err
+	}
+
+	if f.format == formatJSON {
+		return printJSON(e, report)
+	}
+	var rows [][]string
+	for _, c := range report {
+		evmVersion := c.EVMVersion
+		if c.DefaultEVMVersion {
+			evmVersion += " (default)"
+		}
+		optimizer := "disabled"
+		if c.Optimizer.Enabled {
+			optimizer = fmt.Sprintf("enabled, %v runs", c.Optimizer.Runs)
+			if c.Optimizer.Details != nil {
+				optimizer += ", custom details"
+			}
+		}
+		rows = append(rows,
+			[]string{c.Contract, "compiler", c.Compiler},
+			[]string{c.Contract, "evmVersion", evmVersion},
+			[]string{c.Contract, "viaIR", strconv.FormatBool(c.ViaIR)},
+			[]string{c.Contract, "optimizer", optimizer},
+		)
+		if c.BytecodeHash != "" {
+			rows = append(rows, []string{c.Contract, "bytecodeHash", c.BytecodeHash})
+		}
+		if len(c.Remappings) > 0 {
+			rows = append(rows, []string{c.Contract, "remappings", strings.Join(c.Remappings, " ")})
+		}
+		var libraries []string
+		for lib, address := range c.Libraries {
+			libraries = append(libraries, lib+"="+address)
+		}
+		sort.Strings(libraries)
+		if len(libraries) > 0 {
+			rows = append(rows, []string{c.Contract, "libraries", strings.Join(libraries, " ")})
+		}
+		if c.InitSize > 0 {
+			rows = append(rows,
+				[]string{c.Contract, "size", fmt.Sprintf("%v B", c.Size)},
+				[]string{c.Contract, "initSize", fmt.Sprintf("%v B", c.InitSize)},
+			)
+		}
+		if c.Deployment != "" {
+			rows = append(rows, []string{c.Contract, "deployment gas", c.Deployment})
+		}
+		for _, fn := range c.Functions {
+			rows = append(rows, []string{c.Contract, fn.Signature + " gas", fn.Gas})
+		}
+	}
+	return renderTable(e.stdout, f.format, []string{"Contract", "Setting", "Value"}, rows)
+}
+
+func runDecoders(e *env, args []string) error {
+	fs := flag.NewFlagSet("decoders", flag.ContinueOnError)
+	var f reportFlags
+	pkg := fs.String("package", "", "print a Go source file of this package instead of a report")
+	if err := f.parse(e, fs, args); err != nil {
+		return err
+	}
+
+	_, out, err := f.compile(e, solc.SelectAll("abi"))
+	if err != nil {
+		return err
+	}
+	table, err := solc.NewDecoderTable(out)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case *pkg != "":
+		src, err := table.GoSource(*pkg)
+		if err != nil {
+			return err
+		}
+		_, err = e.stdout.Write(src)
+		return err
+	case f.format == formatJSON:
+		return printJSON(e, table)
+	}
+	var rows [][]string
+	for _, kind := range []struct {
+		name     string
+		decoders map[string][]solc.Decoder
+	}{{"event", table.Events}, {"error", table.Errors}} {
+		var selectors []string
+		for selector := range kind.decoders {
+			selectors = append(selectors, selector)
+		}
+		sort.Strings(selectors)
+		for _, selector := range selectors {
+			for _, d := range kind.decoders[selector] {
+				rows = append(rows, []string{kind.name, selector, d.ABI.Signature(), strings.Join(d.Contracts, ", ")})
+			}
+		}
+	}
+	return renderTable(e.stdout, f.format, []string{"Type", "Selector", "Signature", "Contracts"}, rows)
+}
+
+// lineOf returns the one based line of a byte offset of text
+func lineOf(text string, offset int) int {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(text) {
+		offset = len(text)
+	}
+	return strings.Count(text[:offset], "\n") + 1
+}
+
+// renderTable writes rows as an aligned table or a markdown table
+func renderTable(w io.Writer, format string, headers []string, rows [][]string) error {
+	if format == formatMarkdown {
+		fmt.Fprintf(w, "| %v |\n", strings.Join(headers, " | "))
+		fmt.Fprintf(w, "|%v\n", strings.Repeat(" --- |", len(headers)))
+		for _, row := range rows {
+			fmt.Fprintf(w, "| %v |\n", strings.Join(row, " | "))
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
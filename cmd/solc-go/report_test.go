@@ -0,0 +1,73 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReports(t *testing.T) {
+	dir, err := ioutil.TempDir("", "solc-go-report")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	source := filepath.Join(dir, "One.sol")
+	require.NoError(t, ioutil.WriteFile(source, []byte("pragma solidity ^0.6.0; contract One { uint[] values; function one() public pure returns (uint) { return 1; } function sum() public view returns (uint s) { for (uint i = 0; i < values.length; i++) { s += values[i]; } } }"), 0644))
+	name := filepath.ToSlash(filepath.Clean(source))
+
+	code, stdout, stderr := runCLI("", "size", "--soljson", soljson, "--check", source)
+	require.Equal(t, 0, code, "solc-go size should succeed: %v", stderr)
+	assert.Regexp(t, `Contract\s+Size \(B\)\s+Init size \(B\)\s+Margin \(B\)`, stdout)
+	assert.Contains(t, stdout, name+":One")
+
+	code, _, stderr = runCLI("", "size", "--soljson", soljson, "--check", "--max-size", "10", source)
+	assert.Equal(t, 1, code, "Oversized contracts should fail the check")
+	assert.Contains(t, stderr, "contracts exceeding 10 bytes")
+
+	code, stdout, stderr = runCLI("", "gas", "--soljson", soljson, "--format", "markdown", source)
+	require.Equal(t, 0, code, "solc-go gas should succeed: %v", stderr)
+	assert.Contains(t, stdout, "| Contract | Function | Gas |\n| --- | --- | --- |\n")
+	assert.Contains(t, stdout, "| "+name+":One | sum() | infinite |")
+
+	code, _, stderr = runCLI("", "gas", "--soljson", soljson, "--check", "100000", source)
+	assert.Equal(t, 1, code, "Unbounded functions should fail the check")
+	assert.Contains(t, stderr, "sum() (infinite)")
+
+	code, stdout, _ = runCLI("", "gas", "--soljson", soljson, "--format", "json", source)
+	require.Equal(t, 0, code)
+	assert.Contains(t, stdout, `"signature": "one()"`)
+
+	code, stdout, stderr = runCLI("", "explain", "--soljson", soljson, source)
+	require.Equal(t, 0, code, "solc-go explain should succeed: %v", stderr)
+	assert.Regexp(t, name+`:One\s+compiler\s+0\.6\.2\+commit\.bacdbe57`, stdout)
+	assert.Regexp(t, name+`:One\s+evmVersion\s+istanbul \(default\)`, stdout)
+	assert.Regexp(t, name+`:One\s+bytecodeHash\s+ipfs`, stdout)
+	assert.Regexp(t, name+`:One\s+sum\(\) gas\s+infinite`, stdout)
+
+	code, stdout, _ = runCLI("", "explain", "--soljson", soljson, "--format", "json", source)
+	require.Equal(t, 0, code)
+	assert.Contains(t, stdout, `"defaultEvmVersion": true`)
+
+	events := filepath.Join(dir, "Events.sol")
+	require.NoError(t, ioutil.WriteFile(events, []byte("pragma solidity ^0.6.0; contract Events { event Transfer(address indexed from, address indexed to, uint value); }"), 0644))
+	code, stdout, stderr = runCLI("", "decoders", "--soljson", soljson, events)
+	require.Equal(t, 0, code, "solc-go decoders should succeed: %v", stderr)
+	assert.Regexp(t, `event\s+0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef\s+Transfer\(address,address,uint256\)\s+`+filepath.ToSlash(filepath.Clean(events))+`:Events`, stdout)
+
+	code, stdout, stderr = runCLI("", "decoders", "--soljson", soljson, "--package", "decoders", events)
+	require.Equal(t, 0, code, "solc-go decoders --package should succeed: %v", stderr)
+	assert.Contains(t, stdout, "package decoders")
+	assert.Contains(t, stdout, "// Transfer(address,address,uint256)")
+
+	wallet := filepath.Join(dir, "Wallet.sol")
+	require.NoError(t, ioutil.WriteFile(wallet, []byte("pragma solidity ^0.6.0;\ncontract Wallet {\n    function kill(address payable to) public { to.transfer(1); selfdestruct(to); }\n}\n"), 0644))
+	name = filepath.ToSlash(filepath.Clean(wallet))
+	code, stdout, stderr = runCLI("", "calls", "--soljson", soljson, wallet)
+	require.Equal(t, 0, code, "solc-go calls should succeed: %v", stderr)
+	assert.Regexp(t, `Contract\s+Caller\s+Kind\s+Target\s+Location`, stdout)
+	assert.Regexp(t, name+`:Wallet\s+kill\s+transfer\s+`+name+`:3`, stdout)
+	assert.Regexp(t, name+`:Wallet\s+kill\s+selfdestruct\s+`+name+`:3`, stdout)
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	solc "github.com/nmvalera/solc-go"
+	"github.com/nmvalera/solc-go/verify"
+)
+
+// EtherscanAPIKeyEnv is the environment variable holding the Etherscan API key used when --etherscan-api-key is not set
+const EtherscanAPIKeyEnv = "ETHERSCAN_API_KEY"
+
+func init() {
+	register(&command{
+		name:  "verify",
+		usage: "compile a deployed contract and verify it on Sourcify or Etherscan",
+		run:   runVerify,
+	})
+}
+
+func runVerify(e *env, args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	var (
+		cf compilerFlags
+		sf settingsFlags
+	)
+	cf.register(fs)
+	sf.register(fs)
+	address := fs.String("address", "", "address of the deployed contract")
+	chain := fs.Uint64("chain", 1, "chain id of the deployed contract")
+	target := fs.String("target", "sourcify", "verification service: sourcify or etherscan")
+	contract := fs.String("contract", "", "contract to verify, Name or path:Name (optional if a single contract has bytecode)")
+	constructorArgs := fs.String("constructor-args", "", "hex encoded ABI encoded constructor arguments (etherscan)")
+	sourcifyURL := fs.String("sourcify-url", verify.DefaultSourcifyURL, "Sourcify server URL")
+	etherscanURL := fs.String("etherscan-url", verify.DefaultEtherscanURL, "Etherscan API URL")
+	apiKey := fs.String("etherscan-api-key", "", "Etherscan API key (defaults to $"+EtherscanAPIKeyEnv+")")
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: solc-go verify --address 0x... [--chain 1] [--target sourcify|etherscan] [flags] [files|dirs]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *address == "" {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+	addr, err := solc.HexToAddress(*address)
+	if err != nil {
+		return err
+	}
+
+	var verifier verify.Verifier
+	switch *target {
+	case "sourcify":
+		verifier = &verify.Sourcify{URL: *sourcifyURL}
+	case "etherscan":
+		if *apiKey == "" {
+			*apiKey = os.Getenv(EtherscanAPIKeyEnv)
+		}
+		verifier = &verify.Etherscan{URL: *etherscanURL, APIKey: *apiKey}
+	default:
+		return fmt.Errorf("unknown target %q", *target)
+	}
+
+	project, err := loadProject(fs, &sf.importFlags, &cf, &sf)
+	if err != nil {
+		return err
+	}
+	paths := sourcePaths(fs.Args(), project)
+	sources, err := loadSources(paths, sf.normalization(), sf.resolver(paths...))
+	if err != nil {
+		return err
+	}
+	compiler, err := cf.compiler(sources)
+	if err != nil {
+		return err
+	}
+	defer compiler.Close()
+
+	input, out, err := compile(e, compiler, sources, sf.settings())
+	if err != nil {
+		return err
+	}
+	name, err := selectContract(out, *contract)
+	if err != nil {
+		return err
+	}
+
+	req, err := verify.NewRequest(addr, *chain, name, compiler.Version(), input, out)
+	if err != nil {
+		return err
+	}
+	req.ConstructorArguments = *constructorArgs
+	res, err := verifier.Verify(e.ctx, req)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(e.stdout, "Verified %v at %v on chain %v (%v)\n", name, addr.Hex(), *chain, res.Status)
+	return nil
+}
+
+// selectContract returns the fully qualified name of the contract of out named name (Name or path:Name),
+// or of the single contract with bytecode if name is empty
+func selectContract(out *solc.Output, name string) (string, error) {
+	var candidates []string
+	for file, contracts := range out.Contracts {
+		for contractName, contract := range contracts {
+			fqName := solc.ContractID{File: file, Name: contractName}.String()
+			switch {
+			case name == "" && contract.EVM.Bytecode.Object != "":
+				candidates = append(candidates, fqName)
+			case name == fqName || name == contractName:
+				candidates = append(candidates, fqName)
+			}
+		}
+	}
+	sort.Strings(candidates)
+
+	switch len(candidates) {
+	case 1:
+		return candidates[0], nil
+	case 0:
+		if name == "" {
+			return "", fmt.Errorf("no contract with bytecode compiled")
+		}
+		return "", fmt.Errorf("contract %q not found", name)
+	default:
+		return "", fmt.Errorf("several contracts match, select one with --contract: %v", strings.Join(candidates, ", "))
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	var body struct {
+		ChosenContract string            `json:"chosenContract"`
+		Files          map[string]string `json:"files"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Write([]byte(`{"result": [{"status": "perfect"}]}`))
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "solc-go-verify")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	source := filepath.Join(dir, "One.sol")
+	require.NoError(t, ioutil.WriteFile(source, []byte("pragma solidity ^0.6.0; interface I {} contract One {} contract Two {}"), 0644))
+
+	args := []string{"verify", "--soljson", soljson, "--sourcify-url", srv.URL, "--address", "0x00000000000000000000000000000000000000aa", "--chain", "5"}
+	code, _, stderr := runCLI("", append(args, source)...)
+	require.Equal(t, 1, code, "Ambiguous contract should fail")
+	assert.Contains(t, stderr, "several contracts match")
+
+	code, stdout, stderr := runCLI("", append(args, "--contract", "Two", source)...)
+	require.Equal(t, 0, code, "solc-go verify should succeed: %v", stderr)
+	assert.Contains(t, stdout, "(perfect)")
+	name := filepath.ToSlash(filepath.Clean(source))
+	assert.Equal(t, name+":Two", body.ChosenContract)
+	assert.Contains(t, body.Files, "metadata.json", "Metadata should be submitted")
+	assert.Contains(t, body.Files, name, "Sources should be submitted")
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	solc "github.com/nmvalera/solc-go"
+)
+
+func init() {
+	register(&command{
+		name:  "versions",
+		usage: "list, download and prune compiler binaries (list|download <range>|prune)",
+		run:   runVersions,
+	})
+}
+
+func runVersions(e *env, args []string) error {
+	fs := flag.NewFlagSet("versions", flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	var cf compilerFlags
+	fs.StringVar(&cf.binDir, "bin-dir", "", binDirUsage)
+	releasesURL := fs.String("releases-url", solc.DefaultReleasesURL, "base URL of the soljson builds (serving list.json)")
+	remote := fs.Bool("remote", false, "list: list releases available for download instead of installed binaries")
+	keep := fs.String("keep", "", "prune: version range to keep (defaults to the latest patch of each minor version)")
+	dryRun := fs.Bool("dry-run", false, "prune: print binaries that would be removed without removing them")
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: solc-go versions [flags] list [range]\n       solc-go versions [flags] download <range>\n       solc-go versions [flags] prune\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	action, rest := fs.Arg(0), fs.Args()
+	if len(rest) > 0 {
+		rest = rest[1:]
+	}
+	constraint := strings.Join(rest, " ")
+	switch action {
+	case "list":
+		if *remote {
+			return listReleases(e, *releasesURL, cf.dir(), constraint)
+		}
+		return listBinaries(e, cf.dir(), constraint)
+	case "download":
+		if constraint == "" {
+			fs.Usage()
+			return flag.ErrHelp
+		}
+		return downloadRelease(e, *releasesURL, cf.dir(), constraint)
+	case "prune":
+		return pruneBinaries(e, cf.dir(), *keep, *dryRun)
+	default:
+		fs.Usage()
+		return flag.ErrHelp
+	}
+}
+
+func listBinaries(e *env, dir, constraint string) error {
+	binaries, err := solc.ListBinaries(dir)
+	if err != nil {
+		return err
+	}
+	for _, binary := range binaries {
+		ok, err := matches(constraint, binary.Version)
+		if err != nil {
+			return err
+		}
+		if ok {
+			fmt.Fprintf(e.stdout, "%v\t%v\n", binary.Version, binary.Path)
+		}
+	}
+	return nil
+}
+
+// listReleases prints available releases, marking with * the ones installed in dir
+func listReleases(e *env, releasesURL, dir, constraint string) error {
+	releases, err := solc.Releases(e.ctx, nil, releasesURL)
+	if err != nil {
+		return err
+	}
+	installed, err := installedVersions(dir)
+	if err != nil {
+		return err
+	}
+	for _, release := range releases {
+		ok, err := matches(constraint, release.Version)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		mark := " "
+		if installed[release.LongVersion] {
+			mark = "*"
+		}
+		fmt.Fprintf(e.stdout, "%v %v\n", mark, release.LongVersion)
+	}
+	return nil
+}
+
+func downloadRelease(e *env, releasesURL, dir, constraint string) error {
+	releases, err := solc.Releases(e.ctx, nil, releasesURL)
+	if err != nil {
+		return err
+	}
+	release, err := solc.ResolveRelease(releases, constraint)
+	if err != nil {
+		return err
+	}
+	installed, err := installedVersions(dir)
+	if err != nil {
+		return err
+	}
+	if installed[release.LongVersion] {
+		fmt.Fprintf(e.stdout, "%v already installed\n", release.LongVersion)
+		return nil
+	}
+
+	binary, err := solc.DownloadRelease(e.ctx, nil, releasesURL, release, dir)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(e.stdout, "Downloaded %v to %v\n", binary.Version, binary.Path)
+	return nil
+}
+
+// pruneBinaries removes interrupted downloads and binaries outside of keep
+func pruneBinaries(e *env, dir, keep string, dryRun bool) error {
+	binaries, err := solc.ListBinaries(dir)
+	if err != nil {
+		return err
+	}
+
+	// By default keep the latest patch of each minor version
+	latest := make(map[string]string)
+	for _, binary := range binaries {
+		short := solc.ShortVersion(binary.Version)
+		latest[short[:strings.LastIndex(short, ".")]] = binary.Version
+	}
+
+	var remove []string
+	for _, binary := range binaries {
+		kept := false
+		if keep != "" {
+			kept, err = solc.MatchVersion(keep, binary.Version)
+			if err != nil {
+				return err
+			}
+		} else {
+			for _, version := range latest {
+				kept = kept || version == binary.Version
+			}
+		}
+		if !kept {
+			remove = append(remove, binary.Path)
+		}
+	}
+	tmps, err := filepath.Glob(filepath.Join(dir, "soljson.*.tmp"))
+	if err != nil {
+		return err
+	}
+	remove = append(remove, tmps...)
+
+	for _, file := range remove {
+		if !dryRun {
+			err = os.Remove(file)
+			if err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(e.stdout, "Removed %v\n", file)
+	}
+	return nil
+}
+
+func installedVersions(dir string) (map[string]bool, error) {
+	binaries, err := solc.ListBinaries(dir)
+	if err != nil {
+		return nil, err
+	}
+	installed := make(map[string]bool)
+	for _, binary := range binaries {
+		installed[binary.Version] = true
+	}
+	return installed, nil
+}
+
+// matches is like solc.MatchVersion but matches every version on empty constraint
+func matches(constraint, version string) (bool, error) {
+	if constraint == "" {
+		return true, nil
+	}
+	return solc.MatchVersion(constraint, version)
+}
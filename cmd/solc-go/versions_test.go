@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/list.json":
+			fmt.Fprint(w, `{"builds": [
+				{"path": "soljson-v0.6.1+commit.e6f7d5a4.js", "version": "0.6.1", "longVersion": "0.6.1+commit.e6f7d5a4"},
+				{"path": "soljson-v0.6.2+commit.bacdbe57.js", "version": "0.6.2", "longVersion": "0.6.2+commit.bacdbe57"}
+			]}`)
+		default:
+			fmt.Fprint(w, "soljson")
+		}
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "solc-go-versions")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	code, stdout, stderr := runCLI("", "versions", "--bin-dir", dir, "--releases-url", srv.URL, "download", "<0.6.2")
+	require.Equal(t, 0, code, "solc-go versions download should succeed: %v", stderr)
+	assert.Contains(t, stdout, "Downloaded 0.6.1+commit.e6f7d5a4")
+
+	code, stdout, _ = runCLI("", "versions", "--bin-dir", dir, "--releases-url", srv.URL, "download", "^0.6.0")
+	require.Equal(t, 0, code)
+	code, stdout, _ = runCLI("", "versions", "--bin-dir", dir, "--releases-url", srv.URL, "download", "0.6.2")
+	require.Equal(t, 0, code)
+	assert.Contains(t, stdout, "already installed")
+
+	code, stdout, _ = runCLI("", "versions", "--bin-dir", dir, "--releases-url", srv.URL, "--remote", "list")
+	require.Equal(t, 0, code)
+	assert.Equal(t, "* 0.6.1+commit.e6f7d5a4\n* 0.6.2+commit.bacdbe57\n", stdout)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "soljson.123.tmp"), nil, 0644))
+	code, stdout, _ = runCLI("", "versions", "--bin-dir", dir, "prune")
+	require.Equal(t, 0, code)
+	assert.Contains(t, stdout, "soljson-v0.6.1+commit.e6f7d5a4.js", "Older patches should be pruned")
+	assert.Contains(t, stdout, "soljson.123.tmp", "Interrupted downloads should be pruned")
+
+	code, stdout, _ = runCLI("", "versions", "--bin-dir", dir, "list")
+	require.Equal(t, 0, code)
+	assert.Equal(t, fmt.Sprintf("0.6.2+commit.bacdbe57\t%v\n", filepath.Join(dir, "soljson-v0.6.2+commit.bacdbe57.js")), stdout)
+
+	code, _, _ = runCLI("", "versions", "--bin-dir", dir, "download")
+	assert.Equal(t, 2, code, "Missing range should print usage")
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	solc "github.com/nmvalera/solc-go"
+)
+
+func init() {
+	register(&command{
+		name:  "watch",
+		usage: "recompile a directory on every change and print diagnostics",
+		run:   runWatch,
+	})
+}
+
+// ANSI escape sequences
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiCyan   = "\x1b[36m"
+)
+
+func runWatch(e *env, args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	var (
+		cf compilerFlags
+		sf settingsFlags
+	)
+	cf.register(fs)
+	sf.register(fs)
+	cacheDir := fs.String("cache-dir", "", "directory persisting outputs between runs (defaults to an in-memory cache)")
+	debounce := fs.Duration("debounce", solc.DefaultDebounce, "delay letting file changes settle before recompiling")
+	noColor := fs.Bool("no-color", false, "disable colors (also disabled by $NO_COLOR or when stdout is not a terminal)")
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: solc-go watch [flags] [dir]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	project, err := loadProject(fs, &sf.importFlags, &cf, &sf)
+	if err != nil {
+		return err
+	}
+	root := "."
+	if paths := sourcePaths(fs.Args(), project); len(paths) > 1 {
+		fs.Usage()
+		return flag.ErrHelp
+	} else if len(paths) == 1 {
+		root = paths[0]
+	}
+
+	// The compiler is selected once from the sources found at start
+	sources, err := solc.LoadDirNormalized(root, sf.normalization())
+	if err != nil {
+		return err
+	}
+	compiler, err := cf.compiler(sources)
+	if err != nil {
+		return err
+	}
+	defer compiler.Close()
+
+	var cache solc.Cache = solc.NewMemoryCache(16)
+	if *cacheDir != "" {
+		cache, err = solc.NewBuildCache(*cacheDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	color := !*noColor && useColor(e.stdout)
+	w := &solc.Watcher{
+		Solc:          compiler,
+		Settings:      sf.settings(),
+		Cache:         cache,
+		Debounce:      *debounce,
+		Normalization: sf.normalization(),
+		Resolver:      sf.resolver(root),
+	}
+	fmt.Fprintf(e.stdout, "Watching %v with %v\n", root, solc.LongVersion(compiler.Version()))
+	err = w.Watch(e.ctx, root, func(out *solc.Output, err error, elapsed time.Duration) {
+		printResult(e.stdout, out, err, elapsed, color)
+	})
+	if err == e.ctx.Err() {
+		return nil
+	}
+	return err
+}
+
+// printResult prints the diagnostics and a summary of a compilation
+func printResult(w io.Writer, out *solc.Output, err error, elapsed time.Duration, color bool) {
+	paint := func(style, s string) string {
+		if !color {
+			return s
+		}
+		return style + s + ansiReset
+	}
+
+	clock := paint(ansiCyan, time.Now().Format("15:04:05"))
+	if err != nil {
+		fmt.Fprintf(w, "%v %v %v\n", clock, paint(ansiBold+ansiRed, "failed:"), err)
+		return
+	}
+
+	var errors, warnings int
+	for _, diag := range out.Errors {
+		style := ansiYellow
+		if diag.Severity == "error" {
+			style = ansiRed
+			errors++
+		} else {
+			warnings++
+		}
+
+		msg := diag.FormattedMessage
+		if msg == "" {
+			msg = diag.Message
+		}
+		lines := strings.SplitN(strings.TrimRight(msg, "\n"), "\n", 2)
+		fmt.Fprintln(w, paint(ansiBold+style, lines[0]))
+		if len(lines) > 1 {
+			fmt.Fprintln(w, lines[1])
+		}
+	}
+
+	var contracts int
+	for _, c := range out.Contracts {
+		contracts += len(c)
+	}
+	summary := paint(ansiGreen, fmt.Sprintf("compiled %v contracts", contracts))
+	if errors > 0 {
+		summary = paint(ansiRed, "compilation failed")
+	}
+	fmt.Fprintf(w, "%v %v (%v errors, %v warnings) in %v\n", clock, summary, errors, warnings, elapsed.Round(time.Millisecond))
+}
+
+// useColor indicates whether w is a terminal and colors are not disabled by $NO_COLOR
+func useColor(w io.Writer) bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
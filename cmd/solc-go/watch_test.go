@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	solc "github.com/nmvalera/solc-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use
+type syncBuffer struct {
+	mux sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	return b.buf.String()
+}
+
+func TestWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "solc-go-watch")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	libs, err := ioutil.TempDir("", "solc-go-watch-libs")
+	require.NoError(t, err)
+	defer os.RemoveAll(libs)
+	require.NoError(t, os.MkdirAll(filepath.Join(libs, "lib"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(libs, "lib", "L.sol"), []byte("pragma solidity ^0.6.0; library L {}"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "One.sol"), []byte(`pragma solidity ^0.6.0; import "lib/L.sol"; contract One {}`), 0644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var stdout, stderr syncBuffer
+	done := make(chan int)
+	go func() {
+		done <- run([]string{"watch", "--soljson", soljson, "--debounce", "10ms", "--include-path", libs, dir}, &env{ctx: ctx, stdout: &stdout, stderr: &stderr})
+	}()
+
+	waitFor := func(s string) {
+		deadline := time.Now().Add(30 * time.Second)
+		for !strings.Contains(stdout.String(), s) && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		require.Contains(t, stdout.String(), s)
+	}
+	waitFor("compiled 2 contracts (0 errors, 0 warnings)")
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "One.sol"), []byte("pragma solidity ^0.6.0; contract One {"), 0644))
+	waitFor("compilation failed (1 errors, 0 warnings)")
+	assert.Contains(t, stdout.String(), "ParserError", "Diagnostics should be printed")
+	assert.NotContains(t, stdout.String(), ansiReset, "Colors should be disabled when not writing to a terminal")
+
+	cancel()
+	assert.Equal(t, 0, <-done, "Interrupted watch should succeed: %v", stderr.String())
+}
+
+func TestPrintResult(t *testing.T) {
+	var buf bytes.Buffer
+	printResult(&buf, &solc.Output{Errors: []solc.Error{{Severity: "warning", FormattedMessage: "One.sol:1:1: Warning: unused\ncontract One {}\n"}}}, nil, time.Second, true)
+	assert.Contains(t, buf.String(), ansiBold+ansiYellow+"One.sol:1:1: Warning: unused"+ansiReset+"\ncontract One {}\n", "Diagnostics should be colored by severity")
+	assert.Contains(t, buf.String(), ansiGreen+"compiled 0 contracts"+ansiReset)
+}
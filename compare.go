@@ -0,0 +1,292 @@
+package solc
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ContractChange is how a contract changed between two builds
+type ContractChange string
+
+const (
+	// ContractAdded and ContractRemoved contracts are only in the new or the old build
+	ContractAdded   ContractChange = "added"
+	ContractRemoved ContractChange = "removed"
+
+	// ContractChanged contracts have other bytecode (metadata aside) or ABI
+	ContractChanged ContractChange = "changed"
+
+	// ContractUnchanged contracts are functionally identical, their metadata may differ
+	ContractUnchanged ContractChange = "unchanged"
+)
+
+// BuildComparison compares two builds (see CompareBuilds)
+type BuildComparison struct {
+	// Settings are the differences of compiler version, language and settings
+	Settings []SettingChange `json:"settings,omitempty"`
+
+	// Contracts are the contracts of both builds, sorted by name
+	Contracts []ContractComparison `json:"contracts"`
+}
+
+// SettingChange is a setting differing between two builds, values are JSON encoded and empty if unset
+type SettingChange struct {
+	// Path is the path of the setting in the build-info (e.g. settings.optimizer.runs)
+	Path string `json:"path"`
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new,omitempty"`
+}
+
+// ContractComparison compares a contract between two builds
+type ContractComparison struct {
+	// Contract is the fully qualified name of the contract (path:Name)
+	Contract string         `json:"contract"`
+	Change   ContractChange `json:"change"`
+
+	// BytecodeChanged is set if the creation or deployed bytecode differs once the metadata appended by
+	// solc is stripped, MetadataChanged if the metadata differs (e.g. after changing comments only).
+	// The metadata of contracts embedded with new is part of the bytecode
+	BytecodeChanged bool `json:"bytecodeChanged,omitempty"`
+	MetadataChanged bool `json:"metadataChanged,omitempty"`
+
+	// Size and InitSize are the sizes of the new build (see ContractSize), the deltas new minus old
+	Size          int `json:"size"`
+	InitSize      int `json:"initSize"`
+	SizeDelta     int `json:"sizeDelta"`
+	InitSizeDelta int `json:"initSizeDelta"`
+
+	// AddedABI and RemovedABI are the ABI entries of the new build missing from the old one and
+	// conversely (e.g. "function transfer(address,uint256) nonpayable returns (bool)"), compared canonically
+	AddedABI   []string `json:"addedABI,omitempty"`
+	RemovedABI []string `json:"removedABI,omitempty"`
+}
+
+// Changed returns whether the contract is not functionally identical in both builds
+func (c ContractComparison) Changed() bool {
+	return c.Change != ContractUnchanged
+}
+
+// Unchanged returns whether every contract is functionally identical in both builds: same contracts,
+// bytecodes (metadata aside) and ABIs. Settings may differ
+func (c *BuildComparison) Unchanged() bool {
+	for _, contract := range c.Contracts {
+		if contract.Changed() {
+			return false
+		}
+	}
+	return true
+}
+
+// Changed returns the comparisons of the contracts not functionally identical in both builds
+func (c *BuildComparison) Changed() []ContractComparison {
+	var changed []ContractComparison
+	for _, contract := range c.Contracts {
+		if contract.Changed() {
+			changed = append(changed, contract)
+		}
+	}
+	return changed
+}
+
+// CompareBuilds compares the settings and contracts of two builds, e.g. before and after a refactoring
+//
+// Bytecodes require the evm.bytecode.object and evm.deployedBytecode.object outputs, ABI deltas the abi one
+func CompareBuilds(old, new *BuildInfo) (*BuildComparison, error) {
+	c := &BuildComparison{}
+
+	oldSettings, err := buildSettings(old)
+	if err != nil {
+		return nil, err
+	}
+	newSettings, err := buildSettings(new)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for path := range oldSettings {
+		paths = append(paths, path)
+	}
+	for path := range newSettings {
+		if _, ok := oldSettings[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if oldSettings[path] != newSettings[path] {
+			c.Settings = append(c.Settings, SettingChange{Path: path, Old: oldSettings[path], New: newSettings[path]})
+		}
+	}
+
+	oldContracts, newContracts := buildContracts(old), buildContracts(new)
+	var names []string
+	for name := range oldContracts {
+		names = append(names, name)
+	}
+	for name := range newContracts {
+		if _, ok := oldContracts[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		comparison, err := compareContracts(name, oldContracts[name], newContracts[name])
+		if err != nil {
+			return nil, err
+		}
+		c.Contracts = append(c.Contracts, comparison)
+	}
+	return c, nil
+}
+
+// buildSettings returns the compiler version, language and settings of a build by path
+func buildSettings(info *BuildInfo) (map[string]string, error) {
+	settings := map[string]string{}
+	if info.SolcLongVersion != "" {
+		settings["solcLongVersion"] = fmt.Sprintf("%q", info.SolcLongVersion)
+	}
+	if info.Input == nil {
+		return settings, nil
+	}
+	if info.Input.Language != "" {
+		settings["language"] = fmt.Sprintf("%q", info.Input.Language)
+	}
+	b, err := json.Marshal(info.Input.Settings)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	err = json.Unmarshal(b, &v)
+	if err != nil {
+		return nil, err
+	}
+	flattenJSON("settings", v, settings)
+	return settings, nil
+}
+
+// flattenJSON sets the JSON encoded value of every leaf of v into values, by path
+func flattenJSON(path string, v interface{}, values map[string]string) {
+	if object, ok := v.(map[string]interface{}); ok && len(object) > 0 {
+		for key, value := range object {
+			flattenJSON(path+"."+key, value, values)
+		}
+		return
+	}
+	b, _ := json.Marshal(v)
+	values[path] = string(b)
+}
+
+func buildContracts(info *BuildInfo) map[string]*Contract {
+	contracts := map[string]*Contract{}
+	if info.Output == nil {
+		return contracts
+	}
+	for file, fileContracts := range info.Output.Contracts {
+		for name := range fileContracts {
+			contract := fileContracts[name]
+			contracts[ContractID{File: file, Name: name}.String()] = &contract
+		}
+	}
+	return contracts
+}
+
+func compareContracts(name string, old, new *Contract) (ContractComparison, error) {
+	c := ContractComparison{Contract: name, Change: ContractUnchanged}
+	switch {
+	case old == nil:
+		c.Change, old = ContractAdded, &Contract{}
+	case new == nil:
+		c.Change, new = ContractRemoved, &Contract{}
+	}
+
+	c.Size, c.InitSize = len(new.EVM.DeployedBytecode.Object)/2, len(new.EVM.Bytecode.Object)/2
+	c.SizeDelta = c.Size - len(old.EVM.DeployedBytecode.Object)/2
+	c.InitSizeDelta = c.InitSize - len(old.EVM.Bytecode.Object)/2
+
+	for _, codes := range [][2]string{
+		{old.EVM.Bytecode.Object, new.EVM.Bytecode.Object},
+		{old.EVM.DeployedBytecode.Object, new.EVM.DeployedBytecode.Object},
+	} {
+		oldCode, oldMetadata := splitMetadata(codes[0])
+		newCode, newMetadata := splitMetadata(codes[1])
+		c.BytecodeChanged = c.BytecodeChanged || oldCode != newCode
+		c.MetadataChanged = c.MetadataChanged || oldMetadata != newMetadata
+	}
+
+	oldABI, err := abiEntries(old.ABI)
+	if err != nil {
+		return c, fmt.Errorf("solc: invalid ABI of %v: %v", name, err)
+	}
+	newABI, err := abiEntries(new.ABI)
+	if err != nil {
+		return c, fmt.Errorf("solc: invalid ABI of %v: %v", name, err)
+	}
+	for _, entry := range newABI {
+		if !contains(oldABI, entry) {
+			c.AddedABI = append(c.AddedABI, entry)
+		}
+	}
+	for _, entry := range oldABI {
+		if !contains(newABI, entry) {
+			c.RemovedABI = append(c.RemovedABI, entry)
+		}
+	}
+
+	if c.Change == ContractUnchanged && (c.BytecodeChanged || len(c.AddedABI) > 0 || len(c.RemovedABI) > 0) {
+		c.Change = ContractChanged
+	}
+	return c, nil
+}
+
+// splitMetadata splits hex code into the code and the metadata appended by solc, if any
+//
+// Unlinked library placeholders are not hex, such code is kept whole
+func splitMetadata(code string) (string, string) {
+	b, err := hex.DecodeString(code)
+	if err != nil {
+		return code, ""
+	}
+	if m, err := ParseBytecodeMetadata(b); err != nil || m == nil {
+		return code, ""
+	}
+	n := 2 * (int(binary.BigEndian.Uint16(b[len(b)-2:])) + 2)
+	return code[:len(code)-n], code[len(code)-n:]
+}
+
+// abiEntries returns the canonical entries of an ABI as strings (e.g. "event Transfer(address,address,uint256)")
+func abiEntries(abi []json.RawMessage) ([]string, error) {
+	parsed, err := ParseABI(abi)
+	if err != nil {
+		return nil, err
+	}
+	canonical, _ := CanonicalizeABI(parsed)
+	entries := make([]string, len(canonical))
+	for i, entry := range canonical {
+		s := entry.Type
+		switch entry.Type {
+		case "constructor":
+			s += entry.Signature()
+		case "function", "event", "error":
+			s += " " + entry.Signature()
+		}
+		if entry.Anonymous {
+			s += " anonymous"
+		}
+		if entry.StateMutability != "" {
+			s += " " + entry.StateMutability
+		}
+		if len(entry.Outputs) > 0 {
+			types := make([]string, len(entry.Outputs))
+			for j, output := range entry.Outputs {
+				types[j] = output.CanonicalType()
+			}
+			s += " returns (" + strings.Join(types, ",") + ")"
+		}
+		entries[i] = s
+	}
+	return entries, nil
+}
@@ -0,0 +1,75 @@
+package solc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareBuilds(t *testing.T) {
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err, "Creating compiler should not error")
+	defer solc.Close()
+
+	build := func(sources map[string]string) *BuildInfo {
+		in := NewInput().SetOutputSelection(SelectAll("abi", "evm.bytecode.object", "evm.deployedBytecode.object"))
+		for name, content := range sources {
+			in.AddSource(name, content)
+		}
+		out, err := solc.Compile(in)
+		require.NoError(t, err, "Compile should not error")
+		require.Empty(t, out.Errors, "Sources should compile")
+		info, err := NewBuildInfo(solc.Version(), in, out)
+		require.NoError(t, err, "NewBuildInfo should not error")
+		return info
+	}
+	old := build(map[string]string{
+		"A.sol": "pragma solidity ^0.6.0;\ncontract A { function f() external pure returns (uint) { return 1; } }",
+		"B.sol": "pragma solidity ^0.6.0;\n// B\ncontract B {}",
+		"D.sol": "pragma solidity ^0.6.0;\ncontract D {}",
+	})
+	new := build(map[string]string{
+		"A.sol": "pragma solidity ^0.6.0;\ncontract A { function f() external pure returns (uint) { return 1; } function g(address) external {} }",
+		"B.sol": "pragma solidity ^0.6.0;\n// Refactored B\ncontract B {}",
+		"C.sol": "pragma solidity ^0.6.0;\ncontract C {}",
+	})
+
+	c, err := CompareBuilds(old, new)
+	require.NoError(t, err, "CompareBuilds should not error")
+	assert.Empty(t, c.Settings, "Settings should not differ")
+	assert.False(t, c.Unchanged())
+	require.Len(t, c.Contracts, 4)
+
+	a := c.Contracts[0]
+	assert.Equal(t, "A.sol:A", a.Contract)
+	assert.Equal(t, ContractChanged, a.Change)
+	assert.True(t, a.BytecodeChanged, "A bytecode should change")
+	assert.True(t, a.SizeDelta > 0, "A should grow")
+	assert.Equal(t, []string{"function g(address) nonpayable"}, a.AddedABI)
+	assert.Empty(t, a.RemovedABI)
+
+	b := c.Contracts[1]
+	assert.Equal(t, "B.sol:B", b.Contract)
+	assert.Equal(t, ContractUnchanged, b.Change, "Changing comments should not change B")
+	assert.False(t, b.BytecodeChanged)
+	assert.True(t, b.MetadataChanged, "Changing comments should change the metadata")
+	assert.Zero(t, b.SizeDelta)
+
+	assert.Equal(t, ContractAdded, c.Contracts[2].Change, "C should be added")
+	assert.Equal(t, ContractRemoved, c.Contracts[3].Change, "D should be removed")
+	assert.Equal(t, 0, c.Contracts[3].Size)
+	assert.Len(t, c.Changed(), 3)
+
+	same, err := CompareBuilds(old, old)
+	require.NoError(t, err, "CompareBuilds should not error")
+	assert.True(t, same.Unchanged(), "Build should not differ from itself")
+
+	tuned := *old
+	input := *old.Input
+	input.SetOptimizer(true, 1000)
+	tuned.Input = &input
+	c, err = CompareBuilds(old, &tuned)
+	require.NoError(t, err, "CompareBuilds should not error")
+	assert.Equal(t, []SettingChange{{Path: "settings.optimizer.runs", Old: "200", New: "1000"}}, c.Settings)
+}
@@ -0,0 +1,98 @@
+package solc
+
+import (
+	"io/ioutil"
+	"path/filepath"
+)
+
+// CompileOption customizes the input compiled by CompileSource and CompileFiles
+type CompileOption func(*compileConfig)
+
+type compileConfig struct {
+	input    *Input
+	resolver ImportResolver
+}
+
+// WithOptimizer sets whether the optimizer is enabled and its runs (the optimizer is enabled with 200 runs by default)
+func WithOptimizer(enabled bool, runs int) CompileOption {
+	return func(c *compileConfig) {
+		c.input.SetOptimizer(enabled, runs)
+	}
+}
+
+// WithEVMVersion sets the target EVM version
+func WithEVMVersion(version string) CompileOption {
+	return func(c *compileConfig) {
+		c.input.SetEVMVersion(version)
+	}
+}
+
+// WithOutputs replaces the outputs selected for every contract
+func WithOutputs(outputs ...string) CompileOption {
+	return func(c *compileConfig) {
+		c.input.SetOutputSelection(SelectAll(outputs...))
+	}
+}
+
+// WithImportResolver resolves the imports missing from the compiled sources with resolver
+func WithImportResolver(resolver ImportResolver) CompileOption {
+	return func(c *compileConfig) {
+		c.resolver = resolver
+	}
+}
+
+// CompileSource compiles a single source named name
+//
+// The input is the one of NewInput unless opts say otherwise.
+// Compilation errors are reported in the Errors of the output
+func CompileSource(solc Solc, name, content string, opts ...CompileOption) (*Output, error) {
+	return compileDefault(solc, map[string]SourceIn{name: {Content: content}}, opts)
+}
+
+// CompileFiles compiles the files at paths, resolving their imports from the working directory
+//
+// Source unit names are the slash separated paths, the input is the one of CompileSource
+func CompileFiles(solc Solc, paths ...string) (*Output, error) {
+	sources := make(map[string]SourceIn)
+	for _, p := range paths {
+		content, err := ioutil.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		sources[filepath.ToSlash(filepath.Clean(p))] = SourceIn{Content: string(content)}
+	}
+	return compileDefault(solc, sources, []CompileOption{WithImportResolver(fileResolver)})
+}
+
+// fileResolver reads imports as paths, relative to the working directory unless absolute
+var fileResolver = ImportResolverFunc(func(path string) (string, error) {
+	content, err := ioutil.ReadFile(filepath.FromSlash(path))
+	return string(content), err
+})
+
+func compileDefault(solc Solc, sources map[string]SourceIn, opts []CompileOption) (*Output, error) {
+	c := &compileConfig{input: NewInput()}
+	c.input.Sources = sources
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.resolver != nil {
+		graph, err := DependencyGraph(sources, c.resolver)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range graph.Nodes {
+			if _, ok := sources[name]; ok {
+				continue
+			}
+			content, err := c.resolver.Resolve(name)
+			if err != nil {
+				return nil, err
+			}
+			sources[name] = SourceIn{Content: content}
+		}
+	}
+
+	return solc.Compile(c.input)
+}
@@ -0,0 +1,60 @@
+package solc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileSource(t *testing.T) {
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err, "Creating Solc from valid solc emscripten binary should not error")
+	defer solc.Close()
+
+	out, err := CompileSource(solc, "One.sol", "pragma solidity ^0.6.1; contract One { function one() public pure returns (uint) { return 1; } }")
+	require.NoError(t, err, "CompileSource should not error")
+	require.Empty(t, out.Errors, "One.sol should compile")
+	one := out.Contracts["One.sol"]["One"]
+	assert.Len(t, one.ABI, 1, "ABI should be selected")
+	assert.NotEmpty(t, one.EVM.Bytecode.Object, "Bytecode should be selected")
+	assert.NotEmpty(t, one.EVM.DeployedBytecode.Object, "Deployed bytecode should be selected")
+	assert.Equal(t, "901717d1", one.EVM.MethodIdentifiers["one()"], "Method identifiers should be selected")
+	assert.Empty(t, one.Metadata, "Metadata should not be selected")
+
+	out, err = CompileSource(solc, "One.sol", "pragma solidity ^0.6.1; contract One {}", WithOutputs("abi"), WithOptimizer(false, 0))
+	require.NoError(t, err, "CompileSource should not error")
+	assert.Empty(t, out.Contracts["One.sol"]["One"].EVM.Bytecode.Object, "Bytecode should not be selected")
+
+	out, err = CompileSource(solc, "One.sol", "pragma solidity ^0.6.1; import \"./Two.sol\"; contract One is Two {}", WithImportResolver(SourcesResolver{
+		"Two.sol": {Content: "pragma solidity ^0.6.1; contract Two {}"},
+	}))
+	require.NoError(t, err, "CompileSource should not error")
+	require.Empty(t, out.Errors, "Imports should be resolved")
+	assert.Contains(t, out.Contracts, "Two.sol", "Imported source should be compiled")
+}
+
+func TestCompileFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "solc-compile")
+	require.NoError(t, err, "TempDir should not error")
+	defer os.RemoveAll(dir)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "One.sol"), []byte("pragma solidity ^0.6.1; import \"./Two.sol\"; contract One is Two {}"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "Two.sol"), []byte("pragma solidity ^0.6.1; contract Two {}"), 0644))
+
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err, "Creating Solc from valid solc emscripten binary should not error")
+	defer solc.Close()
+
+	one := filepath.ToSlash(filepath.Join(dir, "One.sol"))
+	out, err := CompileFiles(solc, filepath.Join(dir, "One.sol"))
+	require.NoError(t, err, "CompileFiles should not error")
+	require.Empty(t, out.Errors, "Files should compile")
+	assert.NotEmpty(t, out.Contracts[one]["One"].EVM.Bytecode.Object, "One should be compiled")
+	assert.NotEmpty(t, out.Contracts[filepath.ToSlash(filepath.Join(dir, "Two.sol"))]["Two"].EVM.Bytecode.Object, "Imported Two should be compiled")
+
+	_, err = CompileFiles(solc, filepath.Join(dir, "Missing.sol"))
+	assert.Error(t, err, "Missing file should error")
+}
@@ -0,0 +1,90 @@
+package solc
+
+import (
+	"context"
+	"time"
+)
+
+// ProfileCompile compiles in like CompileContext and attaches to the output its profile with the duration
+// of each compiler step
+//
+// Compilers do not report their steps, so they are timed by compiling in up to 3 more times: stopping
+// after parsing (for compilers supporting Settings.StopAfter), without output selected (analysis only),
+// and with the optimizer disabled, each step taking the difference with the previous one. Steps of the
+// compilation of in are then estimates, all the more noisy as it is short. Compilers created
+// WithProfiling time the compiler calls alone, others time whole compilations. Compilers serving
+// outputs from cache (see WithCache) should not be profiled
+func ProfileCompile(ctx context.Context, solc Solc, in *Input) (*Output, error) {
+	timed := func(in *Input) (*Output, time.Duration, error) {
+		start := time.Now()
+		out, err := CompileContext(ctx, solc, in)
+		if err != nil {
+			return nil, 0, err
+		}
+		elapsed := time.Since(start)
+		if out.Profile != nil {
+			elapsed = out.Profile.Call
+		}
+		return out, elapsed, nil
+	}
+	steps := make(map[CompilerStep]time.Duration)
+
+	parsing := *in
+	parsing.Settings.OutputSelection = nil
+	parsing.Settings.StopAfter = "parsing"
+	out, parsed, err := timed(&parsing)
+	if err != nil {
+		return nil, err
+	}
+	if rejectsStopAfter(out) {
+		parsed = 0
+	} else {
+		steps[StepParsing] = parsed
+	}
+
+	analysis := parsing
+	analysis.Settings.StopAfter = ""
+	_, analyzed, err := timed(&analysis)
+	if err != nil {
+		return nil, err
+	}
+	steps[StepAnalysis] = nonNegative(analyzed - parsed)
+
+	generated := analyzed
+	if in.Settings.Optimizer.Enabled {
+		unoptimized := *in
+		unoptimized.Settings.Optimizer.Enabled = false
+		_, generated, err = timed(&unoptimized)
+		if err != nil {
+			return nil, err
+		}
+		steps[StepCodegen] = nonNegative(generated - analyzed)
+	}
+
+	out, compiled, err := timed(in)
+	if err != nil {
+		return nil, err
+	}
+	if in.Settings.Optimizer.Enabled {
+		steps[StepOptimizer] = nonNegative(compiled - generated)
+	} else {
+		steps[StepCodegen] = nonNegative(compiled - analyzed)
+	}
+
+	profile := CompileProfile{Call: compiled}
+	if out.Profile != nil {
+		profile = *out.Profile
+	}
+	profile.Steps = steps
+	// Outputs may be shared (e.g. by caches), the profile is attached to a copy
+	profiled := *out
+	profiled.Profile = &profile
+	return &profiled, nil
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
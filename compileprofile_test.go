@@ -0,0 +1,94 @@
+package solc
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// steppedSolc reports compiler calls lasting longer the further inputs go through the compiler steps
+type steppedSolc struct {
+	fakeSolc
+	rejectStopAfter bool
+}
+
+func (s *steppedSolc) Compile(in *Input) (*Output, error) {
+	s.compiles++
+	out := &Output{}
+	switch {
+	case in.Settings.StopAfter != "" && s.rejectStopAfter:
+		out.Errors = []Error{{Type: "JSONError", Severity: "error", Message: `Unknown key "stopAfter"`}}
+		out.Profile = &CompileProfile{Call: time.Millisecond}
+	case in.Settings.StopAfter != "":
+		out.Profile = &CompileProfile{Call: 10 * time.Millisecond}
+	case len(in.Settings.OutputSelection) == 0:
+		out.Profile = &CompileProfile{Call: 30 * time.Millisecond}
+	case !in.Settings.Optimizer.Enabled:
+		out.Profile = &CompileProfile{Call: 60 * time.Millisecond}
+	default:
+		out.Contracts = map[string]map[string]Contract{"A.sol": {"A": {}}}
+		out.Profile = &CompileProfile{Marshal: time.Millisecond, Call: 100 * time.Millisecond}
+	}
+	return out, nil
+}
+
+func TestProfileCompile(t *testing.T) {
+	in := NewInput().AddSource("A.sol", "contract A {}").SetOutputSelection(SelectAll("abi"))
+	solc := &steppedSolc{}
+	out, err := ProfileCompile(context.Background(), solc, in)
+	require.NoError(t, err, "ProfileCompile should not error")
+	assert.Contains(t, out.Contracts, "A.sol", "Output of the input should be returned")
+	assert.Equal(t, 4, solc.compiles)
+	assert.Equal(t, map[CompilerStep]time.Duration{
+		StepParsing:   10 * time.Millisecond,
+		StepAnalysis:  20 * time.Millisecond,
+		StepCodegen:   30 * time.Millisecond,
+		StepOptimizer: 40 * time.Millisecond,
+	}, out.Profile.Steps)
+	assert.Equal(t, time.Millisecond, out.Profile.Marshal, "Profile of the compilation should be kept")
+	assert.Equal(t, "marshal 1ms, transfer 0s, call 100ms (parsing 10ms, analysis 20ms, codegen 30ms, optimizer 40ms), unmarshal 0s", out.Profile.String())
+	assert.Equal(t, SelectAll("abi"), in.Settings.OutputSelection, "Input should not be modified")
+
+	in.Settings.Optimizer.Enabled = false
+	solc = &steppedSolc{rejectStopAfter: true}
+	out, err = ProfileCompile(context.Background(), solc, in)
+	require.NoError(t, err, "ProfileCompile should not error")
+	assert.Equal(t, 3, solc.compiles, "Unoptimized compilations should not be compiled twice")
+	assert.Equal(t, map[CompilerStep]time.Duration{
+		StepAnalysis: 30 * time.Millisecond,
+		StepCodegen:  30 * time.Millisecond,
+	}, out.Profile.Steps, "Analysis should include parsing for compilers not stopping after it")
+}
+
+func TestWithProfiling(t *testing.T) {
+	src, err := ioutil.ReadFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err)
+	in := NewInput().AddSource("A.sol", "pragma solidity ^0.6.0;\ncontract A { function f() public pure returns (uint) { return 1; } }").SetOutputSelection(SelectAll("abi", "evm.bytecode"))
+
+	solc, err := new(string(src))
+	require.NoError(t, err, "Creating Solc should not error")
+	defer solc.Close()
+	out, err := solc.Compile(in)
+	require.NoError(t, err, "Compile should not error")
+	assert.Nil(t, out.Profile, "Compilations should only be profiled WithProfiling")
+
+	profiling, err := new(string(src), WithProfiling())
+	require.NoError(t, err, "Creating Solc should not error")
+	defer profiling.Close()
+	out, err = profiling.Compile(in)
+	require.NoError(t, err, "Compile should not error")
+	require.NotNil(t, out.Profile, "Compilation should be profiled")
+	assert.True(t, out.Profile.Call > 0, "Compiler call should be timed")
+	assert.True(t, out.Profile.Marshal > 0 && out.Profile.Transfer > 0 && out.Profile.Unmarshal > 0)
+	assert.Equal(t, out.Profile.Marshal+out.Profile.Transfer+out.Profile.Call+out.Profile.Unmarshal, out.Profile.Total())
+
+	out, err = ProfileCompile(context.Background(), profiling, in)
+	require.NoError(t, err, "ProfileCompile should not error")
+	assert.NotEmpty(t, out.Contracts["A.sol"]["A"].EVM.Bytecode.Object)
+	assert.Contains(t, out.Profile.Steps, StepOptimizer, "Optimizer should be timed")
+	assert.Contains(t, out.Profile.Steps, StepCodegen)
+}
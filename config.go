@@ -0,0 +1,185 @@
+package solc
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nmvalera/solc-go/types"
+	"gopkg.in/yaml.v2"
+)
+
+// ProjectConfigFile is the name of the project configuration file
+const ProjectConfigFile = "solc-go.yaml"
+
+// ErrNoProjectConfig is returned by LoadProjectConfig when the directory holds no configuration file
+var ErrNoProjectConfig = errors.New("solc: no " + ProjectConfigFile + " project configuration")
+
+// ProjectConfig is a project configuration file (solc-go.yaml)
+//
+//	version: ^0.6.0
+//	sources: [contracts]
+//	optimizer: {enabled: true, runs: 200}
+//	evmVersion: istanbul
+//	remappings: ["@openzeppelin/=node_modules/@openzeppelin/"]
+//	includePaths: [node_modules]
+//	artifacts: {dir: artifacts, format: hardhat}
+//
+// Paths are relative to the directory of the file
+type ProjectConfig struct {
+	// Version is a compiler version constraint, it defaults to the version pinned in the directory of the
+	// file (see PinnedVersion), then to the pragmas of the sources
+	Version string `yaml:"version"`
+
+	// Sources are the files and directories compiled
+	Sources []string `yaml:"sources"`
+
+	Optimizer struct {
+		Enabled bool `yaml:"enabled"`
+		Runs    int  `yaml:"runs"`
+	} `yaml:"optimizer"`
+	EVMVersion string `yaml:"evmVersion"`
+	ViaIR      bool   `yaml:"viaIR"`
+
+	Remappings []string `yaml:"remappings"`
+
+	// IncludePaths are the directories imports are resolved from when not found relative to the project
+	IncludePaths []string `yaml:"includePaths"`
+
+	// AllowPaths are the directories, other than the project and include paths, imports may read files from
+	AllowPaths []string `yaml:"allowPaths"`
+
+	// OutputSelection is the standard JSON output selection
+	OutputSelection map[string]map[string][]string `yaml:"outputSelection"`
+
+	Artifacts struct {
+		Dir    string `yaml:"dir"`
+		Format string `yaml:"format"`
+	} `yaml:"artifacts"`
+
+	// StripBOM and NormalizeLineEndings normalize the contents of the sources loaded (see SourceNormalization)
+	StripBOM             bool `yaml:"stripBOM"`
+	NormalizeLineEndings bool `yaml:"normalizeLineEndings"`
+
+	// PreserveSourceNames keeps paths and remappings as written, instead of normalizing backslashes (see
+	// NormalizeSourceName) so configurations written on Windows build the same elsewhere
+	PreserveSourceNames bool `yaml:"preserveSourceNames"`
+
+	// Dir is the directory of the configuration file
+	Dir string `yaml:"-"`
+}
+
+// LoadProjectConfig reads the solc-go.yaml file of dir
+func LoadProjectConfig(dir string) (*ProjectConfig, error) {
+	return ReadProjectConfig(filepath.Join(dir, ProjectConfigFile))
+}
+
+// ReadProjectConfig reads a project configuration file
+func ReadProjectConfig(file string) (*ProjectConfig, error) {
+	b, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w in %v", ErrNoProjectConfig, filepath.Dir(file))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	config := &ProjectConfig{Dir: filepath.Dir(file)}
+	err = yaml.UnmarshalStrict(b, config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %v: %w", file, err)
+	}
+	_, err = config.ParsedRemappings()
+	if err != nil {
+		return nil, fmt.Errorf("invalid %v: %w", file, err)
+	}
+	if !config.PreserveSourceNames {
+		for i, r := range config.Remappings {
+			config.Remappings[i] = types.NormalizeRemapping(r)
+		}
+	}
+	if config.Version == "" {
+		config.Version, err = PinnedVersion(config.Dir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return config, nil
+}
+
+// Path returns path relative to the directory of the configuration file
+func (c *ProjectConfig) Path(path string) string {
+	if !c.PreserveSourceNames {
+		path = filepath.FromSlash(strings.Replace(path, `\`, "/", -1))
+	}
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(c.Dir, path)
+}
+
+// ParsedRemappings returns the remappings of the configuration
+func (c *ProjectConfig) ParsedRemappings() ([]Remapping, error) {
+	remappings := make([]Remapping, len(c.Remappings))
+	for i, s := range c.Remappings {
+		r, err := ParseRemapping(s)
+		if err != nil {
+			return nil, err
+		}
+		remappings[i] = r
+	}
+	return remappings, nil
+}
+
+// Settings returns the compilation settings of the configuration
+//
+// Remappings are not included as sources are expected to be loaded with Resolver
+func (c *ProjectConfig) Settings() Settings {
+	runs := c.Optimizer.Runs
+	if runs == 0 {
+		runs = DefaultOptimizerRuns
+	}
+	return Settings{
+		Optimizer:       Optimizer{Enabled: c.Optimizer.Enabled, Runs: runs},
+		EVMVersion:      c.EVMVersion,
+		ViaIR:           c.ViaIR,
+		OutputSelection: c.OutputSelection,
+	}
+}
+
+// SourceNormalization returns the normalization of the contents of sources set by the configuration
+func (c *ProjectConfig) SourceNormalization() SourceNormalization {
+	normalization := NormalizeNone
+	if c.StripBOM {
+		normalization |= StripBOM
+	}
+	if c.NormalizeLineEndings {
+		normalization |= NormalizeLineEndings
+	}
+	return normalization
+}
+
+// Resolver returns a resolver loading imports from the project directory then from include paths, after remapping
+//
+// Imports may only read files from the project directory, include paths and allow paths (see FSResolver).
+// They are normalized (see NormalizeResolver) unless PreserveSourceNames is set
+func (c *ProjectConfig) Resolver() ImportResolver {
+	resolver := NewFSResolver(c.Dir)
+	for _, dir := range c.IncludePaths {
+		resolver.IncludePaths = append(resolver.IncludePaths, c.Path(dir))
+	}
+	for _, dir := range c.AllowPaths {
+		resolver.Allow(c.Path(dir))
+	}
+	resolver.Normalization = c.SourceNormalization()
+	// Remappings are validated on load
+	remappings, _ := c.ParsedRemappings()
+	if c.PreserveSourceNames {
+		return RemappingResolver(remappings, resolver)
+	}
+	return NormalizeResolver(RemappingResolver(remappings, resolver))
+}
@@ -0,0 +1,91 @@
+package solc
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadProjectConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "solc-config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = LoadProjectConfig(dir)
+	assert.True(t, errors.Is(err, ErrNoProjectConfig), "Missing configuration should be reported")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "lib", "math"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "lib", "math", "Math.sol"), []byte("library Math {}"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, ProjectConfigFile), []byte(`
+version: ^0.6.0
+sources: [contracts]
+optimizer:
+  enabled: true
+evmVersion: istanbul
+remappings: ["@math/=math/"]
+includePaths: [lib]
+outputSelection:
+  "*":
+    "*": [abi]
+artifacts:
+  dir: build
+  format: standard-json
+`), 0644))
+
+	config, err := LoadProjectConfig(dir)
+	require.NoError(t, err, "LoadProjectConfig should not error")
+	assert.Equal(t, "^0.6.0", config.Version)
+	assert.Equal(t, filepath.Join(dir, "build"), config.Path(config.Artifacts.Dir), "Paths should be relative to the configuration")
+	assert.Equal(t, Settings{
+		Optimizer:       Optimizer{Enabled: true, Runs: DefaultOptimizerRuns},
+		EVMVersion:      "istanbul",
+		OutputSelection: map[string]map[string][]string{"*": {"*": {"abi"}}},
+	}, config.Settings())
+
+	content, err := config.Resolver().Resolve("@math/Math.sol")
+	require.NoError(t, err, "Resolver should resolve remapped imports from include paths")
+	assert.Equal(t, "library Math {}", content)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, ProjectConfigFile), []byte("optimiser: {enabled: true}"), 0644))
+	_, err = LoadProjectConfig(dir)
+	assert.Error(t, err, "Unknown keys should be rejected")
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, ProjectConfigFile), []byte("remappings: [invalid]"), 0644))
+	_, err = LoadProjectConfig(dir)
+	assert.Error(t, err, "Invalid remappings should be rejected")
+}
+
+func TestProjectConfigSourceNames(t *testing.T) {
+	dir, err := ioutil.TempDir("", "solc-config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "lib", "math"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "lib", "math", "Math.sol"), []byte("library Math {}"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, ProjectConfigFile), []byte(`
+remappings: ['@math\=lib\math\']
+artifacts:
+  dir: build\artifacts
+`), 0644))
+
+	config, err := LoadProjectConfig(dir)
+	require.NoError(t, err, "LoadProjectConfig should not error")
+	assert.Equal(t, []string{"@math/=lib/math/"}, config.Remappings, "Remappings should be normalized")
+	assert.Equal(t, filepath.Join(dir, "build", "artifacts"), config.Path(config.Artifacts.Dir), "Paths should be normalized")
+	content, err := config.Resolver().Resolve(`@math\Math.sol`)
+	require.NoError(t, err, "Resolver should resolve normalized imports")
+	assert.Equal(t, "library Math {}", content)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, ProjectConfigFile), []byte(`
+preserveSourceNames: true
+remappings: ['@math\=lib\math\']
+`), 0644))
+	config, err = LoadProjectConfig(dir)
+	require.NoError(t, err, "LoadProjectConfig should not error")
+	assert.Equal(t, []string{`@math\=lib\math\`}, config.Remappings, "Preserved remappings should be kept as written")
+}
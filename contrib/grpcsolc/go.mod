@@ -0,0 +1,30 @@
+module github.com/nmvalera/solc-go/contrib/grpcsolc
+
+go 1.21
+
+require (
+	github.com/nmvalera/solc-go v0.0.0
+	github.com/stretchr/testify v1.9.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240604185151-ef581f913117
+	google.golang.org/grpc v1.66.0
+	google.golang.org/protobuf v1.34.2
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/fsnotify/fsnotify v1.4.9 // indirect
+	github.com/kr/pretty v0.3.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/crypto v0.24.0 // indirect
+	golang.org/x/net v0.26.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	golang.org/x/text v0.16.0 // indirect
+	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
+	gopkg.in/yaml.v2 v2.4.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+	rogchap.com/v8go v0.2.0 // indirect
+)
+
+replace github.com/nmvalera/solc-go => ../..
+
+replace rogchap.com/v8go => github.com/nmvalera/v8go v0.2.1-0.20200219171212-120cb03004f6
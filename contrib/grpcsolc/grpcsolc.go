@@ -0,0 +1,159 @@
+// Package grpcsolc exposes solc compilers as a gRPC compilation service (see solcpb/solc.proto)
+//
+// It lives in its own module so the solc package does not depend on gRPC
+package grpcsolc
+
+//go:generate protoc -I solcpb --go_out=solcpb --go_opt=paths=source_relative --go-grpc_out=solcpb --go-grpc_opt=paths=source_relative solc.proto
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+
+	solc "github.com/nmvalera/solc-go"
+	"github.com/nmvalera/solc-go/contrib/grpcsolc/solcpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements solcpb.CompilerServer dispatching compilations to compilers by version
+type Server struct {
+	solcpb.UnimplementedCompilerServer
+
+	// Limits bound the inputs compiled, inputs over them fail with codes.ResourceExhausted
+	Limits solc.InputLimits
+
+	// Policy returns the policy bounding the compilation of a request (see solc.CompilePolicy), ctx carries
+	// the metadata and peer of the call. Compilations are only bounded by Limits if nil. Inputs over the
+	// policy fail with codes.ResourceExhausted, or codes.InvalidArgument for denied URL sources,
+	// compilations over its timeout with codes.DeadlineExceeded and over its memory with codes.ResourceExhausted
+	Policy func(ctx context.Context, req *solcpb.CompileRequest) solc.CompilePolicy
+
+	compilers map[string]solc.Solc
+	versions  []string
+}
+
+// NewServer creates a server dispatching compilations to compilers (typically warmed solc.Pool)
+//
+// A version is served by the first of the compilers of that version
+func NewServer(compilers ...solc.Solc) *Server {
+	s := &Server{compilers: make(map[string]solc.Solc)}
+	for _, compiler := range compilers {
+		long := solc.LongVersion(compiler.Version())
+		if _, ok := s.compilers[long]; ok {
+			continue
+		}
+		s.compilers[long] = compiler
+		s.compilers[solc.ShortVersion(long)] = compiler
+		s.compilers[compiler.Version()] = compiler
+		s.versions = append(s.versions, long)
+	}
+	sort.Strings(s.versions)
+	return s
+}
+
+func (s *Server) compiler(version string) (solc.Solc, error) {
+	if version == "" {
+		if len(s.versions) != 1 {
+			return nil, status.Errorf(codes.InvalidArgument, "version is required, available versions are %v", s.versions)
+		}
+		version = s.versions[0]
+	}
+	compiler, ok := s.compilers[version]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown version %q", version)
+	}
+	return compiler, nil
+}
+
+// Compile sends the diagnostics of the output one by one before the output itself
+//
+// The compiler reports diagnostics along with its output, so they are all sent once the compilation is over
+func (s *Server) Compile(req *solcpb.CompileRequest, stream solcpb.Compiler_CompileServer) error {
+	compiler, err := s.compiler(req.GetVersion())
+	if err != nil {
+		return err
+	}
+
+	input := &solc.Input{}
+	err = json.Unmarshal(req.GetInput(), input)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid input: %v", err)
+	}
+	err = s.Limits.Check(input)
+	if err != nil {
+		return status.Error(codeOf(err), err.Error())
+	}
+	ctx := stream.Context()
+	if s.Policy != nil {
+		policy := s.Policy(ctx, req)
+		err = policy.Check(input)
+		if err != nil {
+			return status.Error(codeOf(err), err.Error())
+		}
+		ctx = solc.ContextWithPolicy(ctx, policy)
+	}
+
+	out, err := solc.CompileContext(ctx, compiler, input)
+	if err != nil {
+		return status.Error(codeOf(err), err.Error())
+	}
+
+	for _, e := range out.Errors {
+		err = stream.Send(&solcpb.CompileResponse{Event: &solcpb.CompileResponse_Diagnostic{Diagnostic: &solcpb.Diagnostic{
+			Severity:         e.Severity,
+			Type:             e.Type,
+			Component:        e.Component,
+			Message:          e.Message,
+			FormattedMessage: e.FormattedMessage,
+			File:             e.SourceLocation.File,
+			Start:            int32(e.SourceLocation.Start),
+			End:              int32(e.SourceLocation.End),
+			ErrorCode:        e.ErrorCode,
+		}}})
+		if err != nil {
+			return err
+		}
+	}
+
+	b, err := json.Marshal(out)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return stream.Send(&solcpb.CompileResponse{Event: &solcpb.CompileResponse_Output{Output: b}})
+}
+
+func (s *Server) ResolveVersion(ctx context.Context, req *solcpb.ResolveVersionRequest) (*solcpb.ResolveVersionResponse, error) {
+	version, err := solc.ResolveVersion(req.GetConstraint(), s.versions)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &solcpb.ResolveVersionResponse{Version: version}, nil
+}
+
+func (s *Server) ListVersions(ctx context.Context, req *solcpb.ListVersionsRequest) (*solcpb.ListVersionsResponse, error) {
+	return &solcpb.ListVersionsResponse{Versions: s.versions}, nil
+}
+
+// codeOf maps compilation errors to gRPC codes
+func codeOf(err error) codes.Code {
+	var sizeErr *solc.SizeLimitError
+	var memErr *solc.MemoryLimitError
+	var inputErr *solc.InputError
+	switch {
+	case errors.As(err, &sizeErr), errors.As(err, &memErr):
+		return codes.ResourceExhausted
+	case errors.As(err, &inputErr):
+		return codes.InvalidArgument
+	case err == solc.ErrTimeout:
+		return codes.DeadlineExceeded
+	case err == solc.ErrClosed, err == solc.ErrTerminated:
+		return codes.Unavailable
+	case err == context.Canceled:
+		return codes.Canceled
+	case err == context.DeadlineExceeded:
+		return codes.DeadlineExceeded
+	}
+	return codes.Internal
+}
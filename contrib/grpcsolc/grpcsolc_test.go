@@ -0,0 +1,220 @@
+package grpcsolc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	solc "github.com/nmvalera/solc-go"
+	"github.com/nmvalera/solc-go/contrib/grpcsolc/solcpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeSolc returns a warning for every source it compiles
+type fakeSolc struct {
+	version string
+	err     error
+}
+
+func (s *fakeSolc) License() string                     { return "" }
+func (s *fakeSolc) Version() string                     { return s.version }
+func (s *fakeSolc) HeapStatistics() solc.HeapStatistics { return solc.HeapStatistics{} }
+func (s *fakeSolc) Close() error                        { return nil }
+func (s *fakeSolc) Compile(in *solc.Input) (*solc.Output, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	out := &solc.Output{Contracts: make(map[string]map[string]solc.Contract)}
+	for name := range in.Sources {
+		out.Errors = append(out.Errors, solc.Error{Severity: "warning", ErrorCode: "2072", Message: "unused", SourceLocation: solc.SourceLocation{File: name, Start: 1, End: 2}})
+		out.Contracts[name] = map[string]solc.Contract{"C": solc.Contract{Metadata: s.version}}
+	}
+	return out, nil
+}
+
+func newClient(t *testing.T, compilers ...solc.Solc) (solcpb.CompilerClient, func()) {
+	return newServerClient(t, NewServer(compilers...))
+}
+
+func newServerClient(t *testing.T, s *Server, opts ...grpc.ServerOption) (solcpb.CompilerClient, func()) {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(opts...)
+	solcpb.RegisterCompilerServer(srv, s)
+	go srv.Serve(lis)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err, "Dialing should not error")
+
+	return solcpb.NewCompilerClient(conn), func() {
+		conn.Close()
+		srv.Stop()
+	}
+}
+
+func TestServer(t *testing.T) {
+	client, stop := newClient(t,
+		&fakeSolc{version: "0.6.2+commit.bacdbe57.Emscripten.clang"},
+		&fakeSolc{version: "0.5.9+commit.e560f70d.Emscripten.clang"},
+	)
+	defer stop()
+	ctx := context.Background()
+
+	versions, err := client.ListVersions(ctx, &solcpb.ListVersionsRequest{})
+	require.NoError(t, err, "ListVersions should not error")
+	assert.Equal(t, []string{"0.5.9+commit.e560f70d", "0.6.2+commit.bacdbe57"}, versions.GetVersions())
+
+	resolved, err := client.ResolveVersion(ctx, &solcpb.ResolveVersionRequest{Constraint: ">=0.5.0 <0.6.0"})
+	require.NoError(t, err, "ResolveVersion should not error")
+	assert.Equal(t, "0.5.9+commit.e560f70d", resolved.GetVersion())
+
+	_, err = client.ResolveVersion(ctx, &solcpb.ResolveVersionRequest{Constraint: "^0.7.0"})
+	assert.Equal(t, codes.NotFound, status.Code(err), "Unsatisfiable constraint should not be found")
+
+	input, _ := json.Marshal(&solc.Input{Sources: map[string]solc.SourceIn{"A.sol": solc.SourceIn{Content: "contract C {}"}}})
+	stream, err := client.Compile(ctx, &solcpb.CompileRequest{Version: "0.6.2", Input: input})
+	require.NoError(t, err, "Compile should not error")
+
+	var responses []*solcpb.CompileResponse
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err, "Receiving should not error")
+		responses = append(responses, res)
+	}
+	require.Len(t, responses, 2, "Diagnostic and output should be streamed")
+	assert.Equal(t, "unused", responses[0].GetDiagnostic().GetMessage())
+	assert.Equal(t, "A.sol", responses[0].GetDiagnostic().GetFile())
+	assert.Equal(t, "2072", responses[0].GetDiagnostic().GetErrorCode())
+	out := &solc.Output{}
+	require.NoError(t, json.Unmarshal(responses[1].GetOutput(), out))
+	assert.Equal(t, "0.6.2+commit.bacdbe57.Emscripten.clang", out.Contracts["A.sol"]["C"].Metadata)
+
+	stream, err = client.Compile(ctx, &solcpb.CompileRequest{Version: "0.4.0", Input: input})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.NotFound, status.Code(err), "Unknown version should not be found")
+
+	stream, err = client.Compile(ctx, &solcpb.CompileRequest{Version: "0.6.2", Input: []byte("{")})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "Invalid input should be rejected")
+}
+
+func TestServerSharedVersion(t *testing.T) {
+	client, stop := newClient(t,
+		&fakeSolc{version: "0.6.2+commit.bacdbe57.Emscripten.clang"},
+		&fakeSolc{version: "0.6.2+commit.bacdbe57.Emscripten.clang"},
+	)
+	defer stop()
+
+	versions, err := client.ListVersions(context.Background(), &solcpb.ListVersionsRequest{})
+	require.NoError(t, err, "ListVersions should not error")
+	assert.Equal(t, []string{"0.6.2+commit.bacdbe57"}, versions.GetVersions(), "Shared versions should be listed once")
+}
+
+func TestServerLimits(t *testing.T) {
+	s := NewServer(&fakeSolc{version: "0.6.2+commit.bacdbe57.Emscripten.clang"})
+	s.Limits = solc.InputLimits{MaxSources: 1}
+	client, stop := newServerClient(t, s,
+		grpc.UnaryInterceptor(UnaryRateLimit(solc.NewRateLimiter(1, 1), nil)),
+		grpc.StreamInterceptor(StreamRateLimit(solc.NewRateLimiter(1, 1), nil)),
+	)
+	defer stop()
+	ctx := context.Background()
+
+	input, _ := json.Marshal(&solc.Input{Sources: map[string]solc.SourceIn{"A.sol": {Content: "contract A {}"}, "B.sol": {Content: "contract B {}"}}})
+	stream, err := client.Compile(ctx, &solcpb.CompileRequest{Input: input})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "Input over limits should be rejected")
+	assert.Contains(t, status.Convert(err).Message(), "sources size 2 exceeds limit of 1")
+
+	stream, err = client.Compile(ctx, &solcpb.CompileRequest{Input: input})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "Calls over the rate should be limited")
+	assert.Equal(t, "rate limit exceeded", status.Convert(err).Message())
+
+	_, err = client.ListVersions(ctx, &solcpb.ListVersionsRequest{})
+	require.NoError(t, err, "Unary calls should have their own limiter")
+	_, err = client.ListVersions(ctx, &solcpb.ListVersionsRequest{})
+	st := status.Convert(err)
+	require.Equal(t, codes.ResourceExhausted, st.Code(), "Unary calls over the rate should be limited")
+	require.Len(t, st.Details(), 1, "Retry info should be attached")
+	assert.Greater(t, st.Details()[0].(*errdetails.RetryInfo).GetRetryDelay().AsDuration().Nanoseconds(), int64(0))
+}
+
+func TestServerPolicy(t *testing.T) {
+	compiler := &fakeSolc{version: "0.6.2+commit.bacdbe57.Emscripten.clang"}
+	s := NewServer(compiler)
+	s.Policy = func(ctx context.Context, req *solcpb.CompileRequest) solc.CompilePolicy {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if tenant := md.Get("x-tenant"); len(tenant) == 1 && tenant[0] == "trusted" {
+			return solc.CompilePolicy{}
+		}
+		return solc.CompilePolicy{InputLimits: solc.InputLimits{MaxSourceBytes: 16}, DenyURLs: true, Timeout: time.Second}
+	}
+	client, stop := newServerClient(t, s)
+	defer stop()
+
+	compile := func(ctx context.Context, input string) error {
+		stream, err := client.Compile(ctx, &solcpb.CompileRequest{Input: []byte(input)})
+		require.NoError(t, err)
+		for {
+			_, err = stream.Recv()
+			if err != nil {
+				break
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+	ctx := context.Background()
+
+	err := compile(ctx, `{"sources": {"A.sol": {"content": "contract C {}"}}}`)
+	assert.NoError(t, err, "Input within the policy should compile")
+
+	err = compile(ctx, `{"sources": {"A.sol": {"content": "contract Large {}"}}}`)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "Input over the policy should be rejected")
+
+	err = compile(ctx, `{"sources": {"A.sol": {"urls": ["/etc/passwd"]}}}`)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "URL sources should be denied")
+	assert.Contains(t, status.Convert(err).Message(), "URL sources are denied")
+
+	err = compile(metadata.AppendToOutgoingContext(ctx, "x-tenant", "trusted"), `{"sources": {"A.sol": {"urls": ["A.sol"]}}}`)
+	assert.NoError(t, err, "Policies should apply per request")
+
+	compiler.err = &solc.MemoryLimitError{Usage: 2, Limit: 1}
+	err = compile(ctx, `{"sources": {"A.sol": {"content": "contract C {}"}}}`)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "Compilations over the memory of the policy should be rejected")
+}
+
+func TestPeerIP(t *testing.T) {
+	a := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 4001}})
+	b := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 4002}})
+	assert.Equal(t, "10.0.0.1", PeerIP(a), "Port should be left out")
+	assert.Equal(t, "", PeerIP(context.Background()))
+
+	limiter := solc.NewRateLimiter(1, 1)
+	require.NoError(t, allow(a, limiter, nil), "First call should be allowed")
+	assert.Equal(t, codes.ResourceExhausted, status.Code(allow(b, limiter, nil)), "Connections of a client should share its limit")
+}
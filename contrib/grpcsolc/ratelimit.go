@@ -0,0 +1,67 @@
+package grpcsolc
+
+import (
+	"context"
+	"net"
+
+	solc "github.com/nmvalera/solc-go"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// ClientKey identifies the client of a call for rate limiting
+type ClientKey func(ctx context.Context) string
+
+// PeerIP is the ClientKey of the IP address a call comes from, its port left out so reconnecting
+// clients share their limit
+func PeerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// UnaryRateLimit returns an interceptor failing calls of clients over limiter with codes.ResourceExhausted
+// and a RetryInfo detail, key identifies clients (PeerIP if nil)
+func UnaryRateLimit(limiter *solc.RateLimiter, key ClientKey) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := allow(ctx, limiter, key); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamRateLimit is like UnaryRateLimit for streaming calls (such as Compile)
+func StreamRateLimit(limiter *solc.RateLimiter, key ClientKey) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := allow(stream.Context(), limiter, key); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
+
+func allow(ctx context.Context, limiter *solc.RateLimiter, key ClientKey) error {
+	if key == nil {
+		key = PeerIP
+	}
+	ok, retry := limiter.Allow(key(ctx))
+	if ok {
+		return nil
+	}
+	st, err := status.New(codes.ResourceExhausted, "rate limit exceeded").WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retry)})
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+	return st.Err()
+}
@@ -0,0 +1,201 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: solc.proto
+
+package solcpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Compiler_Compile_FullMethodName        = "/solc.v1.Compiler/Compile"
+	Compiler_ResolveVersion_FullMethodName = "/solc.v1.Compiler/ResolveVersion"
+	Compiler_ListVersions_FullMethodName   = "/solc.v1.Compiler/ListVersions"
+)
+
+// CompilerClient is the client API for Compiler service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CompilerClient interface {
+	Compile(ctx context.Context, in *CompileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CompileResponse], error)
+	ResolveVersion(ctx context.Context, in *ResolveVersionRequest, opts ...grpc.CallOption) (*ResolveVersionResponse, error)
+	ListVersions(ctx context.Context, in *ListVersionsRequest, opts ...grpc.CallOption) (*ListVersionsResponse, error)
+}
+
+type compilerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCompilerClient(cc grpc.ClientConnInterface) CompilerClient {
+	return &compilerClient{cc}
+}
+
+func (c *compilerClient) Compile(ctx context.Context, in *CompileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CompileResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Compiler_ServiceDesc.Streams[0], Compiler_Compile_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[CompileRequest, CompileResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Compiler_CompileClient = grpc.ServerStreamingClient[CompileResponse]
+
+func (c *compilerClient) ResolveVersion(ctx context.Context, in *ResolveVersionRequest, opts ...grpc.CallOption) (*ResolveVersionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResolveVersionResponse)
+	err := c.cc.Invoke(ctx, Compiler_ResolveVersion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *compilerClient) ListVersions(ctx context.Context, in *ListVersionsRequest, opts ...grpc.CallOption) (*ListVersionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListVersionsResponse)
+	err := c.cc.Invoke(ctx, Compiler_ListVersions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CompilerServer is the server API for Compiler service.
+// All implementations must embed UnimplementedCompilerServer
+// for forward compatibility.
+type CompilerServer interface {
+	Compile(*CompileRequest, grpc.ServerStreamingServer[CompileResponse]) error
+	ResolveVersion(context.Context, *ResolveVersionRequest) (*ResolveVersionResponse, error)
+	ListVersions(context.Context, *ListVersionsRequest) (*ListVersionsResponse, error)
+	mustEmbedUnimplementedCompilerServer()
+}
+
+// UnimplementedCompilerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCompilerServer struct{}
+
+func (UnimplementedCompilerServer) Compile(*CompileRequest, grpc.ServerStreamingServer[CompileResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Compile not implemented")
+}
+func (UnimplementedCompilerServer) ResolveVersion(context.Context, *ResolveVersionRequest) (*ResolveVersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveVersion not implemented")
+}
+func (UnimplementedCompilerServer) ListVersions(context.Context, *ListVersionsRequest) (*ListVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVersions not implemented")
+}
+func (UnimplementedCompilerServer) mustEmbedUnimplementedCompilerServer() {}
+func (UnimplementedCompilerServer) testEmbeddedByValue()                  {}
+
+// UnsafeCompilerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CompilerServer will
+// result in compilation errors.
+type UnsafeCompilerServer interface {
+	mustEmbedUnimplementedCompilerServer()
+}
+
+func RegisterCompilerServer(s grpc.ServiceRegistrar, srv CompilerServer) {
+	// If the following call pancis, it indicates UnimplementedCompilerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Compiler_ServiceDesc, srv)
+}
+
+func _Compiler_Compile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CompileRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CompilerServer).Compile(m, &grpc.GenericServerStream[CompileRequest, CompileResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Compiler_CompileServer = grpc.ServerStreamingServer[CompileResponse]
+
+func _Compiler_ResolveVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CompilerServer).ResolveVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Compiler_ResolveVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CompilerServer).ResolveVersion(ctx, req.(*ResolveVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Compiler_ListVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CompilerServer).ListVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Compiler_ListVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CompilerServer).ListVersions(ctx, req.(*ListVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Compiler_ServiceDesc is the grpc.ServiceDesc for Compiler service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Compiler_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "solc.v1.Compiler",
+	HandlerType: (*CompilerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ResolveVersion",
+			Handler:    _Compiler_ResolveVersion_Handler,
+		},
+		{
+			MethodName: "ListVersions",
+			Handler:    _Compiler_ListVersions_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Compile",
+			Handler:       _Compiler_Compile_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "solc.proto",
+}
@@ -0,0 +1,60 @@
+// Package otelsolc traces solc compilers with OpenTelemetry
+//
+// It lives in its own module so the solc package does not depend on OpenTelemetry
+package otelsolc
+
+import (
+	"context"
+	"fmt"
+
+	solc "github.com/nmvalera/solc-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName is the name of the OpenTelemetry tracer spans are created with
+const InstrumentationName = "github.com/nmvalera/solc-go"
+
+type tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer returns a solc.Tracer creating spans from provider (see solc.WithTracer)
+func NewTracer(provider trace.TracerProvider) solc.Tracer {
+	return &tracer{tracer: provider.Tracer(InstrumentationName)}
+}
+
+func (t *tracer) Start(ctx context.Context, name string) (context.Context, solc.Span) {
+	ctx, s := t.tracer.Start(ctx, name)
+	return ctx, &span{span: s}
+}
+
+type span struct {
+	span trace.Span
+}
+
+func (s *span) SetAttribute(key string, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		s.span.SetAttributes(attribute.String(key, v))
+	case int:
+		s.span.SetAttributes(attribute.Int(key, v))
+	case int64:
+		s.span.SetAttributes(attribute.Int64(key, v))
+	case bool:
+		s.span.SetAttributes(attribute.Bool(key, v))
+	case float64:
+		s.span.SetAttributes(attribute.Float64(key, v))
+	default:
+		s.span.SetAttributes(attribute.String(key, fmt.Sprint(v)))
+	}
+}
+
+func (s *span) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
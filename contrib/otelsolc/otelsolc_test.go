@@ -0,0 +1,42 @@
+package otelsolc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	solc "github.com/nmvalera/solc-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := NewTracer(provider)
+
+	ctx, parent := tracer.Start(context.Background(), "request")
+	_, span := tracer.Start(ctx, solc.SpanCompile)
+	span.SetAttribute(solc.AttrVersion, "0.6.2")
+	span.SetAttribute(solc.AttrSources, 2)
+	span.SetAttribute(solc.AttrCached, false)
+	span.End(errors.New("failed"))
+	parent.End(nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	compile := spans[0]
+	assert.Equal(t, solc.SpanCompile, compile.Name())
+	assert.Equal(t, spans[1].SpanContext().SpanID(), compile.Parent().SpanID(), "Span should be parented by the context")
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String(solc.AttrVersion, "0.6.2"),
+		attribute.Int(solc.AttrSources, 2),
+		attribute.Bool(solc.AttrCached, false),
+	}, compile.Attributes())
+	assert.Equal(t, codes.Error, compile.Status().Code, "Failed operation should set an error status")
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+}
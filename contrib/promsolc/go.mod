@@ -0,0 +1,13 @@
+module github.com/nmvalera/solc-go/contrib/promsolc
+
+go 1.13
+
+require (
+	github.com/nmvalera/solc-go v0.0.0
+	github.com/prometheus/client_golang v1.20.5
+	github.com/stretchr/testify v1.9.0
+)
+
+replace github.com/nmvalera/solc-go => ../..
+
+replace rogchap.com/v8go => github.com/nmvalera/v8go v0.2.1-0.20200219171212-120cb03004f6
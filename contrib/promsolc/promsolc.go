@@ -0,0 +1,156 @@
+// Package promsolc exposes Prometheus metrics for solc compilers, pools and caches
+//
+// It lives in its own module so the solc package does not depend on the Prometheus client
+package promsolc
+
+import (
+	"sync"
+
+	solc "github.com/nmvalera/solc-go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics is a prometheus.Collector fed by compiler events
+//
+// Set it as the logger of compilers (see solc.WithLogger) and register it on a prometheus.Registerer
+type Metrics struct {
+	next solc.Logger
+
+	compiles  *prometheus.CounterVec
+	failures  *prometheus.CounterVec
+	durations *prometheus.HistogramVec
+	isolates  *prometheus.CounterVec
+	hits      *prometheus.CounterVec
+	misses    *prometheus.CounterVec
+	downloads *prometheus.CounterVec
+	dlErrors  *prometheus.CounterVec
+
+	poolSize *prometheus.Desc
+	poolBusy *prometheus.Desc
+
+	mux   sync.Mutex
+	pools map[string]*solc.Pool
+}
+
+// New creates metrics forwarding events and messages to next (which may be nil)
+func New(next solc.Logger) *Metrics {
+	labels := []string{"version"}
+	return &Metrics{
+		next: next,
+		compiles: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "solc_compiles_total",
+			Help: "Number of compilations run",
+		}, labels),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "solc_compile_failures_total",
+			Help: "Number of compilations that failed",
+		}, labels),
+		durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "solc_compile_duration_seconds",
+			Help:    "Duration of compilations",
+			Buckets: prometheus.ExponentialBuckets(0.05, 2, 10),
+		}, labels),
+		isolates: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "solc_isolates_created_total",
+			Help: "Number of compiler isolates created, including restarts",
+		}, labels),
+		hits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "solc_cache_hits_total",
+			Help: "Number of compilations served from cache",
+		}, labels),
+		misses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "solc_cache_misses_total",
+			Help: "Number of compilations not found in cache",
+		}, labels),
+		downloads: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "solc_downloads_total",
+			Help: "Number of compiler releases downloaded by registries, including failures",
+		}, labels),
+		dlErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "solc_download_failures_total",
+			Help: "Number of compiler release downloads that failed",
+		}, labels),
+		poolSize: prometheus.NewDesc("solc_pool_instances", "Number of compiler instances of a pool", []string{"pool"}, nil),
+		poolBusy: prometheus.NewDesc("solc_pool_busy_instances", "Number of compiler instances of a pool currently compiling", []string{"pool"}, nil),
+		pools:    make(map[string]*solc.Pool),
+	}
+}
+
+// Register registers the metrics on reg
+func (m *Metrics) Register(reg prometheus.Registerer) error {
+	return reg.Register(m)
+}
+
+// ObservePool reports the utilization of pool under name
+func (m *Metrics) ObservePool(name string, pool *solc.Pool) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.pools[name] = pool
+}
+
+func (m *Metrics) Printf(format string, v ...interface{}) {
+	if m.next != nil {
+		m.next.Printf(format, v...)
+	}
+}
+
+func (m *Metrics) LogEvent(e solc.Event) {
+	switch e.Kind {
+	case solc.EventInit:
+		m.isolates.WithLabelValues(e.Version).Inc()
+	case solc.EventCompile:
+		m.compiles.WithLabelValues(e.Version).Inc()
+		m.durations.WithLabelValues(e.Version).Observe(e.Duration.Seconds())
+		if e.Err != nil {
+			m.failures.WithLabelValues(e.Version).Inc()
+		}
+	case solc.EventCacheHit:
+		m.hits.WithLabelValues(e.Version).Inc()
+	case solc.EventCacheMiss:
+		m.misses.WithLabelValues(e.Version).Inc()
+	case solc.EventDownload:
+		m.downloads.WithLabelValues(e.Version).Inc()
+		if e.Err != nil {
+			m.dlErrors.WithLabelValues(e.Version).Inc()
+		}
+	}
+
+	switch next := m.next.(type) {
+	case nil:
+	case solc.EventLogger:
+		next.LogEvent(e)
+	default:
+		next.Printf("%v", e)
+	}
+}
+
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.compiles.Describe(ch)
+	m.failures.Describe(ch)
+	m.durations.Describe(ch)
+	m.isolates.Describe(ch)
+	m.hits.Describe(ch)
+	m.misses.Describe(ch)
+	m.downloads.Describe(ch)
+	m.dlErrors.Describe(ch)
+	ch <- m.poolSize
+	ch <- m.poolBusy
+}
+
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.compiles.Collect(ch)
+	m.failures.Collect(ch)
+	m.durations.Collect(ch)
+	m.isolates.Collect(ch)
+	m.hits.Collect(ch)
+	m.misses.Collect(ch)
+	m.downloads.Collect(ch)
+	m.dlErrors.Collect(ch)
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	for name, pool := range m.pools {
+		ch <- prometheus.MustNewConstMetric(m.poolSize, prometheus.GaugeValue, float64(pool.Size()), name)
+		ch <- prometheus.MustNewConstMetric(m.poolBusy, prometheus.GaugeValue, float64(pool.Busy()), name)
+	}
+}
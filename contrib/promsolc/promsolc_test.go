@@ -0,0 +1,57 @@
+package promsolc
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	solc "github.com/nmvalera/solc-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	m := New(nil)
+	reg := prometheus.NewPedanticRegistry()
+	require.NoError(t, m.Register(reg), "Register should not error")
+
+	m.LogEvent(solc.Event{Kind: solc.EventInit, Version: "0.6.2"})
+	m.LogEvent(solc.Event{Kind: solc.EventCacheMiss, Version: "0.6.2"})
+	m.LogEvent(solc.Event{Kind: solc.EventCompile, Version: "0.6.2", Duration: 200 * time.Millisecond})
+	m.LogEvent(solc.Event{Kind: solc.EventCompile, Version: "0.6.2", Duration: time.Second, Err: errors.New("failed")})
+	m.LogEvent(solc.Event{Kind: solc.EventCacheHit, Version: "0.6.2"})
+	m.LogEvent(solc.Event{Kind: solc.EventDownload, Version: "0.6.2", Bytes: 1024})
+	m.LogEvent(solc.Event{Kind: solc.EventDownload, Version: "0.6.2", Err: errors.New("checksum mismatch")})
+
+	expected := `
+# HELP solc_compile_failures_total Number of compilations that failed
+# TYPE solc_compile_failures_total counter
+solc_compile_failures_total{version="0.6.2"} 1
+# HELP solc_compiles_total Number of compilations run
+# TYPE solc_compiles_total counter
+solc_compiles_total{version="0.6.2"} 2
+# HELP solc_cache_hits_total Number of compilations served from cache
+# TYPE solc_cache_hits_total counter
+solc_cache_hits_total{version="0.6.2"} 1
+# HELP solc_cache_misses_total Number of compilations not found in cache
+# TYPE solc_cache_misses_total counter
+solc_cache_misses_total{version="0.6.2"} 1
+# HELP solc_isolates_created_total Number of compiler isolates created, including restarts
+# TYPE solc_isolates_created_total counter
+solc_isolates_created_total{version="0.6.2"} 1
+# HELP solc_downloads_total Number of compiler releases downloaded by registries, including failures
+# TYPE solc_downloads_total counter
+solc_downloads_total{version="0.6.2"} 2
+# HELP solc_download_failures_total Number of compiler release downloads that failed
+# TYPE solc_download_failures_total counter
+solc_download_failures_total{version="0.6.2"} 1
+`
+	err := testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"solc_compiles_total", "solc_compile_failures_total", "solc_cache_hits_total", "solc_cache_misses_total", "solc_isolates_created_total",
+		"solc_downloads_total", "solc_download_failures_total")
+	assert.NoError(t, err, "Metrics should match events")
+	assert.Equal(t, 1, testutil.CollectAndCount(m.durations), "Durations should be observed per version")
+}
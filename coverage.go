@@ -0,0 +1,418 @@
+package solc
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/nmvalera/solc-go/ast"
+	"github.com/nmvalera/solc-go/types"
+)
+
+// InstrumentCoverage compiles the AST of in and instruments it (see Instrument), it errors if in does not compile
+func InstrumentCoverage(ctx context.Context, solc Solc, in *Input) (*Input, *CoverageMap, error) {
+	out, err := CompileContext(ctx, solc, astInput(in))
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, e := range out.Errors {
+		if e.Severity == "error" {
+			return nil, nil, fmt.Errorf("solc: compiling for coverage failed: %v", e.Message)
+		}
+	}
+	return Instrument(in, out)
+}
+
+// astInput returns a copy of in selecting the ast output of every source alone
+func astInput(in *Input) *Input {
+	c := *in
+	c.Settings.OutputSelection = OutputSelection{}.Select("*", "", "ast")
+	return &c
+}
+
+// Instrument returns a copy of in whose sources push and pop a marker (assembly { pop(<marker>) })
+// at the beginning of every function and modifier body, before every statement and in both branches
+// of if statements, and the map of the markers. Executions of instrumented contracts are then
+// traced into coverage (see Coverage)
+//
+// Markers are located from the ast output of out. The optimizer, which drops markers, and the IR
+// pipeline are disabled, so instrumented contracts are larger and cost more gas than their release
+// builds, and the locations of compiler errors refer to the instrumented sources
+func Instrument(in *Input, out *Output) (*Input, *CoverageMap, error) {
+	instrumented := *in
+	instrumented.Sources = make(map[string]SourceIn, len(in.Sources))
+	instrumented.Settings.Optimizer = Optimizer{}
+	instrumented.Settings.ViaIR = false
+
+	var names []string
+	for name := range in.Sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	m := &CoverageMap{Markers: []CoverageMarker{}}
+	for _, name := range names {
+		source := in.Sources[name]
+		instrumented.Sources[name] = source
+		if source.Content == "" || len(out.Sources[name].AST) == 0 {
+			continue
+		}
+		unit, err := ast.Parse(out.Sources[name].AST)
+		if err != nil {
+			return nil, nil, fmt.Errorf("solc: could not parse AST of %q: %w", name, err)
+		}
+
+		i := &instrumenter{file: name, content: source.Content}
+		i.unit(unit)
+		sort.SliceStable(i.markers, func(a, b int) bool {
+			return i.markers[a].SourceLocation.Start < i.markers[b].SourceLocation.Start
+		})
+		m.Markers = append(m.Markers, i.markers...)
+		source.Content, source.Keccak256 = i.apply(), ""
+		instrumented.Sources[name] = source
+	}
+	return &instrumented, m, nil
+}
+
+// instrumenter collects the markers of a source and the insertions instrumenting it
+type instrumenter struct {
+	file    string
+	content string
+
+	markers []CoverageMarker
+	inserts []insertion
+	blocks  int
+}
+
+// insertion is text inserted at an offset of a source
+//
+// Insertions at the same offset are ordered so the code they wrap nests: closing insertions go first, the
+// innermost (of highest level) first, then opening ones, the outermost first
+type insertion struct {
+	offset  int
+	closing bool
+	level   int
+	text    string
+}
+
+func (i *instrumenter) unit(unit *ast.Unit) {
+	for _, n := range append(unit.Find("FunctionDefinition"), unit.Find("ModifierDefinition")...) {
+		body := n.Child("body")
+		if body == nil || body.NodeType != "Block" {
+			continue
+		}
+		i.insert(body.Range().Start+1, false, 0, i.mark(CoverageFunction, n, functionName(n), 0, 0))
+		i.block(body, 2)
+	}
+}
+
+// block marks the statements of a block, at level
+func (i *instrumenter) block(n *ast.Node, level int) {
+	for _, s := range n.ChildList("statements") {
+		i.insert(s.Range().Start, false, level, i.mark(CoverageStatement, s, "", 0, 0))
+		i.statement(s, level)
+	}
+}
+
+// statement marks the blocks and branches nested in a statement marked at level
+func (i *instrumenter) statement(s *ast.Node, level int) {
+	switch s.NodeType {
+	case "Block", "UncheckedBlock":
+		i.block(s, level+2)
+	case "IfStatement":
+		block := i.blocks
+		i.blocks++
+		trueBody := s.Child("trueBody")
+		i.body(trueBody, level, i.mark(CoverageBranch, trueBody, "", block, 0))
+		if falseBody := s.Child("falseBody"); falseBody != nil {
+			i.body(falseBody, level, i.mark(CoverageBranch, falseBody, "", block, 1))
+		} else {
+			i.insert(i.end(s), true, level, " else { "+i.mark(CoverageBranch, s, "", block, 1)+"}")
+		}
+	case "ForStatement", "WhileStatement", "DoWhileStatement":
+		i.body(s.Child("body"), level, "")
+	case "TryStatement":
+		for _, clause := range s.ChildList("clauses") {
+			if b := clause.Child("block"); b != nil {
+				i.block(b, level+2)
+			}
+		}
+	}
+}
+
+// body marks the body of a statement marked at level, prefixed by marker (a branch marker) if set
+//
+// Bodies which are not blocks are wrapped into one so markers can precede them
+func (i *instrumenter) body(b *ast.Node, level int, marker string) {
+	if b == nil {
+		return
+	}
+	if b.NodeType == "Block" {
+		i.insert(b.Range().Start+1, false, level+1, marker)
+		i.block(b, level+2)
+		return
+	}
+	i.insert(b.Range().Start, false, level+1, "{ "+marker)
+	i.insert(b.Range().Start, false, level+2, i.mark(CoverageStatement, b, "", 0, 0))
+	i.statement(b, level+2)
+	i.insert(i.end(b), true, level+1, "}")
+}
+
+// mark adds a marker of the code of n and returns the code executing it
+func (i *instrumenter) mark(kind CoverageKind, n *ast.Node, name string, block, branch int) string {
+	r := n.Range()
+	id := "0x" + hex.EncodeToString(types.Keccak256([]byte(fmt.Sprintf("solc-go/coverage:%v:%v", i.file, len(i.markers)))))
+	i.markers = append(i.markers, CoverageMarker{
+		ID:             id,
+		Kind:           kind,
+		Name:           name,
+		Block:          block,
+		Branch:         branch,
+		SourceLocation: SourceLocation{File: i.file, Start: r.Start, End: r.Start + r.Length},
+		Line:           strings.Count(i.content[:r.Start], "\n") + 1,
+	})
+	return "assembly { pop(" + id + ") } "
+}
+
+func (i *instrumenter) insert(offset int, closing bool, level int, text string) {
+	if text != "" {
+		i.inserts = append(i.inserts, insertion{offset: offset, closing: closing, level: level, text: text})
+	}
+}
+
+// end returns the end offset of a statement, its semicolon included: solc locates simple statements without it
+func (i *instrumenter) end(n *ast.Node) int {
+	r := n.Range()
+	end := r.Start + r.Length
+	if end > 0 && i.content[end-1] == '}' {
+		return end
+	}
+	for j := end; j < len(i.content); {
+		switch {
+		case i.content[j] == ';':
+			return j + 1
+		case strings.HasPrefix(i.content[j:], "//"):
+			next := strings.IndexByte(i.content[j:], '\n')
+			if next < 0 {
+				return end
+			}
+			j += next
+		case strings.HasPrefix(i.content[j:], "/*"):
+			next := strings.Index(i.content[j:], "*/")
+			if next < 0 {
+				return end
+			}
+			j += next + 2
+		case strings.ContainsRune(" \t\r\n", rune(i.content[j])):
+			j++
+		default:
+			return end
+		}
+	}
+	return end
+}
+
+// apply returns the content with the insertions
+func (i *instrumenter) apply() string {
+	sort.SliceStable(i.inserts, func(a, b int) bool {
+		x, y := i.inserts[a], i.inserts[b]
+		switch {
+		case x.offset != y.offset:
+			return x.offset < y.offset
+		case x.closing != y.closing:
+			return x.closing
+		case x.closing:
+			return x.level > y.level
+		default:
+			return x.level < y.level
+		}
+	})
+	var b strings.Builder
+	last := 0
+	for _, ins := range i.inserts {
+		b.WriteString(i.content[last:ins.offset])
+		b.WriteString(ins.text)
+		last = ins.offset
+	}
+	b.WriteString(i.content[last:])
+	return b.String()
+}
+
+// functionName returns the name of a function or modifier, constructor, fallback or receive for special functions
+func functionName(n *ast.Node) string {
+	switch {
+	case n.StringAttr("kind") != "" && n.StringAttr("kind") != "function" && n.StringAttr("kind") != "freeFunction":
+		return n.StringAttr("kind")
+	case n.BoolAttr("isConstructor"):
+		return "constructor"
+	case n.NodeType == "FunctionDefinition" && n.Name() == "":
+		return "fallback"
+	}
+	return n.Name()
+}
+
+// TraceStep is a step of an EVM execution trace, as in the structLogs of debug_traceTransaction
+type TraceStep struct {
+	Op string `json:"op"`
+
+	// Stack is hex encoded, the top last
+	Stack []string `json:"stack"`
+}
+
+// Coverage counts the markers of a coverage map hit by executions of instrumented contracts, it is not
+// safe for concurrent use
+type Coverage struct {
+	Map *CoverageMap
+
+	// Hits are the executions of markers, by ID
+	Hits map[string]int
+
+	ids map[string]bool
+}
+
+// NewCoverage returns the coverage of the markers of m, none hit
+func NewCoverage(m *CoverageMap) *Coverage {
+	c := &Coverage{Map: m, Hits: make(map[string]int), ids: make(map[string]bool)}
+	for _, marker := range m.Markers {
+		c.ids[marker.ID] = true
+	}
+	return c
+}
+
+// Hit counts an execution of the marker id, unknown markers are ignored
+func (c *Coverage) Hit(id string) {
+	id = types.NormalizeSelector(id)
+	if c.ids[id] {
+		c.Hits[id]++
+	}
+}
+
+// AddTrace counts the markers popped by the steps of an execution trace
+func (c *Coverage) AddTrace(steps []TraceStep) {
+	for _, step := range steps {
+		if step.Op != "POP" || len(step.Stack) == 0 {
+			continue
+		}
+		// Stack words may be 0x prefixed and have their leading zeros trimmed
+		word := strings.TrimPrefix(strings.ToLower(step.Stack[len(step.Stack)-1]), "0x")
+		if len(word) <= 64 {
+			c.Hit(strings.Repeat("0", 64-len(word)) + word)
+		}
+	}
+}
+
+// CoverageCount is the number of items of a kind (e.g. statements) and of those covered
+type CoverageCount struct {
+	Total   int `json:"total"`
+	Covered int `json:"covered"`
+}
+
+// Percent returns the percentage of items covered, 100 if there is none
+func (c CoverageCount) Percent() float64 {
+	if c.Total == 0 {
+		return 100
+	}
+	return 100 * float64(c.Covered) / float64(c.Total)
+}
+
+func (c *CoverageCount) add(hits int) {
+	c.Total++
+	if hits > 0 {
+		c.Covered++
+	}
+}
+
+// MarkerHits is a marker and its executions
+type MarkerHits struct {
+	CoverageMarker
+	Hits int `json:"hits"`
+}
+
+// FileCoverage is the coverage of a source file
+type FileCoverage struct {
+	File string `json:"file"`
+
+	Functions  CoverageCount `json:"functions"`
+	Statements CoverageCount `json:"statements"`
+	Branches   CoverageCount `json:"branches"`
+	Lines      CoverageCount `json:"lines"`
+
+	// LineHits are the executions of the lines with statements, those of their most executed statement
+	LineHits map[int]int `json:"lineHits"`
+
+	Markers []MarkerHits `json:"markers"`
+}
+
+// Report returns the coverage of the files of the map, sorted by name
+func (c *Coverage) Report() []FileCoverage {
+	report := []FileCoverage{}
+	files := make(map[string]*FileCoverage)
+	for _, marker := range c.Map.Markers {
+		f, ok := files[marker.SourceLocation.File]
+		if !ok {
+			f = &FileCoverage{File: marker.SourceLocation.File, LineHits: make(map[int]int)}
+			files[f.File] = f
+		}
+		hits := c.Hits[marker.ID]
+		f.Markers = append(f.Markers, MarkerHits{CoverageMarker: marker, Hits: hits})
+		switch marker.Kind {
+		case CoverageFunction:
+			f.Functions.add(hits)
+		case CoverageBranch:
+			f.Branches.add(hits)
+		case CoverageStatement:
+			f.Statements.add(hits)
+			if h, ok := f.LineHits[marker.Line]; !ok || hits > h {
+				f.LineHits[marker.Line] = hits
+			}
+		}
+	}
+	for _, f := range files {
+		for _, hits := range f.LineHits {
+			f.Lines.add(hits)
+		}
+		report = append(report, *f)
+	}
+	sort.Slice(report, func(i, j int) bool { return report[i].File < report[j].File })
+	return report
+}
+
+// WriteLCOV writes a coverage report in the LCOV tracefile format read by coverage tools (genhtml, Codecov...)
+func WriteLCOV(w io.Writer, report []FileCoverage) error {
+	var b strings.Builder
+	for _, f := range report {
+		fmt.Fprintf(&b, "TN:\nSF:%v\n", f.File)
+		for _, m := range f.Markers {
+			if m.Kind == CoverageFunction {
+				fmt.Fprintf(&b, "FN:%v,%v\n", m.Line, m.Name)
+			}
+		}
+		for _, m := range f.Markers {
+			if m.Kind == CoverageFunction {
+				fmt.Fprintf(&b, "FNDA:%v,%v\n", m.Hits, m.Name)
+			}
+		}
+		fmt.Fprintf(&b, "FNF:%v\nFNH:%v\n", f.Functions.Total, f.Functions.Covered)
+
+		lines := make([]int, 0, len(f.LineHits))
+		for line := range f.LineHits {
+			lines = append(lines, line)
+		}
+		sort.Ints(lines)
+		for _, line := range lines {
+			fmt.Fprintf(&b, "DA:%v,%v\n", line, f.LineHits[line])
+		}
+		fmt.Fprintf(&b, "LF:%v\nLH:%v\n", f.Lines.Total, f.Lines.Covered)
+
+		for _, m := range f.Markers {
+			if m.Kind == CoverageBranch {
+				fmt.Fprintf(&b, "BRDA:%v,%v,%v,%v\n", m.Line, m.Block, m.Branch, m.Hits)
+			}
+		}
+		fmt.Fprintf(&b, "BRF:%v\nBRH:%v\nend_of_record\n", f.Branches.Total, f.Branches.Covered)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
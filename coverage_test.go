@@ -0,0 +1,113 @@
+package solc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const coverageSource = `pragma solidity ^0.6.0;
+contract A {
+    uint x;
+    modifier m() { _; }
+    constructor() public { x = 1; }
+    function f(uint a) public m returns (uint) {
+        if (a > 1) x = 1; // one
+        else if (a == 0) { x = 2; }
+        for (uint i = 0; i < a; i++) x += i;
+        while (a > 0) a--;
+        do { a++; } while (a < 2);
+        return a > 2 ? 1 : 2;
+    }
+    function g() external pure returns (uint) { return 1; }
+}
+interface I { function i() external; }
+`
+
+func TestInstrument(t *testing.T) {
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err, "Creating Solc from valid solc emscripten binary should not error")
+	defer solc.Close()
+
+	in, m, err := InstrumentCoverage(context.Background(), solc, NewInput().AddSource("A.sol", coverageSource))
+	require.NoError(t, err, "InstrumentCoverage should not error")
+	assert.False(t, in.Settings.Optimizer.Enabled, "Optimizer should be disabled")
+
+	kinds := make(map[CoverageKind]int)
+	var g []CoverageMarker
+	for _, marker := range m.Markers {
+		kinds[marker.Kind]++
+		if marker.Line == 14 {
+			g = append(g, marker)
+		}
+	}
+	assert.Equal(t, map[CoverageKind]int{CoverageFunction: 4, CoverageStatement: 14, CoverageBranch: 4}, kinds)
+	require.Len(t, g, 2)
+	assert.Equal(t, CoverageMarker{ID: g[0].ID, Kind: CoverageFunction, Name: "g", SourceLocation: SourceLocation{File: "A.sol", Start: 374, End: 429}, Line: 14}, g[0])
+	assert.Equal(t, "return 1", coverageSource[g[1].SourceLocation.Start:g[1].SourceLocation.End])
+
+	out, err := solc.Compile(in)
+	require.NoError(t, err, "Compile should not error")
+	require.Empty(t, out.Errors, "Instrumented sources should compile")
+	code := out.Contracts["A.sol"]["A"].EVM.Bytecode.Object
+	for _, marker := range m.Markers {
+		assert.Contains(t, code, "7f"+marker.ID[2:], "Marker %v should be pushed", marker.ID)
+	}
+
+	// Executions of g
+	coverage := NewCoverage(m)
+	trace := []TraceStep{
+		{Op: "PUSH32", Stack: []string{}},
+		{Op: "POP", Stack: []string{"0x1", strings.ToUpper(g[0].ID)}},
+		{Op: "POP", Stack: []string{strings.TrimPrefix(g[1].ID, "0x")}},
+		{Op: "POP", Stack: []string{"0x2"}},
+	}
+	coverage.AddTrace(trace)
+	coverage.AddTrace(trace)
+	assert.Equal(t, map[string]int{g[0].ID: 2, g[1].ID: 2}, coverage.Hits)
+
+	report := coverage.Report()
+	require.Len(t, report, 1)
+	assert.Equal(t, CoverageCount{Total: 4, Covered: 1}, report[0].Functions)
+	assert.Equal(t, CoverageCount{Total: 14, Covered: 1}, report[0].Statements)
+	assert.Equal(t, CoverageCount{Total: 4}, report[0].Branches)
+	assert.Equal(t, 2, report[0].LineHits[14])
+	assert.Equal(t, 25.0, report[0].Functions.Percent())
+
+	var lcov bytes.Buffer
+	require.NoError(t, WriteLCOV(&lcov, report), "WriteLCOV should not error")
+	assert.Contains(t, lcov.String(), "SF:A.sol\nFN:4,m\nFN:5,constructor\n")
+	assert.Contains(t, lcov.String(), "FNDA:2,g\nFNF:4\nFNH:1\n")
+	assert.Contains(t, lcov.String(), "DA:14,2\n")
+	assert.Contains(t, lcov.String(), "BRDA:7,0,0,0\nBRDA:8,0,1,0\nBRDA:8,1,1,0\nBRDA:8,1,0,0\n")
+	assert.True(t, strings.HasSuffix(lcov.String(), "BRF:4\nBRH:0\nend_of_record\n"))
+
+	b, err := json.Marshal(m)
+	require.NoError(t, err)
+	var decoded CoverageMap
+	require.NoError(t, json.Unmarshal(b, &decoded), "Coverage map should decode from JSON")
+	assert.Equal(t, m, &decoded)
+}
+
+func TestWithCoverage(t *testing.T) {
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js", WithCoverage())
+	require.NoError(t, err, "Creating Solc with coverage should not error")
+	defer solc.Close()
+
+	out, err := solc.Compile(NewInput().AddSource("A.sol", coverageSource))
+	require.NoError(t, err, "Compile should not error")
+	require.Empty(t, out.Errors)
+	require.NotNil(t, out.Coverage, "Coverage map should be attached")
+	assert.Len(t, out.Coverage.Markers, 22)
+	assert.Contains(t, out.Contracts["A.sol"]["A"].EVM.DeployedBytecode.Object, out.Coverage.Markers[0].ID[2:])
+
+	out, err = solc.Compile(NewInput().AddSource("B.sol", "contract B {"))
+	require.NoError(t, err, "Compile should not error")
+	assert.NotEmpty(t, out.Errors, "Errors should be reported")
+	assert.Nil(t, out.Coverage, "Inputs not compiling should not be instrumented")
+}
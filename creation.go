@@ -0,0 +1,155 @@
+package solc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// CreationCode is the input of a contract creation transaction split along a compiled contract
+type CreationCode struct {
+	// Constructor is the code run at deployment preceding the runtime code it returns
+	Constructor []byte
+
+	// Runtime is the runtime code embedded in the creation code, Metadata included
+	Runtime []byte
+
+	// Metadata is the CBOR encoded metadata ending Runtime with its 2 bytes length, empty if there is none
+	Metadata []byte
+
+	// MetadataMatch indicates whether Metadata is the one of the compiled contract, contracts compiled
+	// from other source paths or comments differ only by their metadata (partial match)
+	MetadataMatch bool
+
+	// Data is the creation code following the runtime code, usually empty
+	Data []byte
+
+	// Args are the ABI encoded constructor arguments appended to the creation code
+	Args []byte
+}
+
+// CreationMismatchError is returned when a creation input does not match the compiled contract
+type CreationMismatchError struct {
+	// Offset is the offset of the first differing byte of the input
+	Offset int
+}
+
+func (e *CreationMismatchError) Error() string {
+	return fmt.Sprintf("solc: creation code differs from the compiled contract at byte %v", e.Offset)
+}
+
+// SplitCreationCode splits the input of a transaction creating contract into constructor code, runtime
+// code and constructor arguments
+//
+// The input must match the creation bytecode of contract, except for linked libraries and metadata,
+// which may differ in length. It requires the evm.bytecode and evm.deployedBytecode outputs and returns
+// a *CreationMismatchError if the input does not match
+func SplitCreationCode(contract *Contract, input []byte) (*CreationCode, error) {
+	creation := strings.TrimPrefix(contract.EVM.Bytecode.Object, "0x")
+	deployed := strings.TrimPrefix(contract.EVM.DeployedBytecode.Object, "0x")
+	start := strings.Index(creation, deployed)
+	if creation == "" || deployed == "" || start%2 != 0 {
+		return nil, fmt.Errorf("solc: splitting creation code requires the evm.bytecode and evm.deployedBytecode outputs")
+	}
+	constructor, constructorMask, err := decodeObject(creation[:start])
+	if err != nil {
+		return nil, err
+	}
+	runtime, runtimeMask, err := decodeObject(deployed)
+	if err != nil {
+		return nil, err
+	}
+	data, dataMask, err := decodeObject(creation[start+len(deployed):])
+	if err != nil {
+		return nil, err
+	}
+	metadata := runtime[len(runtime)-metadataLength(runtime):]
+	code := runtime[:len(runtime)-len(metadata)]
+
+	offset := 0
+	match := func(expected []byte, wildcards []bool) error {
+		for i := range expected {
+			if offset+i >= len(input) || (!wildcards[i] && input[offset+i] != expected[i]) {
+				return &CreationMismatchError{Offset: offset + i}
+			}
+		}
+		offset += len(expected)
+		return nil
+	}
+
+	split := &CreationCode{}
+	if err := match(constructor, constructorMask); err != nil {
+		return nil, err
+	}
+	split.Constructor = input[:offset]
+	if err := match(code, runtimeMask); err != nil {
+		return nil, err
+	}
+
+	// The metadata of the compiled contract is tried first, a scan finds metadata of another length
+	n := len(metadata)
+	if n > 0 && !validMetadata(input[offset:], n) {
+		for n = 3; n <= len(input)-offset && !validMetadata(input[offset:], n); n++ {
+		}
+		if n > len(input)-offset {
+			return nil, &CreationMismatchError{Offset: offset}
+		}
+	}
+	split.Metadata = input[offset : offset+n]
+	split.MetadataMatch = bytes.Equal(split.Metadata, metadata)
+	split.Runtime = input[len(split.Constructor) : offset+n]
+	offset += n
+
+	dataStart := offset
+	if err := match(data, dataMask); err != nil {
+		return nil, err
+	}
+	split.Data = input[dataStart:offset]
+	split.Args = input[offset:]
+	return split, nil
+}
+
+// decodeObject decodes a hex object, library placeholders decode as wildcards set in the returned mask
+func decodeObject(obj string) ([]byte, []bool, error) {
+	code := make([]byte, len(obj)/2)
+	wildcards := make([]bool, len(code))
+	for i := 0; i < len(code); {
+		if strings.HasPrefix(obj[2*i:], "__") {
+			for j := i; j < i+placeholderLen/2 && j < len(code); j++ {
+				wildcards[j] = true
+			}
+			i += placeholderLen / 2
+			continue
+		}
+		_, err := hex.Decode(code[i:i+1], []byte(obj[2*i:2*i+2]))
+		if err != nil {
+			return nil, nil, fmt.Errorf("solc: invalid bytecode: %v", err)
+		}
+		i++
+	}
+	return code, wildcards, nil
+}
+
+// metadataLength returns the length of the CBOR metadata solc appends to runtime code, its 2 bytes
+// length included, 0 if there is none
+func metadataLength(code []byte) int {
+	if len(code) < 2 {
+		return 0
+	}
+	n := int(binary.BigEndian.Uint16(code[len(code)-2:])) + 2
+	if n > len(code) || !validMetadata(code[len(code)-n:], n) {
+		return 0
+	}
+	return n
+}
+
+// validMetadata indicates whether code starts with n bytes of metadata, a CBOR map followed by its length
+func validMetadata(code []byte, n int) bool {
+	if n < 3 || n > len(code) {
+		return false
+	}
+	// Major type 5 with less than 24 entries
+	return code[0] >= 0xa1 && code[0] <= 0xb7 && int(binary.BigEndian.Uint16(code[n-2:n])) == n-2
+}
@@ -0,0 +1,70 @@
+package solc
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const creationSource = `pragma solidity ^0.6.2;
+library Math {
+    function one() public pure returns (uint256) { return 1; }
+}
+contract Created {
+    uint256 public value;
+    string public name;
+    constructor(uint256 v, string memory n) public { value = v + Math.one(); name = n; }
+}
+`
+
+func TestSplitCreationCode(t *testing.T) {
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err, "Creating compiler should not error")
+	defer solc.Close()
+	out, err := CompileSource(solc, "Created.sol", creationSource)
+	require.NoError(t, err, "Compile should not error")
+	created, _ := out.Contract("Created.sol", "Created")
+	require.Contains(t, created.EVM.Bytecode.Object, "__$", "Creation code should reference the library")
+
+	library, _ := HexToAddress("0x00000000000000000000000000000000000000aa")
+	linked, err := Link(created.EVM.Bytecode, map[string]Address{"Math": library})
+	require.NoError(t, err, "Link should not error")
+	args, err := EncodeConstructorArgs(*created, 41, "answer")
+	require.NoError(t, err, "EncodeConstructorArgs should not error")
+	input, err := hex.DecodeString(linked.Object + args)
+	require.NoError(t, err, "Decoding input should not error")
+
+	split, err := SplitCreationCode(created, input)
+	require.NoError(t, err, "SplitCreationCode should not error")
+	assert.Equal(t, args, hex.EncodeToString(split.Args), "Constructor arguments should be split")
+	assert.Equal(t, created.EVM.DeployedBytecode.Object, hex.EncodeToString(split.Runtime), "Runtime code should be split")
+	assert.Equal(t, len(input)-len(split.Runtime)-len(split.Args), len(split.Constructor), "Constructor code should precede the runtime code")
+	assert.Empty(t, split.Data, "Creation code should end with the runtime code")
+	assert.True(t, split.MetadataMatch, "Metadata should match")
+	assert.Equal(t, metadataLength(split.Runtime), len(split.Metadata))
+	assert.True(t, strings.HasSuffix(hex.EncodeToString(split.Runtime), hex.EncodeToString(split.Metadata)), "Metadata should end the runtime code")
+
+	// Metadata of another length (bzzr0 of solc <0.6.0)
+	bzzr := "a165627a7a72305820" + strings.Repeat("11", 32) + "0029"
+	code := hex.EncodeToString(split.Constructor) + strings.TrimSuffix(hex.EncodeToString(split.Runtime), hex.EncodeToString(split.Metadata))
+	input, _ = hex.DecodeString(code + bzzr + args)
+	split, err = SplitCreationCode(created, input)
+	require.NoError(t, err, "SplitCreationCode should not error")
+	assert.False(t, split.MetadataMatch, "Metadata should not match")
+	assert.Equal(t, bzzr, hex.EncodeToString(split.Metadata), "Metadata should be found")
+	assert.Equal(t, args, hex.EncodeToString(split.Args), "Constructor arguments should be split")
+
+	// Code mismatch
+	input[10] ^= 0xff
+	_, err = SplitCreationCode(created, input)
+	require.IsType(t, &CreationMismatchError{}, err, "Modified code should not match")
+	assert.Equal(t, 10, err.(*CreationMismatchError).Offset)
+	_, err = SplitCreationCode(created, input[:8])
+	assert.IsType(t, &CreationMismatchError{}, err, "Truncated input should not match")
+
+	_, err = SplitCreationCode(&Contract{}, input)
+	assert.Error(t, err, "Missing bytecode should error")
+}
@@ -0,0 +1,118 @@
+package solc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+
+	"github.com/nmvalera/solc-go/types"
+)
+
+// DecoderTable maps event topics (topic0) and error selectors to the ABI entries decoding them, to ship
+// with a build to services decoding its logs and reverts
+//
+// It encodes to JSON as is and to Go source with GoSource
+type DecoderTable struct {
+	// Events are by 0x prefixed lowercase topic, anonymous events have none and are left out
+	Events map[string][]Decoder `json:"events"`
+
+	// Errors are by 0x prefixed lowercase selector
+	Errors map[string][]Decoder `json:"errors"`
+}
+
+// Decoder is an ABI entry and the contracts declaring or using it, sorted
+//
+// Entries with the same selector may differ by their parameter names or indexed parameters, and then
+// decode differently
+type Decoder struct {
+	ABI       ABIEntry `json:"abi"`
+	Contracts []string `json:"contracts"`
+}
+
+// NewDecoderTable returns the decoders of the events and errors of the contracts of out
+//
+// It requires the abi output
+func NewDecoderTable(out *Output) (*DecoderTable, error) {
+	t := &DecoderTable{Events: make(map[string][]Decoder), Errors: make(map[string][]Decoder)}
+	for _, id := range out.ContractIDs() {
+		entries, err := ParseABI(out.Contracts[id.File][id.Name].ABI)
+		if err != nil {
+			return nil, fmt.Errorf("solc: invalid ABI of %v: %v", id, err)
+		}
+		for _, entry := range entries {
+			switch {
+			case entry.Type == "event" && !entry.Anonymous:
+				t.Events[entry.Selector()] = addDecoder(t.Events[entry.Selector()], entry, id.String())
+			case entry.Type == "error":
+				t.Errors[entry.Selector()] = addDecoder(t.Errors[entry.Selector()], entry, id.String())
+			}
+		}
+	}
+	return t, nil
+}
+
+// addDecoder adds contract to the decoder of entry, creating it if missing
+func addDecoder(decoders []Decoder, entry ABIEntry, contract string) []Decoder {
+	b, _ := json.Marshal(entry)
+	for i, d := range decoders {
+		if other, _ := json.Marshal(d.ABI); bytes.Equal(b, other) {
+			decoders[i].Contracts = append(d.Contracts, contract)
+			sort.Strings(decoders[i].Contracts)
+			return decoders
+		}
+	}
+	return append(decoders, Decoder{ABI: entry, Contracts: []string{contract}})
+}
+
+// Event returns the decoders of logs with topic0, which may be upper case or miss its 0x prefix
+func (t *DecoderTable) Event(topic0 string) []Decoder {
+	return t.Events[types.NormalizeSelector(topic0)]
+}
+
+// Error returns the decoders of revert data starting with selector, which may be upper case or miss its 0x prefix
+func (t *DecoderTable) Error(selector string) []Decoder {
+	return t.Errors[types.NormalizeSelector(selector)]
+}
+
+// GoSource returns the formatted source of a Go file of package pkg declaring the table as EventABIs and
+// ErrorABIs, maps of topics and selectors to the JSON ABI entries decoding them
+//
+// Generated code only depends on the standard library, entries decode with any ABI package (e.g. the
+// abi.JSON of go-ethereum once wrapped in brackets)
+func (t *DecoderTable) GoSource(pkg string) ([]byte, error) {
+	if !token.IsIdentifier(pkg) {
+		return nil, fmt.Errorf("solc: invalid package name %q", pkg)
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by solc-go. DO NOT EDIT.\n\npackage %v\n\n", pkg)
+	for _, table := range []struct {
+		name, doc string
+		decoders  map[string][]Decoder
+	}{
+		{"EventABIs", "EventABIs are the JSON ABI entries of events by topic0", t.Events},
+		{"ErrorABIs", "ErrorABIs are the JSON ABI entries of errors by selector", t.Errors},
+	} {
+		keys := make([]string, 0, len(table.decoders))
+		for key := range table.decoders {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fmt.Fprintf(&buf, "// %v\nvar %v = map[string][]string{\n", table.doc, table.name)
+		for _, key := range keys {
+			fmt.Fprintf(&buf, "%q: {\n", key)
+			for _, d := range table.decoders[key] {
+				b, err := json.Marshal(d.ABI)
+				if err != nil {
+					return nil, err
+				}
+				fmt.Fprintf(&buf, "// %v\n%q,\n", d.ABI.Signature(), b)
+			}
+			fmt.Fprintf(&buf, "},\n")
+		}
+		fmt.Fprintf(&buf, "}\n\n")
+	}
+	return format.Source(buf.Bytes())
+}
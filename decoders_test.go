@@ -0,0 +1,61 @@
+package solc
+
+import (
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecoderTable(t *testing.T) {
+	transfer := json.RawMessage(`{"type": "event", "name": "Transfer", "inputs": [{"name": "from", "type": "address", "indexed": true}, {"name": "to", "type": "address", "indexed": true}, {"name": "value", "type": "uint256"}]}`)
+	out := &Output{Contracts: map[string]map[string]Contract{
+		"Token.sol": {
+			"Token": {ABI: []json.RawMessage{
+				transfer,
+				json.RawMessage(`{"type": "event", "name": "Secret", "inputs": [], "anonymous": true}`),
+				json.RawMessage(`{"type": "error", "name": "Unauthorized", "inputs": [{"name": "user", "type": "address"}]}`),
+				json.RawMessage(`{"type": "function", "name": "transfer", "inputs": [{"name": "to", "type": "address"}, {"name": "value", "type": "uint256"}]}`),
+			}},
+			"ERC721": {ABI: []json.RawMessage{
+				json.RawMessage(`{"type": "event", "name": "Transfer", "inputs": [{"name": "from", "type": "address", "indexed": true}, {"name": "to", "type": "address", "indexed": true}, {"name": "tokenId", "type": "uint256", "indexed": true}]}`),
+			}},
+		},
+		"Vault.sol": {
+			"Vault": {ABI: []json.RawMessage{transfer}},
+		},
+	}}
+
+	table, err := NewDecoderTable(out)
+	require.NoError(t, err, "NewDecoderTable should not error")
+	assert.Len(t, table.Events, 1, "Anonymous events should be left out")
+	decoders := table.Event("0xDDF252AD1BE2C89B69C2B068FC378DAA952BA7F163C4A11628F55A4DF523B3EF")
+	require.Len(t, decoders, 2, "Events with different indexed parameters should decode separately")
+	assert.Equal(t, []string{"Token.sol:ERC721"}, decoders[0].Contracts)
+	assert.Equal(t, []string{"Token.sol:Token", "Vault.sol:Vault"}, decoders[1].Contracts, "Identical entries should be shared")
+	assert.Equal(t, "value", decoders[1].ABI.Inputs[2].Name)
+
+	errors := table.Error("8e4a23d6")
+	require.Len(t, errors, 1)
+	assert.Equal(t, "Unauthorized(address)", errors[0].ABI.Signature())
+
+	b, err := json.Marshal(table)
+	require.NoError(t, err)
+	var decoded DecoderTable
+	require.NoError(t, json.Unmarshal(b, &decoded), "Table should decode from JSON")
+	assert.Equal(t, table, &decoded)
+
+	src, err := table.GoSource("decoders")
+	require.NoError(t, err, "GoSource should not error")
+	_, err = parser.ParseFile(token.NewFileSet(), "decoders.go", src, 0)
+	require.NoError(t, err, "Generated source should parse")
+	assert.Contains(t, string(src), "var EventABIs = map[string][]string{")
+	assert.Contains(t, string(src), `"0x8e4a23d6": {`)
+	assert.Contains(t, string(src), "// Unauthorized(address)")
+
+	_, err = table.GoSource("invalid-name")
+	assert.Error(t, err, "Invalid package names should error")
+}
@@ -0,0 +1,97 @@
+package solc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+)
+
+// NondeterminismError is returned by CheckDeterminism when a compilation output differs from the first one
+type NondeterminismError struct {
+	// Run is the index of the diverging compilation, the first one being 0
+	Run int
+
+	// Line is the first line of the deterministic JSON of the outputs that differs (see MarshalDeterministic),
+	// Expected its content in the first output and Got in the diverging one
+	Line     int
+	Expected string
+	Got      string
+}
+
+func (e *NondeterminismError) Error() string {
+	return fmt.Sprintf("solc: compilation %v diverges at line %v of the output: expected %q, got %q", e.Run, e.Line, e.Expected, e.Got)
+}
+
+// CheckDeterminism compiles in n times and checks the outputs are byte-identical once deterministically
+// encoded (see MarshalDeterministic), a *NondeterminismError reporting the first divergence is returned otherwise
+//
+// Compilations run one after the other, or concurrently across the instances of a *Pool, each compiling
+// the input on its own (identical inputs are otherwise compiled once by a pool). Compilers serving
+// compilations from cache (see WithCache) always pass
+//
+// Outputs only depend on the input: sources loaded on machines checking files out with other line endings
+// or encodings still build different metadata, see SourceNormalization
+func CheckDeterminism(solc Solc, in *Input, n int) error {
+	if n < 2 {
+		return fmt.Errorf("solc: checking determinism needs 2 compilations or more, got %v", n)
+	}
+
+	outputs := make([][]byte, n)
+	errs := make([]error, n)
+	run := func(i int, compile func(*Input) (*Output, error)) {
+		out, err := compile(in)
+		if err != nil {
+			errs[i] = err
+			return
+		}
+		outputs[i], errs[i] = out.MarshalDeterministic()
+	}
+
+	if p, ok := solc.(*Pool); ok {
+		ctx := context.Background()
+		compile := func(in *Input) (*Output, error) { return p.compile(ctx, in) }
+		var wg sync.WaitGroup
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				run(i, compile)
+			}(i)
+		}
+		wg.Wait()
+	} else {
+		for i := 0; i < n; i++ {
+			run(i, solc.Compile)
+		}
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	for i := 1; i < n; i++ {
+		if !bytes.Equal(outputs[0], outputs[i]) {
+			return divergence(i, outputs[0], outputs[i])
+		}
+	}
+	return nil
+}
+
+// divergence returns the error reporting the first line of got differing from expected
+func divergence(run int, expected, got []byte) *NondeterminismError {
+	expectedLines, gotLines := bytes.Split(expected, []byte("\n")), bytes.Split(got, []byte("\n"))
+	for i := 0; ; i++ {
+		var e, g []byte
+		if i < len(expectedLines) {
+			e = expectedLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if !bytes.Equal(e, g) || i >= len(expectedLines) || i >= len(gotLines) {
+			return &NondeterminismError{Run: run, Line: i + 1, Expected: string(e), Got: string(g)}
+		}
+	}
+}
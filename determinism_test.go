@@ -0,0 +1,56 @@
+package solc
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sequenceSolc outputs the number of compilations it ran from the fourth one on
+type sequenceSolc struct {
+	fakeSolc
+	mux      *sync.Mutex
+	compiles *int
+}
+
+func (s *sequenceSolc) Compile(input *Input) (*Output, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	*s.compiles++
+	metadata := "stable"
+	if *s.compiles > 3 {
+		metadata = fmt.Sprint(*s.compiles)
+	}
+	return &Output{Contracts: map[string]map[string]Contract{"A.sol": {"A": {Metadata: metadata}}}}, nil
+}
+
+func TestCheckDeterminism(t *testing.T) {
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err, "Creating compiler should not error")
+	defer solc.Close()
+	in := NewInput().AddSource("A.sol", "pragma solidity ^0.6.0;\ncontract A { function f() public pure returns (uint) { return 1; } }")
+	assert.NoError(t, CheckDeterminism(solc, in, 2), "Compilation should be deterministic")
+
+	var mux sync.Mutex
+	var compiles int
+	p, err := NewPool(func() (Solc, error) {
+		return &sequenceSolc{mux: &mux, compiles: &compiles}, nil
+	}, 2)
+	require.NoError(t, err, "NewPool should not error")
+	defer p.Close()
+
+	assert.NoError(t, CheckDeterminism(p, in, 3), "Identical outputs should pass")
+	assert.Equal(t, 3, compiles, "Every compilation should run on the pool")
+
+	err = CheckDeterminism(p, in, 3)
+	require.IsType(t, &NondeterminismError{}, err, "Diverging outputs should error")
+	e := err.(*NondeterminismError)
+	assert.Equal(t, 1, e.Run, "Divergence should be reported against the first output")
+	assert.Contains(t, e.Expected, `"metadata": "`)
+	assert.NotEqual(t, e.Expected, e.Got)
+
+	assert.Error(t, CheckDeterminism(p, in, 1), "A single compilation should error")
+}
@@ -0,0 +1,178 @@
+package solc
+
+import (
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Position is a zero based position in a document, the character offset is in UTF-16 code units as in LSP
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// Range is a range in a document, End is exclusive
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// Diagnostic is a compiler error in the shape of a LSP diagnostic
+type Diagnostic struct {
+	Range    Range              `json:"range"`
+	Severity DiagnosticSeverity `json:"severity"`
+	Code     string             `json:"code,omitempty"`
+	Source   string             `json:"source"`
+	Message  string             `json:"message"`
+}
+
+// Diagnostician computes the diagnostics of solidity documents, for editor and language server integrations
+//
+// Only analysis runs, no code is generated, and the diagnostics of a document are cached until it or one of its imports changes
+type Diagnostician struct {
+	Solc Solc
+
+	// Resolver loads the imports of documents, it is optional and unresolved imports are reported as diagnostics
+	Resolver ImportResolver
+
+	// ParseOnly only reports syntax errors with compilers supporting it (see Settings.StopAfter),
+	// other compilers report analysis errors as well
+	ParseOnly bool
+
+	mux         sync.Mutex
+	last        map[string]diagnosticsEntry
+	noStopAfter bool
+}
+
+type diagnosticsEntry struct {
+	key         string
+	diagnostics []Diagnostic
+}
+
+// Diagnostics returns the diagnostics of the document uri which content is text
+func (d *Diagnostician) Diagnostics(uri, text string) ([]Diagnostic, error) {
+	sources := map[string]SourceIn{uri: SourceIn{Content: text}}
+	queue := []string{uri}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, imp := range ParseImports(sources[name].Content) {
+			dep := ImportPath(name, imp.Path)
+			if _, ok := sources[dep]; ok {
+				continue
+			}
+			// Unresolved imports are left to the compiler to report
+			content, err := loadSource(dep, sources, d.Resolver)
+			if err != nil {
+				continue
+			}
+			sources[dep] = SourceIn{Content: content}
+			queue = append(queue, dep)
+		}
+	}
+
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	input := d.input(sources)
+	key := buildKey(d.Solc.Version(), input)
+	if entry, ok := d.last[uri]; ok && entry.key == key {
+		return entry.diagnostics, nil
+	}
+
+	out, err := d.Solc.Compile(input)
+	if err != nil {
+		return nil, err
+	}
+	if input.Settings.StopAfter != "" && rejectsStopAfter(out) {
+		d.noStopAfter = true
+		input = d.input(sources)
+		key = buildKey(d.Solc.Version(), input)
+		out, err = d.Solc.Compile(input)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	diagnostics := []Diagnostic{}
+	for _, e := range out.Errors {
+		if e.SourceLocation.File != "" && e.SourceLocation.File != uri {
+			continue
+		}
+		diagnostics = append(diagnostics, newDiagnostic(text, e))
+	}
+
+	if d.last == nil {
+		d.last = make(map[string]diagnosticsEntry)
+	}
+	d.last[uri] = diagnosticsEntry{key: key, diagnostics: diagnostics}
+
+	return diagnostics, nil
+}
+
+// input returns an input running analysis only, empty output selection skips code generation
+func (d *Diagnostician) input(sources map[string]SourceIn) *Input {
+	input := &Input{Language: "Solidity", Sources: sources}
+	if d.ParseOnly && !d.noStopAfter {
+		input.Settings.StopAfter = "parsing"
+	}
+	return input
+}
+
+func rejectsStopAfter(out *Output) bool {
+	for _, e := range out.Errors {
+		if e.Type == "JSONError" && strings.Contains(e.Message, "stopAfter") {
+			return true
+		}
+	}
+	return false
+}
+
+func newDiagnostic(text string, e Error) Diagnostic {
+	severity := SeverityError
+	switch e.Severity {
+	case "warning":
+		severity = SeverityWarning
+	case "info":
+		severity = SeverityInformation
+	}
+
+	code := e.ErrorCode
+	if code == "" {
+		code = e.Type
+	}
+
+	return Diagnostic{
+		Range: Range{
+			Start: positionOf(text, e.SourceLocation.Start),
+			End:   positionOf(text, e.SourceLocation.End),
+		},
+		Severity: severity,
+		Code:     code,
+		Source:   "solc",
+		Message:  e.Message,
+	}
+}
+
+// positionOf converts a byte offset of text into a LSP position
+func positionOf(text string, offset int) Position {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(text) {
+		offset = len(text)
+	}
+
+	line := strings.Count(text[:offset], "\n")
+	lineStart := strings.LastIndex(text[:offset], "\n") + 1
+
+	character := 0
+	for _, r := range text[lineStart:offset] {
+		character++
+		if r >= 0x10000 && r <= utf8.MaxRune {
+			character++
+		}
+	}
+
+	return Position{Line: line, Character: character}
+}
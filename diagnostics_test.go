@@ -0,0 +1,66 @@
+package solc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingCompiler counts the compilations of the compiler it wraps
+type countingCompiler struct {
+	Solc
+	compiles int
+}
+
+func (c *countingCompiler) Compile(input *Input) (*Output, error) {
+	c.compiles++
+	return c.Solc.Compile(input)
+}
+
+func TestDiagnostics(t *testing.T) {
+	compiler, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err, "Creating Solc from valid solc emscripten binary should not error")
+	defer compiler.Close()
+	counting := &countingCompiler{Solc: compiler}
+
+	d := &Diagnostician{
+		Solc:      counting,
+		Resolver:  SourcesResolver{"lib/Lib.sol": SourceIn{Content: "pragma solidity ^0.6.0; library Lib {}"}},
+		ParseOnly: true,
+	}
+
+	text := "pragma solidity ^0.6.0;\nimport \"./lib/Lib.sol\";\n// ünïcode 🚀\ncontract A { function f() public { uint x = y; } }\n"
+	diagnostics, err := d.Diagnostics("A.sol", text)
+	require.NoError(t, err, "Diagnostics should not error")
+	require.Len(t, diagnostics, 1, "Undeclared identifier should be reported")
+	assert.Equal(t, Diagnostic{
+		Range:    Range{Start: Position{Line: 3, Character: 44}, End: Position{Line: 3, Character: 45}},
+		Severity: SeverityError,
+		Code:     "DeclarationError",
+		Source:   "solc",
+		Message:  "Undeclared identifier.",
+	}, diagnostics[0])
+	assert.Equal(t, 2, counting.compiles, "Unsupported parse only mode should fall back to analysis")
+
+	_, err = d.Diagnostics("A.sol", text)
+	require.NoError(t, err, "Diagnostics should not error")
+	assert.Equal(t, 2, counting.compiles, "Unchanged document should not be recompiled")
+
+	diagnostics, err = d.Diagnostics("A.sol", "pragma solidity ^0.6.0;\ncontract A { function f() public pure { uint x; } }\n")
+	require.NoError(t, err, "Diagnostics should not error")
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, SeverityWarning, diagnostics[0].Severity, "Unused variable should be a warning")
+	assert.Equal(t, 1, diagnostics[0].Range.Start.Line)
+
+	diagnostics, err = d.Diagnostics("B.sol", "pragma solidity ^0.6.0; import \"./Missing.sol\";")
+	require.NoError(t, err, "Diagnostics should not error")
+	require.Len(t, diagnostics, 1, "Unresolved import should be reported")
+	assert.Contains(t, diagnostics[0].Message, "not found")
+}
+
+func TestPositionOf(t *testing.T) {
+	assert.Equal(t, Position{Line: 0, Character: 0}, positionOf("abc", -1))
+	assert.Equal(t, Position{Line: 1, Character: 2}, positionOf("a\n🚀b", 6), "Characters should be counted in UTF-16 code units")
+	assert.Equal(t, Position{Line: 1, Character: 3}, positionOf("a\n🚀b", 100), "Offsets should be clamped")
+}
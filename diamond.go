@@ -0,0 +1,216 @@
+package solc
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nmvalera/solc-go/types"
+)
+
+// FacetCutAction is the action of a diamond cut (EIP-2535)
+type FacetCutAction uint8
+
+// Actions of facet cuts, in the order of IDiamondCut.FacetCutAction
+const (
+	FacetAdd FacetCutAction = iota
+	FacetReplace
+	FacetRemove
+)
+
+func (a FacetCutAction) String() string {
+	switch a {
+	case FacetAdd:
+		return "add"
+	case FacetReplace:
+		return "replace"
+	case FacetRemove:
+		return "remove"
+	default:
+		return fmt.Sprintf("FacetCutAction(%d)", uint8(a))
+	}
+}
+
+// Facet is the external functions of a facet contract of a diamond
+type Facet struct {
+	Contract ContractID `json:"contract"`
+
+	// Functions map 0x prefixed lowercase selectors to signatures
+	Functions map[string]string `json:"functions"`
+}
+
+// Selectors returns the selectors of the facet, sorted
+func (f *Facet) Selectors() []string {
+	selectors := make([]string, 0, len(f.Functions))
+	for selector := range f.Functions {
+		selectors = append(selectors, selector)
+	}
+	sort.Strings(selectors)
+	return selectors
+}
+
+// Cut returns the cut applying action to all the functions of the facet deployed at address
+func (f *Facet) Cut(action FacetCutAction, address Address) FacetCut {
+	if action == FacetRemove {
+		address = Address{}
+	}
+	return FacetCut{FacetAddress: address, Action: action, Selectors: f.Selectors()}
+}
+
+// NewFacets returns the facets of the contracts names (see FindContract) of out, from their ABI or
+// method identifiers
+func NewFacets(out *Output, names ...string) ([]*Facet, error) {
+	var facets []*Facet
+	for _, name := range names {
+		file, c, err := out.FindContract(name)
+		if err != nil {
+			return nil, err
+		}
+		id, _ := ParseContractID(name)
+		facet := &Facet{Contract: ContractID{File: file, Name: id.Name}, Functions: make(map[string]string)}
+		entries, err := ParseABI(c.ABI)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.Type == "function" {
+				facet.Functions[entry.Selector()] = entry.Signature()
+			}
+		}
+		for signature, selector := range c.EVM.MethodIdentifiers {
+			facet.Functions[types.NormalizeSelector(selector)] = signature
+		}
+		facets = append(facets, facet)
+	}
+	return facets, nil
+}
+
+// FacetCollision is a selector of several facets, a diamond routes it to a single one
+type FacetCollision struct {
+	Selector string `json:"selector"`
+
+	// Functions are sorted by contract
+	Functions []SelectorMatch `json:"functions"`
+}
+
+// FacetCollisions returns the selectors shared by several facets, sorted by selector
+//
+// Different signatures with the same selector and functions declared by several facets (e.g. a shared
+// base contract) both collide
+func FacetCollisions(facets []*Facet) []FacetCollision {
+	r := make(SelectorRegistry)
+	for _, f := range facets {
+		for selector, signature := range f.Functions {
+			r.AddMatch(selector, SelectorMatch{Contract: f.Contract, Type: "function", Signature: signature})
+		}
+	}
+
+	collisions := []FacetCollision{}
+	for selector, matches := range r {
+		if len(matches) > 1 {
+			collisions = append(collisions, FacetCollision{Selector: selector, Functions: matches})
+		}
+	}
+	sort.Slice(collisions, func(i, j int) bool { return collisions[i].Selector < collisions[j].Selector })
+	return collisions
+}
+
+// FacetCut is an IDiamondCut.FacetCut, FacetAddress is zero for FacetRemove
+type FacetCut struct {
+	FacetAddress Address        `json:"facetAddress"`
+	Action       FacetCutAction `json:"action"`
+	Selectors    []string       `json:"functionSelectors"`
+}
+
+// DiffDiamond returns the cuts turning a diamond routing the current selectors to facet addresses
+// into one routing the target ones, as one cut per action and address sorted by action then address
+//
+// Selectors may be upper case or miss their 0x prefix
+func DiffDiamond(current, target map[string]Address) []FacetCut {
+	current, target = normalizeFacets(current), normalizeFacets(target)
+
+	type key struct {
+		action  FacetCutAction
+		address Address
+	}
+	groups := make(map[key][]string)
+	for selector, address := range target {
+		previous, ok := current[selector]
+		switch {
+		case !ok:
+			groups[key{FacetAdd, address}] = append(groups[key{FacetAdd, address}], selector)
+		case previous != address:
+			groups[key{FacetReplace, address}] = append(groups[key{FacetReplace, address}], selector)
+		}
+	}
+	for selector := range current {
+		if _, ok := target[selector]; !ok {
+			groups[key{FacetRemove, Address{}}] = append(groups[key{FacetRemove, Address{}}], selector)
+		}
+	}
+
+	cuts := []FacetCut{}
+	for k, selectors := range groups {
+		sort.Strings(selectors)
+		cuts = append(cuts, FacetCut{FacetAddress: k.address, Action: k.action, Selectors: selectors})
+	}
+	sort.Slice(cuts, func(i, j int) bool {
+		if cuts[i].Action != cuts[j].Action {
+			return cuts[i].Action < cuts[j].Action
+		}
+		return cuts[i].FacetAddress.Hex() < cuts[j].FacetAddress.Hex()
+	})
+	return cuts
+}
+
+func normalizeFacets(facets map[string]Address) map[string]Address {
+	normalized := make(map[string]Address, len(facets))
+	for selector, address := range facets {
+		normalized[types.NormalizeSelector(selector)] = address
+	}
+	return normalized
+}
+
+// diamondCutInputs are the inputs of IDiamondCut.diamondCut
+var diamondCutInputs = []ABIParameter{
+	{Name: "_diamondCut", Type: "tuple[]", Components: []ABIParameter{
+		{Name: "facetAddress", Type: "address"},
+		{Name: "action", Type: "uint8"},
+		{Name: "functionSelectors", Type: "bytes4[]"},
+	}},
+	{Name: "_init", Type: "address"},
+	{Name: "_calldata", Type: "bytes"},
+}
+
+// EncodeDiamondCut returns the calldata of diamondCut(cuts, init, data), init is called with data
+// by the diamond after the cuts, zero to skip it
+//
+// It errors on invalid selectors, cuts without selectors, removals with a facet address and additions
+// or replacements without one
+func EncodeDiamondCut(cuts []FacetCut, init Address, data []byte) ([]byte, error) {
+	tuples := make([]interface{}, len(cuts))
+	for i, cut := range cuts {
+		if len(cut.Selectors) == 0 {
+			return nil, fmt.Errorf("solc: facet cut %v has no selectors", i)
+		}
+		if cut.Action > FacetRemove {
+			return nil, fmt.Errorf("solc: facet cut %v has invalid action %v", i, uint8(cut.Action))
+		}
+		if cut.Action == FacetRemove && cut.FacetAddress != (Address{}) {
+			return nil, fmt.Errorf("solc: facet cut %v removes selectors with a non zero facet address", i)
+		}
+		if cut.Action != FacetRemove && cut.FacetAddress == (Address{}) {
+			return nil, fmt.Errorf("solc: facet cut %v has no facet address", i)
+		}
+		tuples[i] = []interface{}{cut.FacetAddress, uint8(cut.Action), cut.Selectors}
+	}
+
+	args, err := EncodeABI(diamondCutInputs, tuples, init, data)
+	if err != nil {
+		return nil, err
+	}
+	selector := functionSelector(ABIEntry{Name: "diamondCut", Inputs: diamondCutInputs}.Signature())
+	calldata, _ := hex.DecodeString(strings.TrimPrefix(selector, "0x"))
+	return append(calldata, args...), nil
+}
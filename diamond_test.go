@@ -0,0 +1,130 @@
+package solc
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const facetsSource = `pragma solidity ^0.6.2;
+contract Ownable {
+    address owner;
+    function transferOwnership(address next) external { owner = next; }
+}
+contract TokenFacet is Ownable {
+    function balanceOf(address) external pure returns (uint256) { return 0; }
+    function burn(uint256) external {}
+}
+contract AdminFacet is Ownable {
+    function pause() external {}
+}
+contract ClashFacet {
+    function collate_propagate_storage(bytes16) external {}
+}
+`
+
+func TestFacets(t *testing.T) {
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err, "Creating compiler should not error")
+	defer solc.Close()
+	out, err := CompileSource(solc, "Facets.sol", facetsSource)
+	require.NoError(t, err, "Compile should not error")
+
+	facets, err := NewFacets(out, "TokenFacet", "AdminFacet", "Facets.sol:ClashFacet")
+	require.NoError(t, err, "NewFacets should not error")
+	assert.Equal(t, ContractID{File: "Facets.sol", Name: "TokenFacet"}, facets[0].Contract)
+	assert.Equal(t, []string{"0x42966c68", "0x70a08231", "0xf2fde38b"}, facets[0].Selectors(), "Selectors should be sorted")
+	assert.Equal(t, "balanceOf(address)", facets[0].Functions["0x70a08231"])
+
+	// collate_propagate_storage(bytes16) is a known clash of burn(uint256)
+	var collisions []string
+	for _, c := range FacetCollisions(facets) {
+		for _, f := range c.Functions {
+			collisions = append(collisions, c.Selector+" "+f.Contract.Name+"."+f.Signature)
+		}
+	}
+	assert.Equal(
+		t,
+		[]string{
+			"0x42966c68 ClashFacet.collate_propagate_storage(bytes16)",
+			"0x42966c68 TokenFacet.burn(uint256)",
+			"0xf2fde38b AdminFacet.transferOwnership(address)",
+			"0xf2fde38b TokenFacet.transferOwnership(address)",
+		},
+		collisions,
+		"Shared functions and clashing signatures should collide",
+	)
+	_, err = NewFacets(out, "Missing")
+	assert.Error(t, err, "Unknown facet should error")
+}
+
+func TestDiffDiamond(t *testing.T) {
+	a, _ := HexToAddress("0x000000000000000000000000000000000000000a")
+	b, _ := HexToAddress("0x000000000000000000000000000000000000000b")
+	cuts := DiffDiamond(
+		map[string]Address{"0x70a08231": a, "0xf2fde38b": a, "0x8456cb59": a},
+		map[string]Address{"70A08231": a, "0xf2fde38b": b, "0x3f4ba83a": b, "0x42966c68": a},
+	)
+	assert.Equal(
+		t,
+		[]FacetCut{
+			{FacetAddress: a, Action: FacetAdd, Selectors: []string{"0x42966c68"}},
+			{FacetAddress: b, Action: FacetAdd, Selectors: []string{"0x3f4ba83a"}},
+			{FacetAddress: b, Action: FacetReplace, Selectors: []string{"0xf2fde38b"}},
+			{Action: FacetRemove, Selectors: []string{"0x8456cb59"}},
+		},
+		cuts,
+		"Cuts should be grouped by action and address",
+	)
+	assert.Empty(t, DiffDiamond(map[string]Address{"0x70a08231": a}, map[string]Address{"0x70A08231": a}), "Identical diamonds should not be cut")
+}
+
+func TestEncodeDiamondCut(t *testing.T) {
+	a, _ := HexToAddress("0x000000000000000000000000000000000000000a")
+	facet := &Facet{Functions: map[string]string{"0xf2fde38b": "transferOwnership(address)", "0x70a08231": "balanceOf(address)"}}
+	calldata, err := EncodeDiamondCut(
+		[]FacetCut{facet.Cut(FacetAdd, a), facet.Cut(FacetRemove, a)},
+		a,
+		[]byte{0x12, 0x34},
+	)
+	require.NoError(t, err, "EncodeDiamondCut should not error")
+
+	// Matches the encoding of go-ethereum's abi.Pack
+	assert.Equal(
+		t,
+		"1f931c1c"+
+			"0000000000000000000000000000000000000000000000000000000000000060"+
+			"000000000000000000000000000000000000000000000000000000000000000a"+
+			"0000000000000000000000000000000000000000000000000000000000000240"+
+			"0000000000000000000000000000000000000000000000000000000000000002"+
+			"0000000000000000000000000000000000000000000000000000000000000040"+
+			"0000000000000000000000000000000000000000000000000000000000000100"+
+			"000000000000000000000000000000000000000000000000000000000000000a"+
+			"0000000000000000000000000000000000000000000000000000000000000000"+
+			"0000000000000000000000000000000000000000000000000000000000000060"+
+			"0000000000000000000000000000000000000000000000000000000000000002"+
+			"70a0823100000000000000000000000000000000000000000000000000000000"+
+			"f2fde38b00000000000000000000000000000000000000000000000000000000"+
+			"0000000000000000000000000000000000000000000000000000000000000000"+
+			"0000000000000000000000000000000000000000000000000000000000000002"+
+			"0000000000000000000000000000000000000000000000000000000000000060"+
+			"0000000000000000000000000000000000000000000000000000000000000002"+
+			"70a0823100000000000000000000000000000000000000000000000000000000"+
+			"f2fde38b00000000000000000000000000000000000000000000000000000000"+
+			"0000000000000000000000000000000000000000000000000000000000000002"+
+			"1234000000000000000000000000000000000000000000000000000000000000",
+		hex.EncodeToString(calldata),
+		"Calldata should be ABI encoded",
+	)
+
+	_, err = EncodeDiamondCut([]FacetCut{{Action: FacetAdd, Selectors: []string{"0x70a08231"}}}, Address{}, nil)
+	assert.Error(t, err, "Addition without facet address should error")
+	_, err = EncodeDiamondCut([]FacetCut{{FacetAddress: a, Action: FacetRemove, Selectors: []string{"0x70a08231"}}}, Address{}, nil)
+	assert.Error(t, err, "Removal with facet address should error")
+	_, err = EncodeDiamondCut([]FacetCut{{FacetAddress: a, Action: FacetAdd, Selectors: []string{"0x70a082"}}}, Address{}, nil)
+	assert.Error(t, err, "Invalid selector should error")
+	_, err = EncodeDiamondCut([]FacetCut{{FacetAddress: a, Action: FacetAdd}}, Address{}, nil)
+	assert.Error(t, err, "Cut without selectors should error")
+}
@@ -0,0 +1,78 @@
+package solc
+
+import (
+	"fmt"
+	"io"
+
+	"rogchap.com/v8go"
+)
+
+// Phase is the step of the compiler lifecycle during which a JS exception was thrown
+type Phase string
+
+const (
+	PhaseInit    Phase = "init"
+	PhaseVersion Phase = "version"
+	PhaseLicense Phase = "license"
+	PhaseCompile Phase = "compile"
+	PhaseReset   Phase = "reset"
+)
+
+// JSError is a JavaScript exception thrown by the soljson emscripten module
+//
+// Formatting it with %+v outputs the JavaScript stack trace, if available
+type JSError struct {
+	Phase      Phase
+	Message    string
+	Location   string
+	StackTrace string
+}
+
+func (e *JSError) Error() string {
+	return fmt.Sprintf("solc: %v: %v", e.Phase, e.Message)
+}
+
+func (e *JSError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') && e.StackTrace != "" {
+			fmt.Fprintf(s, "solc: %v: %v", e.Phase, e.StackTrace)
+			return
+		}
+		fallthrough
+	case 's':
+		_, _ = io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		fmt.Fprint(s, e.Error())
+	}
+}
+
+// SizeLimitError is returned by Compile when the input or output exceeds the configured size
+type SizeLimitError struct {
+	// Kind is "input" or "output" for compilers, or the limit exceeded for InputLimits
+	Kind  string
+	Size  int
+	Limit int
+}
+
+func (e *SizeLimitError) Error() string {
+	return fmt.Sprintf("solc: %v size %v exceeds limit of %v", e.Kind, e.Size, e.Limit)
+}
+
+// wrapJSError converts v8go exceptions into a *JSError for the given phase
+func wrapJSError(phase Phase, err error) error {
+	if err == nil {
+		return nil
+	}
+	if jsErr, ok := err.(*v8go.JSError); ok {
+		return &JSError{
+			Phase:      phase,
+			Message:    jsErr.Message,
+			Location:   jsErr.Location,
+			StackTrace: jsErr.StackTrace,
+		}
+	}
+	return err
+}
@@ -0,0 +1,129 @@
+package solc
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// EventKind identifies a compiler lifecycle event
+type EventKind string
+
+const (
+	// EventInit is emitted once a compiler instance (and its isolate) is initialized
+	EventInit EventKind = "init"
+
+	// EventCompileStart is emitted when a compilation starts running in the isolate
+	EventCompileStart EventKind = "compile_start"
+
+	// EventCompile is emitted when a compilation completes, successfully or not
+	EventCompile EventKind = "compile"
+
+	// EventCacheHit and EventCacheMiss are emitted by compilers configured with a cache
+	EventCacheHit  EventKind = "cache_hit"
+	EventCacheMiss EventKind = "cache_miss"
+
+	// EventConsole is emitted for every line printed to the JS console by the emscripten module
+	EventConsole EventKind = "console"
+
+	// EventOutputBudget is emitted when the estimated output of an input exceeds the budget (see WithOutputBudget)
+	EventOutputBudget EventKind = "output_budget"
+
+	// EventDownload is emitted by registries when the download of a compiler release completes, successfully or not
+	EventDownload EventKind = "download"
+)
+
+// Event describes something that happened in a compiler
+//
+// Fields not relevant to the event kind are left empty
+type Event struct {
+	Kind     EventKind
+	Version  string
+	Sources  int
+	Duration time.Duration
+	Bytes    int64
+	Key      string
+	Message  string
+	Err      error
+}
+
+func (e Event) String() string {
+	switch e.Kind {
+	case EventInit:
+		return fmt.Sprintf("solc: initialized %v in %v", e.Version, e.Duration)
+	case EventCompileStart:
+		return fmt.Sprintf("solc: compiling %v sources", e.Sources)
+	case EventCompile:
+		if e.Err != nil {
+			return fmt.Sprintf("solc: compilation of %v sources failed after %v: %v", e.Sources, e.Duration, e.Err)
+		}
+		return fmt.Sprintf("solc: compiled %v sources in %v", e.Sources, e.Duration)
+	case EventCacheHit:
+		return fmt.Sprintf("solc: cache hit %v", e.Key)
+	case EventCacheMiss:
+		return fmt.Sprintf("solc: cache miss %v", e.Key)
+	case EventConsole:
+		return fmt.Sprintf("solc: console: %v", e.Message)
+	case EventOutputBudget:
+		return fmt.Sprintf("solc: warning: %v", e.Err)
+	case EventDownload:
+		if e.Err != nil {
+			return fmt.Sprintf("solc: download of %v failed after %v: %v", e.Version, e.Duration, e.Err)
+		}
+		return fmt.Sprintf("solc: downloaded %v (%v bytes) in %v", e.Version, e.Bytes, e.Duration)
+	}
+	return fmt.Sprintf("solc: %v", e.Kind)
+}
+
+// EventLogger is a Logger receiving structured events rather than formatted messages
+//
+// Loggers set with WithLogger that implement it have LogEvent called for every event,
+// other loggers receive the event String()
+type EventLogger interface {
+	Logger
+	LogEvent(e Event)
+}
+
+func (o *options) emit(e Event) {
+	switch logger := o.logger.(type) {
+	case nil:
+	case EventLogger:
+		logger.LogEvent(e)
+	default:
+		logger.Printf("%v", e)
+	}
+}
+
+// consoleScript replaces the JS console with one buffering lines until they are drained,
+// and has the emscripten Module print to it (soljson keeps the properties of an existing Module)
+//
+// It must run before soljson which binds console.log when loading
+const consoleScript = `(function(global) {
+	global.__solc_console = [];
+	function log() { global.__solc_console.push(Array.prototype.join.call(arguments, ' ')); }
+	global.console = { log: log, info: log, warn: log, error: log, debug: log };
+	global.Module = { print: log, printErr: log };
+})(this)`
+
+const drainConsoleScript = `(function() { var l = __solc_console; __solc_console = []; return JSON.stringify(l); })()`
+
+// drainConsole emits and returns the lines printed to the console since last drained
+func (solc *baseSolc) drainConsole() []string {
+	if !solc.opts.capturesConsole() {
+		return nil
+	}
+	val, err := solc.ctx.RunScript(drainConsoleScript, "console.js")
+	if err != nil {
+		return nil
+	}
+	var lines []string
+	_ = json.Unmarshal([]byte(val.String()), &lines)
+	for _, line := range lines {
+		solc.opts.emit(Event{Kind: EventConsole, Version: solc.fullVersion, Message: line})
+	}
+	return lines
+}
+
+func (o *options) capturesConsole() bool {
+	return o.logger != nil || o.console
+}
@@ -0,0 +1,93 @@
+package solc
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingLogger struct {
+	mux    sync.Mutex
+	events []Event
+	lines  []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+func (l *recordingLogger) LogEvent(e Event) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	l.events = append(l.events, e)
+}
+
+func (l *recordingLogger) kinds() []EventKind {
+	var kinds []EventKind
+	for _, e := range l.events {
+		kinds = append(kinds, e.Kind)
+	}
+	return kinds
+}
+
+func TestEvents(t *testing.T) {
+	logger := &recordingLogger{}
+	b, err := ioutil.ReadFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err)
+	solc, err := new(string(b), WithLogger(logger), WithCache(NewMemoryCache(1)))
+	require.NoError(t, err, "Creating Solc from valid solc emscripten binary should not error")
+	defer solc.Close()
+
+	require.Equal(t, []EventKind{EventInit}, logger.kinds(), "Initialization should be reported")
+	assert.Equal(t, "0.6.2+commit.bacdbe57.Emscripten.clang", logger.events[0].Version)
+	assert.Greater(t, int64(logger.events[0].Duration), int64(0), "Initialization duration should be reported")
+
+	in := &Input{Language: "Solidity", Sources: map[string]SourceIn{"One.sol": SourceIn{Content: "contract One {}"}}}
+	_, err = solc.Compile(in)
+	require.NoError(t, err, "Compile should not error")
+	_, err = solc.Compile(in)
+	require.NoError(t, err, "Compile should not error")
+	assert.Equal(t, []EventKind{EventInit, EventCacheMiss, EventCompileStart, EventCompile, EventCacheHit}, logger.kinds())
+	assert.Equal(t, 1, logger.events[3].Sources)
+
+	// Console output is captured
+	_, err = solc.ctx.RunScript("console.log('memory', 'grown'); console.error('oops')", "test.js")
+	require.NoError(t, err)
+	solc.drainConsole()
+	require.Len(t, logger.events, 7)
+	assert.Equal(t, Event{Kind: EventConsole, Version: solc.fullVersion, Message: "memory grown"}, logger.events[5])
+	assert.Equal(t, "oops", logger.events[6].Message)
+}
+
+func TestEventString(t *testing.T) {
+	assert.Equal(t, "solc: compiled 2 sources in 1s", Event{Kind: EventCompile, Sources: 2, Duration: 1e9}.String())
+	assert.Equal(t, "solc: cache hit abc", Event{Kind: EventCacheHit, Key: "abc"}.String())
+}
+
+func TestConsoleCapture(t *testing.T) {
+	solc, err := new(mustReadFile(t, "./solc-bin/soljson-v0.5.9+commit.e560f70d.js"), WithConsoleCapture())
+	require.NoError(t, err, "Creating Solc from valid solc emscripten binary should not error")
+	defer solc.Close()
+
+	_, err = solc.ctx.RunScript("Module.print('out'); Module.printErr('err'); console.log('log')", "test.js")
+	require.NoError(t, err)
+	out, err := solc.Compile(&Input{Language: "Solidity", Sources: map[string]SourceIn{"One.sol": SourceIn{Content: "contract One {}"}}})
+	require.NoError(t, err, "Compile should not error")
+	assert.Equal(t, []string{"out", "err", "log"}, out.Console, "Printed lines should be attached to the output")
+
+	out, err = solc.Compile(&Input{Language: "Solidity", Sources: map[string]SourceIn{"Two.sol": SourceIn{Content: "contract Two {}"}}})
+	require.NoError(t, err, "Compile should not error")
+	assert.Empty(t, out.Console, "Lines should only be attached once")
+}
+
+func mustReadFile(t *testing.T, file string) string {
+	b, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	return string(b)
+}
@@ -0,0 +1,178 @@
+package solc
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// ContractExplanation gathers what determines the bytecode of a contract (compiler, settings and sources)
+// along with the resulting size and gas, to audit a build or to find why bytecode differs from another one
+// (e.g. deployed on mainnet)
+type ContractExplanation struct {
+	// Contract is the fully qualified name of the contract (path:Name)
+	Contract string `json:"contract"`
+
+	// Compiler is the long version of the compiler (e.g. 0.6.2+commit.bacdbe57)
+	Compiler string `json:"compiler"`
+
+	// EVMVersion is the EVM version targeted, DefaultEVMVersion indicates whether it is the compiler default
+	// rather than set by the input
+	EVMVersion        string `json:"evmVersion,omitempty"`
+	DefaultEVMVersion bool   `json:"defaultEvmVersion"`
+
+	ViaIR     bool      `json:"viaIR"`
+	Optimizer Optimizer `json:"optimizer"`
+
+	// BytecodeHash is the hash of metadata appended to the deployed bytecode: ipfs, bzzr0, bzzr1 or none
+	BytecodeHash string `json:"bytecodeHash,omitempty"`
+
+	Remappings []string `json:"remappings,omitempty"`
+
+	// Libraries are the addresses linked at compilation, by fully qualified name
+	Libraries map[string]string `json:"libraries,omitempty"`
+
+	// Sources are the keccak256 hashes of the sources the contract is compiled from, by name
+	Sources map[string]string `json:"sources,omitempty"`
+
+	// Size and InitSize are the sizes in bytes of the deployed and creation bytecodes
+	Size     int `json:"size"`
+	InitSize int `json:"initSize"`
+
+	// Deployment and Functions are gas estimates (see ContractGas)
+	Deployment string        `json:"deployment,omitempty"`
+	Functions  []FunctionGas `json:"functions,omitempty"`
+}
+
+// contractMetadata is the part of the metadata JSON of a contract describing how it was compiled
+type contractMetadata struct {
+	Compiler struct {
+		Version string `json:"version"`
+	} `json:"compiler"`
+	Settings struct {
+		EVMVersion string            `json:"evmVersion"`
+		ViaIR      bool              `json:"viaIR"`
+		Optimizer  Optimizer         `json:"optimizer"`
+		Remappings []string          `json:"remappings"`
+		Libraries  map[string]string `json:"libraries"`
+	} `json:"settings"`
+	Sources map[string]struct {
+		Keccak256 string `json:"keccak256"`
+	} `json:"sources"`
+}
+
+// NewExplainReport returns the explanation of every contract of out, compiled from in by compiler version,
+// sorted by name
+//
+// Settings are read from the metadata of contracts when selected, they then are those the compiler
+// actually applied and hold the hashes of sources. Otherwise they are those of in, completed by the
+// defaults of version. Sizes require the evm.bytecode.object and evm.deployedBytecode.object outputs,
+// the bytecode hash the latter, and gas the evm.gasEstimates output
+func NewExplainReport(version string, in *Input, out *Output) ([]ContractExplanation, error) {
+	sizes := make(map[string]ContractSize)
+	for _, s := range NewSizeReport(out) {
+		sizes[s.Contract] = s
+	}
+	gas := make(map[string]ContractGas)
+	for _, g := range NewGasReport(out) {
+		gas[g.Contract] = g
+	}
+	var defaultEVMVersion string
+	if version != "" {
+		var err error
+		defaultEVMVersion, err = DefaultEVMVersion(ShortVersion(version))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	report := []ContractExplanation{}
+	for _, id := range out.ContractIDs() {
+		c := out.Contracts[id.File][id.Name]
+		e := ContractExplanation{
+			Contract:   id.String(),
+			Compiler:   LongVersion(version),
+			EVMVersion: in.Settings.EVMVersion,
+			ViaIR:      in.Settings.ViaIR,
+			Optimizer:  in.Settings.Optimizer,
+			Remappings: in.Settings.Remappings,
+		}
+		for lib, address := range in.Settings.Libraries[id.File] {
+			if e.Libraries == nil {
+				e.Libraries = make(map[string]string)
+			}
+			e.Libraries[ContractID{File: id.File, Name: lib}.String()] = address
+		}
+
+		if c.Metadata != "" {
+			var metadata contractMetadata
+			err := json.Unmarshal([]byte(c.Metadata), &metadata)
+			if err != nil {
+				return nil, fmt.Errorf("solc: invalid metadata of %v: %v", id, err)
+			}
+			e.Compiler = metadata.Compiler.Version
+			e.EVMVersion = metadata.Settings.EVMVersion
+			e.ViaIR = metadata.Settings.ViaIR
+			e.Optimizer = metadata.Settings.Optimizer
+			e.Remappings = metadata.Settings.Remappings
+			e.Libraries = nil
+			if len(metadata.Settings.Libraries) > 0 {
+				e.Libraries = metadata.Settings.Libraries
+			}
+			for name, source := range metadata.Sources {
+				if e.Sources == nil {
+					e.Sources = make(map[string]string)
+				}
+				e.Sources[name] = source.Keccak256
+			}
+		}
+		if e.EVMVersion == "" {
+			e.EVMVersion = defaultEVMVersion
+		}
+		e.DefaultEVMVersion = in.Settings.EVMVersion == "" && e.EVMVersion == defaultEVMVersion
+		if e.Optimizer.Enabled && e.Optimizer.Runs == 0 {
+			e.Optimizer.Runs = DefaultOptimizerRuns
+		}
+
+		if c.EVM.DeployedBytecode.Object != "" {
+			var err error
+			e.BytecodeHash, err = bytecodeHash(c.EVM.DeployedBytecode.Object)
+			if err != nil {
+				return nil, fmt.Errorf("solc: invalid deployed bytecode of %v: %v", id, err)
+			}
+		}
+		if s, ok := sizes[e.Contract]; ok {
+			e.Size, e.InitSize = s.Size, s.InitSize
+		}
+		if g, ok := gas[e.Contract]; ok {
+			e.Deployment, e.Functions = g.Deployment, g.Functions
+		}
+		report = append(report, e)
+	}
+	sort.Slice(report, func(i, j int) bool { return report[i].Contract < report[j].Contract })
+	return report, nil
+}
+
+// bytecodeHash returns the kind of metadata hash ending a hex encoded runtime code, none if it has none
+func bytecodeHash(obj string) (string, error) {
+	code, _, err := decodeObject(obj)
+	if err != nil {
+		return "", err
+	}
+	metadata, err := ParseBytecodeMetadata(code)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case metadata == nil:
+		return "none", nil
+	case metadata.IPFS != nil:
+		return "ipfs", nil
+	case metadata.Bzzr1 != nil:
+		return "bzzr1", nil
+	case metadata.Bzzr0 != nil:
+		return "bzzr0", nil
+	default:
+		return "none", nil
+	}
+}
@@ -0,0 +1,53 @@
+package solc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainReport(t *testing.T) {
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err, "Creating Solc from valid solc emscripten binary should not error")
+	defer solc.Close()
+
+	in := NewInput().
+		AddSource("One.sol", "pragma solidity ^0.6.1; interface I { function i() external; } contract One { function one() public pure returns (uint) { return 1; } }").
+		SetOutputSelection(SelectAll("metadata", "evm.bytecode.object", "evm.deployedBytecode.object", "evm.gasEstimates"))
+	out, err := solc.Compile(in)
+	require.NoError(t, err, "Compile should not error")
+
+	report, err := NewExplainReport(solc.Version(), in, out)
+	require.NoError(t, err, "NewExplainReport should not error")
+	require.Len(t, report, 2)
+	assert.Equal(t, "One.sol:I", report[0].Contract, "Contracts should be sorted")
+	assert.Zero(t, report[0].Size, "Interfaces should have no bytecode")
+
+	one := report[1]
+	assert.Equal(t, "One.sol:One", one.Contract)
+	assert.Equal(t, "0.6.2+commit.bacdbe57", one.Compiler)
+	assert.Equal(t, "istanbul", one.EVMVersion)
+	assert.True(t, one.DefaultEVMVersion, "Unset EVM versions should be the compiler default")
+	assert.Equal(t, Optimizer{Enabled: true, Runs: DefaultOptimizerRuns}, one.Optimizer)
+	assert.Equal(t, "ipfs", one.BytecodeHash)
+	assert.Contains(t, one.Sources, "One.sol", "Sources should be read from metadata")
+	assert.True(t, one.Size > 0 && one.InitSize > one.Size)
+	assert.NotEmpty(t, one.Deployment)
+	require.Len(t, one.Functions, 1)
+	assert.Equal(t, "one()", one.Functions[0].Signature)
+
+	// Without metadata, settings are those of the input
+	in.SetOptimizer(true, 0).SetEVMVersion("petersburg").SetOutputSelection(SelectAll("evm.deployedBytecode.object"))
+	out, err = solc.Compile(in)
+	require.NoError(t, err, "Compile should not error")
+	report, err = NewExplainReport(solc.Version(), in, out)
+	require.NoError(t, err, "NewExplainReport should not error")
+	one = report[1]
+	assert.Equal(t, "petersburg", one.EVMVersion)
+	assert.False(t, one.DefaultEVMVersion)
+	assert.Equal(t, DefaultOptimizerRuns, one.Optimizer.Runs, "Optimizer runs should default")
+	assert.Empty(t, one.Sources)
+	assert.Zero(t, one.InitSize, "Sizes should require both bytecodes")
+	assert.Empty(t, one.Functions)
+}
@@ -0,0 +1,185 @@
+package solc
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Flatten inlines all imports of entry into a single source file
+//
+// Files are emitted in dependency order, import directives are removed, SPDX license identifiers
+// are merged into a single one and pragma directives are deduplicated at the top of the file
+func Flatten(entry string, resolver ImportResolver) (string, error) {
+	f := &flattener{
+		resolver: resolver,
+		visited:  make(map[string]bool),
+		pragmas:  make(map[string]bool),
+	}
+
+	err := f.visit(entry, "")
+	if err != nil {
+		return "", err
+	}
+
+	return f.String(), nil
+}
+
+type flattener struct {
+	resolver ImportResolver
+	visited  map[string]bool
+
+	licenses    []string
+	pragmas     map[string]bool
+	pragmaLines []string
+	files       []flatFile
+}
+
+type flatFile struct {
+	name string
+	code string
+}
+
+func (f *flattener) visit(name, from string) error {
+	if f.visited[name] {
+		// Already emitted or being emitted (circular import)
+		return nil
+	}
+	f.visited[name] = true
+
+	content, err := f.resolver.Resolve(name)
+	if err != nil {
+		if from == "" {
+			return fmt.Errorf("could not resolve %q: %w", name, err)
+		}
+		return fmt.Errorf("could not resolve %q imported from %q: %w", name, from, err)
+	}
+
+	for _, imp := range ParseImports(content) {
+		err = f.visit(ImportPath(name, imp.Path), name)
+		if err != nil {
+			return err
+		}
+	}
+
+	if license := LicenseIdentifier(content); license != "" {
+		f.addLicense(license)
+	}
+
+	var cuts []cut
+	for _, imp := range ParseImports(content) {
+		cuts = append(cuts, cut{imp.Start, imp.End})
+	}
+	for _, pragma := range ParsePragmas(content) {
+		line := fmt.Sprintf("pragma %v %v;", pragma.Name, pragma.Value)
+		if !f.pragmas[line] {
+			f.pragmas[line] = true
+			f.pragmaLines = append(f.pragmaLines, line)
+		}
+		cuts = append(cuts, cut{pragma.Start, pragma.End})
+	}
+
+	code := removeLicenses(applyCuts(content, cuts))
+	code = blankLinesRe.ReplaceAllString(code, "\n\n")
+	f.files = append(f.files, flatFile{name: name, code: strings.TrimSpace(code)})
+
+	return nil
+}
+
+func (f *flattener) addLicense(license string) {
+	for _, l := range f.licenses {
+		if l == license {
+			return
+		}
+	}
+	f.licenses = append(f.licenses, license)
+}
+
+func (f *flattener) String() string {
+	var b strings.Builder
+
+	switch len(f.licenses) {
+	case 0:
+	case 1:
+		fmt.Fprintf(&b, "// SPDX-License-Identifier: %v\n", f.licenses[0])
+	default:
+		exprs := make([]string, len(f.licenses))
+		for i, l := range f.licenses {
+			if strings.Contains(l, " ") {
+				l = fmt.Sprintf("(%v)", l)
+			}
+			exprs[i] = l
+		}
+		fmt.Fprintf(&b, "// SPDX-License-Identifier: %v\n", strings.Join(exprs, " AND "))
+	}
+
+	for _, line := range f.pragmaLines {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	for _, file := range f.files {
+		fmt.Fprintf(&b, "\n// File: %v\n\n", file.name)
+		if file.code != "" {
+			b.WriteString(file.code)
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
+
+var (
+	emptyCommentRe = regexp.MustCompile(`^\s*/\*\s*\*/\s*$`)
+	blankLinesRe   = regexp.MustCompile(`\n\s*\n(\s*\n)+`)
+)
+
+type cut struct {
+	start, end int
+}
+
+func applyCuts(content string, cuts []cut) string {
+	sort.Slice(cuts, func(i, j int) bool { return cuts[i].start < cuts[j].start })
+
+	var b strings.Builder
+	last := 0
+	for _, c := range cuts {
+		if c.start < last {
+			continue
+		}
+		b.WriteString(content[last:c.start])
+		last = c.end
+	}
+	b.WriteString(content[last:])
+	return b.String()
+}
+
+// removeLicenses drops SPDX license comment lines (and inline identifiers) so the flattened file holds a single one
+func removeLicenses(content string) string {
+	lines := strings.Split(content, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if !licenseRe.MatchString(line) {
+			kept = append(kept, line)
+			continue
+		}
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "//") {
+			// Line holds nothing but the license comment
+			if strings.TrimSpace(strings.TrimPrefix(licenseRe.ReplaceAllString(trimmed, ""), "//")) == "" {
+				continue
+			}
+		}
+		line = licenseRe.ReplaceAllStringFunc(line, func(m string) string {
+			if strings.HasSuffix(m, "*/") {
+				return "*/"
+			}
+			return ""
+		})
+		if emptyCommentRe.MatchString(line) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
+}
@@ -0,0 +1,73 @@
+package solc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlatten(t *testing.T) {
+	sources := SourcesResolver{
+		"contracts/Token.sol": SourceIn{Content: `// SPDX-License-Identifier: MIT
+pragma solidity ^0.6.2;
+
+import "./lib/Math.sol";
+import {Base} from "../interfaces/Base.sol";
+// import "./Commented.sol";
+
+contract Token is Base {}
+`},
+		"contracts/lib/Math.sol": SourceIn{Content: `// SPDX-License-Identifier: MIT
+pragma solidity ^0.6.2;
+import * as B from "../../interfaces/Base.sol";
+
+library Math {}
+`},
+		"interfaces/Base.sol": SourceIn{Content: `/* SPDX-License-Identifier: Apache-2.0 */
+pragma solidity >=0.6.0;
+pragma experimental ABIEncoderV2;
+import "contracts/Token.sol";
+
+contract Base {}
+`},
+	}
+
+	flat, err := Flatten("contracts/Token.sol", sources)
+	require.NoError(t, err, "Flatten should not error")
+
+	expected := `// SPDX-License-Identifier: Apache-2.0 AND MIT
+pragma solidity >=0.6.0;
+pragma experimental ABIEncoderV2;
+pragma solidity ^0.6.2;
+
+// File: interfaces/Base.sol
+
+contract Base {}
+
+// File: contracts/lib/Math.sol
+
+library Math {}
+
+// File: contracts/Token.sol
+
+// import "./Commented.sol";
+
+contract Token is Base {}
+`
+	assert.Equal(t, expected, flat, "Flattened source should be correct")
+
+	_, err = Flatten("contracts/Missing.sol", sources)
+	assert.Error(t, err, "Flatten should error on unresolved entry")
+}
+
+func TestParseImports(t *testing.T) {
+	imports := ParseImports(`import "a.sol"; /* import "b.sol"; */ import 'c.sol' as C; import {X as Y, Z} from "./d.sol";`)
+	var paths []string
+	for _, imp := range imports {
+		paths = append(paths, imp.Path)
+	}
+	assert.Equal(t, []string{"a.sol", "c.sol", "./d.sol"}, paths, "Imports should be parsed")
+	assert.Equal(t, "dir/d.sol", ImportPath("dir/sub/../e.sol", "./d.sol"), "Relative import should be resolved")
+	assert.Equal(t, "lib/x.sol", ImportPath("dir/e.sol", "lib/x.sol"), "Direct import should be kept")
+}
@@ -3,7 +3,10 @@ module github.com/nmvalera/solc-go
 go 1.13
 
 require (
+	github.com/fsnotify/fsnotify v1.4.9
 	github.com/stretchr/testify v1.4.0
+	golang.org/x/crypto v0.0.0-20200220183623-bac4c82f6975
+	gopkg.in/yaml.v2 v2.4.0
 	rogchap.com/v8go v0.2.0
 )
 
@@ -0,0 +1,45 @@
+package solc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalGolden(t *testing.T) {
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err, "Creating compiler should not error")
+	defer solc.Close()
+
+	compile := func(root, comment string) *Output {
+		in := NewInput().
+			AddSource(root+"/src/A.sol", "pragma solidity ^0.6.0;\n// "+comment+"\ncontract B {}\ncontract A { function f() external { new B(); } }").
+			SetOutputSelection(SelectAll("abi", "metadata", "evm.bytecode", "evm.deployedBytecode", "ast"))
+		out, err := solc.Compile(in)
+		require.NoError(t, err, "Compile should not error")
+		require.Empty(t, out.Errors, "A.sol should compile")
+		return out
+	}
+	one, other := compile("/tmp/one", "one"), compile("/home/other", "two")
+	require.NotEqual(t, one.Contracts["/tmp/one/src/A.sol"]["A"].EVM.Bytecode.Object, other.Contracts["/home/other/src/A.sol"]["A"].EVM.Bytecode.Object, "Metadata hashes should differ")
+
+	golden, err := one.MarshalGolden("/tmp/one")
+	require.NoError(t, err, "MarshalGolden should not error")
+	otherGolden, err := other.MarshalGolden("/home/other/")
+	require.NoError(t, err, "MarshalGolden should not error")
+	assert.Equal(t, string(golden), string(otherGolden), "Golden outputs should not depend on metadata nor roots")
+	assert.NotContains(t, string(golden), "/tmp/one", "Root should be removed")
+
+	normalized, err := NormalizeOutput(one, "/tmp/one")
+	require.NoError(t, err, "NormalizeOutput should not error")
+	a := normalized.Contracts["src/A.sol"]["A"]
+	assert.Empty(t, a.Metadata, "Metadata should be dropped")
+	assert.Empty(t, a.EVM.Bytecode.Opcodes, "Opcodes should be dropped")
+	assert.NotEmpty(t, a.ABI)
+	zeroed := "a264697066735822" + strings.Repeat("0", 68) + "64736f6c6343" + "000000" + "0033"
+	assert.Equal(t, 2, strings.Count(a.EVM.Bytecode.Object, zeroed), "Metadata of A and of the embedded B should be zeroed")
+	assert.True(t, strings.HasSuffix(a.EVM.DeployedBytecode.Object, zeroed))
+	assert.NotEmpty(t, one.Contracts["/tmp/one/src/A.sol"]["A"].Metadata, "Output should not be modified")
+}
@@ -0,0 +1,243 @@
+package solc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ImportGraph is the import graph of a set of sources
+type ImportGraph struct {
+	// Nodes are source unit names sorted alphabetically
+	Nodes []string
+
+	// Edges maps a source unit to the source units it imports
+	Edges map[string][]string
+}
+
+// CircularImportError is returned when an import graph can not be ordered
+type CircularImportError struct {
+	Cycles [][]string
+}
+
+func (e *CircularImportError) Error() string {
+	cycles := make([]string, len(e.Cycles))
+	for i, cycle := range e.Cycles {
+		path := append(append([]string{}, cycle...), cycle[0])
+		cycles[i] = strings.Join(path, " -> ")
+	}
+	return fmt.Sprintf("circular imports: %v", strings.Join(cycles, ", "))
+}
+
+// DependencyGraph builds the import graph of sources
+//
+// Imports of sources not present in sources are loaded with resolver (which may be nil if sources are self-contained)
+func DependencyGraph(sources map[string]SourceIn, resolver ImportResolver) (*ImportGraph, error) {
+	g := &ImportGraph{Edges: make(map[string][]string)}
+
+	var queue []string
+	for name := range sources {
+		queue = append(queue, name)
+	}
+	sort.Strings(queue)
+
+	visited := make(map[string]bool)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if visited[name] {
+			continue
+		}
+		visited[name] = true
+
+		content, err := loadSource(name, sources, resolver)
+		if err != nil {
+			return nil, err
+		}
+
+		g.Nodes = append(g.Nodes, name)
+		for _, imp := range ParseImports(content) {
+			dep := ImportPath(name, imp.Path)
+			if !contains(g.Edges[name], dep) {
+				g.Edges[name] = append(g.Edges[name], dep)
+			}
+			queue = append(queue, dep)
+		}
+		sort.Strings(g.Edges[name])
+	}
+	sort.Strings(g.Nodes)
+
+	return g, nil
+}
+
+func loadSource(name string, sources map[string]SourceIn, resolver ImportResolver) (string, error) {
+	if source, ok := sources[name]; ok && source.Content != "" {
+		return source.Content, nil
+	}
+	if resolver == nil {
+		return "", fmt.Errorf("source %q not found", name)
+	}
+	content, err := resolver.Resolve(name)
+	if err != nil {
+		return "", fmt.Errorf("could not resolve %q: %w", name, err)
+	}
+	return content, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Imports returns the source units directly imported by name
+func (g *ImportGraph) Imports(name string) []string {
+	return g.Edges[name]
+}
+
+// Dependencies returns all source units name transitively imports
+func (g *ImportGraph) Dependencies(name string) []string {
+	return g.closure(name, g.Edges)
+}
+
+// Dependents returns all source units transitively importing name
+func (g *ImportGraph) Dependents(name string) []string {
+	reverse := make(map[string][]string)
+	for _, from := range g.Nodes {
+		for _, to := range g.Edges[from] {
+			reverse[to] = append(reverse[to], from)
+		}
+	}
+	return g.closure(name, reverse)
+}
+
+func (g *ImportGraph) closure(name string, edges map[string][]string) []string {
+	seen := map[string]bool{name: true}
+	var result []string
+	stack := append([]string{}, edges[name]...)
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+		result = append(result, n)
+		stack = append(stack, edges[n]...)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Cycles returns the circular imports of the graph
+//
+// Each cycle is a strongly connected set of source units sorted alphabetically
+func (g *ImportGraph) Cycles() [][]string {
+	t := &tarjan{
+		g:       g,
+		index:   make(map[string]int),
+		lowlink: make(map[string]int),
+		onStack: make(map[string]bool),
+	}
+	for _, n := range g.Nodes {
+		if _, ok := t.index[n]; !ok {
+			t.strongConnect(n)
+		}
+	}
+
+	var cycles [][]string
+	for _, scc := range t.sccs {
+		if len(scc) > 1 || contains(g.Edges[scc[0]], scc[0]) {
+			sort.Strings(scc)
+			cycles = append(cycles, scc)
+		}
+	}
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i][0] < cycles[j][0] })
+
+	return cycles
+}
+
+type tarjan struct {
+	g       *ImportGraph
+	counter int
+	index   map[string]int
+	lowlink map[string]int
+	stack   []string
+	onStack map[string]bool
+	sccs    [][]string
+}
+
+func (t *tarjan) strongConnect(n string) {
+	t.index[n] = t.counter
+	t.lowlink[n] = t.counter
+	t.counter++
+	t.stack = append(t.stack, n)
+	t.onStack[n] = true
+
+	for _, m := range t.g.Edges[n] {
+		if _, ok := t.index[m]; !ok {
+			t.strongConnect(m)
+			if t.lowlink[m] < t.lowlink[n] {
+				t.lowlink[n] = t.lowlink[m]
+			}
+		} else if t.onStack[m] && t.index[m] < t.lowlink[n] {
+			t.lowlink[n] = t.index[m]
+		}
+	}
+
+	if t.lowlink[n] == t.index[n] {
+		var scc []string
+		for {
+			m := t.stack[len(t.stack)-1]
+			t.stack = t.stack[:len(t.stack)-1]
+			t.onStack[m] = false
+			scc = append(scc, m)
+			if m == n {
+				break
+			}
+		}
+		t.sccs = append(t.sccs, scc)
+	}
+}
+
+// TopologicalOrder returns the source units ordered so that every unit comes after the units it imports
+//
+// A *CircularImportError is returned if the graph has cycles
+func (g *ImportGraph) TopologicalOrder() ([]string, error) {
+	if cycles := g.Cycles(); len(cycles) > 0 {
+		return nil, &CircularImportError{Cycles: cycles}
+	}
+
+	pending := make(map[string]int)
+	dependents := make(map[string][]string)
+	for _, n := range g.Nodes {
+		pending[n] = len(g.Edges[n])
+		for _, dep := range g.Edges[n] {
+			dependents[dep] = append(dependents[dep], n)
+		}
+	}
+
+	var ready, order []string
+	for _, n := range g.Nodes {
+		if pending[n] == 0 {
+			ready = append(ready, n)
+		}
+	}
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		n := ready[0]
+		ready = ready[1:]
+		order = append(order, n)
+		for _, d := range dependents[n] {
+			pending[d]--
+			if pending[d] == 0 {
+				ready = append(ready, d)
+			}
+		}
+	}
+
+	return order, nil
+}
@@ -0,0 +1,44 @@
+package solc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDependencyGraph(t *testing.T) {
+	lib := SourcesResolver{
+		"lib/Math.sol": SourceIn{Content: `library Math {}`},
+	}
+	sources := map[string]SourceIn{
+		"A.sol": SourceIn{Content: `import "./B.sol"; import "lib/Math.sol";`},
+		"B.sol": SourceIn{Content: `import "lib/Math.sol";`},
+		"C.sol": SourceIn{Content: `import "./A.sol";`},
+	}
+
+	g, err := DependencyGraph(sources, lib)
+	require.NoError(t, err, "DependencyGraph should not error")
+	assert.Equal(t, []string{"A.sol", "B.sol", "C.sol", "lib/Math.sol"}, g.Nodes, "Nodes should be correct")
+	assert.Equal(t, []string{"B.sol", "lib/Math.sol"}, g.Imports("A.sol"), "Edges should be correct")
+	assert.Equal(t, []string{"A.sol", "B.sol", "lib/Math.sol"}, g.Dependencies("C.sol"), "Dependencies should be correct")
+	assert.Equal(t, []string{"A.sol", "C.sol"}, g.Dependents("B.sol"), "Dependents should be correct")
+	assert.Empty(t, g.Cycles(), "Graph should have no cycle")
+
+	order, err := g.TopologicalOrder()
+	require.NoError(t, err, "TopologicalOrder should not error")
+	assert.Equal(t, []string{"lib/Math.sol", "B.sol", "A.sol", "C.sol"}, order, "Order should be correct")
+
+	// Circular imports
+	sources["lib/Math.sol"] = SourceIn{Content: `import "../C.sol";`}
+	g, err = DependencyGraph(sources, nil)
+	require.NoError(t, err, "DependencyGraph should not error")
+	assert.Equal(t, [][]string{{"A.sol", "B.sol", "C.sol", "lib/Math.sol"}}, g.Cycles(), "Cycle should be detected")
+	_, err = g.TopologicalOrder()
+	require.IsType(t, &CircularImportError{}, err, "TopologicalOrder should error on cycles")
+
+	// Unresolved import
+	delete(sources, "lib/Math.sol")
+	_, err = DependencyGraph(sources, nil)
+	assert.Error(t, err, "DependencyGraph should error on missing import")
+}
@@ -0,0 +1,88 @@
+package solc
+
+// HealthChecker is implemented by compilers able to tell whether they can still compile
+type HealthChecker interface {
+	// Healthy returns the error compilations would fail with, nil if the compiler is alive
+	Healthy() error
+}
+
+// Healthy returns the error compilations on solc would fail with, nil if solc is alive or can not tell
+func Healthy(solc Solc) error {
+	if c, ok := solc.(HealthChecker); ok {
+		return c.Healthy()
+	}
+	return nil
+}
+
+// IsReady indicates whether solc can serve compilations right away, only a LazySolc may not be
+func IsReady(solc Solc) bool {
+	if c, ok := solc.(interface{ IsReady() bool }); ok {
+		return c.IsReady()
+	}
+	return true
+}
+
+// Healthy waits for a running compilation to complete
+func (solc *baseSolc) Healthy() error {
+	solc.mux.Lock()
+	defer solc.mux.Unlock()
+	if solc.closed {
+		return ErrClosed
+	}
+	if solc.terminated {
+		return ErrTerminated
+	}
+	if solc.exhausted != nil {
+		return solc.exhausted
+	}
+	return solc.checkMemory()
+}
+
+// Healthy is nil as long as one instance is busy or healthy when idle, or dead instances are being replaced
+func (p *Pool) Healthy() error {
+	p.mux.Lock()
+	if p.closed {
+		p.mux.Unlock()
+		return ErrClosed
+	}
+	if len(p.all) == 0 {
+		err := p.healErr
+		p.mux.Unlock()
+		return err
+	}
+	busy := len(p.idle) < len(p.all)
+	idle := append([]Solc{}, p.idle...)
+	p.mux.Unlock()
+
+	var err error
+	for _, instance := range idle {
+		if err = Healthy(instance); err == nil {
+			return nil
+		}
+	}
+	if busy {
+		return nil
+	}
+	return err
+}
+
+// Healthy is nil until initialization completes, then it is the health of the compiler
+func (l *LazySolc) Healthy() error {
+	select {
+	case <-l.ready:
+	default:
+		return nil
+	}
+	if l.err != nil {
+		return l.err
+	}
+	return Healthy(l.solc)
+}
+
+func (s *sharedSolc) Healthy() error {
+	solc, err := s.get()
+	if err != nil {
+		return err
+	}
+	return Healthy(solc)
+}
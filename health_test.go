@@ -0,0 +1,38 @@
+package solc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthy(t *testing.T) {
+	solc, err := NewFromFile("./solc-bin/soljson-v0.5.9+commit.e560f70d.js")
+	require.NoError(t, err, "Creating compiler should not error")
+	assert.NoError(t, Healthy(solc), "New compiler should be healthy")
+	assert.True(t, IsReady(solc), "Compiler should be ready")
+	solc.Close()
+	assert.Equal(t, ErrClosed, Healthy(solc), "Closed compiler should not be healthy")
+
+	assert.NoError(t, Healthy(&fakeSolc{}), "Compiler without health checks should be healthy")
+
+	pool, err := NewPool(FileFactory("./solc-bin/soljson-v0.5.9+commit.e560f70d.js"), 2)
+	require.NoError(t, err, "Creating pool should not error")
+	assert.NoError(t, Healthy(pool), "New pool should be healthy")
+	pool.Close()
+	assert.Equal(t, ErrClosed, Healthy(pool), "Closed pool should not be healthy")
+
+	release := make(chan struct{})
+	lazy := NewLazy(func() (Solc, error) {
+		<-release
+		return &fakeSolc{version: "0.6.2"}, nil
+	})
+	assert.NoError(t, Healthy(lazy), "Initializing compiler should be healthy")
+	assert.False(t, IsReady(lazy), "Initializing compiler should not be ready")
+	close(release)
+	require.NoError(t, lazy.Warmup(context.Background()), "Warmup should not error")
+	assert.NoError(t, Healthy(lazy), "Initialized compiler should be healthy")
+	assert.True(t, IsReady(lazy), "Initialized compiler should be ready")
+}
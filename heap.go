@@ -0,0 +1,63 @@
+package solc
+
+import (
+	"fmt"
+)
+
+// MemoryLimitError is returned by Compile once the compiler memory usage exceeds the configured limit
+type MemoryLimitError struct {
+	Usage uint64
+	Limit uint64
+}
+
+func (e *MemoryLimitError) Error() string {
+	return fmt.Sprintf("solc: memory usage %v bytes exceeds limit of %v bytes", e.Usage, e.Limit)
+}
+
+// heapStatistics must be called while holding the lock on the v8 context
+func (solc *baseSolc) heapStatistics() HeapStatistics {
+	hs := solc.isolate.GetHeapStatistics()
+	stats := HeapStatistics{
+		TotalHeapSize:           hs.TotalHeapSize,
+		TotalHeapSizeExecutable: hs.TotalHeapSizeExecutable,
+		TotalPhysicalSize:       hs.TotalPhysicalSize,
+		TotalAvailableSize:      hs.TotalAvailableSize,
+		UsedHeapSize:            hs.UsedHeapSize,
+		HeapSizeLimit:           hs.HeapSizeLimit,
+		MallocedMemory:          hs.MallocedMemory,
+		PeakMallocedMemory:      hs.PeakMallocedMemory,
+		ExternalMemory:          hs.ExternalMemory,
+	}
+
+	val, err := solc.ctx.RunScript("Module.HEAP8 ? Module.HEAP8.length : 0", "heap_size.js")
+	if err == nil {
+		stats.ModuleMemory = uint64(val.Int64())
+	}
+
+	return stats
+}
+
+func (solc *baseSolc) HeapStatistics() HeapStatistics {
+	solc.mux.Lock()
+	defer solc.mux.Unlock()
+	if solc.closed {
+		return HeapStatistics{}
+	}
+	return solc.heapStatistics()
+}
+
+// checkMemory must be called while holding the lock on the v8 context
+func (solc *baseSolc) checkMemory() error {
+	return solc.checkMemoryLimit(solc.opts.memoryLimit)
+}
+
+// checkMemoryLimit must be called while holding the lock on the v8 context, a zero limit is not checked
+func (solc *baseSolc) checkMemoryLimit(limit uint64) error {
+	if limit == 0 {
+		return nil
+	}
+	if usage := solc.heapStatistics().Usage(); usage > limit {
+		return &MemoryLimitError{Usage: usage, Limit: limit}
+	}
+	return nil
+}
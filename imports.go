@@ -0,0 +1,126 @@
+package solc
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Import is an import directive of a Solidity source
+type Import struct {
+	// Path is the import path as written in the directive
+	Path string
+
+	// Start and End are the byte offsets of the whole directive in the source
+	Start int
+	End   int
+}
+
+// Pragma is a pragma directive of a Solidity source
+type Pragma struct {
+	Name  string
+	Value string
+	Start int
+	End   int
+}
+
+var (
+	importRe     = regexp.MustCompile(`\bimport\b[^;]*?["']([^"']+)["'][^;]*;`)
+	pragmaRe     = regexp.MustCompile(`\bpragma\s+([A-Za-z_][A-Za-z0-9_]*)\s*([^;]*?)\s*;`)
+	licenseRe    = regexp.MustCompile(`(?m)SPDX-License-Identifier:[ \t]*([^\r\n]*?)[ \t]*(?:\*/|$)`)
+	whitespaceRe = regexp.MustCompile(`\s+`)
+)
+
+// ParseImports returns the import directives of a Solidity source, ignoring comments
+func ParseImports(content string) []Import {
+	var imports []Import
+	for _, loc := range importRe.FindAllStringSubmatchIndex(stripComments(content), -1) {
+		imports = append(imports, Import{
+			Path:  content[loc[2]:loc[3]],
+			Start: loc[0],
+			End:   loc[1],
+		})
+	}
+	return imports
+}
+
+// ParsePragmas returns the pragma directives of a Solidity source, ignoring comments
+func ParsePragmas(content string) []Pragma {
+	var pragmas []Pragma
+	for _, loc := range pragmaRe.FindAllStringSubmatchIndex(stripComments(content), -1) {
+		pragmas = append(pragmas, Pragma{
+			Name:  content[loc[2]:loc[3]],
+			Value: whitespaceRe.ReplaceAllString(content[loc[4]:loc[5]], " "),
+			Start: loc[0],
+			End:   loc[1],
+		})
+	}
+	return pragmas
+}
+
+// VersionPragmas returns the distinct pragma solidity constraints of sources, sorted
+func VersionPragmas(sources map[string]SourceIn) []string {
+	var constraints []string
+	for _, source := range sources {
+		for _, pragma := range ParsePragmas(source.Content) {
+			if pragma.Name == "solidity" && !contains(constraints, pragma.Value) {
+				constraints = append(constraints, pragma.Value)
+			}
+		}
+	}
+	sort.Strings(constraints)
+	return constraints
+}
+
+// LicenseIdentifier returns the SPDX license expression of a Solidity source or "" if none
+func LicenseIdentifier(content string) string {
+	m := licenseRe.FindStringSubmatch(content)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+// ImportPath returns the source unit name targeted by an import path written in source unit from
+//
+// Relative paths (starting with ./ or ../) are resolved against the directory of from,
+// any other path is a source unit name on its own
+func ImportPath(from, imp string) string {
+	if strings.HasPrefix(imp, "./") || strings.HasPrefix(imp, "../") {
+		return path.Join(path.Dir(from), imp)
+	}
+	return imp
+}
+
+// stripComments blanks out comments while preserving offsets and string literals
+func stripComments(content string) string {
+	b := []byte(content)
+	for i := 0; i < len(b); i++ {
+		switch {
+		case b[i] == '"' || b[i] == '\'':
+			quote := b[i]
+			for i++; i < len(b) && b[i] != quote && b[i] != '\n'; i++ {
+				if b[i] == '\\' {
+					i++
+				}
+			}
+		case b[i] == '/' && i+1 < len(b) && b[i+1] == '/':
+			for ; i < len(b) && b[i] != '\n'; i++ {
+				b[i] = ' '
+			}
+		case b[i] == '/' && i+1 < len(b) && b[i+1] == '*':
+			b[i], b[i+1] = ' ', ' '
+			for i += 2; i < len(b) && !(b[i] == '*' && i+1 < len(b) && b[i+1] == '/'); i++ {
+				if b[i] != '\n' {
+					b[i] = ' '
+				}
+			}
+			if i < len(b) {
+				b[i], b[i+1] = ' ', ' '
+				i++
+			}
+		}
+	}
+	return string(b)
+}
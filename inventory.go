@@ -0,0 +1,99 @@
+package solc
+
+import (
+	"sort"
+	"strings"
+)
+
+// SourceSummary is the licenses and pragmas of a source
+type SourceSummary struct {
+	File string `json:"file"`
+
+	// Licenses are the distinct SPDX license expressions of the source, more than one is a conflict
+	Licenses []string `json:"licenses"`
+
+	// Versions are the pragma solidity constraints of the source
+	Versions []string `json:"versions"`
+
+	// Pragmas are the other pragma directives, e.g. "abicoder v2"
+	Pragmas []string `json:"pragmas"`
+}
+
+// SourceInventory is the licenses and version constraints of a set of sources, for compliance checks and flattening
+type SourceInventory struct {
+	// Sources are sorted by file
+	Sources []SourceSummary `json:"sources"`
+
+	// Licenses are the distinct license expressions of all sources, sorted
+	Licenses []string `json:"licenses"`
+
+	// Unlicensed are the files without SPDX license identifier, Conflicting the ones with several different ones
+	Unlicensed  []string `json:"unlicensed"`
+	Conflicting []string `json:"conflicting"`
+
+	// Version is the intersection of the version constraints of all sources (see IntersectVersions),
+	// empty if none satisfies them all (VersionConflict is then set)
+	Version         string `json:"version"`
+	VersionConflict bool   `json:"versionConflict"`
+}
+
+// NewSourceInventory scans the licenses and pragmas of sources, without compiling them
+//
+// It errors if a version constraint is invalid
+func NewSourceInventory(sources map[string]SourceIn) (*SourceInventory, error) {
+	inventory := &SourceInventory{
+		Sources:     []SourceSummary{},
+		Licenses:    []string{},
+		Unlicensed:  []string{},
+		Conflicting: []string{},
+	}
+
+	var constraints []string
+	for file, source := range sources {
+		summary := SourceSummary{File: file, Licenses: []string{}, Versions: []string{}, Pragmas: []string{}}
+		for _, m := range licenseRe.FindAllStringSubmatch(source.Content, -1) {
+			if license := m[1]; license != "" && !contains(summary.Licenses, license) {
+				summary.Licenses = append(summary.Licenses, license)
+			}
+		}
+		for _, pragma := range ParsePragmas(source.Content) {
+			if pragma.Name == "solidity" {
+				summary.Versions = append(summary.Versions, pragma.Value)
+				constraints = append(constraints, pragma.Value)
+			} else {
+				summary.Pragmas = append(summary.Pragmas, strings.TrimSpace(pragma.Name+" "+pragma.Value))
+			}
+		}
+
+		switch len(summary.Licenses) {
+		case 0:
+			inventory.Unlicensed = append(inventory.Unlicensed, file)
+		case 1:
+		default:
+			inventory.Conflicting = append(inventory.Conflicting, file)
+		}
+		for _, license := range summary.Licenses {
+			if !contains(inventory.Licenses, license) {
+				inventory.Licenses = append(inventory.Licenses, license)
+			}
+		}
+		inventory.Sources = append(inventory.Sources, summary)
+	}
+
+	// Maps are iterated in random order
+	sort.Slice(inventory.Sources, func(i, j int) bool { return inventory.Sources[i].File < inventory.Sources[j].File })
+	sort.Strings(inventory.Licenses)
+	sort.Strings(inventory.Unlicensed)
+	sort.Strings(inventory.Conflicting)
+	sort.Strings(constraints)
+
+	version, err := IntersectVersions(constraints...)
+	if _, ok := err.(*VersionConflictError); ok {
+		inventory.VersionConflict = true
+	} else if err != nil {
+		return nil, err
+	}
+	inventory.Version = version
+
+	return inventory, nil
+}
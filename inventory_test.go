@@ -0,0 +1,39 @@
+package solc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceInventory(t *testing.T) {
+	inventory, err := NewSourceInventory(map[string]SourceIn{
+		"A.sol":     SourceIn{Content: "// SPDX-License-Identifier: MIT\npragma solidity ^0.6.0;\npragma experimental ABIEncoderV2;\ncontract A {}"},
+		"B.sol":     SourceIn{Content: "/* SPDX-License-Identifier: GPL-3.0-or-later */\npragma solidity >=0.6.2 <0.8.0;\ncontract B {}"},
+		"C.sol":     SourceIn{Content: "// pragma solidity ^0.4.0;\ncontract C {}"},
+		"Mixed.sol": SourceIn{Content: "// SPDX-License-Identifier: MIT\n// SPDX-License-Identifier: Apache-2.0\ncontract D {}"},
+	})
+	require.NoError(t, err, "Inventory should not error")
+
+	require.Len(t, inventory.Sources, 4)
+	assert.Equal(t, SourceSummary{File: "A.sol", Licenses: []string{"MIT"}, Versions: []string{"^0.6.0"}, Pragmas: []string{"experimental ABIEncoderV2"}}, inventory.Sources[0])
+	assert.Equal(t, []string{"GPL-3.0-or-later"}, inventory.Sources[1].Licenses, "Block comment license should be found")
+	assert.Empty(t, inventory.Sources[2].Versions, "Commented pragma should be ignored")
+	assert.Equal(t, []string{"Apache-2.0", "GPL-3.0-or-later", "MIT"}, inventory.Licenses)
+	assert.Equal(t, []string{"C.sol"}, inventory.Unlicensed)
+	assert.Equal(t, []string{"Mixed.sol"}, inventory.Conflicting)
+	assert.Equal(t, ">=0.6.2 <0.7.0", inventory.Version, "Constraints should be intersected")
+	assert.False(t, inventory.VersionConflict)
+
+	inventory, err = NewSourceInventory(map[string]SourceIn{
+		"A.sol": SourceIn{Content: "pragma solidity ^0.5.0;"},
+		"B.sol": SourceIn{Content: "pragma solidity ^0.6.0;"},
+	})
+	require.NoError(t, err, "Conflicting versions should not error")
+	assert.True(t, inventory.VersionConflict, "Disjoint constraints should conflict")
+	assert.Empty(t, inventory.Version)
+
+	_, err = NewSourceInventory(map[string]SourceIn{"A.sol": SourceIn{Content: "pragma solidity ^0.6.a;"}})
+	assert.Error(t, err, "Invalid constraint should error")
+}
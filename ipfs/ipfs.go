@@ -0,0 +1,129 @@
+// Package ipfs pins contract metadata and sources to IPFS, so the metadata hash embedded in bytecode resolves
+package ipfs
+
+import (
+	"crypto/sha256"
+	"math/big"
+)
+
+// ChunkSize is the size of the chunks of files, the default of IPFS nodes and solc
+const ChunkSize = 256 * 1024
+
+// maxLinks is the number of children of the nodes of the balanced DAG of chunked files
+const maxLinks = 174
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// Hash returns the CIDv0 (base58 sha2-256 multihash) IPFS adds content as, which is the hash solc
+// computes for metadata and sources
+func Hash(content []byte) string {
+	return Base58(Multihash(content))
+}
+
+// Multihash returns the sha2-256 multihash of the root UnixFS node of content, as embedded in bytecode
+func Multihash(content []byte) []byte {
+	var nodes []node
+	for start := 0; start < len(content) || start == 0; start += ChunkSize {
+		end := start + ChunkSize
+		if end > len(content) {
+			end = len(content)
+		}
+		nodes = append(nodes, leaf(content[start:end]))
+	}
+	for len(nodes) > 1 {
+		var parents []node
+		for start := 0; start < len(nodes); start += maxLinks {
+			end := start + maxLinks
+			if end > len(nodes) {
+				end = len(nodes)
+			}
+			parents = append(parents, parent(nodes[start:end]))
+		}
+		nodes = parents
+	}
+	return nodes[0].hash
+}
+
+// Base58 encodes b with the bitcoin alphabet, as CIDv0 are
+func Base58(b []byte) string {
+	var encoded []byte
+	n, radix, mod := new(big.Int).SetBytes(b), big.NewInt(58), new(big.Int)
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		encoded = append(encoded, base58Alphabet[mod.Int64()])
+	}
+	for _, c := range b {
+		if c != 0 {
+			break
+		}
+		encoded = append(encoded, base58Alphabet[0])
+	}
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+	return string(encoded)
+}
+
+// node is a dag-pb node of a file
+type node struct {
+	hash []byte
+
+	// size is the size of the file content under the node, tsize the one of the encoded node and its descendants
+	size  uint64
+	tsize uint64
+}
+
+func newNode(block []byte, size, tsize uint64) node {
+	sum := sha256.Sum256(block)
+	return node{hash: append([]byte{0x12, 0x20}, sum[:]...), size: size, tsize: tsize + uint64(len(block))}
+}
+
+// leaf returns the node of a chunk
+func leaf(chunk []byte) node {
+	// UnixFS Data {Type: File, Data: chunk, filesize}
+	data := protoVarint(nil, 1, 2)
+	if len(chunk) > 0 {
+		data = protoBytes(data, 2, chunk)
+	}
+	data = protoVarint(data, 3, uint64(len(chunk)))
+	return newNode(protoBytes(nil, 1, data), uint64(len(chunk)), 0)
+}
+
+// parent returns the node linking children
+func parent(children []node) node {
+	var block []byte
+	var size, tsize uint64
+	data := protoVarint(nil, 1, 2)
+	for _, child := range children {
+		size += child.size
+		tsize += child.tsize
+	}
+	data = protoVarint(data, 3, size)
+	for _, child := range children {
+		data = protoVarint(data, 4, child.size)
+
+		// PBLink {Hash, Name: "", Tsize}, links precede data in dag-pb nodes
+		link := protoBytes(nil, 1, child.hash)
+		link = protoBytes(link, 2, nil)
+		link = protoVarint(link, 3, child.tsize)
+		block = protoBytes(block, 2, link)
+	}
+	return newNode(protoBytes(block, 1, data), size, tsize)
+}
+
+func protoVarint(b []byte, field int, v uint64) []byte {
+	return appendVarint(appendVarint(b, uint64(field<<3)), v)
+}
+
+func protoBytes(b []byte, field int, v []byte) []byte {
+	b = appendVarint(appendVarint(b, uint64(field<<3|2)), uint64(len(v)))
+	return append(b, v...)
+}
+
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
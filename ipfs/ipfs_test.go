@@ -0,0 +1,95 @@
+package ipfs
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	solc "github.com/nmvalera/solc-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHash(t *testing.T) {
+	assert.Equal(t, "QmbFMke1KXqnYyBBWxB74N4c5SBnJMVAiMNRcGu6x1AwQH", Hash(nil), "Empty file hash should be correct")
+	assert.Equal(t, "QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o", Hash([]byte("hello world\n")), "File hash should be correct")
+	assert.NotEqual(t, Hash(make([]byte, ChunkSize)), Hash(make([]byte, ChunkSize+1)), "Chunked file hash should differ")
+	assert.Equal(t, "1112", Base58([]byte{0, 0, 0, 1}), "Leading zeros should be encoded")
+}
+
+type pinnerFunc func(ctx context.Context, name string, content []byte) (string, error)
+
+func (f pinnerFunc) Pin(ctx context.Context, name string, content []byte) (string, error) {
+	return f(ctx, name, content)
+}
+
+func TestPinMetadata(t *testing.T) {
+	var added []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v0/add", r.URL.Path)
+		assert.Equal(t, "0", r.URL.Query().Get("cid-version"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		file, header, err := r.FormFile("file")
+		require.NoError(t, err, "Request should have a file")
+		content, _ := ioutil.ReadAll(file)
+		if strings.Contains(string(content), "invalid") {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"Message": "invalid file", "Code": 0}`))
+			return
+		}
+		added = append(added, header.Filename)
+		w.Write([]byte(`{"Name": "` + header.Filename + `", "Hash": "` + Hash(content) + `", "Size": "1"}`))
+	}))
+	defer srv.Close()
+
+	compiler, err := solc.NewFromFile("../solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err, "Creating compiler should not error")
+	defer compiler.Close()
+	in := solc.NewInput().
+		AddSource("Base.sol", "pragma solidity ^0.6.2; contract Base {}").
+		AddSource("A.sol", `pragma solidity ^0.6.2; import "Base.sol"; contract A is Base {}`)
+	in.Settings.OutputSelection.Select("*", "*", "metadata", "evm.deployedBytecode")
+	out, err := compiler.Compile(in)
+	require.NoError(t, err, "Compile should not error")
+
+	kubo := &Kubo{URL: srv.URL, Header: http.Header{"Authorization": {"Bearer token"}}}
+	pinned, err := PinMetadata(context.Background(), kubo, in, out)
+	require.NoError(t, err, "PinMetadata should not error")
+	var names []string
+	for _, p := range pinned {
+		names = append(names, p.Contract.String()+" "+p.Name)
+	}
+	assert.Equal(
+		t,
+		[]string{" A.sol", " Base.sol", "A.sol:A metadata.json", "Base.sol:Base metadata.json"},
+		names,
+		"Metadata should follow its sources, pinned once",
+	)
+	assert.Equal(t, []string{"A.sol", "Base.sol", "metadata.json", "metadata.json"}, added)
+	assert.Equal(t, Hash([]byte(out.Contracts["A.sol"]["A"].Metadata)), pinned[2].Hash)
+
+	// Sources differing from the compiled ones do not have the hash of the metadata
+	in.Sources["Base.sol"] = solc.SourceIn{Content: "contract Base {}"}
+	_, err = PinMetadata(context.Background(), kubo, in, out)
+	require.IsType(t, &HashMismatchError{}, err, "Modified source should not match")
+	assert.Equal(t, "Base.sol", err.(*HashMismatchError).Name)
+
+	// Pinners must return the hash solc computes
+	truncating := pinnerFunc(func(ctx context.Context, name string, content []byte) (string, error) { return Hash(content[1:]), nil })
+	in.Sources["Base.sol"] = solc.SourceIn{Content: "pragma solidity ^0.6.2; contract Base {}"}
+	_, err = PinMetadata(context.Background(), truncating, in, &solc.Output{Contracts: map[string]map[string]solc.Contract{
+		"Base.sol": {"Base": out.Contracts["Base.sol"]["Base"]},
+	}})
+	require.IsType(t, &HashMismatchError{}, err, "Other hashes should not match")
+	assert.Equal(t, "Base.sol", err.(*HashMismatchError).Name, "Sources should be pinned first")
+
+	_, err = PinMetadata(context.Background(), kubo, solc.NewInput(), out)
+	assert.Error(t, err, "Missing source should error")
+
+	_, err = kubo.Pin(context.Background(), "invalid.sol", []byte("invalid"))
+	require.Error(t, err, "Failed addition should error")
+	assert.Contains(t, err.Error(), "invalid file")
+}
@@ -0,0 +1,200 @@
+package ipfs
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strings"
+
+	solc "github.com/nmvalera/solc-go"
+)
+
+// DefaultKuboURL is the URL of the RPC API of a local IPFS node
+const DefaultKuboURL = "http://127.0.0.1:5001"
+
+// Pinner adds and pins files to IPFS
+type Pinner interface {
+	// Pin adds content under name and returns its CIDv0
+	Pin(ctx context.Context, name string, content []byte) (string, error)
+}
+
+// Kubo pins files with the RPC API of an IPFS node (POST /api/v0/add), which hosted pinning
+// services such as Infura also expose
+type Kubo struct {
+	// URL defaults to DefaultKuboURL
+	URL string
+
+	// Header is added to requests, e.g. Authorization for hosted nodes
+	Header http.Header
+
+	// Client defaults to http.DefaultClient
+	Client *http.Client
+}
+
+// Pin adds and pins content with CIDv0 and the default chunker, so its CID is the one solc computes
+func (k *Kubo) Pin(ctx context.Context, name string, content []byte) (string, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", name)
+	if err != nil {
+		return "", err
+	}
+	_, _ = part.Write(content)
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	url := k.URL
+	if url == "" {
+		url = DefaultKuboURL
+	}
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(url, "/")+"/api/v0/add?pin=true&cid-version=0", &body)
+	if err != nil {
+		return "", err
+	}
+	req = req.WithContext(ctx)
+	for key, values := range k.Header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	client := k.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(res.Body)
+		var e struct{ Message string }
+		if json.Unmarshal(msg, &e) == nil && e.Message != "" {
+			msg = []byte(e.Message)
+		}
+		return "", fmt.Errorf("ipfs: adding %v failed: %v: %s", name, res.Status, bytes.TrimSpace(msg))
+	}
+	var added struct{ Hash string }
+	err = json.NewDecoder(res.Body).Decode(&added)
+	if err != nil {
+		return "", err
+	}
+	return added.Hash, nil
+}
+
+// Pinned is a file pinned by PinMetadata
+type Pinned struct {
+	// Name is the source unit name of sources, "metadata.json" of contracts for metadata
+	Name string
+
+	// Contract is set for metadata
+	Contract solc.ContractID
+
+	Hash string
+}
+
+// HashMismatchError is returned when a pinned file has not the hash referenced by a contract,
+// its metadata or sources differ from the compiled ones
+type HashMismatchError struct {
+	Name     string
+	Expected string
+	Got      string
+}
+
+func (e *HashMismatchError) Error() string {
+	return fmt.Sprintf("ipfs: %v pinned as %v, expected %v", e.Name, e.Got, e.Expected)
+}
+
+// PinMetadata pins the metadata of the contracts of out and the sources of in they reference, in the order
+// of the contracts with the metadata of each contract following its sources, which are pinned once
+//
+// Pinned files must have the hash embedded in bytecode for metadata and listed by metadata for sources,
+// a *HashMismatchError is returned otherwise. Contracts without metadata output are skipped, sources with
+// their content in metadata (useLiteralContent) are not pinned
+func PinMetadata(ctx context.Context, p Pinner, in *solc.Input, out *solc.Output) ([]Pinned, error) {
+	var pinned []Pinned
+	sources := make(map[string]bool)
+	for _, id := range out.ContractIDs() {
+		c := out.Contracts[id.File][id.Name]
+		if c.Metadata == "" {
+			continue
+		}
+		var metadata struct {
+			Sources map[string]struct {
+				Content string   `json:"content"`
+				URLs    []string `json:"urls"`
+			} `json:"sources"`
+		}
+		err := json.Unmarshal([]byte(c.Metadata), &metadata)
+		if err != nil {
+			return nil, fmt.Errorf("ipfs: invalid metadata of %v: %v", id, err)
+		}
+
+		var names []string
+		for name := range metadata.Sources {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			source := metadata.Sources[name]
+			if source.Content != "" || sources[name] {
+				continue
+			}
+			content, ok := in.Sources[name]
+			if !ok {
+				return nil, fmt.Errorf("ipfs: source %v of %v not found in input", name, id)
+			}
+			expected := ""
+			for _, url := range source.URLs {
+				if strings.HasPrefix(url, "dweb:/ipfs/") {
+					expected = strings.TrimPrefix(url, "dweb:/ipfs/")
+				}
+			}
+			hash, err := pin(ctx, p, name, []byte(content.Content), expected)
+			if err != nil {
+				return nil, err
+			}
+			sources[name] = true
+			pinned = append(pinned, Pinned{Name: name, Hash: hash})
+		}
+
+		// Metadata follows the library placeholders of unlinked code
+		expected := ""
+		obj := strings.TrimPrefix(c.EVM.DeployedBytecode.Object, "0x")
+		if i := strings.LastIndex(obj, "__"); i >= 0 {
+			obj = obj[i+2:]
+		}
+		code, err := hex.DecodeString(obj)
+		if err == nil {
+			if m, err := solc.ParseBytecodeMetadata(code); err == nil && m != nil && len(m.IPFS) > 0 {
+				expected = Base58(m.IPFS)
+			}
+		}
+		hash, err := pin(ctx, p, "metadata.json", []byte(c.Metadata), expected)
+		if err != nil {
+			return nil, err
+		}
+		pinned = append(pinned, Pinned{Name: "metadata.json", Contract: id, Hash: hash})
+	}
+	return pinned, nil
+}
+
+// pin pins content and checks its hash if expected is set
+func pin(ctx context.Context, p Pinner, name string, content []byte, expected string) (string, error) {
+	hash, err := p.Pin(ctx, name, content)
+	if err != nil {
+		return "", err
+	}
+	if expected != "" && hash != expected {
+		return "", &HashMismatchError{Name: name, Expected: expected, Got: hash}
+	}
+	return hash, nil
+}
@@ -0,0 +1,88 @@
+package solc
+
+import (
+	"fmt"
+)
+
+// IRTransform rewrites the optimized Yul IR of a contract before RoundTripIR recompiles it
+type IRTransform func(ir string) (string, error)
+
+// IRRoundTrip is a contract compiled from Solidity and recompiled from its optimized Yul IR
+type IRRoundTrip struct {
+	Contract ContractID
+
+	// IR is the recompiled IR, the irOptimized output transformed if a transform is set
+	IR string
+
+	// Direct is the creation bytecode of the Solidity compilation, Yul the one of the recompiled IR
+	Direct Bytecode
+	Yul    Bytecode
+
+	// Errors are the ones of the Yul compilation, Yul is empty if one of them is an error
+	Errors []Error
+}
+
+// Match returns whether the recompiled IR has the creation bytecode of the Solidity compilation
+func (rt *IRRoundTrip) Match() bool {
+	return rt.Yul.Object != "" && rt.Yul.Object == rt.Direct.Object
+}
+
+// RoundTripIR compiles in, extracts the irOptimized output of contract name (see FindContract),
+// transforms it if transform is not nil and recompiles it as Yul with the optimizer and EVM settings of in
+//
+// Only inputs compiled through the IR pipeline (see SetViaIR) are expected to match, compilers
+// generating legacy code produce other bytecode from the same contract.
+// Errors of the Solidity compilation are returned, the ones of the Yul compilation reported
+func RoundTripIR(solc Solc, in *Input, name string, transform IRTransform) (*IRRoundTrip, error) {
+	direct := *in
+	direct.Settings.OutputSelection = SelectAll("irOptimized", "evm.bytecode")
+	out, err := solc.Compile(&direct)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range out.Errors {
+		if e.Severity == "error" {
+			return nil, fmt.Errorf("solc: compiling %v failed: %v", name, e.Message)
+		}
+	}
+	file, contract, err := out.FindContract(name)
+	if err != nil {
+		return nil, err
+	}
+	id := ContractID{File: file, Name: name}
+	if parsed, err := ParseContractID(name); err == nil {
+		id.Name = parsed.Name
+	}
+	if contract.IROptimized == "" {
+		return nil, fmt.Errorf("solc: no optimized IR for %v, solc %v may not generate it", id, ShortVersion(solc.Version()))
+	}
+
+	rt := &IRRoundTrip{Contract: id, IR: contract.IROptimized, Direct: contract.EVM.Bytecode}
+	if transform != nil {
+		rt.IR, err = transform(rt.IR)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	yulFile := id.Name + ".yul"
+	yulOut, err := solc.Compile(&Input{
+		Language: "Yul",
+		Sources:  map[string]SourceIn{yulFile: {Content: rt.IR}},
+		Settings: Settings{
+			Optimizer:       in.Settings.Optimizer,
+			EVMVersion:      in.Settings.EVMVersion,
+			EOFVersion:      in.Settings.EOFVersion,
+			OutputSelection: SelectAll("evm.bytecode"),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	rt.Errors = yulOut.Errors
+	// A Yul source has a single top level object, named after the contract and its AST ID
+	for _, object := range yulOut.Contracts[yulFile] {
+		rt.Yul = object.EVM.Bytecode
+	}
+	return rt, nil
+}
@@ -0,0 +1,45 @@
+package solc
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTripIR(t *testing.T) {
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err, "Creating compiler should not error")
+	defer solc.Close()
+
+	in := NewInput().AddSource("A.sol", "pragma solidity ^0.6.0;\ncontract A { uint x; function f(uint y) public { x = y + 1; } }")
+	rt, err := RoundTripIR(solc, in, "A", nil)
+	require.NoError(t, err, "RoundTripIR should not error")
+	assert.Equal(t, ContractID{File: "A.sol", Name: "A"}, rt.Contract)
+	assert.Contains(t, rt.IR, `object "A_16"`, "Optimized IR should be extracted")
+	assert.NotEmpty(t, rt.Yul.Object, "IR should be recompiled")
+	assert.NotEmpty(t, rt.Direct.Object, "Solidity bytecode should be kept")
+	assert.False(t, rt.Match(), "Legacy code generation should not match the IR pipeline")
+
+	// Storing y + 2 instead of y + 1 changes the recompiled code
+	transformed, err := RoundTripIR(solc, in, "A.sol:A", func(ir string) (string, error) {
+		return strings.Replace(ir, "add(_2, 0x01)", "add(_2, 0x02)", 1), nil
+	})
+	require.NoError(t, err, "RoundTripIR should not error")
+	assert.Equal(t, ContractID{File: "A.sol", Name: "A"}, transformed.Contract)
+	assert.Contains(t, transformed.IR, "add(_2, 0x02)", "IR should be transformed")
+	assert.NotEqual(t, rt.Yul.Object, transformed.Yul.Object, "Transformed IR should be recompiled")
+
+	broken, err := RoundTripIR(solc, in, "A", func(ir string) (string, error) { return ir + "}", nil })
+	require.NoError(t, err, "Yul errors should be reported")
+	assert.Empty(t, broken.Yul.Object)
+	assert.False(t, broken.Match())
+	assert.Equal(t, "error", broken.Errors[len(broken.Errors)-1].Severity)
+
+	_, err = RoundTripIR(solc, in, "A", func(ir string) (string, error) { return "", fmt.Errorf("transform failed") })
+	assert.EqualError(t, err, "transform failed", "Transform errors should be returned")
+	_, err = RoundTripIR(solc, in, "B", nil)
+	assert.Error(t, err, "Unknown contracts should error")
+}
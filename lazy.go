@@ -0,0 +1,154 @@
+package solc
+
+import (
+	"context"
+	"sync"
+)
+
+// LazySolc is a compiler initialized in the background (see Warmup) or on first use
+type LazySolc struct {
+	factory Factory
+
+	once  sync.Once
+	ready chan struct{}
+
+	solc Solc
+	err  error
+}
+
+// NewLazy creates a compiler which instance is only created by Warmup or on first use
+func NewLazy(factory Factory) *LazySolc {
+	return &LazySolc{
+		factory: factory,
+		ready:   make(chan struct{}),
+	}
+}
+
+// Warmup starts initializing the compiler in the background and waits until it is ready or ctx is done
+//
+// Initialization keeps running in the background if ctx is done first
+func (l *LazySolc) Warmup(ctx context.Context) error {
+	l.start()
+	select {
+	case <-l.ready:
+		return l.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Ready returns a channel closed once initialization is complete (successful or not)
+func (l *LazySolc) Ready() <-chan struct{} {
+	return l.ready
+}
+
+// IsReady indicates whether the compiler is initialized and can serve compilations right away
+func (l *LazySolc) IsReady() bool {
+	select {
+	case <-l.ready:
+		return l.err == nil
+	default:
+		return false
+	}
+}
+
+// Err returns the initialization error, if initialization is complete
+func (l *LazySolc) Err() error {
+	select {
+	case <-l.ready:
+		return l.err
+	default:
+		return nil
+	}
+}
+
+func (l *LazySolc) start() {
+	l.once.Do(func() {
+		go func() {
+			l.solc, l.err = l.factory()
+			close(l.ready)
+		}()
+	})
+}
+
+// get initializes the compiler if needed and waits for it or ctx to be done
+func (l *LazySolc) get(ctx context.Context) (Solc, error) {
+	l.start()
+	select {
+	case <-l.ready:
+		return l.solc, l.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (l *LazySolc) License() string {
+	solc, err := l.get(context.Background())
+	if err != nil {
+		return ""
+	}
+	return solc.License()
+}
+
+func (l *LazySolc) Version() string {
+	solc, err := l.get(context.Background())
+	if err != nil {
+		return ""
+	}
+	return solc.Version()
+}
+
+func (l *LazySolc) Compile(input *Input) (*Output, error) {
+	return l.CompileContext(context.Background(), input)
+}
+
+// CompileContext is like Compile, ctx bounds the wait for initialization and is passed to the compiler
+// once initialized
+func (l *LazySolc) CompileContext(ctx context.Context, input *Input) (*Output, error) {
+	solc, err := l.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return CompileContext(ctx, solc, input)
+}
+
+func (l *LazySolc) HeapStatistics() HeapStatistics {
+	if !l.IsReady() {
+		return HeapStatistics{}
+	}
+	return l.solc.HeapStatistics()
+}
+
+// Close waits for a started initialization to complete and closes the compiler
+func (l *LazySolc) Close() error {
+	return l.CloseContext(context.Background())
+}
+
+// CloseContext is like Close, if ctx is done before a started initialization completes it returns
+// ctx.Err() and the compiler is closed in the background once initialized
+func (l *LazySolc) CloseContext(ctx context.Context) error {
+	started := true
+	l.once.Do(func() {
+		started = false
+		l.err = ErrClosed
+		close(l.ready)
+	})
+	if !started {
+		return nil
+	}
+	select {
+	case <-l.ready:
+	case <-ctx.Done():
+		go func() {
+			<-l.ready
+			if l.solc != nil {
+				l.solc.Close()
+			}
+		}()
+		return ctx.Err()
+	}
+	if l.solc != nil {
+		return l.solc.Close()
+	}
+	return nil
+}
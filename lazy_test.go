@@ -0,0 +1,60 @@
+package solc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazy(t *testing.T) {
+	release := make(chan struct{})
+	created := 0
+	lazy := NewLazy(func() (Solc, error) {
+		<-release
+		created++
+		return &fakeSolc{version: "0.6.2", output: &Output{}}, nil
+	})
+	assert.False(t, lazy.IsReady(), "Compiler should not be ready before warmup")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, lazy.Warmup(ctx), "Warmup should return on context deadline")
+	assert.False(t, lazy.IsReady(), "Compiler should not be ready while initializing")
+
+	close(release)
+	<-lazy.Ready()
+	assert.True(t, lazy.IsReady(), "Compiler should be ready once initialized")
+	require.NoError(t, lazy.Warmup(context.Background()), "Warmup should not error")
+
+	_, err := lazy.Compile(&Input{})
+	require.NoError(t, err, "Compile should not error")
+	assert.Equal(t, "0.6.2", lazy.Version())
+	assert.Equal(t, 1, created, "Compiler should be created once")
+
+	// Closing a compiler never initialized prevents later use
+	lazy = NewLazy(func() (Solc, error) { return &fakeSolc{}, nil })
+	lazy.Close()
+	_, err = lazy.Compile(&Input{})
+	assert.Equal(t, ErrClosed, err, "Compile should error once closed")
+}
+
+func TestLazyContext(t *testing.T) {
+	release := make(chan struct{})
+	lazy := NewLazy(func() (Solc, error) {
+		<-release
+		return &fakeSolc{version: "0.6.2", output: &Output{}}, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := lazy.CompileContext(ctx, &Input{})
+	assert.Equal(t, context.DeadlineExceeded, err, "CompileContext should return on context deadline while initializing")
+	assert.Equal(t, context.DeadlineExceeded, lazy.CloseContext(ctx), "CloseContext should return on context deadline while initializing")
+
+	close(release)
+	<-lazy.Ready()
+	assert.True(t, lazy.IsReady(), "Compiler should be initialized in the background")
+}
@@ -0,0 +1,55 @@
+package solc
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+var leaks = struct {
+	mux     sync.Mutex
+	enabled bool
+	live    map[*baseSolc][]byte
+}{
+	live: make(map[*baseSolc][]byte),
+}
+
+// EnableLeakDetection records the creation stack of every compiler so CheckLeaks can report those never closed
+//
+// It is meant for tests, typically called from TestMain before m.Run()
+func EnableLeakDetection() {
+	leaks.mux.Lock()
+	defer leaks.mux.Unlock()
+	leaks.enabled = true
+}
+
+// CheckLeaks returns an error listing the compilers created since EnableLeakDetection and not closed yet
+func CheckLeaks() error {
+	leaks.mux.Lock()
+	defer leaks.mux.Unlock()
+	if len(leaks.live) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "solc: %v compiler(s) not closed", len(leaks.live))
+	for solc, stack := range leaks.live {
+		fmt.Fprintf(&b, "\n\n%v created at:\n%s", solc.fullVersion, stack)
+	}
+	return fmt.Errorf("%v", b.String())
+}
+
+func trackInstance(solc *baseSolc) {
+	leaks.mux.Lock()
+	defer leaks.mux.Unlock()
+	if leaks.enabled {
+		leaks.live[solc] = debug.Stack()
+	}
+}
+
+func untrackInstance(solc *baseSolc) {
+	leaks.mux.Lock()
+	defer leaks.mux.Unlock()
+	delete(leaks.live, solc)
+}
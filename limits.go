@@ -0,0 +1,182 @@
+package solc
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// InputLimits bound the inputs a shared compilation service accepts, zero values are not limited
+type InputLimits struct {
+	// MaxSources is the number of sources of an input
+	MaxSources int
+
+	// MaxSourceBytes is the total size of the contents of the sources of an input
+	MaxSourceBytes int
+
+	// MaxOutputs is the number of outputs selected, counting each output of each file and contract entry
+	MaxOutputs int
+
+	// MaxEstimatedOutput is the estimated size of the output in bytes (see EstimateOutput)
+	MaxEstimatedOutput int
+}
+
+// Check returns a *SizeLimitError of kind "sources", "source bytes" or "outputs" if in exceeds the limits,
+// or an *OutputBudgetError if its estimated output does
+func (l InputLimits) Check(in *Input) error {
+	if l.MaxSources > 0 && len(in.Sources) > l.MaxSources {
+		return &SizeLimitError{Kind: "sources", Size: len(in.Sources), Limit: l.MaxSources}
+	}
+
+	if l.MaxSourceBytes > 0 {
+		size := 0
+		for _, source := range in.Sources {
+			size += len(source.Content)
+		}
+		if size > l.MaxSourceBytes {
+			return &SizeLimitError{Kind: "source bytes", Size: size, Limit: l.MaxSourceBytes}
+		}
+	}
+
+	if l.MaxOutputs > 0 {
+		outputs := 0
+		for _, contracts := range in.Settings.OutputSelection {
+			for _, selected := range contracts {
+				outputs += len(selected)
+			}
+		}
+		if outputs > l.MaxOutputs {
+			return &SizeLimitError{Kind: "outputs", Size: outputs, Limit: l.MaxOutputs}
+		}
+	}
+
+	return CheckOutputBudget(in, l.MaxEstimatedOutput)
+}
+
+// CompilePolicy bounds the resources a compilation of an untrusted input may use, zero values are not limited
+//
+// Policies apply to compilations with a context returned by ContextWithPolicy, on top of the options
+// of the compiler (WithTimeout, WithMemoryLimit), the tightest limit applying
+type CompilePolicy struct {
+	InputLimits
+
+	// Timeout is the wall time of the compilation, the compiler is terminated once it elapses like
+	// with WithTimeout (pools replace it)
+	Timeout time.Duration
+
+	// MaxMemory is the memory usage (see HeapStatistics.Usage) the compiler may have before and after
+	// the compilation. As emscripten memory does not shrink, a compiler exceeding it returns a
+	// *MemoryLimitError and is unhealthy (pools replace it)
+	MaxMemory uint64
+
+	// DenyURLs rejects sources given by URLs instead of content
+	DenyURLs bool
+}
+
+// Check returns the *SizeLimitError of InputLimits.Check if in exceeds the limits, or an *InputError
+// listing the sources given by URLs if they are denied
+func (p CompilePolicy) Check(in *Input) error {
+	err := p.InputLimits.Check(in)
+	if err != nil || !p.DenyURLs {
+		return err
+	}
+
+	var problems []string
+	for name, source := range in.Sources {
+		if len(source.URLs) > 0 {
+			problems = append(problems, fmt.Sprintf("sources[%q]: URL sources are denied", name))
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return &InputError{Problems: problems}
+	}
+	return nil
+}
+
+type policyKey struct{}
+
+// ContextWithPolicy returns a context whose compilations are bounded by policy
+func ContextWithPolicy(ctx context.Context, policy CompilePolicy) context.Context {
+	return context.WithValue(ctx, policyKey{}, policy)
+}
+
+func policyFromContext(ctx context.Context) (CompilePolicy, bool) {
+	policy, ok := ctx.Value(policyKey{}).(CompilePolicy)
+	return policy, ok
+}
+
+// RateLimiter limits the rate of requests of each client with a token bucket per client
+//
+// RateLimiter is safe for concurrent use
+type RateLimiter struct {
+	rate  float64
+	burst float64
+
+	mux     sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// maxIdleBuckets is the number of buckets above which full buckets are dropped
+const maxIdleBuckets = 1024
+
+// NewRateLimiter allows each client rate requests per second on average and burst requests at once (1 if <= 0)
+//
+// A rate <= 0 allows every request
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Allow consumes a request of client and indicates whether it is allowed, or else how long to wait before retrying
+func (l *RateLimiter) Allow(client string) (bool, time.Duration) {
+	if l.rate <= 0 {
+		return true, 0
+	}
+
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	now := l.now()
+	b, ok := l.buckets[client]
+	if !ok {
+		if len(l.buckets) >= maxIdleBuckets {
+			l.prune(now)
+		}
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[client] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
+
+// prune drops the buckets refilled since, which behave like new ones
+func (l *RateLimiter) prune(now time.Time) {
+	for client, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, client)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package solc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInputLimits(t *testing.T) {
+	in := NewInput().AddSource("A.sol", "contract A {}").AddSource("B.sol", "contract B {}")
+	assert.NoError(t, InputLimits{}.Check(in), "Zero limits should not limit")
+	assert.NoError(t, InputLimits{MaxSources: 2, MaxSourceBytes: 26, MaxOutputs: 4}.Check(in), "Input within limits should pass")
+
+	assert.Equal(t, &SizeLimitError{Kind: "sources", Size: 2, Limit: 1}, InputLimits{MaxSources: 1}.Check(in))
+	assert.Equal(t, &SizeLimitError{Kind: "source bytes", Size: 26, Limit: 25}, InputLimits{MaxSourceBytes: 25}.Check(in))
+	assert.Equal(t, &SizeLimitError{Kind: "outputs", Size: 4, Limit: 3}, InputLimits{MaxOutputs: 3}.Check(in))
+}
+
+func TestCompilePolicy(t *testing.T) {
+	in := NewInput().AddSource("A.sol", "contract A {}")
+	in.Sources["B.sol"] = SourceIn{URLs: []string{"https://example.com/B.sol"}}
+	assert.NoError(t, CompilePolicy{}.Check(in), "Zero policy should not limit")
+	assert.Equal(t, &SizeLimitError{Kind: "sources", Size: 2, Limit: 1}, CompilePolicy{InputLimits: InputLimits{MaxSources: 1}}.Check(in))
+	assert.Equal(t, &InputError{Problems: []string{`sources["B.sol"]: URL sources are denied`}}, CompilePolicy{DenyURLs: true}.Check(in))
+
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err, "Creating compiler should not error")
+	defer solc.Close()
+
+	ctx := ContextWithPolicy(context.Background(), CompilePolicy{DenyURLs: true})
+	_, err = CompileContext(ctx, solc, in)
+	assert.IsType(t, &InputError{}, err, "Denied inputs should not compile")
+
+	in = NewInput().AddSource("A.sol", "contract A {}")
+	_, err = CompileContext(ContextWithPolicy(context.Background(), CompilePolicy{MaxMemory: 1024}), solc, in)
+	assert.IsType(t, &MemoryLimitError{}, err, "Compilers over the memory of the policy should not compile")
+	out, err := CompileContext(ContextWithPolicy(context.Background(), CompilePolicy{MaxMemory: 1 << 40, Timeout: time.Minute}), solc, in)
+	require.NoError(t, err, "Compilation within the policy should not error")
+	assert.NotEmpty(t, out.Contracts["A.sol"]["A"].EVM.Bytecode.Object)
+	assert.NoError(t, Healthy(solc), "Compiler should remain healthy")
+
+	var b strings.Builder
+	b.WriteString("pragma solidity ^0.6.0;\ncontract Big {\n")
+	for i := 0; i < 50; i++ {
+		fmt.Fprintf(&b, "  function f%d(uint a) public pure returns (uint) { return a * %d; }\n", i, i)
+	}
+	b.WriteString("}\n")
+	_, err = CompileContext(ContextWithPolicy(context.Background(), CompilePolicy{Timeout: time.Millisecond}), solc, NewInput().AddSource("Big.sol", b.String()))
+	assert.Equal(t, ErrTimeout, err, "Compilation over the policy wall time should be terminated")
+	assert.Equal(t, ErrTerminated, Healthy(solc), "Terminated compiler should be unhealthy")
+}
+
+func TestPoolPolicy(t *testing.T) {
+	p, _ := newEchoPool(t, 1, 0)
+	defer p.Close()
+
+	in := NewInput().AddSource("A.sol", "contract A {}")
+	ctx := ContextWithPolicy(context.Background(), CompilePolicy{InputLimits: InputLimits{MaxSources: 1}})
+	_, err := p.CompileContext(ctx, in.AddSource("B.sol", "contract B {}"))
+	assert.IsType(t, &SizeLimitError{}, err, "Inputs over the policy should be rejected")
+	_, err = p.CompileContext(ctx, NewInput().AddSource("A.sol", "contract A {}"))
+	assert.NoError(t, err, "Inputs within the policy should compile")
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := NewRateLimiter(2, 2)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		ok, _ := l.Allow("a")
+		assert.True(t, ok, "Requests within the burst should be allowed")
+	}
+	ok, retry := l.Allow("a")
+	assert.False(t, ok, "Requests over the burst should be limited")
+	assert.Equal(t, 500*time.Millisecond, retry, "Retry should be the time to refill a token")
+
+	ok, _ = l.Allow("b")
+	assert.True(t, ok, "Clients should have their own bucket")
+
+	now = now.Add(500 * time.Millisecond)
+	ok, _ = l.Allow("a")
+	assert.True(t, ok, "Tokens should refill over time")
+
+	ok, _ = NewRateLimiter(0, 0).Allow("a")
+	assert.True(t, ok, "Zero rate should not limit")
+}
@@ -0,0 +1,204 @@
+package solc
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nmvalera/solc-go/types"
+)
+
+// placeholderLen is the length in hex characters of a library placeholder (20 bytes)
+const placeholderLen = 40
+
+// UnresolvedLibrariesError is returned when bytecode references libraries with no address
+type UnresolvedLibrariesError struct {
+	// Libraries are fully qualified library names, or raw placeholders when the name is unknown
+	Libraries []string
+}
+
+func (e *UnresolvedLibrariesError) Error() string {
+	return fmt.Sprintf("unresolved libraries: %v", strings.Join(e.Libraries, ", "))
+}
+
+// LibraryPlaceholder returns the placeholder solc >=0.5.0 writes in bytecode for a library (e.g. "lib/Math.sol:Math")
+func LibraryPlaceholder(fqName string) string {
+	return "__$" + hex.EncodeToString(types.Keccak256([]byte(fqName)))[:34] + "$__"
+}
+
+// LegacyLibraryPlaceholder returns the placeholder solc <0.5.0 writes in bytecode for a library
+func LegacyLibraryPlaceholder(name string) string {
+	if len(name) > placeholderLen-4 {
+		name = name[:placeholderLen-4]
+	}
+	placeholder := "__" + name
+	return placeholder + strings.Repeat("_", placeholderLen-len(placeholder))
+}
+
+// Link substitutes library addresses in bytecode
+//
+// Libraries are keyed by fully qualified name ("lib/Math.sol:Math") or by bare name ("Math").
+// Offsets from bytecode.LinkReferences are used when present, placeholders are searched in the object otherwise.
+// An *UnresolvedLibrariesError is returned if some references remain unlinked
+func Link(bytecode Bytecode, libraries map[string]Address) (Bytecode, error) {
+	obj := []byte(bytecode.Object)
+	offset := 0
+	if strings.HasPrefix(bytecode.Object, "0x") {
+		offset = 2
+	}
+
+	unresolved := make(map[string]bool)
+	for file, libs := range bytecode.LinkReferences {
+		for lib, refs := range libs {
+			addr, ok := lookupLibrary(libraries, file, lib)
+			if !ok {
+				unresolved[ContractID{File: file, Name: lib}.String()] = true
+				continue
+			}
+			for _, ref := range refs {
+				start, end := offset+2*ref.Start, offset+2*(ref.Start+ref.Length)
+				if start < 0 || end > len(obj) || end-start != placeholderLen {
+					return bytecode, fmt.Errorf("invalid link reference %v:%v at %v", file, lib, ref.Start)
+				}
+				copy(obj[start:end], hex.EncodeToString(addr[:]))
+			}
+		}
+	}
+
+	// Substitute placeholders not covered by link references
+	linked := string(obj)
+	for name, addr := range libraries {
+		a := hex.EncodeToString(addr[:])
+		linked = strings.Replace(linked, LibraryPlaceholder(name), a, -1)
+		linked = strings.Replace(linked, LegacyLibraryPlaceholder(name), a, -1)
+	}
+
+	for _, placeholder := range findPlaceholders(linked) {
+		if !unresolvedContains(unresolved, placeholder) {
+			unresolved[placeholder] = true
+		}
+	}
+
+	if len(unresolved) > 0 {
+		libs := make([]string, 0, len(unresolved))
+		for lib := range unresolved {
+			libs = append(libs, lib)
+		}
+		sort.Strings(libs)
+		return bytecode, &UnresolvedLibrariesError{Libraries: libs}
+	}
+
+	bytecode.Object = linked
+	bytecode.LinkReferences = nil
+	return bytecode, nil
+}
+
+func lookupLibrary(libraries map[string]Address, file, lib string) (Address, bool) {
+	if addr, ok := libraries[ContractID{File: file, Name: lib}.String()]; ok {
+		return addr, true
+	}
+	addr, ok := libraries[lib]
+	return addr, ok
+}
+
+// unresolvedContains indicates whether a placeholder belongs to an already reported library
+func unresolvedContains(unresolved map[string]bool, placeholder string) bool {
+	for name := range unresolved {
+		if placeholder == LibraryPlaceholder(name) || placeholder == LegacyLibraryPlaceholder(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// findPlaceholders returns the distinct library placeholders remaining in a hex object
+func findPlaceholders(obj string) []string {
+	var placeholders []string
+	seen := make(map[string]bool)
+	for i := strings.Index(obj, "__"); i >= 0; i = strings.Index(obj, "__") {
+		end := i + placeholderLen
+		if end > len(obj) {
+			end = len(obj)
+		}
+		placeholder := obj[i:end]
+		if !seen[placeholder] {
+			seen[placeholder] = true
+			placeholders = append(placeholders, placeholder)
+		}
+		obj = obj[end:]
+	}
+	return placeholders
+}
+
+// UnresolvedReferencesError is returned by CheckBytecode for bytecode that can not be deployed or executed as is
+type UnresolvedReferencesError struct {
+	// Libraries are fully qualified names of unlinked libraries, or raw placeholders when the name is unknown
+	Libraries []string
+
+	// Immutables are AST ids of immutable variables whose value regions are still zero
+	Immutables []string
+}
+
+func (e *UnresolvedReferencesError) Error() string {
+	var parts []string
+	if len(e.Libraries) > 0 {
+		parts = append(parts, fmt.Sprintf("unlinked libraries: %v", strings.Join(e.Libraries, ", ")))
+	}
+	if len(e.Immutables) > 0 {
+		parts = append(parts, fmt.Sprintf("unset immutables: %v", strings.Join(e.Immutables, ", ")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// CheckBytecode reports library placeholders remaining in bytecode and immutable references whose value is still zero
+//
+// Immutables are only checked for runtime bytecode carrying immutableReferences (solc >=0.6.5)
+func CheckBytecode(bytecode Bytecode) error {
+	obj := strings.TrimPrefix(bytecode.Object, "0x")
+	e := &UnresolvedReferencesError{}
+
+	libs := make(map[string]bool)
+	for file, refs := range bytecode.LinkReferences {
+		for lib, locs := range refs {
+			for _, loc := range locs {
+				if region(obj, loc) == "" || strings.HasPrefix(region(obj, loc), "__") {
+					libs[ContractID{File: file, Name: lib}.String()] = true
+				}
+			}
+		}
+	}
+	for _, placeholder := range findPlaceholders(obj) {
+		if !unresolvedContains(libs, placeholder) {
+			libs[placeholder] = true
+		}
+	}
+	for lib := range libs {
+		e.Libraries = append(e.Libraries, lib)
+	}
+	sort.Strings(e.Libraries)
+
+	for id, locs := range bytecode.ImmutableReferences {
+		for _, loc := range locs {
+			if r := region(obj, loc); r == "" || strings.Trim(r, "0") == "" {
+				e.Immutables = append(e.Immutables, id)
+				break
+			}
+		}
+	}
+	sort.Strings(e.Immutables)
+
+	if len(e.Libraries) == 0 && len(e.Immutables) == 0 {
+		return nil
+	}
+	return e
+}
+
+// region returns the hex characters of obj covered by ref or "" if out of bounds
+func region(obj string, ref LinkReference) string {
+	start, end := 2*ref.Start, 2*(ref.Start+ref.Length)
+	if start < 0 || end > len(obj) || start >= end {
+		return ""
+	}
+	return obj[start:end]
+}
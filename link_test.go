@@ -0,0 +1,75 @@
+package solc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const unlinkedObject = "608060405234801561001057600080fd5b50610104806100206000396000f3fe6080604052348015600f57600080fd5b506004361060285760003560e01c806326121ff014602d575b600080fd5b60336045565b60408051918252519081900360200190f35b600073__$681a532c988234dfef2962265debbeb850$__63771602f7600160026040518363ffffffff1660e01b8152600401808381526020018281526020019250505060206040518083038186803b158015609f57600080fd5b505af415801560b2573d6000803e3d6000fd5b505050506040513d602081101560c757600080fd5b505190509056fea264697066735822122052095711cc6f06ed626a87c6168ce3946849bf25db74a934f7abdda661c62ff464736f6c63430006020033"
+
+func TestLink(t *testing.T) {
+	assert.Equal(t, "__$681a532c988234dfef2962265debbeb850$__", LibraryPlaceholder("lib/Math.sol:Math"), "Placeholder should be correct")
+	assert.Equal(t, "__lib/Math.sol:Math_____________________", LegacyLibraryPlaceholder("lib/Math.sol:Math"), "Legacy placeholder should be correct")
+
+	addr, err := HexToAddress("0x1234567890abcdef1234567890abcdef12345678")
+	require.NoError(t, err, "HexToAddress should not error")
+
+	unlinked := Bytecode{
+		Object: unlinkedObject,
+		LinkReferences: map[string]map[string][]LinkReference{
+			"lib/Math.sol": map[string][]LinkReference{"Math": []LinkReference{{Start: 105, Length: 20}}},
+		},
+	}
+
+	// Link using link references and bare library name
+	linked, err := Link(unlinked, map[string]Address{"Math": addr})
+	require.NoError(t, err, "Link should not error")
+	assert.Equal(t, strings.Replace(unlinkedObject, LibraryPlaceholder("lib/Math.sol:Math"), "1234567890abcdef1234567890abcdef12345678", 1), linked.Object, "Object should be linked")
+	assert.Nil(t, linked.LinkReferences, "Linked bytecode should have no link references")
+
+	// Link by placeholder search when link references are missing
+	linked, err = Link(Bytecode{Object: unlinkedObject}, map[string]Address{"lib/Math.sol:Math": addr})
+	require.NoError(t, err, "Link should not error")
+	assert.NotContains(t, linked.Object, "__", "Object should be linked")
+
+	// Unresolved libraries
+	_, err = Link(unlinked, map[string]Address{"Other": addr})
+	require.IsType(t, &UnresolvedLibrariesError{}, err, "Link should error on unresolved library")
+	assert.Equal(t, []string{"lib/Math.sol:Math"}, err.(*UnresolvedLibrariesError).Libraries)
+
+	_, err = Link(Bytecode{Object: unlinkedObject}, nil)
+	require.IsType(t, &UnresolvedLibrariesError{}, err, "Link should error on unresolved placeholder")
+	assert.Equal(t, []string{LibraryPlaceholder("lib/Math.sol:Math")}, err.(*UnresolvedLibrariesError).Libraries)
+}
+
+func TestCheckBytecode(t *testing.T) {
+	unlinked := Bytecode{
+		Object: unlinkedObject,
+		LinkReferences: map[string]map[string][]LinkReference{
+			"lib/Math.sol": map[string][]LinkReference{"Math": []LinkReference{{Start: 105, Length: 20}}},
+		},
+	}
+	err := CheckBytecode(unlinked)
+	require.IsType(t, &UnresolvedReferencesError{}, err, "CheckBytecode should error on unlinked bytecode")
+	assert.Equal(t, []string{"lib/Math.sol:Math"}, err.(*UnresolvedReferencesError).Libraries)
+
+	addr, _ := HexToAddress("0x1234567890abcdef1234567890abcdef12345678")
+	linked, err := Link(unlinked, map[string]Address{"Math": addr})
+	require.NoError(t, err)
+	assert.NoError(t, CheckBytecode(linked), "CheckBytecode should not error on linked bytecode")
+
+	runtime := Bytecode{
+		Object: "6080" + strings.Repeat("00", 32) + "60" + strings.Repeat("ab", 32),
+		ImmutableReferences: map[string][]LinkReference{
+			"3": []LinkReference{{Start: 2, Length: 32}},
+			"5": []LinkReference{{Start: 35, Length: 32}},
+		},
+	}
+	err = CheckBytecode(runtime)
+	require.IsType(t, &UnresolvedReferencesError{}, err, "CheckBytecode should error on unset immutable")
+	assert.Equal(t, []string{"3"}, err.(*UnresolvedReferencesError).Immutables)
+	assert.Empty(t, err.(*UnresolvedReferencesError).Libraries)
+}
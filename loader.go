@@ -0,0 +1,93 @@
+package solc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SourceNormalization selects how file loaders (LoadDirNormalized, FSResolver) rewrite the contents of sources
+//
+// Metadata holds the keccak256 hash of every source and its own hash is appended to bytecode, so the same
+// sources checked out with CRLF line endings (e.g. by git on Windows) or saved with a byte order mark build
+// different bytecode, and a BOM may even fail parsing with older compilers. Normalizing makes builds
+// reproducible across machines, at the cost of compiling other bytes than the files: a contract verified
+// from its original files (see the verify package) must be built the same way it was deployed
+type SourceNormalization uint
+
+const (
+	// StripBOM removes the UTF-8 byte order mark starting a source
+	StripBOM SourceNormalization = 1 << iota
+
+	// NormalizeLineEndings replaces CRLF and CR line endings by LF
+	NormalizeLineEndings
+
+	// NormalizeNone loads sources byte for byte
+	NormalizeNone SourceNormalization = 0
+
+	// NormalizeAll applies every normalization
+	NormalizeAll = StripBOM | NormalizeLineEndings
+)
+
+// utf8BOM is the UTF-8 encoded byte order mark
+const utf8BOM = "\ufeff"
+
+// Normalize returns content normalized
+func (n SourceNormalization) Normalize(content string) string {
+	if n&StripBOM != 0 {
+		content = strings.TrimPrefix(content, utf8BOM)
+	}
+	if n&NormalizeLineEndings != 0 && strings.Contains(content, "\r") {
+		content = strings.Replace(content, "\r\n", "\n", -1)
+		content = strings.Replace(content, "\r", "\n", -1)
+	}
+	return content
+}
+
+// LoadDir reads every .sol file under root
+//
+// Source unit names are paths relative to root using forward slashes. Contents are read byte for byte,
+// see LoadDirNormalized
+func LoadDir(root string) (map[string]SourceIn, error) {
+	return LoadDirNormalized(root, NormalizeNone)
+}
+
+// LoadDirNormalized reads every .sol file under root like LoadDir, normalizing their contents
+func LoadDirNormalized(root string, normalization SourceNormalization) (map[string]SourceIn, error) {
+	sources := make(map[string]SourceIn)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != root && skipDir(info.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".sol" {
+			return nil
+		}
+
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		sources[filepath.ToSlash(name)] = SourceIn{Content: normalization.Normalize(string(content))}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sources, nil
+}
+
+// skipDir indicates whether a directory should not be searched for sources
+func skipDir(name string) bool {
+	return strings.HasPrefix(name, ".") || name == "node_modules"
+}
@@ -0,0 +1,54 @@
+package solc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceNormalization(t *testing.T) {
+	content := "\ufeffpragma solidity ^0.6.0;\r\ncontract A {}\rcontract B {}\n"
+	assert.Equal(t, content, NormalizeNone.Normalize(content), "NormalizeNone should keep content")
+	assert.Equal(t, "pragma solidity ^0.6.0;\r\ncontract A {}\rcontract B {}\n", StripBOM.Normalize(content))
+	assert.Equal(t, "\ufeffpragma solidity ^0.6.0;\ncontract A {}\ncontract B {}\n", NormalizeLineEndings.Normalize(content))
+	assert.Equal(t, "pragma solidity ^0.6.0;\ncontract A {}\ncontract B {}\n", NormalizeAll.Normalize(content))
+}
+
+func TestLoadDirNormalized(t *testing.T) {
+	dir, err := ioutil.TempDir("", "solc-loader")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "A.sol"), []byte("\ufeffpragma solidity ^0.6.0;\r\ncontract A {}\r\n"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "B.sol"), []byte("pragma solidity ^0.6.0;\ncontract B {}\n"), 0644))
+
+	sources, err := LoadDir(dir)
+	require.NoError(t, err, "LoadDir should not error")
+	assert.Equal(t, "\ufeffpragma solidity ^0.6.0;\r\ncontract A {}\r\n", sources["A.sol"].Content, "LoadDir should read sources byte for byte")
+
+	normalized, err := LoadDirNormalized(dir, NormalizeAll)
+	require.NoError(t, err, "LoadDirNormalized should not error")
+	assert.Equal(t, "pragma solidity ^0.6.0;\ncontract A {}\n", normalized["A.sol"].Content)
+	assert.Equal(t, sources["B.sol"], normalized["B.sol"], "Normalized sources should be kept")
+
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err, "Creating compiler should not error")
+	defer solc.Close()
+	compile := func(sources map[string]SourceIn) string {
+		out, err := solc.Compile(NewInput().AddSources(map[string]SourceIn{"A.sol": sources["A.sol"]}).SetOutputSelection(SelectAll("metadata")))
+		require.NoError(t, err, "Compile should not error")
+		return out.Contracts["A.sol"]["A"].Metadata
+	}
+	lf := map[string]SourceIn{"A.sol": {Content: "pragma solidity ^0.6.0;\ncontract A {}\n"}}
+	assert.NotEqual(t, compile(lf), compile(sources), "CRLF and BOM should change metadata")
+	assert.Equal(t, compile(lf), compile(normalized), "Normalized sources should build like LF sources")
+
+	resolver := NewFSResolver(dir)
+	resolver.Normalization = NormalizeAll
+	content, err := resolver.Resolve("A.sol")
+	require.NoError(t, err, "Resolve should not error")
+	assert.Equal(t, normalized["A.sol"].Content, content, "FSResolver should normalize contents")
+}
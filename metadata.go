@@ -0,0 +1,160 @@
+package solc
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoSolcVersion is returned for bytecode without compiler version in its metadata (solc < 0.5.9 or
+// metadata disabled)
+var ErrNoSolcVersion = errors.New("solc: no compiler version in bytecode metadata")
+
+// BytecodeMetadata is the CBOR encoded metadata solc appends to runtime bytecode
+type BytecodeMetadata struct {
+	// IPFS is the sha2-256 multihash of the metadata JSON, the default of solc >=0.6.0
+	IPFS []byte
+
+	// Bzzr0 and Bzzr1 are the Swarm hashes of the metadata JSON written by older compilers
+	Bzzr0 []byte
+	Bzzr1 []byte
+
+	// Solc is the compiler version, 3 bytes (major, minor, patch) for releases and a version string
+	// for prereleases (solc >=0.5.9)
+	Solc []byte
+
+	// Experimental is set for contracts compiled with experimental features
+	Experimental bool
+}
+
+// ParseBytecodeMetadata decodes the metadata ending runtime code, it returns nil if code has none
+func ParseBytecodeMetadata(code []byte) (*BytecodeMetadata, error) {
+	n := metadataLength(code)
+	if n == 0 {
+		return nil, nil
+	}
+	cbor := code[len(code)-n : len(code)-2]
+
+	m := &BytecodeMetadata{}
+	entries, cbor := int(cbor[0]&0x1f), cbor[1:]
+	for i := 0; i < entries; i++ {
+		key, rest, err := cborItem(cbor, 3)
+		if err != nil {
+			return nil, err
+		}
+		cbor = rest
+		if string(key) == "experimental" {
+			if len(cbor) == 0 || (cbor[0] != 0xf4 && cbor[0] != 0xf5) {
+				return nil, fmt.Errorf("solc: invalid metadata: experimental is not a boolean")
+			}
+			m.Experimental, cbor = cbor[0] == 0xf5, cbor[1:]
+			continue
+		}
+
+		// Prerelease versions are text strings, hashes and release versions byte strings
+		major := 2
+		if string(key) == "solc" && len(cbor) > 0 && cbor[0]>>5 == 3 {
+			major = 3
+		}
+		value, rest, err := cborItem(cbor, byte(major))
+		if err != nil {
+			return nil, err
+		}
+		cbor = rest
+		switch string(key) {
+		case "ipfs":
+			m.IPFS = value
+		case "bzzr0":
+			m.Bzzr0 = value
+		case "bzzr1":
+			m.Bzzr1 = value
+		case "solc":
+			m.Solc = value
+		}
+	}
+	if len(cbor) != 0 {
+		return nil, fmt.Errorf("solc: invalid metadata: %v trailing bytes", len(cbor))
+	}
+	return m, nil
+}
+
+// SolcVersion returns the compiler version of the metadata (e.g. 0.6.2 or 0.6.0-nightly.2019.12.10), "" if it has none
+func (m *BytecodeMetadata) SolcVersion() string {
+	if len(m.Solc) == 3 && m.Solc[0] < 10 {
+		return fmt.Sprintf("%v.%v.%v", m.Solc[0], m.Solc[1], m.Solc[2])
+	}
+	return string(m.Solc)
+}
+
+// SolcVersionFromBytecode returns the version of the compiler which produced hex encoded runtime code
+// (e.g. deployed code fetched from a node), read from its metadata
+//
+// Code may be 0x prefixed and hold unlinked library placeholders. ErrNoSolcVersion is returned for code
+// without compiler version
+func SolcVersionFromBytecode(code string) (string, error) {
+	b, _, err := decodeObject(strings.TrimPrefix(code, "0x"))
+	if err != nil {
+		return "", err
+	}
+	m, err := ParseBytecodeMetadata(b)
+	if err != nil {
+		return "", err
+	}
+	if m == nil || len(m.Solc) == 0 {
+		return "", ErrNoSolcVersion
+	}
+	return m.SolcVersion(), nil
+}
+
+// SolcVersionMismatchError is returned when the compiler version of bytecode is not the one of a compiled contract
+type SolcVersionMismatchError struct {
+	// Bytecode is the version read from bytecode, Metadata the long version of the contract metadata
+	Bytecode string
+	Metadata string
+}
+
+func (e *SolcVersionMismatchError) Error() string {
+	return fmt.Sprintf("solc: bytecode compiled by %v, not by %v", e.Bytecode, e.Metadata)
+}
+
+// CheckSolcVersion returns the compiler version of hex encoded runtime code (see SolcVersionFromBytecode)
+// and checks it is the version of the metadata of contract, if selected, it returns a
+// *SolcVersionMismatchError otherwise
+func CheckSolcVersion(code string, contract *Contract) (string, error) {
+	version, err := SolcVersionFromBytecode(code)
+	if err != nil || contract.Metadata == "" {
+		return version, err
+	}
+	var metadata contractMetadata
+	err = json.Unmarshal([]byte(contract.Metadata), &metadata)
+	if err != nil {
+		return "", fmt.Errorf("solc: invalid metadata: %v", err)
+	}
+	long := metadata.Compiler.Version
+	if long != version && !strings.HasPrefix(long, version+"+") {
+		return version, &SolcVersionMismatchError{Bytecode: version, Metadata: long}
+	}
+	return version, nil
+}
+
+// cborItem decodes a CBOR byte (major type 2) or text (3) string at the beginning of b
+func cborItem(b []byte, major byte) ([]byte, []byte, error) {
+	if len(b) == 0 || b[0]>>5 != major {
+		return nil, nil, fmt.Errorf("solc: invalid metadata: expected CBOR major type %v", major)
+	}
+	n, b := int(b[0]&0x1f), b[1:]
+	switch {
+	case n == 24 && len(b) >= 1:
+		n, b = int(b[0]), b[1:]
+	case n == 25 && len(b) >= 2:
+		n, b = int(binary.BigEndian.Uint16(b)), b[2:]
+	case n >= 24:
+		return nil, nil, fmt.Errorf("solc: invalid metadata: unsupported CBOR length")
+	}
+	if n > len(b) {
+		return nil, nil, fmt.Errorf("solc: invalid metadata: truncated CBOR item")
+	}
+	return b[:n], b[n:], nil
+}
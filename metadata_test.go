@@ -0,0 +1,84 @@
+package solc
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBytecodeMetadata(t *testing.T) {
+	for _, test := range []struct {
+		soljson string
+		version []byte
+		hash    func(m *BytecodeMetadata) []byte
+		length  int
+	}{
+		{"./solc-bin/soljson-v0.6.2+commit.bacdbe57.js", []byte{0, 6, 2}, func(m *BytecodeMetadata) []byte { return m.IPFS }, 34},
+		{"./solc-bin/soljson-v0.5.9+commit.e560f70d.js", []byte{0, 5, 9}, func(m *BytecodeMetadata) []byte { return m.Bzzr0 }, 32},
+	} {
+		solc, err := NewFromFile(test.soljson)
+		require.NoError(t, err, "Creating compiler should not error")
+		out, err := CompileSource(solc, "A.sol", "contract A {}")
+		solc.Close()
+		require.NoError(t, err, "Compile should not error")
+
+		code, _ := hex.DecodeString(out.Contracts["A.sol"]["A"].EVM.DeployedBytecode.Object)
+		m, err := ParseBytecodeMetadata(code)
+		require.NoError(t, err, "ParseBytecodeMetadata should not error")
+		require.NotNil(t, m, "Metadata should be found")
+		assert.Len(t, test.hash(m), test.length, "Hash should be decoded")
+		assert.Equal(t, test.version, m.Solc, "Version should be decoded")
+	}
+
+	// {"bzzr1": 32 bytes, "experimental": true, "solc": "0.6.0-nightly"} with its length
+	code, _ := hex.DecodeString("6080" + "a3" + "65627a7a7231" + "5820" + strings.Repeat("ab", 32) + "6c6578706572696d656e74616cf5" + "64736f6c63" + "6d302e362e302d6e696768746c79" + "004a")
+	m, err := ParseBytecodeMetadata(code)
+	require.NoError(t, err, "ParseBytecodeMetadata should not error")
+	assert.Len(t, m.Bzzr1, 32)
+	assert.True(t, m.Experimental, "Experimental flag should be decoded")
+	assert.Equal(t, "0.6.0-nightly", string(m.Solc), "Prerelease version should be decoded")
+
+	m, err = ParseBytecodeMetadata([]byte{0x60, 0x80, 0x00})
+	assert.NoError(t, err, "Code without metadata should not error")
+	assert.Nil(t, m, "Code without metadata should have none")
+}
+
+func TestSolcVersionFromBytecode(t *testing.T) {
+	contracts := make(map[string]*Contract)
+	for _, soljson := range []string{"./solc-bin/soljson-v0.6.2+commit.bacdbe57.js", "./solc-bin/soljson-v0.5.9+commit.e560f70d.js"} {
+		solc, err := NewFromFile(soljson)
+		require.NoError(t, err, "Creating compiler should not error")
+		out, err := solc.Compile(NewInput().AddSource("A.sol", "contract A {}").SetOutputSelection(SelectAll("metadata", "evm.deployedBytecode.object")))
+		version := ShortVersion(solc.Version())
+		solc.Close()
+		require.NoError(t, err, "Compile should not error")
+		c := out.Contracts["A.sol"]["A"]
+		contracts[version] = &c
+
+		v, err := SolcVersionFromBytecode("0x" + c.EVM.DeployedBytecode.Object)
+		require.NoError(t, err, "SolcVersionFromBytecode should not error")
+		assert.Equal(t, version, v)
+
+		v, err = CheckSolcVersion(c.EVM.DeployedBytecode.Object, &c)
+		assert.NoError(t, err, "Versions of bytecode and metadata should match")
+		assert.Equal(t, version, v)
+	}
+
+	v, err := CheckSolcVersion(contracts["0.5.9"].EVM.DeployedBytecode.Object, contracts["0.6.2"])
+	assert.Equal(t, "0.5.9", v)
+	assert.Equal(t, &SolcVersionMismatchError{Bytecode: "0.5.9", Metadata: "0.6.2+commit.bacdbe57"}, err)
+
+	// {"bzzr1": 32 bytes, "solc": "0.6.0-nightly"} with its length, after a library placeholder
+	code := "73" + LibraryPlaceholder("L.sol:L") + "a2" + "65627a7a7231" + "5820" + strings.Repeat("ab", 32) + "64736f6c63" + "6d302e362e302d6e696768746c79" + "003c"
+	v, err = SolcVersionFromBytecode(code)
+	require.NoError(t, err, "Unlinked code should not error")
+	assert.Equal(t, "0.6.0-nightly", v, "Prerelease version should be read")
+	_, err = CheckSolcVersion(code, &Contract{Metadata: `{"compiler":{"version":"0.6.0-nightly+commit.c3f639f4"}}`})
+	assert.NoError(t, err, "Prerelease versions should match their long version")
+
+	_, err = SolcVersionFromBytecode("6080")
+	assert.Equal(t, ErrNoSolcVersion, err)
+}
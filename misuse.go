@@ -0,0 +1,175 @@
+package solc
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// Kinds of MisuseError
+const (
+	// MisuseUseAfterClose is a call on a closed compiler
+	MisuseUseAfterClose = "use-after-close"
+
+	// MisuseCloseDuringCompile is a Close while a compilation is running, which would free the v8 isolate under it
+	MisuseCloseDuringCompile = "close-during-compile"
+
+	// MisuseConcurrentCalls is a call while another one is running on a compiler which is not a Pool
+	MisuseConcurrentCalls = "concurrent-calls"
+)
+
+// MisuseError reports a compiler used in a way that may corrupt or crash it (see DetectMisuse)
+type MisuseError struct {
+	Misuse string
+
+	// Call is the misusing method, Conflict the call it conflicts with and ConflictStack the
+	// stack of the goroutine that made it
+	Call          string
+	Conflict      string
+	ConflictStack []byte
+}
+
+func (e *MisuseError) Error() string {
+	return fmt.Sprintf("solc: %v: %v called during or after %v", e.Misuse, e.Call, e.Conflict)
+}
+
+// DetectMisuse wraps solc so calls racing with one another or with Close fail with a *MisuseError
+// instead of reaching v8, where such misuse crashes the process
+//
+// Instances created by New are meant to be used by one goroutine at a time, concurrent calls are only
+// expected on a Pool. Closing several times is not a misuse. Builds tagged solcdebug panic on misuse
+// with the stacks of both calls, so misuse is caught where it happens in tests
+func DetectMisuse(solc Solc) Solc {
+	_, concurrent := solc.(*Pool)
+	return &misuseSolc{Solc: solc, concurrent: concurrent}
+}
+
+// misuseSolc checks the calls on a compiler (see DetectMisuse)
+type misuseSolc struct {
+	Solc
+	concurrent bool
+
+	mux sync.Mutex
+	// running is the number of calls in progress, the first of which is call, made with stack
+	running int
+	call    string
+	stack   []byte
+
+	closed     bool
+	closeStack []byte
+}
+
+// enter marks the start of call, it must be followed by exit unless it errors
+func (s *misuseSolc) enter(call string) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.closed {
+		return misuse(&MisuseError{Misuse: MisuseUseAfterClose, Call: call, Conflict: "Close", ConflictStack: s.closeStack})
+	}
+	if s.running > 0 && !s.concurrent {
+		return misuse(&MisuseError{Misuse: MisuseConcurrentCalls, Call: call, Conflict: s.call, ConflictStack: s.stack})
+	}
+	if s.running == 0 {
+		s.call, s.stack = call, debug.Stack()
+	}
+	s.running++
+	return nil
+}
+
+func (s *misuseSolc) exit() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.running--
+}
+
+// resume marks a call suspended by exit as running again
+func (s *misuseSolc) resume() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.running++
+}
+
+// misuse returns err, or panics with it and the stacks of both calls in builds tagged solcdebug
+func misuse(err *MisuseError) error {
+	if panicOnMisuse {
+		panic(fmt.Sprintf("%v\n\n%v at:\n%s\n%v at:\n%s", err, err.Call, debug.Stack(), err.Conflict, err.ConflictStack))
+	}
+	return err
+}
+
+func (s *misuseSolc) Compile(input *Input) (*Output, error) {
+	if err := s.enter("Compile"); err != nil {
+		return nil, err
+	}
+	defer s.exit()
+	return s.Solc.Compile(input)
+}
+
+func (s *misuseSolc) CompileContext(ctx context.Context, input *Input) (*Output, error) {
+	if err := s.enter("CompileContext"); err != nil {
+		return nil, err
+	}
+	defer s.exit()
+	return CompileContext(ctx, s.Solc, input)
+}
+
+func (s *misuseSolc) compileStream(ctx context.Context, input *Input, fn ContractFunc) (*Output, error) {
+	if err := s.enter("CompileStream"); err != nil {
+		return nil, err
+	}
+	defer s.exit()
+	// Contracts are streamed once the compilation is over, fn may use the compiler meanwhile
+	return CompileStreamContext(ctx, s.Solc, input, func(file, name string, contract *Contract) error {
+		s.exit()
+		defer s.resume()
+		return fn(file, name, contract)
+	})
+}
+
+func (s *misuseSolc) compileJSON(input []byte) ([]byte, error) {
+	if err := s.enter("CompileJSON"); err != nil {
+		return nil, err
+	}
+	defer s.exit()
+	return CompileJSON(s.Solc, input)
+}
+
+// HeapStatistics reads the v8 heap, it is checked like compilations and returns zero statistics on misuse
+func (s *misuseSolc) HeapStatistics() HeapStatistics {
+	if err := s.enter("HeapStatistics"); err != nil {
+		return HeapStatistics{}
+	}
+	defer s.exit()
+	return s.Solc.HeapStatistics()
+}
+
+func (s *misuseSolc) Healthy() error {
+	return Healthy(s.Solc)
+}
+
+func (s *misuseSolc) IsReady() bool {
+	return IsReady(s.Solc)
+}
+
+func (s *misuseSolc) collectStats(r *statsRecorder) {
+	if c, ok := s.Solc.(statsCollector); ok {
+		c.collectStats(r)
+	}
+}
+
+// Close closes the compiler unless a call is running, which is a misuse
+func (s *misuseSolc) Close() error {
+	s.mux.Lock()
+	if s.running > 0 {
+		defer s.mux.Unlock()
+		return misuse(&MisuseError{Misuse: MisuseCloseDuringCompile, Call: "Close", Conflict: s.call, ConflictStack: s.stack})
+	}
+	if s.closed {
+		s.mux.Unlock()
+		return nil
+	}
+	s.closed, s.closeStack = true, debug.Stack()
+	s.mux.Unlock()
+	return s.Solc.Close()
+}
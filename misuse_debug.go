@@ -0,0 +1,7 @@
+//go:build solcdebug
+// +build solcdebug
+
+package solc
+
+// panicOnMisuse makes misuse detected by DetectMisuse panic, in builds tagged solcdebug
+const panicOnMisuse = true
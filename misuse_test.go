@@ -0,0 +1,71 @@
+package solc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingSolc holds compilations until release is closed
+type blockingSolc struct {
+	fakeSolc
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingSolc) Compile(input *Input) (*Output, error) {
+	s.started <- struct{}{}
+	<-s.release
+	return &Output{}, nil
+}
+
+func TestDetectMisuse(t *testing.T) {
+	blocking := &blockingSolc{started: make(chan struct{}, 1), release: make(chan struct{})}
+	solc := DetectMisuse(blocking)
+
+	done := make(chan error)
+	go func() {
+		_, err := solc.Compile(NewInput())
+		done <- err
+	}()
+	<-blocking.started
+
+	_, err := solc.Compile(NewInput())
+	require.IsType(t, &MisuseError{}, err, "Concurrent compilation should be a misuse")
+	misuseErr := err.(*MisuseError)
+	assert.Equal(t, MisuseConcurrentCalls, misuseErr.Misuse)
+	assert.Equal(t, "Compile", misuseErr.Conflict)
+	assert.Contains(t, string(misuseErr.ConflictStack), "TestDetectMisuse", "Stack of the running compilation should be reported")
+
+	err = solc.Close()
+	require.IsType(t, &MisuseError{}, err, "Close during a compilation should be a misuse")
+	assert.Equal(t, MisuseCloseDuringCompile, err.(*MisuseError).Misuse)
+
+	close(blocking.release)
+	require.NoError(t, <-done, "Running compilation should complete")
+
+	require.NoError(t, solc.Close(), "Close should not error once idle")
+	assert.NoError(t, solc.Close(), "Closing twice should not be a misuse")
+	_, err = CompileJSON(solc, []byte(`{}`))
+	require.IsType(t, &MisuseError{}, err, "Compilation after Close should be a misuse")
+	assert.Equal(t, MisuseUseAfterClose, err.(*MisuseError).Misuse)
+	assert.Equal(t, "CompileJSON", err.(*MisuseError).Call)
+}
+
+func TestDetectMisusePool(t *testing.T) {
+	p, _ := newEchoPool(t, 2, 0)
+	solc := DetectMisuse(p)
+
+	outputs := make(chan error, 4)
+	for i := 0; i < 4; i++ {
+		go func() {
+			_, err := solc.Compile(echoInputs("a")[0])
+			outputs <- err
+		}()
+	}
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, <-outputs, "Concurrent compilations on a pool should not be a misuse")
+	}
+	require.NoError(t, solc.Close())
+}
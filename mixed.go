@@ -0,0 +1,116 @@
+package solc
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// SourceLanguage detects the language of a source from the extension of its name (.sol or .yul),
+// and from its content for other names: Yul objects and blocks start with object or a brace
+func SourceLanguage(name, content string) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".sol":
+		return "Solidity"
+	case ".yul":
+		return "Yul"
+	}
+
+	code := skipComments(content)
+	if strings.HasPrefix(code, "{") || (strings.HasPrefix(code, "object") && strings.HasPrefix(strings.TrimSpace(code[6:]), `"`)) {
+		return "Yul"
+	}
+	return "Solidity"
+}
+
+// skipComments returns content without its leading whitespace and comments
+func skipComments(content string) string {
+	for {
+		content = strings.TrimSpace(content)
+		switch {
+		case strings.HasPrefix(content, "//"):
+			i := strings.IndexByte(content, '\n')
+			if i < 0 {
+				return ""
+			}
+			content = content[i:]
+		case strings.HasPrefix(content, "/*"):
+			i := strings.Index(content[2:], "*/")
+			if i < 0 {
+				return ""
+			}
+			content = content[i+4:]
+		default:
+			return content
+		}
+	}
+}
+
+// CompileMixed compiles an input mixing Solidity and Yul sources, whose language is detected with
+// SourceLanguage (the Language of in is ignored)
+//
+// Solidity sources are compiled together and every Yul source on its own, as solc compiles a single
+// Yul file per input, all with the settings of in. Outputs are merged, errors in the order of
+// Units which list the compilation units the sources come from
+func CompileMixed(solc Solc, in *Input) (*Output, error) {
+	units := mixedUnits(in.Sources)
+	out := &Output{
+		Sources:   make(map[string]SourceOut),
+		Contracts: make(map[string]map[string]Contract),
+		Units:     units,
+	}
+	for _, unit := range units {
+		input := &Input{Language: unit.Language, Sources: make(map[string]SourceIn), Settings: in.Settings}
+		for _, name := range unit.Sources {
+			input.Sources[name] = in.Sources[name]
+		}
+		if unit.Language == "Yul" {
+			// Yul has no imports nor libraries to link by name
+			input.Settings.Remappings = nil
+			input.Settings.Libraries = nil
+		}
+
+		unitOut, err := solc.Compile(input)
+		if err != nil {
+			return nil, err
+		}
+		mergeUnit(out, unitOut)
+	}
+	return out, nil
+}
+
+// mergeUnit adds the output of a compilation unit to out
+func mergeUnit(out, unit *Output) {
+	out.Errors = append(out.Errors, unit.Errors...)
+	out.Console = append(out.Console, unit.Console...)
+	out.Findings = append(out.Findings, unit.Findings...)
+	for name, source := range unit.Sources {
+		out.Sources[name] = source
+	}
+	for file, contracts := range unit.Contracts {
+		out.Contracts[file] = contracts
+	}
+}
+
+// mixedUnits groups sources by compilation unit, the Solidity one first then Yul sources by name
+func mixedUnits(sources map[string]SourceIn) []CompilationUnit {
+	var names []string
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	solidity := CompilationUnit{Language: "Solidity"}
+	var yul []CompilationUnit
+	for _, name := range names {
+		if SourceLanguage(name, sources[name].Content) == "Yul" {
+			yul = append(yul, CompilationUnit{Language: "Yul", Sources: []string{name}})
+		} else {
+			solidity.Sources = append(solidity.Sources, name)
+		}
+	}
+	if len(solidity.Sources) == 0 {
+		return yul
+	}
+	return append([]CompilationUnit{solidity}, yul...)
+}
@@ -0,0 +1,52 @@
+package solc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceLanguage(t *testing.T) {
+	assert.Equal(t, "Yul", SourceLanguage("Echo.yul", "contract A {}"), "Extension should take precedence")
+	assert.Equal(t, "Solidity", SourceLanguage("A.sol", "{}"), "Extension should take precedence")
+	assert.Equal(t, "Yul", SourceLanguage("echo", "// SPDX-License-Identifier: MIT\n/* Echo */ object \"Echo\" { code {} }"))
+	assert.Equal(t, "Yul", SourceLanguage("block", "{ mstore(0, 1) }"))
+	assert.Equal(t, "Solidity", SourceLanguage("A", "// SPDX-License-Identifier: MIT\npragma solidity ^0.6.0;"))
+	assert.Equal(t, "Solidity", SourceLanguage("objects", "contract object {}"))
+}
+
+func TestCompileMixed(t *testing.T) {
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err, "Creating compiler should not error")
+	defer solc.Close()
+
+	in := NewInput().
+		AddSource("A.sol", "pragma solidity ^0.6.0;\nimport \"B.sol\";\ncontract A is B {}").
+		AddSource("B.sol", "pragma solidity ^0.6.0;\ncontract B {}").
+		AddSource("Echo.yul", "object \"Echo\" { code { mstore(0, 1) return(0, 32) } }").
+		AddSource("Block.yul", "{ sstore(0, 1) }")
+	out, err := CompileMixed(solc, in)
+	require.NoError(t, err, "CompileMixed should not error")
+	for _, e := range out.Errors {
+		assert.NotEqual(t, "error", e.Severity, e.Message)
+	}
+
+	assert.Equal(t, []CompilationUnit{
+		{Language: "Solidity", Sources: []string{"A.sol", "B.sol"}},
+		{Language: "Yul", Sources: []string{"Block.yul"}},
+		{Language: "Yul", Sources: []string{"Echo.yul"}},
+	}, out.Units, "Yul sources should be compiled on their own")
+	assert.Equal(t, "Yul", out.Unit("Echo.yul").Language, "Unit should be found")
+	assert.Nil(t, out.Unit("C.sol"), "Unknown files should have no unit")
+
+	assert.Equal(t, "600160005260206000f3", out.Contracts["Echo.yul"]["Echo"].EVM.Bytecode.Object, "Yul object should be compiled")
+	assert.NotEmpty(t, out.Contracts["Block.yul"], "Yul block should be compiled")
+	assert.NotEmpty(t, out.Contracts["A.sol"]["A"].EVM.Bytecode.Object, "Solidity contract should be compiled")
+	assert.Contains(t, out.Sources, "B.sol", "Solidity sources should be merged")
+
+	out, err = CompileMixed(solc, NewInput().AddSource("Echo.yul", "object \"Echo\" { code { mstore(0, x) } }"))
+	require.NoError(t, err, "CompileMixed should not error")
+	require.NotEmpty(t, out.Errors, "Yul errors should be reported")
+	assert.Equal(t, "error", out.Errors[len(out.Errors)-1].Severity)
+}
@@ -0,0 +1,320 @@
+package solc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nmvalera/solc-go/ast"
+)
+
+// MutationOperator is a kind of change Mutate makes to binary operations and compound assignments
+type MutationOperator string
+
+const (
+	// MutationSwap swaps operators for their opposite (+ and -, * and /, < and >, == and !=, && and ||...)
+	MutationSwap MutationOperator = "swap"
+
+	// MutationBoundary moves the boundary of comparisons (< and <=, > and >=)
+	MutationBoundary MutationOperator = "boundary"
+)
+
+// MutationOperators are the operators Mutate applies by default
+var MutationOperators = []MutationOperator{MutationSwap, MutationBoundary}
+
+// mutations map the operators of the code to their replacement, by mutation operator
+var mutations = map[MutationOperator]map[string]string{
+	MutationSwap: {
+		"+": "-", "-": "+", "*": "/", "/": "*", "%": "*", "**": "*",
+		"<": ">", ">": "<", "<=": ">=", ">=": "<=", "==": "!=", "!=": "==",
+		"&&": "||", "||": "&&", "&": "|", "|": "&", "<<": ">>", ">>": "<<",
+		"+=": "-=", "-=": "+=", "*=": "/=", "/=": "*=", "&=": "|=", "|=": "&=",
+	},
+	MutationBoundary: {
+		"<": "<=", "<=": "<", ">": ">=", ">=": ">",
+	},
+}
+
+// Mutant is a single change to the source of an input
+type Mutant struct {
+	ID       int              `json:"id"`
+	Operator MutationOperator `json:"operator"`
+
+	// Original is replaced by Replacement at SourceLocation, Line locates it in the source before mutation
+	Original       string         `json:"original"`
+	Replacement    string         `json:"replacement"`
+	SourceLocation SourceLocation `json:"sourceLocation"`
+	Line           int            `json:"line"`
+}
+
+// Input returns a copy of in whose source is mutated
+func (m *Mutant) Input(in *Input) *Input {
+	mutated := *in
+	mutated.Sources = make(map[string]SourceIn, len(in.Sources))
+	for name, source := range in.Sources {
+		mutated.Sources[name] = source
+	}
+	source := in.Sources[m.SourceLocation.File]
+	source.Content = source.Content[:m.SourceLocation.Start] + m.Replacement + source.Content[m.SourceLocation.End:]
+	source.Keccak256 = ""
+	mutated.Sources[m.SourceLocation.File] = source
+	return &mutated
+}
+
+// Mutate returns the mutants of the binary operations and compound assignments of in, applying operators
+// (MutationOperators if none), located from the ast output of out
+//
+// Mutants are sorted by file then location. Operators separated from their operands by other than
+// whitespace (e.g. comments) are left out
+func Mutate(in *Input, out *Output, operators ...MutationOperator) ([]Mutant, error) {
+	if len(operators) == 0 {
+		operators = MutationOperators
+	}
+	for _, op := range operators {
+		if mutations[op] == nil {
+			return nil, fmt.Errorf("solc: unknown mutation operator %q", op)
+		}
+	}
+
+	var names []string
+	for name := range in.Sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	mutants := []Mutant{}
+	for _, name := range names {
+		content := in.Sources[name].Content
+		if content == "" || len(out.Sources[name].AST) == 0 {
+			continue
+		}
+		unit, err := ast.Parse(out.Sources[name].AST)
+		if err != nil {
+			return nil, fmt.Errorf("solc: could not parse AST of %q: %w", name, err)
+		}
+
+		var nodes []*ast.Node
+		unit.Walk(func(n *ast.Node) bool {
+			if n.NodeType == "BinaryOperation" || n.NodeType == "Assignment" {
+				nodes = append(nodes, n)
+			}
+			return true
+		})
+		var located []Mutant
+		for _, n := range nodes {
+			start, end, ok := operatorLocation(n, content)
+			if !ok {
+				continue
+			}
+			for _, op := range operators {
+				replacement, ok := mutations[op][n.StringAttr("operator")]
+				if !ok {
+					continue
+				}
+				located = append(located, Mutant{
+					Operator:       op,
+					Original:       content[start:end],
+					Replacement:    replacement,
+					SourceLocation: SourceLocation{File: name, Start: start, End: end},
+					Line:           strings.Count(content[:start], "\n") + 1,
+				})
+			}
+		}
+		sort.SliceStable(located, func(a, b int) bool {
+			return located[a].SourceLocation.Start < located[b].SourceLocation.Start
+		})
+		mutants = append(mutants, located...)
+	}
+	for i := range mutants {
+		mutants[i].ID = i
+	}
+	return mutants, nil
+}
+
+// operatorLocation returns the location of the operator of a binary operation or an assignment in content,
+// which must be all that separates its operands but whitespace
+func operatorLocation(n *ast.Node, content string) (int, int, bool) {
+	left, right := n.Child("leftExpression"), n.Child("rightExpression")
+	if n.NodeType == "Assignment" {
+		left, right = n.Child("leftHandSide"), n.Child("rightHandSide")
+	}
+	operator := n.StringAttr("operator")
+	if left == nil || right == nil || operator == "" {
+		return 0, 0, false
+	}
+	from, to := left.Range().Start+left.Range().Length, right.Range().Start
+	if from < 0 || from > to || to > len(content) {
+		return 0, 0, false
+	}
+	between := content[from:to]
+	if strings.TrimSpace(between) != operator {
+		return 0, 0, false
+	}
+	start := from + strings.Index(between, operator)
+	return start, start + len(operator), true
+}
+
+// MutantStatus is the outcome of the compilation of a mutant
+type MutantStatus string
+
+const (
+	// MutantChanged mutants change the bytecode of some contracts
+	MutantChanged MutantStatus = "changed"
+
+	// MutantEquivalent mutants compile to the bytecode of the original input (e.g. in unused code)
+	MutantEquivalent MutantStatus = "equivalent"
+
+	// MutantInvalid mutants do not compile
+	MutantInvalid MutantStatus = "invalid"
+)
+
+// MutantResult is the outcome of the compilation of a mutant
+type MutantResult struct {
+	Mutant
+	Status MutantStatus `json:"status"`
+
+	// Contracts are the IDs of the contracts whose creation or deployed bytecode differs
+	Contracts []string `json:"contracts,omitempty"`
+
+	// Error is the first compilation error of invalid mutants
+	Error string `json:"error,omitempty"`
+}
+
+// MutationReport reports the compilation of the mutants of an input
+type MutationReport struct {
+	Results []MutantResult `json:"results"`
+}
+
+// Count returns the number of mutants of status
+func (r *MutationReport) Count(status MutantStatus) int {
+	n := 0
+	for _, result := range r.Results {
+		if result.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Changed returns the results of the mutants changing bytecode, those a test suite should catch
+func (r *MutationReport) Changed() []MutantResult {
+	var changed []MutantResult
+	for _, result := range r.Results {
+		if result.Status == MutantChanged {
+			changed = append(changed, result)
+		}
+	}
+	return changed
+}
+
+// MutationTest mutates in (see Mutate) and compiles its mutants in parallel across the instances of the
+// pool, reporting which change the bytecode of the original input. It errors if in does not compile
+//
+// Inputs are compiled without the metadata hash, which changes with any change of the sources, so it
+// requires solc >= 0.6.0
+func (p *Pool) MutationTest(ctx context.Context, in *Input, operators ...MutationOperator) (*MutationReport, error) {
+	metadata, err := mutationMetadata(p.Version(), in.Settings.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	original := *in
+	original.Settings.Metadata = metadata
+	original.Settings.OutputSelection = OutputSelection{}.
+		Select("*", "", "ast").
+		Select("*", "*", "evm.bytecode.object", "evm.deployedBytecode.object")
+	out, err := p.CompileContext(ctx, &original)
+	if err != nil {
+		return nil, err
+	}
+	if e := firstError(out); e != "" {
+		return nil, fmt.Errorf("solc: compiling for mutation failed: %v", e)
+	}
+
+	mutants, err := Mutate(in, out, operators...)
+	if err != nil {
+		return nil, err
+	}
+	original.Settings.OutputSelection = OutputSelection{}.Select("*", "*", "evm.bytecode.object", "evm.deployedBytecode.object")
+	inputs := make([]*Input, len(mutants))
+	for i := range mutants {
+		inputs[i] = mutants[i].Input(&original)
+	}
+	outputs, err := p.CompileAll(ctx, inputs, 0)
+	batch, _ := err.(*BatchError)
+	if err != nil && batch == nil {
+		return nil, err
+	}
+
+	report := &MutationReport{Results: make([]MutantResult, len(mutants))}
+	for i, mutant := range mutants {
+		result := MutantResult{Mutant: mutant, Status: MutantEquivalent}
+		switch {
+		case batch != nil && batch.Errors[i] != nil:
+			result.Status, result.Error = MutantInvalid, batch.Errors[i].Error()
+		case firstError(outputs[i]) != "":
+			result.Status, result.Error = MutantInvalid, firstError(outputs[i])
+		default:
+			result.Contracts = changedContracts(out, outputs[i])
+			if len(result.Contracts) > 0 {
+				result.Status = MutantChanged
+			}
+		}
+		report.Results[i] = result
+	}
+	return report, nil
+}
+
+// mutationMetadata returns a copy of metadata leaving the metadata hash out of the bytecode compiled by
+// version, so that bytecode embedded in other contracts (e.g. by new) does not change with the sources
+func mutationMetadata(version string, metadata *MetadataSettings) (*MetadataSettings, error) {
+	settings := MetadataSettings{}
+	if metadata != nil {
+		settings = *metadata
+	}
+	short := ShortVersion(version)
+	if ok, err := MatchVersion(">=0.8.18", short); err != nil {
+		return nil, err
+	} else if ok {
+		appendCBOR := false
+		settings.AppendCBOR = &appendCBOR
+		return &settings, nil
+	}
+	if ok, _ := MatchVersion(">=0.6.0", short); !ok {
+		return nil, fmt.Errorf("solc: mutation testing requires solc >= 0.6.0 to leave metadata out, got %v", version)
+	}
+	settings.BytecodeHash = "none"
+	return &settings, nil
+}
+
+// firstError returns the message of the first error of out, empty if none
+func firstError(out *Output) string {
+	for _, e := range out.Errors {
+		if e.Severity == "error" {
+			return e.Message
+		}
+	}
+	return ""
+}
+
+// changedContracts returns the IDs of the contracts of out whose bytecode differs in mutated
+func changedContracts(out, mutated *Output) []string {
+	var changed []string
+	for _, id := range out.ContractIDs() {
+		before := out.Contracts[id.File][id.Name]
+		after, ok := mutated.Contracts[id.File][id.Name]
+		if !ok {
+			changed = append(changed, id.String())
+			continue
+		}
+		for _, codes := range [][2]string{
+			{before.EVM.Bytecode.Object, after.EVM.Bytecode.Object},
+			{before.EVM.DeployedBytecode.Object, after.EVM.DeployedBytecode.Object},
+		} {
+			if codes[0] != codes[1] {
+				changed = append(changed, id.String())
+				break
+			}
+		}
+	}
+	return changed
+}
@@ -0,0 +1,105 @@
+package solc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mutationSource = `pragma solidity ^0.6.0;
+contract A {
+    uint8 constant C = 255 - 1;
+    uint x;
+    function f(uint a) public returns (uint) {
+        if (a < 10) x += a;
+        return a * C;
+    }
+    function g(uint a) internal pure returns (bool) { return a >= 1 /* one */ && a != 3; }
+}
+`
+
+func TestMutate(t *testing.T) {
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err, "Creating Solc from valid solc emscripten binary should not error")
+	defer solc.Close()
+
+	in := NewInput().AddSource("A.sol", mutationSource)
+	out, err := solc.Compile(astInput(in))
+	require.NoError(t, err, "Compile should not error")
+
+	mutants, err := Mutate(in, out)
+	require.NoError(t, err, "Mutate should not error")
+	var changes []string
+	for i, mutant := range mutants {
+		assert.Equal(t, i, mutant.ID)
+		assert.Equal(t, mutant.Original, mutationSource[mutant.SourceLocation.Start:mutant.SourceLocation.End])
+		changes = append(changes, mutant.Original+" "+mutant.Replacement)
+	}
+	assert.Equal(t, []string{"- +", "< >", "< <=", "+= -=", "* /", ">= <=", ">= >", "!= =="}, changes, "Operators separated by comments should be left out")
+	assert.Equal(t, Mutant{ID: 2, Operator: MutationBoundary, Original: "<", Replacement: "<=", SourceLocation: SourceLocation{File: "A.sol", Start: 142, End: 143}, Line: 6}, mutants[2])
+	assert.Contains(t, mutants[2].Input(in).Sources["A.sol"].Content, "if (a <= 10) x += a;")
+	assert.Equal(t, mutationSource, in.Sources["A.sol"].Content, "Input should not be mutated")
+
+	mutants, err = Mutate(in, out, MutationBoundary)
+	require.NoError(t, err, "Mutate should not error")
+	assert.Len(t, mutants, 2)
+
+	_, err = Mutate(in, out, "unknown")
+	assert.Error(t, err, "Unknown operators should error")
+}
+
+func TestPoolMutationTest(t *testing.T) {
+	p, err := NewPool(func() (Solc, error) {
+		return NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	}, 2)
+	require.NoError(t, err, "NewPool should not error")
+	defer p.Close()
+
+	report, err := p.MutationTest(context.Background(), NewInput().AddSource("A.sol", mutationSource))
+	require.NoError(t, err, "MutationTest should not error")
+	require.Len(t, report.Results, 8)
+	assert.Equal(t, MutantInvalid, report.Results[0].Status, "Constants out of range should not compile")
+	assert.Contains(t, report.Results[0].Error, "not implicitly convertible")
+	for _, result := range report.Results[1:5] {
+		assert.Equal(t, MutantChanged, result.Status)
+		assert.Equal(t, []string{"A.sol:A"}, result.Contracts)
+	}
+	for _, result := range report.Results[5:] {
+		assert.Equal(t, MutantEquivalent, result.Status, "Unused functions should not change bytecode")
+	}
+	assert.Equal(t, 1, report.Count(MutantInvalid))
+	assert.Len(t, report.Changed(), 4)
+
+	_, err = p.MutationTest(context.Background(), NewInput().AddSource("B.sol", "contract B {"))
+	assert.Error(t, err, "Inputs not compiling should error")
+
+	factory := `pragma solidity ^0.6.0;
+contract Child {
+    function f(uint a) internal pure returns (uint) { return a + 1; }
+}
+contract Factory {
+    function deploy() public returns (Child) { return new Child(); }
+}
+`
+	report, err = p.MutationTest(context.Background(), NewInput().AddSource("F.sol", factory))
+	require.NoError(t, err, "MutationTest should not error")
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, MutantEquivalent, report.Results[0].Status, "Factories should not change with the metadata of the contracts they deploy")
+}
+
+func TestMutationMetadata(t *testing.T) {
+	metadata, err := mutationMetadata("0.6.2+commit.bacdbe57.Emscripten.clang", &MetadataSettings{UseLiteralContent: true})
+	require.NoError(t, err, "mutationMetadata should not error")
+	assert.Equal(t, &MetadataSettings{UseLiteralContent: true, BytecodeHash: "none"}, metadata)
+
+	metadata, err = mutationMetadata("0.8.20+commit.a1b79de6", nil)
+	require.NoError(t, err, "mutationMetadata should not error")
+	require.NotNil(t, metadata.AppendCBOR)
+	assert.False(t, *metadata.AppendCBOR)
+	assert.Empty(t, metadata.BytecodeHash)
+
+	_, err = mutationMetadata("0.5.9+commit.e560f70d.Emscripten.clang", nil)
+	assert.Error(t, err, "Compilers not leaving metadata out should error")
+}
@@ -0,0 +1,370 @@
+// Package npm resolves imports of npm packages (e.g. @openzeppelin/contracts) from their release tarballs,
+// fetched from the npm registry and cached on disk, so dependencies resolve without node nor npm
+package npm
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha1"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	solc "github.com/nmvalera/solc-go"
+)
+
+// DefaultRegistryURL is the npm registry packages are fetched from unless set otherwise
+const DefaultRegistryURL = "https://registry.npmjs.org"
+
+// OpenZeppelin packages
+const (
+	OpenZeppelin            = "@openzeppelin/contracts"
+	OpenZeppelinUpgradeable = "@openzeppelin/contracts-upgradeable"
+)
+
+// Package is an npm package at a version, exact (4.9.3) or a range (^4.9.0) resolved to its highest release
+type Package struct {
+	Name    string
+	Version string
+}
+
+// Resolver is a solc.ImportResolver loading the files of imports starting with the name of one of its
+// packages (e.g. @openzeppelin/contracts/token/ERC20/ERC20.sol) from the package, fetching it on first use
+//
+// Packages are extracted into <Dir>/<name>@<version> and reused from there, version ranges are satisfied
+// by cached versions before querying the registry. Resolver is safe for concurrent use
+type Resolver struct {
+	// Dir caches the extracted packages
+	Dir string
+
+	// RegistryURL defaults to DefaultRegistryURL
+	RegistryURL string
+
+	// HTTPClient defaults to http.DefaultClient
+	HTTPClient *http.Client
+
+	packages []Package
+
+	mux sync.Mutex
+	// dirs holds the directory of the packages fetched, by name
+	dirs map[string]string
+}
+
+// NewResolver creates a resolver of packages caching them in dir
+func NewResolver(dir string, packages ...Package) *Resolver {
+	return &Resolver{Dir: dir, packages: packages, dirs: make(map[string]string)}
+}
+
+// NewOpenZeppelinResolver creates a resolver of @openzeppelin/contracts and @openzeppelin/contracts-upgradeable
+// at version (e.g. 4.9.3 or ^5.0.0) caching them in dir
+func NewOpenZeppelinResolver(dir, version string) *Resolver {
+	return NewResolver(dir, Package{Name: OpenZeppelin, Version: version}, Package{Name: OpenZeppelinUpgradeable, Version: version})
+}
+
+// Resolve returns the content of the file of an import of one of the packages
+func (r *Resolver) Resolve(importPath string) (string, error) {
+	var pkg *Package
+	for i, p := range r.packages {
+		if strings.HasPrefix(importPath, p.Name+"/") && (pkg == nil || len(p.Name) > len(pkg.Name)) {
+			pkg = &r.packages[i]
+		}
+	}
+	if pkg == nil {
+		return "", fmt.Errorf("source %q not found in npm packages", importPath)
+	}
+
+	dir, err := r.Fetch(context.Background(), *pkg)
+	if err != nil {
+		return "", err
+	}
+	rel := path.Clean(strings.TrimPrefix(importPath, pkg.Name+"/"))
+	if strings.HasPrefix(rel, "../") {
+		return "", fmt.Errorf("source %q not found in %v", importPath, pkg.Name)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("source %q not found in %v", importPath, pkg.Name)
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// Fetch downloads and extracts pkg into the cache if missing, and returns its directory
+func (r *Resolver) Fetch(ctx context.Context, pkg Package) (string, error) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if dir, ok := r.dirs[pkg.Name+"@"+pkg.Version]; ok {
+		return dir, nil
+	}
+
+	version := pkg.Version
+	var rel *release
+	if !isExact(version) {
+		var err error
+		version, err = r.cachedVersion(pkg)
+		if err != nil {
+			return "", err
+		}
+		if version == "" {
+			rel, err = r.resolveRelease(ctx, pkg)
+			if err != nil {
+				return "", err
+			}
+			version = rel.Version
+		}
+	}
+
+	dir := filepath.Join(r.Dir, filepath.FromSlash(pkg.Name)+"@"+version)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if rel == nil {
+			rel = &release{}
+			err = r.get(ctx, r.registryURL()+"/"+escapeName(pkg.Name)+"/"+version, rel)
+			if err != nil {
+				return "", err
+			}
+		}
+		err = r.extract(ctx, pkg.Name, rel, dir)
+		if err != nil {
+			return "", err
+		}
+	} else if err != nil {
+		return "", err
+	}
+
+	r.dirs[pkg.Name+"@"+pkg.Version] = dir
+	return dir, nil
+}
+
+// release is the registry document of a package version
+type release struct {
+	Version string `json:"version"`
+	Dist    struct {
+		Tarball   string `json:"tarball"`
+		Shasum    string `json:"shasum"`
+		Integrity string `json:"integrity"`
+	} `json:"dist"`
+}
+
+// isExact indicates whether version is a single version rather than a range
+func isExact(version string) bool {
+	return version != "" && strings.Trim(version, "0123456789.") == ""
+}
+
+// stable returns the versions without prerelease tags, which version constraints do not parse
+func stable(versions []string) []string {
+	var result []string
+	for _, v := range versions {
+		if isExact(v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// cachedVersion returns the highest version of pkg in the cache satisfying its range, "" if there is none
+func (r *Resolver) cachedVersion(pkg Package) (string, error) {
+	entries, err := ioutil.ReadDir(filepath.Dir(filepath.Join(r.Dir, filepath.FromSlash(pkg.Name))))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	prefix := path.Base(pkg.Name) + "@"
+	var versions []string
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), prefix) {
+			versions = append(versions, strings.TrimPrefix(entry.Name(), prefix))
+		}
+	}
+	version, err := solc.ResolveVersion(rangeConstraint(pkg.Version), stable(versions))
+	if err != nil {
+		return "", nil
+	}
+	return version, nil
+}
+
+// resolveRelease returns the highest release of pkg satisfying its range
+func (r *Resolver) resolveRelease(ctx context.Context, pkg Package) (*release, error) {
+	var doc struct {
+		Versions map[string]*release `json:"versions"`
+	}
+	err := r.get(ctx, r.registryURL()+"/"+escapeName(pkg.Name), &doc)
+	if err != nil {
+		return nil, err
+	}
+	var versions []string
+	for v := range doc.Versions {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	version, err := solc.ResolveVersion(rangeConstraint(pkg.Version), stable(versions))
+	if err != nil {
+		return nil, fmt.Errorf("npm: no release of %v satisfies %v", pkg.Name, pkg.Version)
+	}
+	return doc.Versions[version], nil
+}
+
+// rangeConstraint returns the version constraint of an npm range, latest and empty ranges match every version
+func rangeConstraint(version string) string {
+	if version == "" || version == "latest" || version == "*" {
+		return ">=0.0.0"
+	}
+	return version
+}
+
+// extract downloads the tarball of rel, checks its integrity and extracts its package directory into dir
+func (r *Resolver) extract(ctx context.Context, name string, rel *release, dir string) error {
+	body, err := r.fetch(ctx, rel.Dist.Tarball)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	// Extract to a temporary directory first so an interrupted download never looks like a valid package
+	err = os.MkdirAll(filepath.Dir(dir), 0755)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempDir(filepath.Dir(dir), ".npm-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	var h hash.Hash
+	var expected string
+	switch {
+	case strings.HasPrefix(rel.Dist.Integrity, "sha512-"):
+		h, expected = sha512.New(), rel.Dist.Integrity
+	case rel.Dist.Shasum != "":
+		h, expected = sha1.New(), rel.Dist.Shasum
+	default:
+		return fmt.Errorf("npm: no checksum for %v@%v", name, rel.Version)
+	}
+
+	err = untar(io.TeeReader(body, h), tmp)
+	if err != nil {
+		return fmt.Errorf("npm: extracting %v@%v: %v", name, rel.Version, err)
+	}
+	// Read what the archive does not hold so the whole tarball is hashed
+	_, err = io.Copy(ioutil.Discard, io.TeeReader(body, h))
+	if err != nil {
+		return err
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+	if strings.HasPrefix(expected, "sha512-") {
+		sum = "sha512-" + base64.StdEncoding.EncodeToString(h.Sum(nil))
+	}
+	if sum != expected {
+		return fmt.Errorf("npm: checksum mismatch for %v@%v: expected %v, got %v", name, rel.Version, expected, sum)
+	}
+
+	return os.Rename(tmp, dir)
+}
+
+// untar extracts the regular files of a gzipped tarball into dir, without their first directory (package/)
+func untar(r io.Reader, dir string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(header.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return fmt.Errorf("invalid path %q", header.Name)
+		}
+		i := strings.Index(name, "/")
+		if i < 0 {
+			continue
+		}
+		name = name[i+1:]
+
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		err = os.MkdirAll(filepath.Dir(file), 0755)
+		if err != nil {
+			return err
+		}
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(f, tr)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (r *Resolver) registryURL() string {
+	if r.RegistryURL == "" {
+		return DefaultRegistryURL
+	}
+	return strings.TrimSuffix(r.RegistryURL, "/")
+}
+
+// escapeName escapes the slash of scoped package names, as registry URLs expect
+func escapeName(name string) string {
+	return strings.Replace(name, "/", "%2F", 1)
+}
+
+func (r *Resolver) get(ctx context.Context, url string, v interface{}) error {
+	body, err := r.fetch(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return json.NewDecoder(body).Decode(v)
+}
+
+func (r *Resolver) fetch(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	client := r.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, fmt.Errorf("npm: fetching %v: %v", url, res.Status)
+	}
+	return res.Body, nil
+}
@@ -0,0 +1,184 @@
+package npm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	solc "github.com/nmvalera/solc-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ solc.ImportResolver = &Resolver{}
+
+func tarball(t *testing.T, files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		require.NoError(t, err, "WriteHeader should not error")
+		_, err = tw.Write([]byte(content))
+		require.NoError(t, err, "Write should not error")
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+// registry serves releases of a package from tarballs by version, counting requests
+type registry struct {
+	*httptest.Server
+	name     string
+	tarballs map[string][]byte
+	shasums  map[string]string
+
+	mux      sync.Mutex
+	requests []string
+}
+
+func newRegistry(t *testing.T, name string, tarballs map[string][]byte) *registry {
+	r := &registry{name: name, tarballs: tarballs, shasums: make(map[string]string)}
+	for version, b := range tarballs {
+		sum := sha1.Sum(b)
+		r.shasums[version] = hex.EncodeToString(sum[:])
+	}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.mux.Lock()
+		r.requests = append(r.requests, req.URL.EscapedPath())
+		r.mux.Unlock()
+
+		escaped := "/" + strings.Replace(name, "/", "%2F", 1)
+		path := req.URL.EscapedPath()
+		switch {
+		case strings.HasPrefix(path, "/tarballs/"):
+			b, ok := tarballs[strings.TrimPrefix(path, "/tarballs/")]
+			if !ok {
+				http.NotFound(w, req)
+				return
+			}
+			w.Write(b)
+		case path == escaped:
+			versions := make(map[string]interface{})
+			for version := range tarballs {
+				versions[version] = r.release(version)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"versions": versions})
+		case strings.HasPrefix(path, escaped+"/"):
+			version := strings.TrimPrefix(path, escaped+"/")
+			if _, ok := tarballs[version]; !ok {
+				http.NotFound(w, req)
+				return
+			}
+			json.NewEncoder(w).Encode(r.release(version))
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	return r
+}
+
+func (r *registry) release(version string) map[string]interface{} {
+	return map[string]interface{}{
+		"version": version,
+		"dist":    map[string]string{"tarball": r.URL + "/tarballs/" + version, "shasum": r.shasums[version]},
+	}
+}
+
+func (r *registry) count() int {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return len(r.requests)
+}
+
+func TestResolver(t *testing.T) {
+	reg := newRegistry(t, OpenZeppelin, map[string][]byte{
+		"4.8.0":      tarball(t, map[string]string{"package/token/ERC20/ERC20.sol": "// 4.8.0"}),
+		"4.9.3":      tarball(t, map[string]string{"package/token/ERC20/ERC20.sol": "// 4.9.3", "package/package.json": "{}"}),
+		"5.0.0":      tarball(t, map[string]string{"package/token/ERC20/ERC20.sol": "// 5.0.0"}),
+		"5.1.0-rc.0": tarball(t, map[string]string{"package/token/ERC20/ERC20.sol": "// 5.1.0-rc.0"}),
+	})
+	defer reg.Close()
+	dir, err := ioutil.TempDir("", "npm")
+	require.NoError(t, err, "MkdirTemp should not error")
+	defer os.RemoveAll(dir)
+
+	r := NewResolver(dir, Package{Name: OpenZeppelin, Version: "^4.8.0"})
+	r.RegistryURL = reg.URL
+	content, err := r.Resolve("@openzeppelin/contracts/token/ERC20/ERC20.sol")
+	require.NoError(t, err, "Resolve should not error")
+	assert.Equal(t, "// 4.9.3", content, "Highest release in range should be used")
+	assert.FileExists(t, filepath.Join(dir, "@openzeppelin", "contracts@4.9.3", "package.json"), "Package should be cached")
+
+	requests := reg.count()
+	_, err = r.Resolve("@openzeppelin/contracts/token/ERC20/ERC20.sol")
+	require.NoError(t, err, "Resolve should not error")
+	_, err = r.Resolve("@openzeppelin/contracts/token/ERC20/Missing.sol")
+	assert.Error(t, err, "Missing file should error")
+	_, err = r.Resolve("@openzeppelin/contracts/../../escape.sol")
+	assert.Error(t, err, "Paths out of the package should error")
+	_, err = r.Resolve("lib/A.sol")
+	assert.Error(t, err, "Other imports should not be resolved")
+	assert.Equal(t, requests, reg.count(), "Fetched package should be reused")
+
+	// A new resolver reuses the cache without querying the registry
+	r = NewResolver(dir, Package{Name: OpenZeppelin, Version: "^4.0.0"})
+	r.RegistryURL = reg.URL
+	content, err = r.Resolve("@openzeppelin/contracts/token/ERC20/ERC20.sol")
+	require.NoError(t, err, "Resolve should not error")
+	assert.Equal(t, "// 4.9.3", content)
+	assert.Equal(t, requests, reg.count(), "Cached version satisfying the range should be used")
+
+	r = NewOpenZeppelinResolver(dir, "5.0.0")
+	r.RegistryURL = reg.URL
+	content, err = r.Resolve("@openzeppelin/contracts/token/ERC20/ERC20.sol")
+	require.NoError(t, err, "Resolve should not error")
+	assert.Equal(t, "// 5.0.0", content, "Exact version should be used")
+
+	r = NewResolver(dir, Package{Name: OpenZeppelin, Version: "latest"})
+	r.RegistryURL = reg.URL
+	pkgDir, err := r.Fetch(context.Background(), Package{Name: OpenZeppelin, Version: "^6.0.0"})
+	assert.Error(t, err, "Unsatisfiable range should error")
+	assert.Empty(t, pkgDir)
+}
+
+func TestResolverChecksum(t *testing.T) {
+	reg := newRegistry(t, OpenZeppelin, map[string][]byte{
+		"4.9.3": tarball(t, map[string]string{"package/token/ERC20/ERC20.sol": "// 4.9.3"}),
+	})
+	defer reg.Close()
+	reg.shasums["4.9.3"] = strings.Repeat("0", 40)
+	dir, err := ioutil.TempDir("", "npm")
+	require.NoError(t, err, "MkdirTemp should not error")
+	defer os.RemoveAll(dir)
+
+	r := NewResolver(dir, Package{Name: OpenZeppelin, Version: "4.9.3"})
+	r.RegistryURL = reg.URL
+	_, err = r.Resolve("@openzeppelin/contracts/token/ERC20/ERC20.sol")
+	require.Error(t, err, "Corrupted tarball should error")
+	assert.Contains(t, err.Error(), "checksum mismatch")
+	entries, _ := ioutil.ReadDir(filepath.Join(dir, "@openzeppelin"))
+	assert.Empty(t, entries, "Corrupted tarball should not be cached")
+}
+
+func TestUntar(t *testing.T) {
+	dir, err := ioutil.TempDir("", "npm")
+	require.NoError(t, err, "MkdirTemp should not error")
+	defer os.RemoveAll(dir)
+
+	err = untar(bytes.NewReader(tarball(t, map[string]string{"package/../../escape.sol": "// escape"})), dir)
+	assert.Error(t, err, "Paths out of the package should error")
+}
@@ -0,0 +1,244 @@
+package solc
+
+import (
+	"net/http"
+	"path/filepath"
+	"time"
+)
+
+// Option configures a Solc instance
+type Option func(*options) error
+
+type options struct {
+	logger       Logger
+	tracer       Tracer
+	httpClient   *http.Client
+	apiKeyHeader string
+	apiKey       string
+	timeout      time.Duration
+	cache        Cache
+	memoryLimit  uint64
+	finalizer    bool
+
+	maxInputSize  int
+	maxOutputSize int
+	outputBudget  int
+
+	validate bool
+	strict   bool
+	console  bool
+	reset    bool
+	profile  bool
+	coverage bool
+
+	analyzers []Analyzer
+}
+
+// Logger receives diagnostic messages (*log.Logger implements it)
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+func newOptions(opts ...Option) (*options, error) {
+	o := &options{reset: true}
+	for _, opt := range opts {
+		err := opt(o)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+func (o *options) logf(format string, v ...interface{}) {
+	if o.logger != nil {
+		o.logger.Printf(format, v...)
+	}
+}
+
+// WithLogger sets a logger receiving compiler lifecycle events (see EventLogger)
+func WithLogger(logger Logger) Option {
+	return func(o *options) error {
+		o.logger = logger
+		return nil
+	}
+}
+
+// WithTracer sets a tracer receiving spans for initialization and compilations
+func WithTracer(tracer Tracer) Option {
+	return func(o *options) error {
+		o.tracer = tracer
+		return nil
+	}
+}
+
+// WithTimeout bounds the duration of a single compilation
+//
+// A compilation exceeding the timeout is terminated and Compile returns ErrTimeout.
+// Terminating leaves the compiler unusable so later calls return ErrTerminated and the instance should be replaced
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) error {
+		o.timeout = timeout
+		return nil
+	}
+}
+
+// WithCacheDir persists compilation outputs under dir so identical inputs are not recompiled
+func WithCacheDir(dir string) Option {
+	return func(o *options) error {
+		cache, err := NewBuildCache(filepath.Join(dir, "builds"))
+		if err != nil {
+			return err
+		}
+		o.cache = cache
+		return nil
+	}
+}
+
+// WithCache serves compilations of identical inputs from cache
+func WithCache(cache Cache) Option {
+	return func(o *options) error {
+		o.cache = cache
+		return nil
+	}
+}
+
+// WithMemoryLimit sets a memory budget in bytes for the compiler (see HeapStatistics.Usage)
+//
+// v8 can not shrink the emscripten memory, so once the budget is exceeded every Compile
+// returns a *MemoryLimitError and the instance should be replaced
+func WithMemoryLimit(limit uint64) Option {
+	return func(o *options) error {
+		o.memoryLimit = limit
+		return nil
+	}
+}
+
+// WithFinalizer closes the compiler when it is garbage collected if Close has not been called
+//
+// Relying on it delays releasing the isolate memory to an unpredictable time, prefer calling Close
+func WithFinalizer() Option {
+	return func(o *options) error {
+		o.finalizer = true
+		return nil
+	}
+}
+
+// WithMaxInputSize caps the size in bytes of the standard JSON input pushed into the compiler
+func WithMaxInputSize(size int) Option {
+	return func(o *options) error {
+		o.maxInputSize = size
+		return nil
+	}
+}
+
+// WithMaxOutputSize caps the size of the standard JSON output accepted back from the compiler
+//
+// The size is checked inside v8 (in UTF-16 code units) before the output is copied into Go memory
+func WithMaxOutputSize(size int) Option {
+	return func(o *options) error {
+		o.maxOutputSize = size
+		return nil
+	}
+}
+
+// WithOutputBudget estimates the output of inputs before compiling them (see EstimateOutput) and emits an
+// EventOutputBudget when the estimate exceeds budget bytes, or with WithStrictSettings returns an *OutputBudgetError
+//
+// Unlike WithMaxOutputSize it catches output selections too wide before the compiler spends time and
+// memory producing them
+func WithOutputBudget(budget int) Option {
+	return func(o *options) error {
+		o.outputBudget = budget
+		return nil
+	}
+}
+
+// WithValidation validates inputs before compiling them, returning an *InputError instead of solc's output errors (see Input.Validate)
+func WithValidation() Option {
+	return func(o *options) error {
+		o.validate = true
+		return nil
+	}
+}
+
+// WithStrictSettings rejects inputs with settings the compiler version ignores silently (e.g. optimizer
+// details before 0.5.5), returning an *UnsupportedSettingsError since ignoring them changes the bytecode
+func WithStrictSettings() Option {
+	return func(o *options) error {
+		o.strict = true
+		return nil
+	}
+}
+
+// WithMemoryReset sets whether solidity_reset is called after each compilation to free the
+// allocations of solc (enabled by default, ignored by versions before 0.6.0 not exporting it)
+//
+// Resetting bounds the memory growth of long running instances without recreating their isolate
+func WithMemoryReset(enabled bool) Option {
+	return func(o *options) error {
+		o.reset = enabled
+		return nil
+	}
+}
+
+// WithConsoleCapture attaches the lines printed by the compiler during a compilation to Output.Console
+//
+// Lines are captured from the JS console and the emscripten Module print and printErr,
+// they are emitted as EventConsole to the logger whether or not the option is set
+func WithConsoleCapture() Option {
+	return func(o *options) error {
+		o.console = true
+		return nil
+	}
+}
+
+// WithProfiling attaches the breakdown of the duration of each compilation to Output.Profile, see ProfileCompile
+// to time the steps of the compiler as well
+//
+// Outputs served from cache (see WithCache) keep the profile of the compilation that produced them
+func WithProfiling() Option {
+	return func(o *options) error {
+		o.profile = true
+		return nil
+	}
+}
+
+// WithCoverage compiles inputs instrumented for coverage and attaches the map of their markers to
+// Output.Coverage, see Instrument
+//
+// Each compilation first compiles the AST of the input, inputs which do not compile are compiled as is
+// for their errors, without coverage
+func WithCoverage() Option {
+	return func(o *options) error {
+		o.coverage = true
+		return nil
+	}
+}
+
+// WithAnalyzers runs analyzers after each compilation, their findings are attached to Output.Findings
+//
+// The ast output is selected for every source if the input does not select it
+func WithAnalyzers(analyzers ...Analyzer) Option {
+	return func(o *options) error {
+		o.analyzers = append(o.analyzers, analyzers...)
+		return nil
+	}
+}
+
+// WithHTTPClient sets the client remote compilers send requests with (see NewRemote)
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) error {
+		o.httpClient = client
+		return nil
+	}
+}
+
+// WithAPIKey sets the API key remote compilers authenticate with, sent in header (X-API-Key if empty,
+// see server.APIKey)
+func WithAPIKey(header, key string) Option {
+	return func(o *options) error {
+		o.apiKeyHeader, o.apiKey = header, key
+		return nil
+	}
+}
@@ -0,0 +1,9 @@
+package solc
+
+// NormalizeResolver resolves normalized imports (see NormalizeSourceName) with resolver, so imports written
+// with backslashes (lib\token\ERC20.sol) are remapped and found like their slash separated equivalent
+func NormalizeResolver(resolver ImportResolver) ImportResolver {
+	return ImportResolverFunc(func(path string) (string, error) {
+		return resolver.Resolve(NormalizeSourceName(path))
+	})
+}
@@ -0,0 +1,39 @@
+package solc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeResolver(t *testing.T) {
+	resolver := NormalizeResolver(RemappingResolver(
+		[]Remapping{{Prefix: "@oz/", Target: "lib/oz/"}},
+		SourcesResolver{"lib/oz/token/ERC20.sol": SourceIn{Content: "erc20"}},
+	))
+	content, err := resolver.Resolve(`@oz\token\ERC20.sol`)
+	require.NoError(t, err, "Resolve should not error")
+	assert.Equal(t, "erc20", content, "Backslashed imports should be remapped")
+}
+
+func TestArtifactWriteFileSourceName(t *testing.T) {
+	dir, err := ioutil.TempDir("", "solc-artifacts")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file, err := (&Artifact{Format: ArtifactFormat, ContractName: "A", SourceName: `src\A.sol`}).WriteFile(dir)
+	require.NoError(t, err, "WriteFile should not error")
+	assert.Equal(t, filepath.Join(dir, "src", "A.sol", "A.json"), file, "Artifacts should be laid out alike on every platform")
+
+	for _, name := range []string{`src\A.sol`, "/abs/A.sol", "./src//A.sol", "lib/../A.sol"} {
+		a := &Artifact{Format: ArtifactFormat, ContractName: "A", SourceName: name}
+		file, err := a.WriteFile(dir)
+		require.NoError(t, err, "WriteFile should not error")
+		assert.Equal(t, filepath.Join(dir, filepath.FromSlash(a.Path())), file, "Path of %v should be where the artifact is written", name)
+	}
+	assert.Equal(t, "abs/A.sol/A.json", (&Artifact{ContractName: "A", SourceName: "/abs/A.sol"}).Path())
+}
@@ -0,0 +1,132 @@
+package solc
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// VersionFile pins the compiler version of a project, like solc-select and svm do
+	VersionFile = ".solc-version"
+
+	// FoundryConfigFile is the Foundry configuration, whose solc (or solc_version) field pins the compiler version
+	FoundryConfigFile = "foundry.toml"
+
+	// FoundryProfileEnv selects the Foundry profile, the default one if unset
+	FoundryProfileEnv = "FOUNDRY_PROFILE"
+)
+
+// PinnedVersion returns the compiler version pinned in dir by a .solc-version file, or else by the solc
+// field of the Foundry profile in use in foundry.toml (see FoundryProfileEnv), "" if there is none
+//
+// Pins are version constraints, typically exact versions (e.g. 0.8.20)
+func PinnedVersion(dir string) (string, error) {
+	file := filepath.Join(dir, VersionFile)
+	b, err := ioutil.ReadFile(file)
+	if err == nil {
+		return checkPin(file, versionFileContent(b))
+	}
+	if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	file = filepath.Join(dir, FoundryConfigFile)
+	b, err = ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	version, err := foundryVersion(b, os.Getenv(FoundryProfileEnv))
+	if err != nil {
+		return "", fmt.Errorf("invalid %v: %w", file, err)
+	}
+	return checkPin(file, version)
+}
+
+// versionFileContent returns the first line of a version file that is neither empty nor a comment
+func versionFileContent(b []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return line
+		}
+	}
+	return ""
+}
+
+func checkPin(file, version string) (string, error) {
+	if version == "" {
+		return "", nil
+	}
+	_, err := MatchVersion(version, "0.0.0")
+	if err != nil {
+		return "", fmt.Errorf("invalid %v: %w", file, err)
+	}
+	return version, nil
+}
+
+// foundryVersion returns the solc field of profile, or of the default profile if profile does not set it
+//
+// Only the subset of TOML Foundry configurations use for it is read: [profile.<name>] tables of
+// key = "string" pairs. Paths to solc binaries are not versions and are ignored
+func foundryVersion(b []byte, profile string) (string, error) {
+	versions := make(map[string]string)
+	table := ""
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "" || strings.HasPrefix(text, "#"):
+			continue
+		case strings.HasPrefix(text, "["):
+			end := strings.Index(text, "]")
+			if end < 0 {
+				return "", fmt.Errorf("line %v: unterminated table header", line)
+			}
+			table = strings.TrimSpace(strings.Trim(text[:end], "[]"))
+			continue
+		}
+
+		eq := strings.Index(text, "=")
+		if eq < 0 || !strings.HasPrefix(table, "profile.") {
+			continue
+		}
+		key := strings.TrimSpace(text[:eq])
+		if key != "solc" && key != "solc_version" {
+			continue
+		}
+		value := strings.TrimSpace(text[eq+1:])
+		if i := strings.Index(value, "#"); i >= 0 && strings.Count(value[:i], `"`)%2 == 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+		unquoted, err := strconv.Unquote(value)
+		if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+			// Literal string
+			unquoted, err = value[1:len(value)-1], nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("line %v: %v is not a string", line, key)
+		}
+		if strings.ContainsAny(unquoted, `/\`) {
+			continue
+		}
+		versions[strings.TrimPrefix(table, "profile.")] = strings.TrimPrefix(unquoted, "v")
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	if version, ok := versions[profile]; ok && profile != "" {
+		return version, nil
+	}
+	return versions["default"], nil
+}
@@ -0,0 +1,68 @@
+package solc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPinnedVersion(t *testing.T) {
+	dir, err := ioutil.TempDir("", "solc-pin")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	version, err := PinnedVersion(dir)
+	require.NoError(t, err, "PinnedVersion should not error")
+	assert.Empty(t, version, "Directory without pin should have no version")
+
+	foundry := `# Foundry configuration
+[profile.default]
+src = "src"
+solc = "0.8.20" # pinned
+
+[profile.ci]
+solc_version = '0.8.19'
+
+[profile.local]
+solc = "/usr/local/bin/solc"
+
+[rpc_endpoints]
+solc = "0.1.0"
+`
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, FoundryConfigFile), []byte(foundry), 0644))
+	version, err = PinnedVersion(dir)
+	require.NoError(t, err, "PinnedVersion should not error")
+	assert.Equal(t, "0.8.20", version, "Default profile should be used")
+
+	for profile, expected := range map[string]string{"ci": "0.8.19", "local": "0.8.20", "missing": "0.8.20"} {
+		os.Setenv(FoundryProfileEnv, profile)
+		version, err = PinnedVersion(dir)
+		require.NoError(t, err, "PinnedVersion should not error")
+		assert.Equal(t, expected, version, "Profile %v should pin %v", profile, expected)
+	}
+	os.Unsetenv(FoundryProfileEnv)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, VersionFile), []byte("# pinned by solc-select\n0.6.2\n"), 0644))
+	version, err = PinnedVersion(dir)
+	require.NoError(t, err, "PinnedVersion should not error")
+	assert.Equal(t, "0.6.2", version, ".solc-version should take precedence")
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, VersionFile), []byte("latest\n"), 0644))
+	_, err = PinnedVersion(dir)
+	assert.Error(t, err, "Invalid version should error")
+
+	require.NoError(t, os.Remove(filepath.Join(dir, VersionFile)))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, FoundryConfigFile), []byte("[profile.default]\nsolc = 0.8\n"), 0644))
+	_, err = PinnedVersion(dir)
+	assert.Error(t, err, "Unquoted version should error")
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, FoundryConfigFile), []byte("[profile.default]\nsolc = \"0.5.9\"\n"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, ProjectConfigFile), []byte("sources: [contracts]\n"), 0644))
+	config, err := LoadProjectConfig(dir)
+	require.NoError(t, err, "LoadProjectConfig should not error")
+	assert.Equal(t, "0.5.9", config.Version, "Project version should default to the pinned one")
+}
@@ -0,0 +1,158 @@
+package solc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// outputFactors are the estimated bytes of each output per byte of source, measured on typical contracts
+//
+// Contract outputs are counted for the whole file selected. Contracts inheriting code from imported files
+// output more, so estimates are orders of magnitude rather than exact sizes
+var outputFactors = map[string]float64{
+	"abi":                    2.5,
+	"metadata":               4,
+	"devdoc":                 0.1,
+	"userdoc":                0.1,
+	"ir":                     10,
+	"irAst":                  40,
+	"irOptimized":            6,
+	"irOptimizedAst":         30,
+	"storageLayout":          1.2,
+	"transientStorageLayout": 0.1,
+	"evm.assembly":           24,
+	"evm.legacyAssembly":     45,
+	"evm.methodIdentifiers":  0.35,
+	"evm.gasEstimates":       0.5,
+
+	"evm.bytecode.object":                      4,
+	"evm.bytecode.opcodes":                     5,
+	"evm.bytecode.sourceMap":                   2,
+	"evm.bytecode.linkReferences":              0.1,
+	"evm.bytecode.generatedSources":            20,
+	"evm.bytecode.functionDebugData":           0.5,
+	"evm.deployedBytecode.object":              4,
+	"evm.deployedBytecode.opcodes":             5,
+	"evm.deployedBytecode.sourceMap":           2,
+	"evm.deployedBytecode.linkReferences":      0.1,
+	"evm.deployedBytecode.generatedSources":    20,
+	"evm.deployedBytecode.functionDebugData":   0.5,
+	"evm.deployedBytecode.immutableReferences": 0.1,
+}
+
+// fileOutputFactors are the estimated bytes of file outputs (selected with an empty contract name) per byte of source
+var fileOutputFactors = map[string]float64{
+	"ast":       30,
+	"legacyAST": 25,
+}
+
+// sourceOverhead is the size of the output entry of every source, regardless of the selection
+const sourceOverhead = 32
+
+// OutputEstimate is the estimated size of the standard JSON output of an input, see EstimateOutput
+type OutputEstimate struct {
+	// Size is the estimated size of the output in bytes
+	Size int
+
+	// Outputs are the estimated sizes of the outputs selected, by output (e.g. ast, evm.bytecode.object)
+	Outputs map[string]int
+}
+
+// Largest returns the n outputs with the largest estimated sizes, the largest first
+func (e OutputEstimate) Largest(n int) []string {
+	outputs := make([]string, 0, len(e.Outputs))
+	for output := range e.Outputs {
+		outputs = append(outputs, output)
+	}
+	sort.Slice(outputs, func(i, j int) bool {
+		if e.Outputs[outputs[i]] != e.Outputs[outputs[j]] {
+			return e.Outputs[outputs[i]] > e.Outputs[outputs[j]]
+		}
+		return outputs[i] < outputs[j]
+	})
+	if n >= 0 && n < len(outputs) {
+		outputs = outputs[:n]
+	}
+	return outputs
+}
+
+// EstimateOutput estimates the size of the output of in from the size of its sources and its output
+// selection, before compiling it
+//
+// Estimates are meant to reject selections too wide for a memory budget (e.g. "*": {"*": ["*"], "": ["*"]}
+// selects outputs hundreds of times the size of the sources), they are not exact
+func EstimateOutput(in *Input) OutputEstimate {
+	estimate := OutputEstimate{Outputs: make(map[string]int)}
+	for name, source := range in.Sources {
+		estimate.Size += sourceOverhead + len(name)
+
+		// Outputs selected for the file, by "*" and by its name, counted once
+		selected := make(map[string]bool)
+		for _, file := range []string{"*", name} {
+			for contract, outputs := range in.Settings.OutputSelection[file] {
+				factors := outputFactors
+				if contract == "" {
+					factors = fileOutputFactors
+				}
+				for _, output := range outputs {
+					for _, o := range expandOutput(output, factors) {
+						selected[o] = true
+					}
+				}
+			}
+		}
+
+		for output := range selected {
+			factor := outputFactors[output]
+			if f, ok := fileOutputFactors[output]; ok {
+				factor = f
+			}
+			size := int(factor * float64(len(source.Content)))
+			estimate.Outputs[output] += size
+			estimate.Size += size
+		}
+	}
+	return estimate
+}
+
+// expandOutput returns the outputs of factors a selected output stands for: itself, every output for
+// "*", or its sub outputs (e.g. evm.bytecode.object and evm.bytecode.opcodes for evm.bytecode)
+func expandOutput(output string, factors map[string]float64) []string {
+	var outputs []string
+	for o := range factors {
+		if output == "*" || o == output || strings.HasPrefix(o, output+".") {
+			outputs = append(outputs, o)
+		}
+	}
+	return outputs
+}
+
+// OutputBudgetError is returned when the estimated output of an input exceeds a budget (see EstimateOutput)
+//
+// It unwraps to a *SizeLimitError of kind "estimated output"
+type OutputBudgetError struct {
+	Estimate OutputEstimate
+	Budget   int
+}
+
+func (e *OutputBudgetError) Error() string {
+	return fmt.Sprintf("solc: estimated output size %v exceeds budget of %v, narrow the selection of the largest outputs: %v",
+		e.Estimate.Size, e.Budget, strings.Join(e.Estimate.Largest(3), ", "))
+}
+
+func (e *OutputBudgetError) Unwrap() error {
+	return &SizeLimitError{Kind: "estimated output", Size: e.Estimate.Size, Limit: e.Budget}
+}
+
+// CheckOutputBudget returns an *OutputBudgetError if the estimated output of in exceeds budget, if positive
+func CheckOutputBudget(in *Input, budget int) error {
+	if budget <= 0 {
+		return nil
+	}
+	estimate := EstimateOutput(in)
+	if estimate.Size > budget {
+		return &OutputBudgetError{Estimate: estimate, Budget: budget}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package solc
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const plannerSource = `pragma solidity ^0.6.0;
+contract Token {
+    mapping(address => uint256) public balanceOf;
+    event Transfer(address indexed from, address indexed to, uint256 value);
+    function transfer(address to, uint256 value) external returns (bool) {
+        require(balanceOf[msg.sender] >= value, "balance");
+        balanceOf[msg.sender] -= value;
+        balanceOf[to] += value;
+        emit Transfer(msg.sender, to, value);
+        return true;
+    }
+}
+`
+
+func TestEstimateOutput(t *testing.T) {
+	narrow := EstimateOutput(NewInput().AddSource("A.sol", plannerSource).SetOutputSelection(SelectAll("abi")))
+	assert.Equal(t, []string{"abi"}, narrow.Largest(-1))
+
+	in := NewInput().
+		AddSource("A.sol", plannerSource).
+		AddSource("B.sol", plannerSource).
+		SetOutputSelection(OutputSelection{"*": {"*": {"*"}, "": {"*"}}, "A.sol": {"Token": {"abi"}}})
+	wide := EstimateOutput(in)
+	assert.True(t, wide.Size > 100*narrow.Size, "Wildcard selection should be estimated far larger")
+	assert.Equal(t, []string{"evm.legacyAssembly", "irAst", "ast"}, wide.Largest(3))
+	assert.Equal(t, 2*narrow.Outputs["abi"], wide.Outputs["abi"], "Outputs selected twice for a file should be counted once")
+
+	evm := EstimateOutput(NewInput().AddSource("A.sol", plannerSource).SetOutputSelection(SelectAll("evm.bytecode")))
+	assert.Contains(t, evm.Outputs, "evm.bytecode.object", "Selections should expand to their sub outputs")
+	assert.NotContains(t, evm.Outputs, "evm.deployedBytecode.object")
+
+	err := CheckOutputBudget(in, wide.Size)
+	assert.NoError(t, err, "Estimates within budget should pass")
+	err = CheckOutputBudget(in, narrow.Size)
+	var budgetErr *OutputBudgetError
+	require.True(t, errors.As(err, &budgetErr), "Estimates over budget should error")
+	assert.Contains(t, err.Error(), "evm.legacyAssembly, irAst, ast", "Largest outputs should be suggested")
+	var sizeErr *SizeLimitError
+	require.True(t, errors.As(err, &sizeErr), "Budget errors should unwrap to size limit errors")
+	assert.Equal(t, "estimated output", sizeErr.Kind)
+	assert.Error(t, InputLimits{MaxEstimatedOutput: narrow.Size}.Check(in), "Limits should check the estimated output")
+}
+
+func TestEstimateOutputAccuracy(t *testing.T) {
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err, "Creating compiler should not error")
+	defer solc.Close()
+
+	for _, selection := range []OutputSelection{
+		SelectAll("abi", "evm.bytecode.object", "evm.deployedBytecode.object"),
+		{"*": {"*": {"evm.legacyAssembly"}, "": {"ast"}}},
+	} {
+		in := NewInput().AddSource("A.sol", plannerSource).SetOutputSelection(selection)
+		b, err := json.Marshal(in)
+		require.NoError(t, err)
+		out, err := CompileJSON(solc, b)
+		require.NoError(t, err, "CompileJSON should not error")
+		estimate := EstimateOutput(in).Size
+		assert.True(t, estimate > len(out)/4 && estimate < len(out)*4, "Estimate %v should be close to the output size %v", estimate, len(out))
+	}
+}
+
+func TestOutputBudget(t *testing.T) {
+	in := NewInput().AddSource("A.sol", plannerSource).SetOutputSelection(OutputSelection{"*": {"*": {"*"}, "": {"*"}}})
+	src, err := ioutil.ReadFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err)
+
+	logger := &recordingLogger{}
+	solc, err := new(string(src), WithOutputBudget(16<<10), WithLogger(logger))
+	require.NoError(t, err, "Creating Solc should not error")
+	defer solc.Close()
+	_, err = solc.Compile(in)
+	require.NoError(t, err, "Inputs over budget should compile outside of strict mode")
+	require.Contains(t, logger.kinds(), EventOutputBudget, "Inputs over budget should be reported")
+	for _, e := range logger.events {
+		if e.Kind == EventOutputBudget {
+			assert.True(t, strings.HasPrefix(e.String(), "solc: warning: solc: estimated output size"))
+		}
+	}
+
+	strict, err := new(string(src), WithOutputBudget(16<<10), WithStrictSettings())
+	require.NoError(t, err, "Creating Solc should not error")
+	defer strict.Close()
+	_, err = strict.Compile(in)
+	var budgetErr *OutputBudgetError
+	assert.True(t, errors.As(err, &budgetErr), "Inputs over budget should be rejected in strict mode")
+	_, err = strict.Compile(NewInput().AddSource("A.sol", plannerSource).SetOutputSelection(SelectAll("abi")))
+	assert.NoError(t, err, "Inputs within budget should compile")
+}
@@ -0,0 +1,478 @@
+package solc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Pool dispatches compilations to a fixed set of compiler instances, each with its own isolate
+//
+// Instances found dead after a compilation (stuck and terminated by WithTimeout, over WithMemoryLimit...)
+// are discarded and replaced in the background, only the compilation that killed them fails.
+//
+// Pool implements Solc and is safe for concurrent use
+type Pool struct {
+	factory Factory
+	size    int
+
+	version string
+	license string
+
+	mux     sync.Mutex
+	closed  bool
+	flights map[string]*flight
+
+	// all holds the live instances, fewer than size while dead ones are being replaced
+	all []Solc
+	// healErr is the error of the last failed replacement, until one succeeds
+	healErr error
+	// retired holds the stats of replaced instances
+	retired *statsRecorder
+
+	// idle instances are handed to waiters by priority (see ContextWithPriority)
+	idle    []Solc
+	waiters []*waiter
+	seq     uint64
+
+	// running counts the instances held by each caller (see ContextWithCaller)
+	running      map[string]int
+	maxPerCaller int
+}
+
+// waiter is a caller waiting for an idle instance
+type waiter struct {
+	priority Priority
+	caller   string
+	seq      uint64
+
+	// instance receives the instance handed to the waiter, it is closed if the pool is
+	instance chan Solc
+}
+
+// flight is a compilation shared by concurrent identical requests
+type flight struct {
+	done chan struct{}
+	out  *Output
+	err  error
+}
+
+// NewPool creates size compiler instances (concurrently) using factory
+func NewPool(factory Factory, size int) (*Pool, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("solc: invalid pool size %v", size)
+	}
+
+	instances := make([]Solc, size)
+	errs := make([]error, size)
+	var wg sync.WaitGroup
+	for i := range instances {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			instances[i], errs[i] = factory()
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			for _, instance := range instances {
+				if instance != nil {
+					instance.Close()
+				}
+			}
+			return nil, err
+		}
+	}
+
+	p := &Pool{
+		factory: factory,
+		size:    size,
+		retired: newStatsRecorder(),
+		all:     instances,
+		idle:    append([]Solc{}, instances...),
+		version: instances[0].Version(),
+		license: instances[0].License(),
+		flights: make(map[string]*flight),
+		running: make(map[string]int),
+	}
+
+	return p, nil
+}
+
+// LimitPerCaller caps the number of instances a single caller (see ContextWithCaller) uses at once (0 for no limit)
+//
+// It keeps a large batch from a caller from starving the others. Compilations with no caller are not limited
+func (p *Pool) LimitPerCaller(max int) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.maxPerCaller = max
+	p.dispatch()
+}
+
+// Size returns the number of compiler instances of the pool
+func (p *Pool) Size() int {
+	return p.size
+}
+
+// Busy returns the number of instances currently compiling
+func (p *Pool) Busy() int {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	return len(p.all) - len(p.idle)
+}
+
+// Waiting returns the number of compilations waiting for an idle instance
+func (p *Pool) Waiting() int {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	return len(p.waiters)
+}
+
+func (p *Pool) License() string {
+	return p.license
+}
+
+func (p *Pool) Version() string {
+	return p.version
+}
+
+// Compile waits for an idle instance and compiles input on it
+func (p *Pool) Compile(input *Input) (*Output, error) {
+	return p.CompileContext(context.Background(), input)
+}
+
+// CompileContext is like Compile but stops waiting for an idle instance once ctx is done
+//
+// A compilation already running is not interrupted (see WithTimeout). Idle instances go to the
+// waiting compilations of highest priority first, in arrival order (see ContextWithPriority)
+//
+// Identical inputs compiled concurrently are only compiled once and all callers share
+// the same output, which must thus not be modified. Inputs over the policy of ctx are
+// rejected right away (see ContextWithPolicy), as are compilations for other versions
+// (see ContextWithVersion)
+func (p *Pool) CompileContext(ctx context.Context, input *Input) (*Output, error) {
+	if err := checkVersion(ctx, p.version); err != nil {
+		return nil, err
+	}
+	key := buildKey(p.version, input)
+	if policy, ok := policyFromContext(ctx); ok {
+		// Rejected before waiting for an instance, and not shared with compilations under other policies
+		if err := policy.Check(input); err != nil {
+			return nil, err
+		}
+		key += fmt.Sprintf("/%+v", policy)
+	}
+
+	for {
+		f, leader := p.join(key)
+		if leader {
+			f.out, f.err = p.compile(ctx, input)
+			p.land(key, f)
+			return f.out, f.err
+		}
+
+		select {
+		case <-f.done:
+			if (f.err == context.Canceled || f.err == context.DeadlineExceeded) && ctx.Err() == nil {
+				// The leading caller gave up, compile on our own
+				continue
+			}
+			return f.out, f.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// join returns the flight in progress for key, or registers a new one led by the caller
+func (p *Pool) join(key string) (f *flight, leader bool) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if f, ok := p.flights[key]; ok {
+		return f, false
+	}
+	f = &flight{done: make(chan struct{})}
+	p.flights[key] = f
+	return f, true
+}
+
+func (p *Pool) land(key string, f *flight) {
+	p.mux.Lock()
+	delete(p.flights, key)
+	p.mux.Unlock()
+	close(f.done)
+}
+
+func (p *Pool) compile(ctx context.Context, input *Input) (*Output, error) {
+	caller := callerFromContext(ctx)
+	for {
+		instance, err := p.acquire(ctx, priorityFromContext(ctx), caller)
+		if err != nil {
+			return nil, err
+		}
+		out, err := CompileContext(ctx, instance, input)
+		p.release(instance, caller)
+		if err == ErrTerminated {
+			// The instance died before running this compilation, it is being replaced so try another one
+			continue
+		}
+		return out, err
+	}
+}
+
+// BatchError holds the errors of a CompileAll call, aligned with its inputs (nil for successful inputs)
+type BatchError struct {
+	Errors []error
+}
+
+func (e *BatchError) Error() string {
+	var msgs []string
+	for i, err := range e.Errors {
+		if err != nil {
+			msgs = append(msgs, fmt.Sprintf("input %v: %v", i, err))
+		}
+	}
+	return fmt.Sprintf("solc: %v of %v compilations failed: %v", len(msgs), len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// CompileAll compiles inputs running at most parallelism compilations at once (pool size if <= 0)
+//
+// Outputs are aligned with inputs. If any compilation fails a *BatchError is returned along with
+// the successful outputs. Inputs not started when ctx is done fail with ctx.Err()
+func (p *Pool) CompileAll(ctx context.Context, inputs []*Input, parallelism int) ([]*Output, error) {
+	if parallelism <= 0 || parallelism > p.Size() {
+		parallelism = p.Size()
+	}
+
+	outputs := make([]*Output, len(inputs))
+	errs := make([]error, len(inputs))
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				if err := ctx.Err(); err != nil {
+					errs[i] = err
+					continue
+				}
+				outputs[i], errs[i] = p.CompileContext(ctx, inputs[i])
+			}
+		}()
+	}
+	for i := range inputs {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return outputs, &BatchError{Errors: errs}
+		}
+	}
+	return outputs, nil
+}
+
+// HeapStatistics sums the statistics of all instances of the pool
+func (p *Pool) HeapStatistics() HeapStatistics {
+	p.mux.Lock()
+	all := append([]Solc{}, p.all...)
+	p.mux.Unlock()
+
+	var total HeapStatistics
+	for _, instance := range all {
+		stats := instance.HeapStatistics()
+		total.TotalHeapSize += stats.TotalHeapSize
+		total.TotalHeapSizeExecutable += stats.TotalHeapSizeExecutable
+		total.TotalPhysicalSize += stats.TotalPhysicalSize
+		total.TotalAvailableSize += stats.TotalAvailableSize
+		total.UsedHeapSize += stats.UsedHeapSize
+		total.HeapSizeLimit += stats.HeapSizeLimit
+		total.MallocedMemory += stats.MallocedMemory
+		total.PeakMallocedMemory += stats.PeakMallocedMemory
+		total.ExternalMemory += stats.ExternalMemory
+		total.ModuleMemory += stats.ModuleMemory
+	}
+	return total
+}
+
+// Close closes idle instances right away and busy instances once their compilation completes
+func (p *Pool) Close() error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+
+	for _, w := range p.waiters {
+		close(w.instance)
+	}
+	p.waiters = nil
+
+	var err error
+	for _, instance := range p.idle {
+		if closeErr := instance.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	p.idle = nil
+	return err
+}
+
+// acquire waits for an idle instance handed to the caller according to priority
+func (p *Pool) acquire(ctx context.Context, priority Priority, caller string) (Solc, error) {
+	p.mux.Lock()
+	if p.closed {
+		p.mux.Unlock()
+		return nil, ErrClosed
+	}
+	p.seq++
+	w := &waiter{priority: priority, caller: caller, seq: p.seq, instance: make(chan Solc, 1)}
+	p.waiters = append(p.waiters, w)
+	p.dispatch()
+	p.mux.Unlock()
+
+	select {
+	case instance, ok := <-w.instance:
+		if !ok {
+			return nil, ErrClosed
+		}
+		return instance, nil
+	case <-ctx.Done():
+		p.mux.Lock()
+		defer p.mux.Unlock()
+		for i, other := range p.waiters {
+			if other == w {
+				p.waiters = append(p.waiters[:i], p.waiters[i+1:]...)
+				return nil, ctx.Err()
+			}
+		}
+		// An instance was handed over meanwhile, give it back
+		if instance, ok := <-w.instance; ok {
+			p.releaseLocked(instance, caller)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// release gives instance back to the pool, or replaces it if it died
+func (p *Pool) release(instance Solc, caller string) {
+	// Checked before locking as it may wait on the instance
+	err := Healthy(instance)
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if err == nil || p.closed {
+		p.releaseLocked(instance, caller)
+		return
+	}
+	p.uncount(caller)
+	p.replace(instance)
+}
+
+func (p *Pool) releaseLocked(instance Solc, caller string) {
+	p.uncount(caller)
+	if p.closed {
+		instance.Close()
+		return
+	}
+	p.idle = append(p.idle, instance)
+	p.dispatch()
+}
+
+func (p *Pool) uncount(caller string) {
+	p.running[caller]--
+	if p.running[caller] <= 0 {
+		delete(p.running, caller)
+	}
+}
+
+const (
+	// healDelay is the delay before retrying a failed replacement, doubled on each failure up to maxHealDelay
+	healDelay    = 100 * time.Millisecond
+	maxHealDelay = 30 * time.Second
+)
+
+// replace discards a dead instance and creates a new one in the background, it must be called while holding the lock
+func (p *Pool) replace(dead Solc) {
+	for i, instance := range p.all {
+		if instance == dead {
+			p.all = append(p.all[:i], p.all[i+1:]...)
+			break
+		}
+	}
+	if c, ok := dead.(statsCollector); ok {
+		c.collectStats(p.retired)
+	}
+	p.retired.restarted()
+	go func() {
+		dead.Close()
+		p.spawn()
+	}()
+}
+
+// spawn creates an instance for the pool, retrying until it succeeds or the pool is closed
+func (p *Pool) spawn() {
+	delay := healDelay
+	for {
+		instance, err := p.factory()
+
+		p.mux.Lock()
+		if p.closed {
+			p.mux.Unlock()
+			if err == nil {
+				instance.Close()
+			}
+			return
+		}
+		if err == nil {
+			p.healErr = nil
+			p.all = append(p.all, instance)
+			p.idle = append(p.idle, instance)
+			p.dispatch()
+			p.mux.Unlock()
+			return
+		}
+		p.healErr = err
+		p.mux.Unlock()
+
+		time.Sleep(delay)
+		if delay *= 2; delay > maxHealDelay {
+			delay = maxHealDelay
+		}
+	}
+}
+
+// dispatch hands idle instances to waiters, it must be called while holding the lock
+func (p *Pool) dispatch() {
+	for len(p.idle) > 0 {
+		best := -1
+		for i, w := range p.waiters {
+			if w.caller != "" && p.maxPerCaller > 0 && p.running[w.caller] >= p.maxPerCaller {
+				continue
+			}
+			if best < 0 || w.priority > p.waiters[best].priority {
+				best = i
+			}
+		}
+		if best < 0 {
+			return
+		}
+
+		// Waiters are appended in arrival order so the first of highest priority is the oldest
+		w := p.waiters[best]
+		p.waiters = append(p.waiters[:best], p.waiters[best+1:]...)
+		instance := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+		p.running[w.caller]++
+		w.instance <- instance
+	}
+}
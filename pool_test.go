@@ -0,0 +1,300 @@
+package solc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// echoSolc outputs the content of the single source it compiles as metadata
+type echoSolc struct {
+	fakeSolc
+	delay time.Duration
+
+	mux     *sync.Mutex
+	running *int
+	maxRun  *int
+}
+
+func (s *echoSolc) Compile(input *Input) (*Output, error) {
+	s.mux.Lock()
+	*s.running++
+	if *s.running > *s.maxRun {
+		*s.maxRun = *s.running
+	}
+	s.mux.Unlock()
+	defer func() {
+		s.mux.Lock()
+		*s.running--
+		s.mux.Unlock()
+	}()
+
+	time.Sleep(s.delay)
+	for _, src := range input.Sources {
+		if src.Content == "fail" {
+			return nil, fmt.Errorf("failed")
+		}
+		return &Output{Contracts: map[string]map[string]Contract{"": {"": {Metadata: src.Content}}}}, nil
+	}
+	return &Output{}, nil
+}
+
+func newEchoPool(t *testing.T, size int, delay time.Duration) (*Pool, *int) {
+	var mux sync.Mutex
+	var running, maxRun int
+	p, err := NewPool(func() (Solc, error) {
+		return &echoSolc{fakeSolc: fakeSolc{version: "0.6.2"}, delay: delay, mux: &mux, running: &running, maxRun: &maxRun}, nil
+	}, size)
+	require.NoError(t, err, "NewPool should not error")
+	return p, &maxRun
+}
+
+func echoInputs(contents ...string) []*Input {
+	var inputs []*Input
+	for _, content := range contents {
+		inputs = append(inputs, &Input{Sources: map[string]SourceIn{"A.sol": SourceIn{Content: content}}})
+	}
+	return inputs
+}
+
+func TestPoolCompileAll(t *testing.T) {
+	p, maxRun := newEchoPool(t, 4, 10*time.Millisecond)
+	defer p.Close()
+	assert.Equal(t, "0.6.2", p.Version())
+	assert.Equal(t, 0, p.Busy(), "No instance should be busy")
+
+	contents := []string{"a", "b", "fail", "d", "e", "f", "g", "h"}
+	outputs, err := p.CompileAll(context.Background(), echoInputs(contents...), 2)
+	require.IsType(t, &BatchError{}, err, "CompileAll should return a BatchError")
+	assert.Equal(t, 2, *maxRun, "At most 2 compilations should run at once")
+
+	batchErr := err.(*BatchError)
+	for i, content := range contents {
+		if content == "fail" {
+			assert.Error(t, batchErr.Errors[i], "Failing input should have an error")
+			assert.Nil(t, outputs[i])
+			continue
+		}
+		assert.NoError(t, batchErr.Errors[i], "Input #%v should not error", i)
+		assert.Equal(t, content, outputs[i].Contracts[""][""].Metadata, "Output #%v should match its input", i)
+	}
+}
+
+func TestPoolCompileAllCanceled(t *testing.T) {
+	p, _ := newEchoPool(t, 1, 20*time.Millisecond)
+	defer p.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	_, err := p.CompileAll(ctx, echoInputs("a", "b", "c", "d", "e"), 0)
+	require.IsType(t, &BatchError{}, err, "CompileAll should return a BatchError")
+	errs := err.(*BatchError).Errors
+	assert.NoError(t, errs[0], "First input should have compiled")
+	assert.Equal(t, context.DeadlineExceeded, errs[4], "Last input should not have been started")
+}
+
+func TestPoolClose(t *testing.T) {
+	p, _ := newEchoPool(t, 2, 0)
+	require.NoError(t, p.Close(), "Close should not error")
+	require.NoError(t, p.Close(), "Close should be idempotent")
+	_, err := p.Compile(&Input{})
+	assert.Equal(t, ErrClosed, err, "Compile should fail after Close")
+}
+
+func TestPoolDeduplication(t *testing.T) {
+	var mux sync.Mutex
+	compiles := 0
+	p, err := NewPool(func() (Solc, error) {
+		return &countingSolc{delay: 20 * time.Millisecond, mux: &mux, compiles: &compiles}, nil
+	}, 4)
+	require.NoError(t, err, "NewPool should not error")
+	defer p.Close()
+
+	var wg sync.WaitGroup
+	outputs := make([]*Output, 8)
+	for i := range outputs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			outputs[i], _ = p.Compile(echoInputs("a")[0])
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, compiles, "Identical concurrent inputs should be compiled once")
+	for _, out := range outputs {
+		assert.True(t, out == outputs[0], "Callers should share the output")
+	}
+
+	_, err = p.Compile(echoInputs("a")[0])
+	require.NoError(t, err, "Compile should not error")
+	assert.Equal(t, 2, compiles, "Sequential inputs should not be deduplicated")
+}
+
+// countingSolc counts compilations across instances
+type countingSolc struct {
+	fakeSolc
+	delay    time.Duration
+	mux      *sync.Mutex
+	compiles *int
+}
+
+func (s *countingSolc) Compile(input *Input) (*Output, error) {
+	s.mux.Lock()
+	*s.compiles++
+	s.mux.Unlock()
+	time.Sleep(s.delay)
+	return &Output{}, nil
+}
+
+func TestPoolPriority(t *testing.T) {
+	p, _ := newEchoPool(t, 1, 20*time.Millisecond)
+	defer p.Close()
+
+	var mux sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	compile := func(ctx context.Context, content string) {
+		defer wg.Done()
+		out, err := p.CompileContext(ctx, echoInputs(content)[0])
+		if !assert.NoError(t, err, "CompileContext should not error") {
+			return
+		}
+		mux.Lock()
+		order = append(order, out.Contracts[""][""].Metadata)
+		mux.Unlock()
+	}
+	waitQueued := func(n int) {
+		for p.Waiting() < n {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	wg.Add(1)
+	go compile(context.Background(), "running")
+	for p.Busy() < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	batch := ContextWithPriority(context.Background(), PriorityBatch)
+	for i, content := range []string{"batch1", "batch2"} {
+		wg.Add(1)
+		go compile(batch, content)
+		waitQueued(i + 1)
+	}
+	wg.Add(1)
+	go compile(context.Background(), "normal")
+	waitQueued(3)
+	wg.Add(1)
+	go compile(ContextWithPriority(context.Background(), PriorityInteractive), "interactive")
+	waitQueued(4)
+	wg.Wait()
+
+	assert.Equal(t, []string{"running", "interactive", "normal", "batch1", "batch2"}, order, "Waiters should be served by priority then arrival")
+}
+
+func TestPoolLimitPerCaller(t *testing.T) {
+	p, _ := newEchoPool(t, 2, 20*time.Millisecond)
+	defer p.Close()
+	p.LimitPerCaller(1)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	batch := ContextWithCaller(context.Background(), "batch")
+	for _, content := range []string{"a", "b", "c"} {
+		wg.Add(1)
+		go func(content string) {
+			defer wg.Done()
+			_, err := p.CompileContext(batch, echoInputs(content)[0])
+			assert.NoError(t, err, "CompileContext should not error")
+		}(content)
+	}
+	for p.Waiting() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, 1, p.Busy(), "A caller should not use more instances than its limit")
+
+	_, err := p.CompileContext(ContextWithCaller(context.Background(), "user"), echoInputs("d")[0])
+	require.NoError(t, err, "CompileContext should not error")
+	interactive := time.Since(start)
+	wg.Wait()
+
+	assert.Less(t, int64(interactive), int64(40*time.Millisecond), "Another caller should not wait behind the batch")
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(60*time.Millisecond), "Batch compilations should run one at a time")
+}
+
+func TestPoolCanceledWaiter(t *testing.T) {
+	p, _ := newEchoPool(t, 1, 20*time.Millisecond)
+	defer p.Close()
+
+	go p.Compile(echoInputs("a")[0])
+	for p.Busy() < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	_, err := p.CompileContext(ctx, echoInputs("b")[0])
+	assert.Equal(t, context.DeadlineExceeded, err, "Waiter should give up once ctx is done")
+	assert.Equal(t, 0, p.Waiting(), "Canceled waiter should leave the queue")
+
+	_, err = p.Compile(echoInputs("c")[0])
+	assert.NoError(t, err, "Instance should remain usable")
+}
+
+// crashSolc dies compiling "crash", as if terminated by a timeout
+type crashSolc struct {
+	fakeSolc
+	dead bool
+}
+
+func (s *crashSolc) Healthy() error {
+	if s.dead {
+		return ErrTerminated
+	}
+	return nil
+}
+
+func (s *crashSolc) Compile(input *Input) (*Output, error) {
+	if s.dead {
+		return nil, ErrTerminated
+	}
+	if input.Sources["A.sol"].Content == "crash" {
+		s.dead = true
+		return nil, ErrTimeout
+	}
+	return &Output{}, nil
+}
+
+func TestPoolSelfHealing(t *testing.T) {
+	var mux sync.Mutex
+	var created []*crashSolc
+	p, err := NewPool(func() (Solc, error) {
+		mux.Lock()
+		defer mux.Unlock()
+		instance := &crashSolc{fakeSolc: fakeSolc{version: "0.6.2"}}
+		created = append(created, instance)
+		return instance, nil
+	}, 1)
+	require.NoError(t, err, "NewPool should not error")
+	defer p.Close()
+
+	_, err = p.Compile(echoInputs("crash")[0])
+	assert.Equal(t, ErrTimeout, err, "Compilation killing the instance should fail")
+
+	_, err = p.Compile(echoInputs("a")[0])
+	assert.NoError(t, err, "Next compilation should run on a replacement")
+	mux.Lock()
+	assert.Len(t, created, 2, "Dead instance should have been replaced")
+	// Dies while idle
+	created[1].dead = true
+	mux.Unlock()
+
+	_, err = p.Compile(echoInputs("b")[0])
+	assert.NoError(t, err, "Compilation on a dead instance should be retried on a replacement")
+	assert.NoError(t, p.Healthy(), "Healed pool should be healthy")
+	assert.Equal(t, 0, p.Busy())
+}
@@ -0,0 +1,39 @@
+package solc
+
+import (
+	"context"
+)
+
+// Priority orders the compilations waiting for an instance of a Pool
+type Priority int
+
+// Priority classes, compilations have PriorityNormal unless set with ContextWithPriority
+const (
+	PriorityBatch       Priority = -1
+	PriorityNormal      Priority = 0
+	PriorityInteractive Priority = 1
+)
+
+type priorityKey struct{}
+
+type callerKey struct{}
+
+// ContextWithPriority returns a context whose compilations are scheduled with priority by pools
+func ContextWithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// ContextWithCaller returns a context whose compilations are accounted to caller by pools (see Pool.LimitPerCaller)
+func ContextWithCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+func priorityFromContext(ctx context.Context) Priority {
+	priority, _ := ctx.Value(priorityKey{}).(Priority)
+	return priority
+}
+
+func callerFromContext(ctx context.Context) string {
+	caller, _ := ctx.Value(callerKey{}).(string)
+	return caller
+}
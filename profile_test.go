@@ -0,0 +1,77 @@
+package solc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultEVMVersion(t *testing.T) {
+	for version, expected := range map[string]string{
+		"0.4.11":                "",
+		"0.5.9+commit.e560f70d": "petersburg",
+		"v0.6.2":                "istanbul",
+		"0.8.19":                "paris",
+		"0.8.30":                "prague",
+	} {
+		evmVersion, err := DefaultEVMVersion(version)
+		require.NoError(t, err, "DefaultEVMVersion should not error")
+		assert.Equal(t, expected, evmVersion, version)
+	}
+	_, err := DefaultEVMVersion("latest")
+	assert.Error(t, err, "Invalid versions should error")
+}
+
+func TestApplyVerificationProfile(t *testing.T) {
+	for _, test := range []struct {
+		soljson      string
+		evmVersion   string
+		bytecodeHash string
+	}{
+		{"./solc-bin/soljson-v0.6.2+commit.bacdbe57.js", "istanbul", "ipfs"},
+		{"./solc-bin/soljson-v0.5.9+commit.e560f70d.js", "petersburg", ""},
+	} {
+		solc, err := NewFromFile(test.soljson, WithStrictSettings())
+		require.NoError(t, err, "Creating compiler should not error")
+
+		in := &Input{Language: "Solidity", Sources: map[string]SourceIn{"A.sol": {Content: "contract A {}"}}}
+		in.Settings.Optimizer.Enabled = true
+		_, err = in.ApplyVerificationProfile(solc.Version())
+		require.NoError(t, err, "ApplyVerificationProfile should not error")
+		assert.Equal(t, test.evmVersion, in.Settings.EVMVersion, "EVM version should be pinned")
+		assert.Equal(t, DefaultOptimizerRuns, in.Settings.Optimizer.Runs, "Optimizer runs should be pinned")
+		assert.Equal(t, &MetadataSettings{UseLiteralContent: true, BytecodeHash: test.bytecodeHash}, in.Settings.Metadata)
+
+		out, err := solc.Compile(in)
+		solc.Close()
+		require.NoError(t, err, "Compile should not error")
+		var metadata struct {
+			Settings struct {
+				EVMVersion string `json:"evmVersion"`
+				Metadata   struct {
+					UseLiteralContent bool   `json:"useLiteralContent"`
+					BytecodeHash      string `json:"bytecodeHash"`
+				} `json:"metadata"`
+			} `json:"settings"`
+			Sources map[string]struct {
+				Content string `json:"content"`
+			} `json:"sources"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(out.Contracts["A.sol"]["A"].Metadata), &metadata), "Metadata should be selected")
+		assert.Equal(t, test.evmVersion, metadata.Settings.EVMVersion)
+		assert.True(t, metadata.Settings.Metadata.UseLiteralContent, "Sources should be embedded")
+		assert.Equal(t, "contract A {}", metadata.Sources["A.sol"].Content)
+		assert.NotEmpty(t, out.Contracts["A.sol"]["A"].EVM.Bytecode.Object, "Default outputs should be selected")
+	}
+
+	in := NewInput().SetEVMVersion("byzantium")
+	in.Settings.Metadata = &MetadataSettings{BytecodeHash: "none"}
+	_, err := in.ApplyVerificationProfile("0.8.30")
+	require.NoError(t, err, "ApplyVerificationProfile should not error")
+	assert.Equal(t, "byzantium", in.Settings.EVMVersion, "Set EVM version should be kept")
+	assert.Equal(t, "ipfs", in.Settings.Metadata.BytecodeHash, "IPFS hash should be appended")
+	assert.True(t, in.Settings.OutputSelection.Selected("A.sol", "A", "metadata"), "Metadata should be selected")
+	assert.True(t, in.Settings.OutputSelection.Selected("A.sol", "A", "abi"), "Selected outputs should be kept")
+}
@@ -0,0 +1,199 @@
+package solc
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/nmvalera/solc-go/types"
+)
+
+const (
+	// InTotoStatementType is the type of in-toto v1 statements
+	InTotoStatementType = "https://in-toto.io/Statement/v1"
+
+	// SLSAProvenanceType is the predicate type of SLSA v1 provenance
+	SLSAProvenanceType = "https://slsa.dev/provenance/v1"
+
+	// ProvenanceBuildType identifies builds compiling a standard JSON input with a soljson release
+	ProvenanceBuildType = "https://github.com/nmvalera/solc-go/buildtypes/standard-json/v1"
+
+	// InTotoPayloadType is the DSSE payload type of in-toto statements
+	InTotoPayloadType = "application/vnd.in-toto+json"
+)
+
+// Provenance is an in-toto statement attesting SLSA provenance of artifacts and build-infos
+type Provenance struct {
+	Type          string               `json:"_type"`
+	Subject       []ResourceDescriptor `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+	Predicate     ProvenancePredicate  `json:"predicate"`
+}
+
+// ResourceDescriptor describes a subject or dependency of a build, by name or URI and digests (sha256)
+type ResourceDescriptor struct {
+	Name   string            `json:"name,omitempty"`
+	URI    string            `json:"uri,omitempty"`
+	Digest map[string]string `json:"digest,omitempty"`
+}
+
+type ProvenancePredicate struct {
+	BuildDefinition BuildDefinition `json:"buildDefinition"`
+	RunDetails      RunDetails      `json:"runDetails"`
+}
+
+type BuildDefinition struct {
+	BuildType string `json:"buildType"`
+
+	// ExternalParameters are the compiler version and the hash of the input (see Input.Hash)
+	ExternalParameters ProvenanceParameters `json:"externalParameters"`
+
+	// ResolvedDependencies are the compiler binary followed by the sources of the input, by name
+	ResolvedDependencies []ResourceDescriptor `json:"resolvedDependencies"`
+}
+
+type ProvenanceParameters struct {
+	SolcVersion string `json:"solcVersion"`
+	InputHash   string `json:"inputHash"`
+}
+
+type RunDetails struct {
+	Builder  ProvenanceBuilder  `json:"builder"`
+	Metadata ProvenanceMetadata `json:"metadata"`
+}
+
+type ProvenanceBuilder struct {
+	ID string `json:"id"`
+}
+
+type ProvenanceMetadata struct {
+	// InvocationID is the ID of the build-info
+	InvocationID string `json:"invocationId"`
+}
+
+// NewProvenance returns the provenance of the build-info and artifacts of a compilation run by builder (a URI
+// identifying who compiled, e.g. a CI workflow)
+//
+// Subjects are the artifacts and the build-info, named by their path relative to the artifacts directory
+// (see WriteFile) and digested as written. The compiler binary is referenced by its release URL, its digest
+// can be added to the first dependency once downloaded (see Release.SHA256)
+func NewProvenance(builder string, info *BuildInfo, artifacts []*Artifact) (*Provenance, error) {
+	p := &Provenance{
+		Type:          InTotoStatementType,
+		PredicateType: SLSAProvenanceType,
+		Predicate: ProvenancePredicate{
+			BuildDefinition: BuildDefinition{
+				BuildType: ProvenanceBuildType,
+				ExternalParameters: ProvenanceParameters{
+					SolcVersion: info.SolcLongVersion,
+					InputHash:   info.Input.Hash(),
+				},
+				ResolvedDependencies: []ResourceDescriptor{{
+					Name: "soljson",
+					URI:  fmt.Sprintf("%v/soljson-v%v.js", DefaultReleasesURL, info.SolcLongVersion),
+				}},
+			},
+			RunDetails: RunDetails{
+				Builder:  ProvenanceBuilder{ID: builder},
+				Metadata: ProvenanceMetadata{InvocationID: info.ID},
+			},
+		},
+	}
+
+	for _, a := range artifacts {
+		digest, err := deterministicDigest(a)
+		if err != nil {
+			return nil, err
+		}
+		p.Subject = append(p.Subject, ResourceDescriptor{Name: a.Path(), Digest: digest})
+	}
+	digest, err := deterministicDigest(info)
+	if err != nil {
+		return nil, err
+	}
+	p.Subject = append(p.Subject, ResourceDescriptor{Name: path.Join("build-info", info.ID+".json"), Digest: digest})
+
+	var names []string
+	for name := range info.Input.Sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sum := sha256.Sum256([]byte(info.Input.Sources[name].Content))
+		p.Predicate.BuildDefinition.ResolvedDependencies = append(p.Predicate.BuildDefinition.ResolvedDependencies, ResourceDescriptor{
+			Name:   name,
+			Digest: map[string]string{"sha256": hex.EncodeToString(sum[:])},
+		})
+	}
+	return p, nil
+}
+
+func deterministicDigest(v interface{}) (map[string]string, error) {
+	b, err := types.MarshalDeterministic(v)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(b)
+	return map[string]string{"sha256": hex.EncodeToString(sum[:])}, nil
+}
+
+// Envelope is a DSSE envelope, the signed form of provenance
+type Envelope struct {
+	PayloadType string              `json:"payloadType"`
+	Payload     []byte              `json:"payload"`
+	Signatures  []EnvelopeSignature `json:"signatures"`
+}
+
+type EnvelopeSignature struct {
+	KeyID string `json:"keyid,omitempty"`
+	Sig   []byte `json:"sig"`
+}
+
+// Sign returns the provenance in an envelope signed by key (see Sign for the supported keys)
+func (p *Provenance) Sign(key crypto.Signer, keyID string) (*Envelope, error) {
+	payload, err := types.MarshalDeterministic(p)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := signBytes(pae(InTotoPayloadType, payload), key)
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{
+		PayloadType: InTotoPayloadType,
+		Payload:     payload,
+		Signatures:  []EnvelopeSignature{{KeyID: keyID, Sig: sig}},
+	}, nil
+}
+
+// Verify returns the provenance of the envelope if one of its signatures is by the private key of pub
+func (e *Envelope) Verify(pub crypto.PublicKey) (*Provenance, error) {
+	if e.PayloadType != InTotoPayloadType {
+		return nil, fmt.Errorf("solc: unexpected payload type %q", e.PayloadType)
+	}
+	err := ErrInvalidSignature
+	for _, sig := range e.Signatures {
+		if err = verifyBytes(pae(e.PayloadType, e.Payload), sig.Sig, pub); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Provenance{}
+	err = json.Unmarshal(e.Payload, p)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// pae is the DSSE pre-authentication encoding of a payload, which is what gets signed
+func pae(payloadType string, payload []byte) []byte {
+	return append([]byte(fmt.Sprintf("DSSEv1 %v %v %v ", len(payloadType), payloadType, len(payload))), payload...)
+}
@@ -0,0 +1,56 @@
+package solc
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvenance(t *testing.T) {
+	in := NewInput().AddSource("One.sol", "contract One {}")
+	out := &Output{Contracts: map[string]map[string]Contract{"One.sol": {"One": {EVM: EVM{Bytecode: Bytecode{Object: "6080"}}}}}}
+	info, err := NewBuildInfo("0.6.2+commit.bacdbe57.Emscripten.clang", in, out)
+	require.NoError(t, err, "NewBuildInfo should not error")
+
+	p, err := NewProvenance("https://ci.example.com/builder", info, NewArtifacts(out))
+	require.NoError(t, err, "NewProvenance should not error")
+	assert.Equal(t, InTotoStatementType, p.Type)
+	assert.Equal(t, SLSAProvenanceType, p.PredicateType)
+	require.Len(t, p.Subject, 2, "Artifacts and build-info should be subjects")
+	assert.Equal(t, "One.sol/One.json", p.Subject[0].Name)
+	assert.Equal(t, "build-info/"+info.ID+".json", p.Subject[1].Name)
+
+	digest, err := deterministicDigest(info)
+	require.NoError(t, err)
+	assert.Equal(t, digest, p.Subject[1].Digest, "Subjects should be digested as written")
+
+	definition := p.Predicate.BuildDefinition
+	assert.Equal(t, ProvenanceParameters{SolcVersion: "0.6.2+commit.bacdbe57", InputHash: in.Hash()}, definition.ExternalParameters)
+	assert.Equal(t, []ResourceDescriptor{
+		{Name: "soljson", URI: "https://binaries.soliditylang.org/bin/soljson-v0.6.2+commit.bacdbe57.js"},
+		{Name: "One.sol", Digest: map[string]string{"sha256": "1b81c969b403f7a6856b16b30482c448af71b2b71f6139bddeb8971490886528"}},
+	}, definition.ResolvedDependencies)
+	assert.Equal(t, "https://ci.example.com/builder", p.Predicate.RunDetails.Builder.ID)
+	assert.Equal(t, info.ID, p.Predicate.RunDetails.Metadata.InvocationID)
+
+	pub, key, _ := ed25519.GenerateKey(rand.Reader)
+	envelope, err := p.Sign(key, "ci")
+	require.NoError(t, err, "Sign should not error")
+	b, err := json.Marshal(envelope)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"payloadType":"application/vnd.in-toto+json"`)
+
+	read := &Envelope{}
+	require.NoError(t, json.Unmarshal(b, read))
+	verified, err := read.Verify(pub)
+	require.NoError(t, err, "Envelope should verify")
+	assert.Equal(t, p, verified, "Provenance should be signed")
+
+	read.Payload = append(read.Payload, ' ')
+	_, err = read.Verify(pub)
+	assert.Equal(t, ErrInvalidSignature, err, "Modified payload should not verify")
+}
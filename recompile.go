@@ -0,0 +1,103 @@
+package solc
+
+import (
+	"fmt"
+	"sort"
+)
+
+// RecompileChanged recompiles the build of prev after changes to some of its sources, compiling
+// only the sources affected by the changes and reusing the output of prev for the others
+//
+// changed maps source unit names to their new content, sources mapped to an empty SourceIn are removed.
+// Changed sources, the sources transitively importing them (see ImportGraph.Dependents) and their
+// dependencies are compiled together with the settings of prev, and merged with the output of the
+// other sources, whose errors are kept. Source IDs and source maps are thus relative to the compilation
+// the source comes from. solc must be the version of prev
+func RecompileChanged(solc Solc, prev *BuildInfo, changed map[string]SourceIn) (*BuildInfo, error) {
+	if LongVersion(solc.Version()) != prev.SolcLongVersion {
+		return nil, fmt.Errorf("solc: build of %v can not be recompiled with %v", prev.SolcLongVersion, LongVersion(solc.Version()))
+	}
+
+	in := *prev.Input
+	in.Sources = make(map[string]SourceIn)
+	for name, source := range prev.Input.Sources {
+		in.Sources[name] = source
+	}
+	var names []string
+	for name, source := range changed {
+		if source.Content == "" && len(source.URLs) == 0 {
+			delete(in.Sources, name)
+		} else {
+			in.Sources[name] = source
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rebuild := recompiledSources(&in, names)
+	out := &Output{
+		Sources:   make(map[string]SourceOut),
+		Contracts: make(map[string]map[string]Contract),
+	}
+	reused := func(file string) bool {
+		_, ok := in.Sources[file]
+		return ok && !rebuild[file]
+	}
+
+	if prev.Output != nil {
+		for _, e := range prev.Output.Errors {
+			if reused(e.SourceLocation.File) {
+				out.Errors = append(out.Errors, e)
+			}
+		}
+		for name, source := range prev.Output.Sources {
+			if reused(name) {
+				out.Sources[name] = source
+			}
+		}
+		for file, contracts := range prev.Output.Contracts {
+			if reused(file) {
+				out.Contracts[file] = contracts
+			}
+		}
+	}
+
+	if len(rebuild) > 0 {
+		partial := in
+		partial.Sources = make(map[string]SourceIn)
+		for name := range rebuild {
+			partial.Sources[name] = in.Sources[name]
+		}
+		partialOut, err := solc.Compile(&partial)
+		if err != nil {
+			return nil, err
+		}
+		mergeUnit(out, partialOut)
+	}
+
+	return NewBuildInfo(solc.Version(), &in, out)
+}
+
+// recompiledSources returns the sources of in affected by changes to names, with their dependencies
+func recompiledSources(in *Input, names []string) map[string]bool {
+	g := importGraph(in)
+
+	var affected []string
+	for _, name := range names {
+		if _, ok := in.Sources[name]; ok {
+			affected = append(affected, name)
+		}
+		affected = append(affected, g.Dependents(name)...)
+	}
+
+	rebuild := make(map[string]bool)
+	for _, name := range affected {
+		rebuild[name] = true
+		for _, dep := range g.Dependencies(name) {
+			if _, ok := in.Sources[dep]; ok {
+				rebuild[dep] = true
+			}
+		}
+	}
+	return rebuild
+}
@@ -0,0 +1,70 @@
+package solc
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSolc records the sources of the inputs it compiles
+type recordingSolc struct {
+	Solc
+	compiled [][]string
+}
+
+func (s *recordingSolc) Compile(input *Input) (*Output, error) {
+	var names []string
+	for name := range input.Sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	s.compiled = append(s.compiled, names)
+	return s.Solc.Compile(input)
+}
+
+func TestRecompileChanged(t *testing.T) {
+	compiler, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err, "Creating compiler should not error")
+	defer compiler.Close()
+	solc := &recordingSolc{Solc: compiler}
+
+	in := NewInput().
+		AddSource("A.sol", "pragma solidity ^0.6.0;\nimport \"./L.sol\";\ncontract A is L {}").
+		AddSource("B.sol", "pragma solidity ^0.6.0;\nimport \"./L.sol\";\ncontract B is L {}").
+		AddSource("L.sol", "pragma solidity ^0.6.0;\ncontract L {}").
+		AddSource("C.sol", "pragma solidity ^0.6.0;\ncontract C { function f() public { uint x; } }").
+		SetOutputSelection(SelectAll("evm.bytecode.object"))
+	out, err := compiler.Compile(in)
+	require.NoError(t, err, "Compile should not error")
+	prev, err := NewBuildInfo(compiler.Version(), in, out)
+	require.NoError(t, err, "NewBuildInfo should not error")
+
+	info, err := RecompileChanged(solc, prev, map[string]SourceIn{
+		"A.sol": {Content: "pragma solidity ^0.6.0;\nimport \"./L.sol\";\ncontract A is L { uint a; }"},
+	})
+	require.NoError(t, err, "RecompileChanged should not error")
+	assert.Equal(t, [][]string{{"A.sol", "L.sol"}}, solc.compiled, "Only A.sol and its dependencies should be compiled")
+	assert.Contains(t, info.Input.Sources["A.sol"].Content, "uint a", "Input should hold the change")
+	assert.NotEqual(t, out.Contracts["A.sol"]["A"].EVM.Bytecode.Object, info.Output.Contracts["A.sol"]["A"].EVM.Bytecode.Object, "A should be recompiled")
+	assert.Equal(t, out.Contracts["B.sol"], info.Output.Contracts["B.sol"], "B should be reused")
+	assert.Len(t, info.Output.Contracts, 4)
+	assert.Equal(t, out.Errors, info.Output.Errors, "Warnings of reused sources should be kept")
+
+	solc.compiled = nil
+	info, err = RecompileChanged(solc, info, map[string]SourceIn{"L.sol": {Content: "pragma solidity ^0.6.0;\ncontract L { uint l; }"}})
+	require.NoError(t, err, "RecompileChanged should not error")
+	assert.Equal(t, [][]string{{"A.sol", "B.sol", "L.sol"}}, solc.compiled, "Dependents of L.sol should be compiled")
+
+	solc.compiled = nil
+	info, err = RecompileChanged(solc, info, map[string]SourceIn{"C.sol": {}})
+	require.NoError(t, err, "RecompileChanged should not error")
+	assert.Empty(t, solc.compiled, "Removing a source imported by none should not compile")
+	assert.NotContains(t, info.Input.Sources, "C.sol", "Removed source should leave the input")
+	assert.NotContains(t, info.Output.Contracts, "C.sol", "Removed source should leave the output")
+	assert.Empty(t, info.Output.Errors)
+
+	_, err = RecompileChanged(solc, &BuildInfo{SolcLongVersion: "0.5.9+commit.e560f70d", Input: in}, nil)
+	assert.Error(t, err, "Other version should error")
+}
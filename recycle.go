@@ -0,0 +1,160 @@
+package solc
+
+import (
+	"context"
+	"sync"
+)
+
+// Factory creates compiler instances
+type Factory func() (Solc, error)
+
+// FileFactory returns a Factory creating compilers from a soljson emscripten binary file
+func FileFactory(file string, opts ...Option) Factory {
+	return func() (Solc, error) {
+		return NewFromFile(file, opts...)
+	}
+}
+
+// RecyclePolicy decides when a recycling compiler replaces its underlying instance
+type RecyclePolicy struct {
+	// MaxCompiles is the number of compilations after which the instance is recreated (0 for no limit)
+	MaxCompiles int
+
+	// MaxHeap is the memory usage (see HeapStatistics.Usage) above which the instance is recreated (0 for no limit)
+	MaxHeap uint64
+}
+
+// recyclingSolc transparently disposes and recreates its compiler to bound emscripten memory growth
+type recyclingSolc struct {
+	factory Factory
+	policy  RecyclePolicy
+
+	mux      sync.Mutex
+	current  Solc
+	compiles int
+	restarts int
+	closed   bool
+
+	// retired holds the stats of recycled instances
+	retired *statsRecorder
+
+	version string
+	license string
+}
+
+// NewRecycling creates a compiler that recreates its underlying instance according to policy
+//
+// Instances left unusable by a timeout or over their memory limit are recreated as well
+func NewRecycling(factory Factory, policy RecyclePolicy) (Solc, error) {
+	current, err := factory()
+	if err != nil {
+		return nil, err
+	}
+
+	return &recyclingSolc{
+		factory: factory,
+		policy:  policy,
+		current: current,
+		retired: newStatsRecorder(),
+		version: current.Version(),
+		license: current.License(),
+	}, nil
+}
+
+func (solc *recyclingSolc) License() string {
+	return solc.license
+}
+
+func (solc *recyclingSolc) Version() string {
+	return solc.version
+}
+
+func (solc *recyclingSolc) Compile(input *Input) (*Output, error) {
+	return solc.CompileContext(context.Background(), input)
+}
+
+// CompileContext is like Compile, instances left unhealthy by a compilation (see CompilePolicy) are recreated
+func (solc *recyclingSolc) CompileContext(ctx context.Context, input *Input) (*Output, error) {
+	solc.mux.Lock()
+	defer solc.mux.Unlock()
+
+	current, err := solc.instance()
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := CompileContext(ctx, current, input)
+	if _, ok := err.(*MemoryLimitError); ok {
+		// Retry once on a fresh instance
+		_ = solc.recycle()
+		current, err = solc.instance()
+		if err != nil {
+			return nil, err
+		}
+		out, err = CompileContext(ctx, current, input)
+	}
+	solc.compiles++
+
+	if err == ErrTimeout || err == ErrTerminated || solc.exhausted(current) || Healthy(current) != nil {
+		_ = solc.recycle()
+	}
+
+	return out, err
+}
+
+func (solc *recyclingSolc) HeapStatistics() HeapStatistics {
+	solc.mux.Lock()
+	defer solc.mux.Unlock()
+	if solc.current == nil {
+		return HeapStatistics{}
+	}
+	return solc.current.HeapStatistics()
+}
+
+func (solc *recyclingSolc) Close() error {
+	solc.mux.Lock()
+	defer solc.mux.Unlock()
+	solc.closed = true
+	return solc.recycle()
+}
+
+// instance returns the current instance, creating a new one if it has been recycled
+func (solc *recyclingSolc) instance() (Solc, error) {
+	if solc.closed {
+		return nil, ErrClosed
+	}
+	if solc.current != nil {
+		return solc.current, nil
+	}
+
+	current, err := solc.factory()
+	if err != nil {
+		return nil, err
+	}
+	solc.current = current
+	solc.compiles = 0
+	solc.restarts++
+	solc.retired.restarted()
+
+	return current, nil
+}
+
+func (solc *recyclingSolc) exhausted(current Solc) bool {
+	if solc.policy.MaxCompiles > 0 && solc.compiles >= solc.policy.MaxCompiles {
+		return true
+	}
+	return solc.policy.MaxHeap > 0 && current.HeapStatistics().Usage() > solc.policy.MaxHeap
+}
+
+// recycle disposes the current instance, a new one is created on next use
+func (solc *recyclingSolc) recycle() error {
+	if solc.current == nil {
+		return nil
+	}
+	if c, ok := solc.current.(statsCollector); ok {
+		c.collectStats(solc.retired)
+	}
+	err := solc.current.Close()
+	solc.current = nil
+	return err
+}
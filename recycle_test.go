@@ -0,0 +1,44 @@
+package solc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecycling(t *testing.T) {
+	var created []*fakeSolc
+	factory := func() (Solc, error) {
+		s := &fakeSolc{version: "0.6.2", output: &Output{}}
+		created = append(created, s)
+		return s, nil
+	}
+
+	solc, err := NewRecycling(factory, RecyclePolicy{MaxCompiles: 2})
+	require.NoError(t, err, "NewRecycling should not error")
+	assert.Equal(t, "0.6.2", solc.Version())
+
+	for i := 0; i < 5; i++ {
+		_, err = solc.Compile(&Input{})
+		require.NoError(t, err, "Compile should not error")
+	}
+	require.Len(t, created, 3, "Instance should be recreated every 2 compilations")
+	assert.Equal(t, 2, created[0].compiles)
+	assert.Equal(t, 2, created[1].compiles)
+	assert.Equal(t, 1, created[2].compiles)
+
+	// Timed out instances are replaced
+	created[2].err = ErrTimeout
+	_, err = solc.Compile(&Input{})
+	assert.Equal(t, ErrTimeout, err, "Compile should return the timeout")
+	_, err = solc.Compile(&Input{})
+	assert.NoError(t, err, "Compile should succeed on a fresh instance")
+	assert.Len(t, created, 4, "Timed out instance should be replaced")
+
+	// Instances over their memory limit are replaced and compilation retried
+	created[3].err = &MemoryLimitError{}
+	_, err = solc.Compile(&Input{})
+	assert.NoError(t, err, "Compile should be retried on a fresh instance")
+	assert.Len(t, created, 5, "Instance over memory limit should be replaced")
+}
@@ -0,0 +1,395 @@
+package solc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nmvalera/solc-go/types"
+)
+
+// RegistryConfig configures a Registry
+type RegistryConfig struct {
+	// Dir holds the soljson-v<version>.js binaries, versions missing from it are downloaded into it
+	Dir string
+
+	// PoolSize is the number of instances of each version (1 if <= 0)
+	PoolSize int
+
+	// MaxMemory is the memory usage (see HeapStatistics.Usage) of loaded versions above which the
+	// least recently used idle versions are evicted (0 for no limit)
+	MaxMemory uint64
+
+	// ReleasesURL is where missing versions are downloaded from (DefaultReleasesURL if empty)
+	ReleasesURL string
+
+	// HTTPClient downloads missing versions (http.DefaultClient if nil)
+	HTTPClient *http.Client
+
+	// Offline disables downloads, only the binaries of Dir are loaded
+	Offline bool
+
+	// Options are passed to every compiler instance
+	Options []Option
+}
+
+// Registry holds a pool for each compiler version in use and routes compilations to them
+//
+// Versions are loaded on first use, downloading them if missing from the binaries directory,
+// and evicted when the registry exceeds its memory budget. Registry is safe for concurrent use
+type Registry struct {
+	config RegistryConfig
+
+	mux      sync.Mutex
+	entries  map[string]*registryEntry
+	releases []Release
+	closed   bool
+}
+
+// registryEntry is a loaded (or loading) compiler version
+type registryEntry struct {
+	version string
+
+	// ready is closed once pool or err is set, cancel stops the loading once no request waits for it
+	ready  chan struct{}
+	cancel context.CancelFunc
+	pool   *Pool
+	err    error
+
+	// active is the number of compilations in progress
+	active   int
+	lastUsed time.Time
+	usage    uint64
+}
+
+// NewRegistry creates a registry loading versions according to config
+func NewRegistry(config RegistryConfig) *Registry {
+	if config.PoolSize <= 0 {
+		config.PoolSize = 1
+	}
+	return &Registry{
+		config:  config,
+		entries: make(map[string]*registryEntry),
+	}
+}
+
+type versionKey struct{}
+
+// ContextWithVersion returns a context whose compilations are routed by registries to the highest version
+// satisfying constraint (e.g. "0.6.2", "0.6.2+commit.bacdbe57", "^0.6.0"), and rejected by pools of other versions
+func ContextWithVersion(ctx context.Context, constraint string) context.Context {
+	return context.WithValue(ctx, versionKey{}, constraint)
+}
+
+func versionFromContext(ctx context.Context) string {
+	constraint, _ := ctx.Value(versionKey{}).(string)
+	return constraint
+}
+
+// VersionMismatchError is returned by a pool compiling for a version constraint it does not satisfy (see ContextWithVersion)
+type VersionMismatchError struct {
+	Constraint string
+	Version    string
+}
+
+func (e *VersionMismatchError) Error() string {
+	return fmt.Sprintf("solc: version %v does not satisfy %q", e.Version, e.Constraint)
+}
+
+// checkVersion checks version satisfies the version constraint of ctx, if any
+func checkVersion(ctx context.Context, version string) error {
+	constraint := versionFromContext(ctx)
+	if constraint == "" {
+		return nil
+	}
+	matched, err := MatchVersion(constraint, version)
+	if err != nil {
+		return err
+	}
+	if !matched {
+		return &VersionMismatchError{Constraint: constraint, Version: version}
+	}
+	return nil
+}
+
+// Compile compiles input with the highest version satisfying constraint, or the version constraint of ctx
+// (see ContextWithVersion) or else the version pragmas of the sources if constraint is empty
+//
+// Versions already in the binaries directory are preferred over newer releases, missing ones are downloaded
+// before compiling
+func (r *Registry) Compile(ctx context.Context, constraint string, input *Input) (*Output, error) {
+	if constraint == "" {
+		constraint = versionFromContext(ctx)
+	}
+	constraints := []string{constraint}
+	if constraint == "" {
+		constraints = VersionPragmas(input.Sources)
+	}
+
+	entry, err := r.acquire(ctx, constraints)
+	if err != nil {
+		return nil, err
+	}
+	defer r.release(entry)
+
+	// Already routed, an explicit constraint overrides the one of ctx
+	return entry.pool.CompileContext(ContextWithVersion(ctx, ""), input)
+}
+
+// CompileContext compiles input with the version constraint of ctx (see ContextWithVersion), or the version
+// pragmas of the sources if it has none, so a registry routes compilations like CompileContext
+func (r *Registry) CompileContext(ctx context.Context, input *Input) (*Output, error) {
+	return r.Compile(ctx, "", input)
+}
+
+// Load loads the highest version satisfying constraint, if not loaded yet, and returns its long version
+//
+// Requests for a version being loaded wait for the same download, which is cancelled once the contexts of
+// all of them are done
+func (r *Registry) Load(ctx context.Context, constraint string) (string, error) {
+	entry, err := r.acquire(ctx, []string{constraint})
+	if err != nil {
+		return "", err
+	}
+	r.release(entry)
+	return entry.version, nil
+}
+
+// Versions returns the long versions currently loaded, sorted
+func (r *Registry) Versions() []string {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	versions := []string{}
+	for version, entry := range r.entries {
+		if entry.pool != nil {
+			versions = append(versions, version)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versionLess(versions[i], versions[j])
+	})
+	return versions
+}
+
+// Close closes the pools of every loaded version (see Pool.Close)
+func (r *Registry) Close() error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+
+	var err error
+	for version, entry := range r.entries {
+		if entry.pool != nil {
+			if closeErr := entry.pool.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		delete(r.entries, version)
+	}
+	return err
+}
+
+// acquire returns the loaded entry of the version satisfying constraints, loading it if needed
+func (r *Registry) acquire(ctx context.Context, constraints []string) (*registryEntry, error) {
+	binary, release, err := r.resolve(ctx, constraints)
+	if err != nil {
+		return nil, err
+	}
+	version := binary.Version
+	if version == "" {
+		version = release.LongVersion
+	}
+
+	r.mux.Lock()
+	if r.closed {
+		r.mux.Unlock()
+		return nil, ErrClosed
+	}
+	entry, ok := r.entries[version]
+	if !ok {
+		loadCtx, cancel := context.WithCancel(context.Background())
+		entry = &registryEntry{version: version, ready: make(chan struct{}), cancel: cancel}
+		r.entries[version] = entry
+		go r.load(loadCtx, entry, binary, release)
+	}
+	entry.active++
+	r.mux.Unlock()
+
+	select {
+	case <-entry.ready:
+	case <-ctx.Done():
+		r.release(entry)
+		return nil, ctx.Err()
+	}
+	if entry.err != nil {
+		r.release(entry)
+		return nil, entry.err
+	}
+	return entry, nil
+}
+
+// load downloads the binary of entry if needed and creates its pool
+//
+// ctx is not the context of the request loading it, as other requests may wait for it, but is cancelled
+// once the last of them stops waiting
+func (r *Registry) load(ctx context.Context, entry *registryEntry, binary Binary, release Release) {
+	defer close(entry.ready)
+	defer entry.cancel()
+
+	if binary.Path == "" {
+		binary, entry.err = r.download(ctx, release)
+		if entry.err != nil {
+			r.forget(entry)
+			return
+		}
+	}
+
+	pool, err := NewPool(FileFactory(binary.Path, r.config.Options...), r.config.PoolSize)
+	if err != nil {
+		entry.err = err
+		r.forget(entry)
+		return
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	switch {
+	case r.closed:
+		pool.Close()
+		entry.err = ErrClosed
+	case r.entries[entry.version] != entry:
+		// No request waits for the entry anymore
+		pool.Close()
+		entry.err = context.Canceled
+	default:
+		entry.pool = pool
+	}
+}
+
+// download downloads release into the binaries directory, emitting EventDownload to the logger of the
+// compiler options and tracing it as SpanDownload
+func (r *Registry) download(ctx context.Context, release Release) (Binary, error) {
+	opts, err := newOptions(r.config.Options...)
+	if err != nil {
+		return Binary{}, err
+	}
+	ctx, span := opts.startSpan(ctx, SpanDownload)
+	span.SetAttribute(AttrVersion, release.LongVersion)
+
+	start := time.Now()
+	binary, err := DownloadRelease(ctx, r.config.HTTPClient, r.config.ReleasesURL, release, r.config.Dir)
+	e := Event{Kind: EventDownload, Version: release.LongVersion, Duration: time.Since(start), Err: err}
+	if err == nil {
+		if info, statErr := os.Stat(binary.Path); statErr == nil {
+			e.Bytes = info.Size()
+			span.SetAttribute(AttrDownloadBytes, e.Bytes)
+		}
+	}
+	opts.emit(e)
+	span.End(err)
+	return binary, err
+}
+
+// forget removes a failed entry so the version can be loaded again
+func (r *Registry) forget(entry *registryEntry) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if r.entries[entry.version] == entry {
+		delete(r.entries, entry.version)
+	}
+}
+
+// release marks the end of a use of entry, measuring its memory and evicting versions over budget
+func (r *Registry) release(entry *registryEntry) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	entry.active--
+	entry.lastUsed = time.Now()
+	if entry.pool == nil && entry.active == 0 {
+		// Stop loading a version no request waits for anymore, so it is loaded again on next use
+		entry.cancel()
+		if r.entries[entry.version] == entry {
+			delete(r.entries, entry.version)
+		}
+		return
+	}
+	if entry.pool == nil || entry.active > 0 || r.closed {
+		return
+	}
+
+	// Idle pools are measured without waiting on a compilation
+	entry.usage = entry.pool.HeapStatistics().Usage()
+	r.evict(entry)
+}
+
+// evict closes the least recently used idle versions, except keep, while usage exceeds the budget
+func (r *Registry) evict(keep *registryEntry) {
+	if r.config.MaxMemory == 0 {
+		return
+	}
+	for {
+		var total uint64
+		var lru *registryEntry
+		for _, entry := range r.entries {
+			total += entry.usage
+			if entry != keep && entry.pool != nil && entry.active == 0 && (lru == nil || entry.lastUsed.Before(lru.lastUsed)) {
+				lru = entry
+			}
+		}
+		if total <= r.config.MaxMemory || lru == nil {
+			return
+		}
+		lru.pool.Close()
+		delete(r.entries, lru.version)
+	}
+}
+
+// resolve returns the binary of Dir satisfying constraints, or the release to download if there is none
+func (r *Registry) resolve(ctx context.Context, constraints []string) (Binary, Release, error) {
+	binary, err := ResolveBinary(r.config.Dir, constraints...)
+	if err == nil || r.config.Offline {
+		return binary, Release{}, err
+	}
+
+	releases, err := r.listReleases(ctx)
+	if err != nil {
+		return Binary{}, Release{}, err
+	}
+	for i := len(releases) - 1; i >= 0; i-- {
+		matched, err := types.MatchVersions(constraints, releases[i].Version)
+		if err != nil {
+			return Binary{}, Release{}, err
+		}
+		if matched {
+			return Binary{}, releases[i], nil
+		}
+	}
+	return Binary{}, Release{}, &NoVersionError{Constraints: constraints}
+}
+
+// listReleases returns the releases, fetched once
+func (r *Registry) listReleases(ctx context.Context) ([]Release, error) {
+	r.mux.Lock()
+	releases := r.releases
+	r.mux.Unlock()
+	if releases != nil {
+		return releases, nil
+	}
+
+	releases, err := Releases(ctx, r.config.HTTPClient, r.config.ReleasesURL)
+	if err != nil {
+		return nil, err
+	}
+	r.mux.Lock()
+	r.releases = releases
+	r.mux.Unlock()
+	return releases, nil
+}
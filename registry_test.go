@@ -0,0 +1,163 @@
+package solc
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(RegistryConfig{Dir: "./solc-bin", Offline: true})
+	defer r.Close()
+
+	out, err := r.Compile(context.Background(), "", NewInput().AddSource("One.sol", "pragma solidity ^0.5.2; contract One {}"))
+	require.NoError(t, err, "Compile should not error")
+	assert.Empty(t, out.Errors, "One.sol should compile with 0.5.9")
+
+	out, err = r.Compile(context.Background(), "0.6.2", NewInput().AddSource("One.sol", "pragma solidity ^0.6.1; contract One {}"))
+	require.NoError(t, err, "Compile should not error")
+	assert.Empty(t, out.Errors, "One.sol should compile with 0.6.2")
+	assert.Equal(t, []string{"0.5.9+commit.e560f70d", "0.6.2+commit.bacdbe57"}, r.Versions(), "Both versions should be loaded")
+
+	_, err = r.Compile(context.Background(), "^0.7.0", NewInput().AddSource("One.sol", "contract One {}"))
+	assert.Error(t, err, "Missing version should error offline")
+
+	require.NoError(t, r.Close(), "Close should not error")
+	assert.Empty(t, r.Versions(), "Close should unload versions")
+	_, err = r.Load(context.Background(), "0.6.2")
+	assert.Equal(t, ErrClosed, err, "Load should error once closed")
+}
+
+func TestRegistryContextVersion(t *testing.T) {
+	r := NewRegistry(RegistryConfig{Dir: "./solc-bin", Offline: true})
+	defer r.Close()
+	input := NewInput().AddSource("One.sol", "contract One {}")
+
+	_, err := r.CompileContext(ContextWithVersion(context.Background(), "0.5.9"), input)
+	require.NoError(t, err, "CompileContext should not error")
+	assert.Equal(t, []string{"0.5.9+commit.e560f70d"}, r.Versions(), "Version of the context should be loaded")
+
+	_, err = r.Compile(ContextWithVersion(context.Background(), "0.5.9"), "^0.6.0", input)
+	require.NoError(t, err, "Explicit constraint should override the one of the context")
+	assert.Equal(t, []string{"0.5.9+commit.e560f70d", "0.6.2+commit.bacdbe57"}, r.Versions())
+
+	_, err = r.CompileContext(ContextWithVersion(context.Background(), "^0.7.0"), input)
+	assert.Error(t, err, "Missing version should error offline")
+}
+
+func TestPoolContextVersion(t *testing.T) {
+	p, _ := newEchoPool(t, 1, 0)
+	defer p.Close()
+
+	_, err := p.CompileContext(ContextWithVersion(context.Background(), p.Version()), NewInput())
+	assert.NoError(t, err, "Version of the pool should compile")
+
+	_, err = p.CompileContext(ContextWithVersion(context.Background(), "^0.4.0"), NewInput())
+	assert.IsType(t, &VersionMismatchError{}, err, "Other version should be rejected")
+}
+
+func TestRegistryEviction(t *testing.T) {
+	r := NewRegistry(RegistryConfig{Dir: "./solc-bin", Offline: true, MaxMemory: 1})
+	defer r.Close()
+
+	version, err := r.Load(context.Background(), "^0.5.0")
+	require.NoError(t, err, "Load should not error")
+	assert.Equal(t, "0.5.9+commit.e560f70d", version)
+	_, err = r.Load(context.Background(), "^0.6.0")
+	require.NoError(t, err, "Load should not error")
+	assert.Equal(t, []string{"0.6.2+commit.bacdbe57"}, r.Versions(), "Least recently used version should be evicted over budget")
+}
+
+func TestRegistryDownload(t *testing.T) {
+	soljson, err := ioutil.ReadFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err)
+	downloads := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/list.json":
+			w.Write([]byte(`{"builds": [{"path": "soljson-v0.6.2+commit.bacdbe57.js", "version": "0.6.2", "longVersion": "0.6.2+commit.bacdbe57"}]}`))
+		case "/soljson-v0.6.2+commit.bacdbe57.js":
+			downloads++
+			w.Write(soljson)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "solc-registry")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	logger, tracer := &recordingLogger{}, &recordingTracer{}
+	r := NewRegistry(RegistryConfig{Dir: dir, ReleasesURL: srv.URL, Options: []Option{WithLogger(logger), WithTracer(tracer)}})
+	defer r.Close()
+
+	version, err := r.Load(context.Background(), "^0.6.0")
+	require.NoError(t, err, "Load should download the missing version")
+	assert.Equal(t, "0.6.2+commit.bacdbe57", version)
+	_, err = r.Load(context.Background(), "0.6.2")
+	require.NoError(t, err, "Load should not error")
+	assert.Equal(t, 1, downloads, "Loaded version should not be downloaded again")
+
+	require.NotEmpty(t, logger.events)
+	e := logger.events[0]
+	assert.Equal(t, EventDownload, e.Kind, "Download should be emitted")
+	assert.Equal(t, "0.6.2+commit.bacdbe57", e.Version)
+	assert.Equal(t, int64(len(soljson)), e.Bytes)
+	assert.NoError(t, e.Err)
+	require.NotEmpty(t, tracer.spans)
+	assert.Equal(t, SpanDownload, tracer.spans[0].name, "Download should be traced")
+	assert.True(t, tracer.spans[0].ended)
+	assert.Equal(t, int64(len(soljson)), tracer.spans[0].attrs[AttrDownloadBytes])
+
+	binaries, err := ListBinaries(dir)
+	require.NoError(t, err)
+	assert.Len(t, binaries, 1, "Downloaded binary should be kept in the binaries directory")
+}
+
+func TestRegistryDownloadCancel(t *testing.T) {
+	cancelled := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/list.json":
+			w.Write([]byte(`{"builds": [{"path": "soljson-v0.6.2+commit.bacdbe57.js", "version": "0.6.2", "longVersion": "0.6.2+commit.bacdbe57"}]}`))
+		default:
+			<-r.Context().Done()
+			close(cancelled)
+		}
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "solc-registry")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	logger := &recordingLogger{}
+	r := NewRegistry(RegistryConfig{Dir: dir, ReleasesURL: srv.URL, Options: []Option{WithLogger(logger)}})
+	defer r.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = r.Load(ctx, "0.6.2")
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Download should be cancelled once no request waits for it")
+	}
+	assert.Empty(t, r.Versions())
+	assert.Eventually(t, func() bool {
+		logger.mux.Lock()
+		defer logger.mux.Unlock()
+		return len(logger.events) == 1 && logger.events[0].Kind == EventDownload && logger.events[0].Err != nil
+	}, 5*time.Second, 10*time.Millisecond, "Failed download should be emitted")
+}
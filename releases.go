@@ -0,0 +1,157 @@
+package solc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultReleasesURL is the base URL of the official soljson builds
+const DefaultReleasesURL = "https://binaries.soliditylang.org/bin"
+
+// Release is a soljson build listed in <baseURL>/list.json
+type Release struct {
+	Path        string `json:"path"`
+	Version     string `json:"version"`
+	Prerelease  string `json:"prerelease,omitempty"`
+	LongVersion string `json:"longVersion"`
+	SHA256      string `json:"sha256"`
+}
+
+// Releases returns the builds listed at baseURL (DefaultReleasesURL if empty), excluding prereleases, sorted by version
+//
+// client may be nil to use http.DefaultClient
+func Releases(ctx context.Context, client *http.Client, baseURL string) ([]Release, error) {
+	body, err := fetch(ctx, client, releaseURL(baseURL, "list.json"))
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var list struct {
+		Builds []Release `json:"builds"`
+	}
+	err = json.NewDecoder(body).Decode(&list)
+	if err != nil {
+		return nil, err
+	}
+
+	var releases []Release
+	for _, build := range list.Builds {
+		if build.Prerelease != "" {
+			continue
+		}
+		if !completeVersion(build.Version) {
+			continue
+		}
+		releases = append(releases, build)
+	}
+	sort.Slice(releases, func(i, j int) bool {
+		return versionLess(releases[i].Version, releases[j].Version)
+	})
+
+	return releases, nil
+}
+
+// ResolveRelease returns the release with the highest version satisfying constraint (see MatchVersion)
+func ResolveRelease(releases []Release, constraint string) (Release, error) {
+	versions := make([]string, len(releases))
+	for i, release := range releases {
+		versions[i] = release.Version
+	}
+	version, err := ResolveVersion(constraint, versions)
+	if err != nil {
+		return Release{}, err
+	}
+	for _, release := range releases {
+		if release.Version == version {
+			return release, nil
+		}
+	}
+	return Release{}, nil
+}
+
+// DownloadRelease downloads release from baseURL into dir after verifying its checksum
+//
+// The binary is named soljson-v<long version>.js so it is found by ListBinaries
+func DownloadRelease(ctx context.Context, client *http.Client, baseURL string, release Release, dir string) (Binary, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return Binary{}, err
+	}
+
+	body, err := fetch(ctx, client, releaseURL(baseURL, release.Path))
+	if err != nil {
+		return Binary{}, err
+	}
+	defer body.Close()
+
+	// Write to a temporary file first so an interrupted download never looks like a valid binary
+	tmp, err := ioutil.TempFile(dir, "soljson.*.tmp")
+	if err != nil {
+		return Binary{}, err
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tmp, h), body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return Binary{}, err
+	}
+
+	if sum := hex.EncodeToString(h.Sum(nil)); release.SHA256 != "" && sum != strings.TrimPrefix(release.SHA256, "0x") {
+		return Binary{}, fmt.Errorf("solc: checksum mismatch for %v: expected %v, got %v", release.Path, release.SHA256, sum)
+	}
+
+	binary := Binary{
+		Version: release.LongVersion,
+		Path:    filepath.Join(dir, fmt.Sprintf("soljson-v%v.js", release.LongVersion)),
+	}
+	err = os.Rename(tmp.Name(), binary.Path)
+	if err != nil {
+		return Binary{}, err
+	}
+
+	return binary, nil
+}
+
+func releaseURL(baseURL, path string) string {
+	if baseURL == "" {
+		baseURL = DefaultReleasesURL
+	}
+	return strings.TrimSuffix(baseURL, "/") + "/" + path
+}
+
+func fetch(ctx context.Context, client *http.Client, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, fmt.Errorf("solc: fetching %v: %v", url, res.Status)
+	}
+
+	return res.Body, nil
+}
@@ -0,0 +1,59 @@
+package solc
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleases(t *testing.T) {
+	soljson := "soljson content"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/list.json":
+			fmt.Fprint(w, `{"builds": [
+				{"path": "soljson-v0.6.2+commit.bacdbe57.js", "version": "0.6.2", "longVersion": "0.6.2+commit.bacdbe57", "sha256": "0xa88efe031d20dd81c00e3cfff7582bfe66b7aad547729d474eae67eba5e4a558"},
+				{"path": "soljson-v0.7.0-nightly.2020.6.8+commit.3a8a2a4b.js", "version": "0.7.0", "prerelease": "nightly.2020.6.8", "longVersion": "0.7.0-nightly.2020.6.8+commit.3a8a2a4b"},
+				{"path": "soljson-v0.5.9+commit.e560f70d.js", "version": "0.5.9", "longVersion": "0.5.9+commit.e560f70d", "sha256": "0x00"}
+			]}`)
+		case "/soljson-v0.6.2+commit.bacdbe57.js", "/soljson-v0.5.9+commit.e560f70d.js":
+			fmt.Fprint(w, soljson)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	releases, err := Releases(context.Background(), nil, srv.URL)
+	require.NoError(t, err, "Releases should not error")
+	require.Len(t, releases, 2, "Prereleases should be excluded")
+	assert.Equal(t, "0.5.9", releases[0].Version, "Releases should be sorted by version")
+
+	release, err := ResolveRelease(releases, "^0.6.0")
+	require.NoError(t, err, "ResolveRelease should not error")
+	assert.Equal(t, "0.6.2+commit.bacdbe57", release.LongVersion)
+
+	dir, err := ioutil.TempDir("", "solc-releases")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	binary, err := DownloadRelease(context.Background(), nil, srv.URL, release, dir)
+	require.NoError(t, err, "DownloadRelease should not error")
+	b, err := ioutil.ReadFile(binary.Path)
+	require.NoError(t, err, "Binary should be written")
+	assert.Equal(t, soljson, string(b))
+
+	_, err = DownloadRelease(context.Background(), nil, srv.URL, releases[0], dir)
+	assert.Error(t, err, "DownloadRelease should error on checksum mismatch")
+
+	binaries, err := ListBinaries(dir)
+	require.NoError(t, err, "ListBinaries should not error")
+	assert.Equal(t, []Binary{binary}, binaries, "Only verified binaries should be kept")
+}
@@ -0,0 +1,106 @@
+package solc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/nmvalera/solc-go/remote"
+)
+
+// RemoteError is an error returned by a remote compile service
+type RemoteError = remote.Error
+
+// remoteSolc adds the options of the solc package to a remote client
+type remoteSolc struct {
+	*remote.Client
+	opts *options
+
+	mux    sync.Mutex
+	closed bool
+}
+
+// NewRemote creates a compiler delegating compilations to the compile service at rawurl
+//
+// The version is selected with a version query parameter (e.g. http://solc:8080?version=0.6.2),
+// it can be omitted if the service serves a single version. WithHTTPClient, WithAPIKey, WithTracer
+// and WithCache apply to remote compilers, other options are ignored
+//
+// Binaries compiling remotely only should use package remote, which does not link v8
+func NewRemote(rawurl string, opts ...Option) (Solc, error) {
+	o, err := newOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteOpts := []remote.Option{remote.WithHTTPClient(o.httpClient)}
+	if o.apiKey != "" {
+		remoteOpts = append(remoteOpts, remote.WithAPIKey(o.apiKeyHeader, o.apiKey))
+	}
+	client, err := remote.New(rawurl, remoteOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &remoteSolc{Client: client, opts: o}, nil
+}
+
+func (solc *remoteSolc) Compile(input *Input) (*Output, error) {
+	return solc.CompileContext(context.Background(), input)
+}
+
+func (solc *remoteSolc) CompileContext(ctx context.Context, input *Input) (*Output, error) {
+	if solc.isClosed() {
+		return nil, ErrClosed
+	}
+
+	ctx, span := solc.opts.startSpan(ctx, SpanCompile)
+	span.SetAttribute(AttrVersion, solc.Version())
+	span.SetAttribute(AttrSources, len(input.Sources))
+
+	if len(solc.opts.analyzers) > 0 {
+		input = withAST(input)
+	}
+
+	var (
+		compiler = remoteCompiler{solc, ctx}
+		out      *Output
+		err      error
+	)
+	if solc.opts.cache != nil {
+		out, _, err = compileCached(solc.opts.cache, compiler, input)
+	} else {
+		out, err = compiler.Compile(input)
+	}
+	if err == nil && len(solc.opts.analyzers) > 0 {
+		out, err = solc.opts.analyze(out)
+	}
+	span.End(err)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// Close closes the client, outputs are no longer served from cache either
+func (solc *remoteSolc) Close() error {
+	solc.mux.Lock()
+	defer solc.mux.Unlock()
+	solc.closed = true
+	return solc.Client.Close()
+}
+
+func (solc *remoteSolc) isClosed() bool {
+	solc.mux.Lock()
+	defer solc.mux.Unlock()
+	return solc.closed
+}
+
+// remoteCompiler binds a context to a remote compiler
+type remoteCompiler struct {
+	*remoteSolc
+	ctx context.Context
+}
+
+func (c remoteCompiler) Compile(input *Input) (*Output, error) {
+	return c.Client.CompileContext(c.ctx, input)
+}
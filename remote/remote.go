@@ -0,0 +1,191 @@
+// Package remote delegates compilations to a compile service (see package server), it does not depend
+// on the v8 binding of package solc so binaries compiling remotely only do not link v8
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/nmvalera/solc-go/types"
+)
+
+// DefaultAPIKeyHeader is the header WithAPIKey sends keys in when given none, server.APIKey reads it by default
+const DefaultAPIKeyHeader = "X-API-Key"
+
+// Error is an error returned by a compile service
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("solc: remote compilation failed (%v): %v", e.StatusCode, e.Message)
+}
+
+// Option configures a Client
+type Option func(c *Client) error
+
+// WithHTTPClient sets the client requests are sent with (http.DefaultClient by default)
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) error {
+		c.httpClient = client
+		return nil
+	}
+}
+
+// WithAPIKey authenticates requests with key, sent in header (DefaultAPIKeyHeader if empty)
+func WithAPIKey(header, key string) Option {
+	return func(c *Client) error {
+		if key == "" {
+			return fmt.Errorf("solc: empty API key")
+		}
+		if header == "" {
+			header = DefaultAPIKeyHeader
+		}
+		c.apiKeyHeader, c.apiKey = header, key
+		return nil
+	}
+}
+
+// Client is a compiler (types.Solc) delegating compilations to a compile service
+type Client struct {
+	url     string
+	version string
+
+	httpClient   *http.Client
+	apiKeyHeader string
+	apiKey       string
+
+	mux    sync.Mutex
+	closed bool
+}
+
+// New creates a client of the compile service at rawurl
+//
+// The version is selected with a version query parameter (e.g. http://solc:8080?version=0.6.2),
+// it can be omitted if the service serves a single version
+func New(rawurl string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	version := u.Query().Get("version")
+	u.RawQuery = ""
+	c := &Client{url: strings.TrimSuffix(u.String(), "/"), httpClient: http.DefaultClient}
+	for _, opt := range opts {
+		err = opt(c)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var res struct {
+		Versions []string `json:"versions"`
+	}
+	err = c.do(context.Background(), http.MethodGet, "/versions", nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range res.Versions {
+		if version == "" && len(res.Versions) == 1 || version == v || version == types.ShortVersion(v) {
+			c.version = v
+		}
+	}
+	if c.version == "" {
+		return nil, fmt.Errorf("solc: version %q not served by %v, available versions are %v", version, c.url, res.Versions)
+	}
+
+	return c, nil
+}
+
+func (c *Client) License() string {
+	return ""
+}
+
+func (c *Client) Version() string {
+	return c.version
+}
+
+func (c *Client) Compile(input *types.Input) (*types.Output, error) {
+	return c.CompileContext(context.Background(), input)
+}
+
+// CompileContext is like Compile, the compile request is canceled with ctx
+func (c *Client) CompileContext(ctx context.Context, input *types.Input) (*types.Output, error) {
+	if c.isClosed() {
+		return nil, types.ErrClosed
+	}
+
+	out := &types.Output{}
+	err := c.do(ctx, http.MethodPost, "/compile?version="+url.QueryEscape(c.version), input, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *Client) HeapStatistics() types.HeapStatistics {
+	return types.HeapStatistics{}
+}
+
+func (c *Client) Close() error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.closed = true
+	return nil
+}
+
+func (c *Client) isClosed() bool {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.closed
+}
+
+func (c *Client) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body bytes.Buffer
+	if in != nil {
+		err := json.NewEncoder(&body).Encode(in)
+		if err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, c.url+path, &body)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set(c.apiKeyHeader, c.apiKey)
+	}
+
+	client := c.httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		var e struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(res.Body).Decode(&e)
+		if res.StatusCode == http.StatusGatewayTimeout {
+			return types.ErrTimeout
+		}
+		return &Error{StatusCode: res.StatusCode, Message: e.Error}
+	}
+
+	return json.NewDecoder(res.Body).Decode(out)
+}
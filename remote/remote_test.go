@@ -0,0 +1,104 @@
+package remote
+
+import (
+	"encoding/json"
+	"go/build"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nmvalera/solc-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newService(t *testing.T, key string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if key != "" && r.Header.Get("X-Key") != key {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid API key"})
+			return
+		}
+		switch r.URL.Path {
+		case "/versions":
+			json.NewEncoder(w).Encode(map[string][]string{"versions": {"0.5.9+commit.e560f70d", "0.6.2+commit.bacdbe57"}})
+		case "/compile":
+			in := &types.Input{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(in))
+			if r.URL.Query().Get("version") != "0.6.2+commit.bacdbe57" {
+				w.WriteHeader(http.StatusGatewayTimeout)
+				return
+			}
+			out := &types.Output{Contracts: map[string]map[string]types.Contract{}}
+			for name := range in.Sources {
+				out.Contracts[name] = map[string]types.Contract{"C": {Metadata: r.URL.Query().Get("version")}}
+			}
+			json.NewEncoder(w).Encode(out)
+		}
+	}))
+}
+
+func TestClient(t *testing.T) {
+	srv := newService(t, "")
+	defer srv.Close()
+
+	_, err := New(srv.URL)
+	assert.Error(t, err, "Version should be required when several are served")
+
+	c, err := New(srv.URL+"?version=0.6.2", WithHTTPClient(srv.Client()))
+	require.NoError(t, err, "New should not error")
+	assert.Equal(t, "0.6.2+commit.bacdbe57", c.Version())
+
+	out, err := c.Compile(types.NewInput().AddSource("A.sol", "contract C {}"))
+	require.NoError(t, err, "Compile should not error")
+	assert.Equal(t, "0.6.2+commit.bacdbe57", out.Contracts["A.sol"]["C"].Metadata)
+
+	c, err = New(srv.URL + "?version=0.5.9")
+	require.NoError(t, err, "New should not error")
+	_, err = c.Compile(types.NewInput())
+	assert.Equal(t, types.ErrTimeout, err, "Timeouts should be reported")
+
+	require.NoError(t, c.Close())
+	_, err = c.Compile(types.NewInput())
+	assert.Equal(t, types.ErrClosed, err, "Compile should error once closed")
+}
+
+func TestClientAPIKey(t *testing.T) {
+	srv := newService(t, "secret")
+	defer srv.Close()
+
+	_, err := New(srv.URL + "?version=0.6.2")
+	require.IsType(t, &Error{}, err, "Requests without key should be rejected")
+	assert.Equal(t, http.StatusUnauthorized, err.(*Error).StatusCode)
+	assert.Equal(t, "solc: remote compilation failed (401): invalid API key", err.Error())
+
+	c, err := New(srv.URL+"?version=0.6.2", WithAPIKey("X-Key", "secret"))
+	require.NoError(t, err, "New should not error with a valid key")
+	_, err = c.Compile(types.NewInput().AddSource("A.sol", "contract C {}"))
+	assert.NoError(t, err, "Compile should send the key")
+
+	_, err = New(srv.URL, WithAPIKey("", ""))
+	assert.Error(t, err, "Empty keys should error")
+}
+
+func TestNoV8(t *testing.T) {
+	seen := make(map[string]bool)
+	var walk func(path string)
+	walk = func(path string) {
+		if seen[path] {
+			return
+		}
+		seen[path] = true
+		pkg, err := build.Import(path, ".", 0)
+		require.NoError(t, err, "Importing %v should not error", path)
+		for _, imp := range pkg.Imports {
+			assert.NotEqual(t, "rogchap.com/v8go", imp, "%v should not import v8", path)
+			if strings.HasPrefix(imp, "github.com/nmvalera/solc-go") {
+				walk(imp)
+			}
+		}
+	}
+	walk("github.com/nmvalera/solc-go/remote")
+	assert.False(t, seen["github.com/nmvalera/solc-go"], "remote should not depend on package solc")
+}
@@ -0,0 +1,197 @@
+package solc
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/nmvalera/solc-go/ast"
+)
+
+// MaxCodeSize is the maximum size of deployed bytecode (EIP-170)
+const MaxCodeSize = 24576
+
+// MaxInitCodeSize is the maximum size of creation bytecode (EIP-3860)
+const MaxInitCodeSize = 2 * MaxCodeSize
+
+// ContractSize is the bytecode size of a contract
+type ContractSize struct {
+	// Contract is the fully qualified name of the contract (path:Name)
+	Contract string `json:"contract"`
+
+	// Size and InitSize are the sizes in bytes of the deployed and creation bytecodes
+	Size     int `json:"size"`
+	InitSize int `json:"initSize"`
+}
+
+// Margin returns the bytes left before reaching MaxCodeSize (negative if exceeded)
+func (s ContractSize) Margin() int {
+	return MaxCodeSize - s.Size
+}
+
+// NewSizeReport returns the sizes of the contracts of out having bytecode, sorted by name
+//
+// Sizes require the evm.bytecode.object and evm.deployedBytecode.object outputs
+func NewSizeReport(out *Output) []ContractSize {
+	var report []ContractSize
+	for file, contracts := range out.Contracts {
+		for name, c := range contracts {
+			if c.EVM.Bytecode.Object == "" {
+				continue
+			}
+			// Unlinked library placeholders have the size of the addresses they stand for
+			report = append(report, ContractSize{
+				Contract: ContractID{File: file, Name: name}.String(),
+				Size:     len(c.EVM.DeployedBytecode.Object) / 2,
+				InitSize: len(c.EVM.Bytecode.Object) / 2,
+			})
+		}
+	}
+	sort.Slice(report, func(i, j int) bool { return report[i].Contract < report[j].Contract })
+	return report
+}
+
+// GasUnbounded is the gas estimate of executions with unbounded cost (e.g. loops over storage)
+const GasUnbounded = "infinite"
+
+// ContractGas is the gas estimates of a contract
+type ContractGas struct {
+	// Contract is the fully qualified name of the contract (path:Name)
+	Contract string `json:"contract"`
+
+	// Deployment is the total creation cost (code deposit and constructor execution)
+	Deployment string `json:"deployment"`
+
+	// Functions are the estimates of external functions, sorted by signature
+	Functions []FunctionGas `json:"functions"`
+}
+
+// FunctionGas is the gas estimate of a function, GasUnbounded if unbounded
+type FunctionGas struct {
+	Signature string `json:"signature"`
+	Gas       string `json:"gas"`
+}
+
+// Exceeds indicates whether the estimate is unbounded or above limit
+func (f FunctionGas) Exceeds(limit uint64) bool {
+	gas, err := strconv.ParseUint(f.Gas, 10, 64)
+	return err != nil || gas > limit
+}
+
+// NewGasReport returns the gas estimates of the contracts of out having estimates, sorted by name
+//
+// Estimates require the evm.gasEstimates output
+func NewGasReport(out *Output) []ContractGas {
+	var report []ContractGas
+	for file, contracts := range out.Contracts {
+		for name, c := range contracts {
+			estimates := c.EVM.GasEstimates
+			if estimates == nil {
+				continue
+			}
+			contract := ContractGas{
+				Contract:   ContractID{File: file, Name: name}.String(),
+				Deployment: estimates["creation"]["totalCost"],
+				Functions:  []FunctionGas{},
+			}
+			for signature, gas := range estimates["external"] {
+				// The fallback function is estimated with an empty signature
+				if signature == "" {
+					signature = "fallback()"
+				}
+				contract.Functions = append(contract.Functions, FunctionGas{Signature: signature, Gas: gas})
+			}
+			sort.Slice(contract.Functions, func(i, j int) bool {
+				return contract.Functions[i].Signature < contract.Functions[j].Signature
+			})
+			report = append(report, contract)
+		}
+	}
+	sort.Slice(report, func(i, j int) bool { return report[i].Contract < report[j].Contract })
+	return report
+}
+
+// ContractCalls is the inventory of the calls leaving a contract
+type ContractCalls struct {
+	// Contract is the fully qualified name of the contract (path:Name)
+	Contract string `json:"contract"`
+
+	// Calls are sorted by location
+	Calls []CallSite `json:"calls"`
+}
+
+// CallSite is a call leaving a contract
+type CallSite struct {
+	// Kind is one of "call", "delegatecall", "staticcall", "callcode", "send", "transfer", "external"
+	// (high level call to a function of another contract), "selfdestruct" or "assembly"
+	Kind string `json:"kind"`
+
+	// Function is the called function of external calls
+	Function string `json:"function,omitempty"`
+
+	// Caller is the function or modifier making the call ("constructor", "fallback" and "receive" for special
+	// functions), empty for state variable initializers
+	Caller string `json:"caller,omitempty"`
+
+	// Opcodes are the call, create and selfdestruct opcodes used by assembly blocks
+	Opcodes []string `json:"opcodes,omitempty"`
+
+	SourceLocation SourceLocation `json:"sourceLocation"`
+}
+
+// NewCallReport returns the external calls, delegatecalls, selfdestructs and assembly blocks of every contract
+// declared in the sources of out, sorted by name
+//
+// The inventory requires the ast output
+func NewCallReport(out *Output) ([]ContractCalls, error) {
+	report := []ContractCalls{}
+	for file, source := range out.Sources {
+		if len(source.AST) == 0 {
+			continue
+		}
+		unit, err := ast.Parse(source.AST)
+		if err != nil {
+			return nil, fmt.Errorf("solc: could not parse AST of %q: %w", file, err)
+		}
+
+		for _, c := range unit.Contracts() {
+			contract := ContractCalls{Contract: ContractID{File: file, Name: c.Name}.String(), Calls: []CallSite{}}
+			for _, call := range c.ExternalCalls() {
+				contract.Calls = append(contract.Calls, newCallSite(unit, call.Node, call.Kind, call.Function))
+			}
+			for _, call := range c.Selfdestructs() {
+				contract.Calls = append(contract.Calls, newCallSite(unit, call, "selfdestruct", ""))
+			}
+			for _, block := range c.AssemblyBlocks() {
+				site := newCallSite(unit, block.Node, "assembly", "")
+				site.Opcodes = block.Opcodes
+				contract.Calls = append(contract.Calls, site)
+			}
+			sort.SliceStable(contract.Calls, func(i, j int) bool {
+				return contract.Calls[i].SourceLocation.Start < contract.Calls[j].SourceLocation.Start
+			})
+			report = append(report, contract)
+		}
+	}
+	sort.Slice(report, func(i, j int) bool { return report[i].Contract < report[j].Contract })
+	return report, nil
+}
+
+func newCallSite(unit *ast.Unit, n *ast.Node, kind, function string) CallSite {
+	site := CallSite{Kind: kind, Function: function, SourceLocation: NodeLocation(unit, n)}
+	if f := n.Ancestor("FunctionDefinition"); f != nil {
+		switch {
+		case f.StringAttr("kind") != "" && f.StringAttr("kind") != "function":
+			site.Caller = f.StringAttr("kind")
+		case f.BoolAttr("isConstructor"):
+			site.Caller = "constructor"
+		case f.Name() == "":
+			site.Caller = "fallback"
+		default:
+			site.Caller = f.Name()
+		}
+	} else if m := n.Ancestor("ModifierDefinition"); m != nil {
+		site.Caller = m.Name()
+	}
+	return site
+}
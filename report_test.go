@@ -0,0 +1,90 @@
+package solc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReports(t *testing.T) {
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err, "Creating Solc from valid solc emscripten binary should not error")
+	defer solc.Close()
+
+	out, err := solc.Compile(&Input{
+		Language: "Solidity",
+		Sources: map[string]SourceIn{
+			"One.sol": SourceIn{Content: "pragma solidity ^0.6.1; interface I { function i() external; } contract One { uint[] values; function one() public pure returns (uint) { return 1; } function sum() public view returns (uint s) { for (uint i = 0; i < values.length; i++) { s += values[i]; } } }"},
+		},
+		Settings: Settings{
+			OutputSelection: map[string]map[string][]string{"*": {"*": {"evm.bytecode.object", "evm.deployedBytecode.object", "evm.gasEstimates"}}},
+		},
+	})
+	require.NoError(t, err, "Compile should not error")
+
+	sizes := NewSizeReport(out)
+	require.Len(t, sizes, 1, "Contracts without bytecode should be skipped")
+	assert.Equal(t, "One.sol:One", sizes[0].Contract)
+	assert.Equal(t, len(out.Contracts["One.sol"]["One"].EVM.DeployedBytecode.Object)/2, sizes[0].Size)
+	assert.Greater(t, sizes[0].InitSize, sizes[0].Size, "Creation bytecode should embed deployed bytecode")
+	assert.Equal(t, MaxCodeSize-sizes[0].Size, sizes[0].Margin())
+
+	gas := NewGasReport(out)
+	require.Len(t, gas, 1, "Contracts without estimates should be skipped")
+	assert.NotEmpty(t, gas[0].Deployment)
+	require.Len(t, gas[0].Functions, 2)
+	assert.Equal(t, "one()", gas[0].Functions[0].Signature, "Functions should be sorted")
+	assert.False(t, gas[0].Functions[0].Exceeds(1000))
+	assert.Equal(t, GasUnbounded, gas[0].Functions[1].Gas, "Loops over storage should be unbounded")
+	assert.True(t, gas[0].Functions[1].Exceeds(1000000), "Unbounded estimates should exceed any limit")
+}
+
+const callsSource = `pragma solidity ^0.6.2;
+interface Token { function transfer(address to, uint amount) external returns (bool); }
+contract Wallet {
+    address payable owner;
+    modifier refund() { _; owner.transfer(1); }
+    constructor(address impl) public { impl.delegatecall(""); }
+    function pay(Token token) public refund { token.transfer(owner, 1); }
+    function kill() public { selfdestruct(owner); }
+    function raw(address to) public { assembly { let ok := call(gas(), to, 0, 0, 0, 0, 0) } }
+}
+`
+
+func TestCallReport(t *testing.T) {
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err, "Creating Solc from valid solc emscripten binary should not error")
+	defer solc.Close()
+
+	out, err := solc.Compile(NewInput().AddSource("Wallet.sol", callsSource).SetOutputSelection(OutputSelection{}.Select("*", "", "ast")))
+	require.NoError(t, err, "Compile should not error")
+
+	report, err := NewCallReport(out)
+	require.NoError(t, err, "Call report should not error")
+	require.Len(t, report, 2, "Every contract should be reported")
+	assert.Equal(t, "Wallet.sol:Token", report[0].Contract)
+	assert.Empty(t, report[0].Calls, "Interfaces make no call")
+
+	var calls []string
+	for _, call := range report[1].Calls {
+		calls = append(calls, call.Caller+" "+call.Kind+" "+call.Function)
+	}
+	assert.Equal(t, []string{"refund transfer ", "constructor delegatecall ", "pay external transfer", "kill selfdestruct ", "raw assembly "}, calls, "Calls should be sorted by location")
+	assembly := report[1].Calls[4]
+	assert.Equal(t, []string{"call"}, assembly.Opcodes, "Assembly opcodes should be listed")
+	assert.Equal(t, "Wallet.sol", assembly.SourceLocation.File)
+	assert.Contains(t, callsSource[assembly.SourceLocation.Start:assembly.SourceLocation.End], "call(gas()", "Location should point at the block")
+
+	// Assembly blocks of solc <0.6.0 have no Yul AST
+	solc5, err := NewFromFile("./solc-bin/soljson-v0.5.9+commit.e560f70d.js")
+	require.NoError(t, err, "Creating Solc from valid solc emscripten binary should not error")
+	defer solc5.Close()
+	out, err = solc5.Compile(NewInput().AddSource("Raw.sol", "pragma solidity ^0.5.9; contract Raw { function f(address to) public { assembly { let ok := delegatecall(gas, to, 0, 0, 0, 0) } } }").SetOutputSelection(OutputSelection{}.Select("*", "", "ast")))
+	require.NoError(t, err, "Compile should not error")
+	report, err = NewCallReport(out)
+	require.NoError(t, err, "Call report should not error")
+	require.Len(t, report, 1)
+	require.Len(t, report[0].Calls, 1)
+	assert.Equal(t, []string{"delegatecall"}, report[0].Calls[0].Opcodes, "Opcodes should be found in assembly source")
+}
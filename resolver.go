@@ -0,0 +1,198 @@
+package solc
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ImportResolver resolves source unit names (as found in import statements) to source contents
+type ImportResolver interface {
+	Resolve(path string) (string, error)
+}
+
+// ImportResolverFunc is an adapter to use an ordinary function as an ImportResolver
+type ImportResolverFunc func(path string) (string, error)
+
+func (f ImportResolverFunc) Resolve(path string) (string, error) {
+	return f(path)
+}
+
+// SourcesResolver resolves imports from in-memory sources
+type SourcesResolver map[string]SourceIn
+
+func (sources SourcesResolver) Resolve(path string) (string, error) {
+	source, ok := sources[path]
+	if !ok {
+		return "", fmt.Errorf("source %q not found", path)
+	}
+	return source.Content, nil
+}
+
+// RemappingResolver resolves imports with resolver after replacing the longest remapping prefix they start with
+//
+// Resolvers do not know the importing source unit, so remapping contexts are ignored
+func RemappingResolver(remappings []Remapping, resolver ImportResolver) ImportResolver {
+	return ImportResolverFunc(func(path string) (string, error) {
+		var best *Remapping
+		for i, r := range remappings {
+			if strings.HasPrefix(path, r.Prefix) && (best == nil || len(r.Prefix) > len(best.Prefix)) {
+				best = &remappings[i]
+			}
+		}
+		if best != nil {
+			path = best.Target + strings.TrimPrefix(path, best.Prefix)
+		}
+		return resolver.Resolve(path)
+	})
+}
+
+// DirResolver resolves imports from the first of dirs holding the imported file
+//
+// Imports may read any file, including out of dirs (e.g. ../../etc/passwd), and contents are read byte
+// for byte, see FSResolver to restrict and normalize them
+func DirResolver(dirs ...string) ImportResolver {
+	return ImportResolverFunc(func(path string) (string, error) {
+		for _, dir := range dirs {
+			content, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+			if err == nil {
+				return string(content), nil
+			}
+			if !os.IsNotExist(err) {
+				return "", err
+			}
+		}
+		return "", fmt.Errorf("source %q not found in %v", path, strings.Join(dirs, ", "))
+	})
+}
+
+// FSResolver resolves imports from the file system like native solc does: from BasePath, then from the
+// first of IncludePaths holding the imported file (see --base-path and --include-path). Files are only read
+// from BasePath, IncludePaths and AllowedPaths (see --allow-paths), after resolving symbolic links
+type FSResolver struct {
+	// BasePath defaults to the working directory
+	BasePath string
+
+	IncludePaths []string
+
+	// AllowedPaths are directories, other than the base and include paths, files may be read from
+	AllowedPaths []string
+
+	// Normalization applies to the contents read, which are kept byte for byte by default
+	Normalization SourceNormalization
+}
+
+// NewFSResolver creates a resolver of imports from basePath then includePaths
+func NewFSResolver(basePath string, includePaths ...string) *FSResolver {
+	return &FSResolver{BasePath: basePath, IncludePaths: includePaths}
+}
+
+// Allow adds allowed paths
+func (r *FSResolver) Allow(paths ...string) *FSResolver {
+	r.AllowedPaths = append(r.AllowedPaths, paths...)
+	return r
+}
+
+// NotAllowedError is returned when an import designates a file out of the allowed paths of a FSResolver
+type NotAllowedError struct {
+	// Path is the imported path
+	Path string
+
+	// File is the absolute file designated, with symbolic links resolved
+	File string
+
+	Allowed []string
+}
+
+func (e *NotAllowedError) Error() string {
+	return fmt.Sprintf("source %q not allowed: %v is outside of allowed paths %v", e.Path, e.File, strings.Join(e.Allowed, ", "))
+}
+
+func (r *FSResolver) Resolve(path string) (string, error) {
+	base := r.BasePath
+	if base == "" {
+		base = "."
+	}
+	var candidates []string
+	if filepath.IsAbs(filepath.FromSlash(path)) {
+		candidates = []string{filepath.FromSlash(path)}
+	} else {
+		for _, dir := range append([]string{base}, r.IncludePaths...) {
+			candidates = append(candidates, filepath.Join(dir, filepath.FromSlash(path)))
+		}
+	}
+
+	allowed, err := r.allowed(base)
+	if err != nil {
+		return "", err
+	}
+	// Files out of the allowed paths are reported unless found in another include path
+	var notAllowed error
+	for _, candidate := range candidates {
+		file, err := filepath.Abs(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !within(allowed, file) {
+			notAllowed = r.notAllowed(path, file, allowed)
+			continue
+		}
+		resolved, err := filepath.EvalSymlinks(file)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if !within(allowed, resolved) {
+			notAllowed = r.notAllowed(path, resolved, allowed)
+			continue
+		}
+		content, err := ioutil.ReadFile(resolved)
+		if err != nil {
+			return "", err
+		}
+		return r.Normalization.Normalize(string(content)), nil
+	}
+	if notAllowed != nil {
+		return "", notAllowed
+	}
+	return "", fmt.Errorf("source %q not found in %v", path, strings.Join(append([]string{base}, r.IncludePaths...), ", "))
+}
+
+// allowed returns the absolute allowed directories, with symbolic links resolved
+func (r *FSResolver) allowed(base string) ([]string, error) {
+	var dirs []string
+	for _, dir := range append(append([]string{base}, r.IncludePaths...), r.AllowedPaths...) {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, err
+		}
+		dirs = append(dirs, abs)
+		if resolved, err := filepath.EvalSymlinks(abs); err == nil && resolved != abs {
+			dirs = append(dirs, resolved)
+		}
+	}
+	return dirs, nil
+}
+
+func (r *FSResolver) notAllowed(path, file string, allowed []string) error {
+	dirs := append(append([]string{r.BasePath}, r.IncludePaths...), r.AllowedPaths...)
+	if r.BasePath == "" {
+		dirs[0] = "."
+	}
+	return &NotAllowedError{Path: path, File: file, Allowed: dirs}
+}
+
+// within indicates whether file is in one of dirs
+func within(dirs []string, file string) bool {
+	for _, dir := range dirs {
+		rel, err := filepath.Rel(dir, file)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package solc
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemappingResolver(t *testing.T) {
+	r, err := ParseRemapping("src:@oz/=lib/openzeppelin/contracts/")
+	require.NoError(t, err, "ParseRemapping should not error")
+	assert.Equal(t, Remapping{Context: "src", Prefix: "@oz/", Target: "lib/openzeppelin/contracts/"}, r)
+	assert.Equal(t, "src:@oz/=lib/openzeppelin/contracts/", r.String())
+
+	_, err = ParseRemapping("@oz/")
+	assert.Error(t, err, "ParseRemapping should error on missing target")
+
+	resolver := RemappingResolver(
+		[]Remapping{{Prefix: "@oz/", Target: "lib/oz/"}, {Prefix: "@oz/token/", Target: "lib/token/"}},
+		SourcesResolver{
+			"lib/oz/access/Ownable.sol": SourceIn{Content: "ownable"},
+			"lib/token/ERC20.sol":       SourceIn{Content: "erc20"},
+			"One.sol":                   SourceIn{Content: "one"},
+		},
+	)
+	for path, expected := range map[string]string{
+		"@oz/access/Ownable.sol": "ownable",
+		"@oz/token/ERC20.sol":    "erc20",
+		"One.sol":                "one",
+	} {
+		content, err := resolver.Resolve(path)
+		require.NoError(t, err, "Resolve should not error")
+		assert.Equal(t, expected, content, "Longest remapping should apply to %v", path)
+	}
+}
+
+func TestFSResolver(t *testing.T) {
+	dir, err := ioutil.TempDir("", "solc-fs-resolver")
+	require.NoError(t, err, "TempDir should not error")
+	defer os.RemoveAll(dir)
+	for file, content := range map[string]string{
+		"project/src/A.sol": "a",
+		"project/lib/B.sol": "project b",
+		"one/B.sol":         "one b",
+		"two/B.sol":         "two b",
+		"two/C.sol":         "two c",
+		"shared/D.sol":      "d",
+		"secret/key.txt":    "secret",
+	} {
+		file = filepath.Join(dir, filepath.FromSlash(file))
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+		require.NoError(t, ioutil.WriteFile(file, []byte(content), 0644))
+	}
+	require.NoError(t, os.Symlink(filepath.Join(dir, "secret"), filepath.Join(dir, "project", "link")))
+
+	resolver := NewFSResolver(filepath.Join(dir, "project"), filepath.Join(dir, "one"), filepath.Join(dir, "two"))
+	for path, expected := range map[string]string{
+		"src/A.sol": "a",
+		"lib/B.sol": "project b",
+		"B.sol":     "one b",
+		"C.sol":     "two c",
+	} {
+		content, err := resolver.Resolve(path)
+		require.NoError(t, err, "Resolve should not error")
+		assert.Equal(t, expected, content, "Include paths should be searched in order for %v", path)
+	}
+
+	for _, path := range []string{"../secret/key.txt", filepath.ToSlash(filepath.Join(dir, "secret", "key.txt")), "link/key.txt", "../shared/D.sol"} {
+		_, err := resolver.Resolve(path)
+		var notAllowed *NotAllowedError
+		require.True(t, errors.As(err, &notAllowed), "Resolve %v should not be allowed", path)
+		assert.Equal(t, path, notAllowed.Path)
+		assert.Contains(t, err.Error(), "outside of allowed paths")
+	}
+
+	_, err = resolver.Resolve("Missing.sol")
+	assert.Error(t, err, "Missing file should error")
+	var notAllowed *NotAllowedError
+	assert.False(t, errors.As(err, &notAllowed), "Missing file should not be reported as not allowed")
+
+	content, err := resolver.Allow(filepath.Join(dir, "shared")).Resolve("../shared/D.sol")
+	require.NoError(t, err, "Resolve should not error once allowed")
+	assert.Equal(t, "d", content)
+}
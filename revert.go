@@ -0,0 +1,145 @@
+package solc
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Selectors of the errors the compiler reverts with
+const (
+	// ErrorSelector is the selector of Error(string), of require and revert reasons
+	ErrorSelector = "0x08c379a0"
+
+	// PanicSelector is the selector of Panic(uint256), of failed assertions and runtime errors (solc >= 0.8.0)
+	PanicSelector = "0x4e487b71"
+)
+
+// panicReasons describe the codes of Panic(uint256)
+var panicReasons = map[uint64]string{
+	0x00: "generic compiler inserted panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "conversion to an out of range enum value",
+	0x22: "incorrectly encoded storage byte array",
+	0x31: "pop on an empty array",
+	0x32: "array index out of bounds",
+	0x41: "too much memory allocated",
+	0x51: "call to a zero initialized internal function",
+}
+
+var (
+	errorEntry = ABIEntry{Type: "error", Name: "Error", Inputs: []ABIParameter{{Name: "reason", Type: "string"}}}
+	panicEntry = ABIEntry{Type: "error", Name: "Panic", Inputs: []ABIParameter{{Name: "code", Type: "uint256"}}}
+)
+
+// Revert is the decoded revert data of a call
+type Revert struct {
+	// Error is the error reverted with (Error, Panic or a custom error), nil if unknown or without data
+	Error *ABIEntry
+
+	// Contracts are the contracts declaring custom errors
+	Contracts []string
+
+	Args []interface{}
+
+	// Message describes the revert (e.g. "insufficient balance" or "Unauthorized(user: 0x...)")
+	Message string
+}
+
+// DecodeRevert decodes the revert data of a call: the reasons of Error(string), the codes of Panic(uint256)
+// and the custom errors of the contracts of out (which requires the abi output), if not nil
+//
+// Data of unknown errors decodes to a Revert without Error whose message gives their selector. It errors
+// if data of a known error does not decode
+func DecodeRevert(data []byte, out *Output) (*Revert, error) {
+	if len(data) == 0 {
+		return &Revert{Message: "reverted without reason"}, nil
+	}
+	if len(data) < 4 {
+		return &Revert{Message: fmt.Sprintf("reverted with invalid data 0x%x", data)}, nil
+	}
+	selector := "0x" + hex.EncodeToString(data[:4])
+
+	var decoders []Decoder
+	switch selector {
+	case ErrorSelector:
+		decoders = []Decoder{{ABI: errorEntry}}
+	case PanicSelector:
+		decoders = []Decoder{{ABI: panicEntry}}
+	default:
+		if out != nil {
+			table, err := NewDecoderTable(out)
+			if err != nil {
+				return nil, err
+			}
+			decoders = table.Error(selector)
+		}
+	}
+	if len(decoders) == 0 {
+		return &Revert{Message: fmt.Sprintf("reverted with unknown error %v", selector)}, nil
+	}
+
+	// Errors sharing a selector decode the same data, unless they differ by their types
+	var err error
+	for _, d := range decoders {
+		var args []interface{}
+		args, err = DecodeABI(d.ABI.Inputs, data[4:])
+		if err != nil {
+			continue
+		}
+		entry := d.ABI
+		r := &Revert{Error: &entry, Contracts: d.Contracts, Args: args}
+		switch selector {
+		case ErrorSelector:
+			r.Message = args[0].(string)
+		case PanicSelector:
+			r.Message = panicMessage(args[0].(*big.Int))
+		default:
+			r.Message = errorMessage(entry, args)
+		}
+		return r, nil
+	}
+	return nil, fmt.Errorf("solc: invalid revert data for %v: %v", decoders[0].ABI.Signature(), err)
+}
+
+func panicMessage(code *big.Int) string {
+	if code.IsUint64() {
+		if reason, ok := panicReasons[code.Uint64()]; ok {
+			return fmt.Sprintf("panic: %v (0x%02x)", reason, code.Uint64())
+		}
+	}
+	return fmt.Sprintf("panic: unknown code 0x%x", code)
+}
+
+// errorMessage formats a custom error and its arguments, named after its parameters (e.g. Unauthorized(user: 0x...))
+func errorMessage(entry ABIEntry, args []interface{}) string {
+	formatted := make([]string, len(args))
+	for i, arg := range args {
+		formatted[i] = formatABIValue(arg)
+		if name := entry.Inputs[i].Name; name != "" {
+			formatted[i] = name + ": " + formatted[i]
+		}
+	}
+	return entry.Name + "(" + strings.Join(formatted, ", ") + ")"
+}
+
+// formatABIValue formats a value decoded by DecodeABI
+func formatABIValue(v interface{}) string {
+	switch v := v.(type) {
+	case []byte:
+		return "0x" + hex.EncodeToString(v)
+	case string:
+		return fmt.Sprintf("%q", v)
+	case []interface{}:
+		formatted := make([]string, len(v))
+		for i, e := range v {
+			formatted[i] = formatABIValue(e)
+		}
+		return "[" + strings.Join(formatted, ", ") + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
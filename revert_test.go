@@ -0,0 +1,58 @@
+package solc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeRevert(t *testing.T) {
+	out := &Output{Contracts: map[string]map[string]Contract{
+		"Vault.sol": {"Vault": {ABI: []json.RawMessage{
+			json.RawMessage(`{"type": "error", "name": "Unauthorized", "inputs": [{"name": "user", "type": "address"}, {"name": "", "type": "uint256[]"}]}`),
+		}}},
+	}}
+	revertData := func(selector string, params []ABIParameter, values ...interface{}) []byte {
+		b, err := EncodeABI(params, values...)
+		require.NoError(t, err, "EncodeABI should not error")
+		s, _ := hex.DecodeString(selector[2:])
+		return append(s, b...)
+	}
+
+	r, err := DecodeRevert(revertData(ErrorSelector, errorEntry.Inputs, "insufficient balance"), nil)
+	require.NoError(t, err, "DecodeRevert should not error")
+	assert.Equal(t, "insufficient balance", r.Message)
+	assert.Equal(t, "Error(string)", r.Error.Signature())
+
+	r, err = DecodeRevert(revertData(PanicSelector, panicEntry.Inputs, 0x11), nil)
+	require.NoError(t, err, "DecodeRevert should not error")
+	assert.Equal(t, "panic: arithmetic underflow or overflow (0x11)", r.Message)
+	assert.Equal(t, []interface{}{big.NewInt(0x11)}, r.Args)
+	r, err = DecodeRevert(revertData(PanicSelector, panicEntry.Inputs, 0x99), nil)
+	require.NoError(t, err, "DecodeRevert should not error")
+	assert.Equal(t, "panic: unknown code 0x99", r.Message)
+
+	unauthorized, err := ParseABI(out.Contracts["Vault.sol"]["Vault"].ABI)
+	require.NoError(t, err)
+	data := revertData(unauthorized[0].Selector(), unauthorized[0].Inputs, Address{0x01}, []int{1, 2})
+	r, err = DecodeRevert(data, out)
+	require.NoError(t, err, "DecodeRevert should not error")
+	assert.Equal(t, "Unauthorized(user: 0x0100000000000000000000000000000000000000, [1, 2])", r.Message)
+	assert.Equal(t, []string{"Vault.sol:Vault"}, r.Contracts)
+
+	r, err = DecodeRevert(data, nil)
+	require.NoError(t, err, "Unknown errors should not error")
+	assert.Nil(t, r.Error)
+	assert.Equal(t, "reverted with unknown error "+unauthorized[0].Selector(), r.Message)
+
+	r, err = DecodeRevert(nil, out)
+	require.NoError(t, err)
+	assert.Equal(t, "reverted without reason", r.Message)
+
+	_, err = DecodeRevert(data[:40], out)
+	assert.Error(t, err, "Truncated data of known errors should error")
+}
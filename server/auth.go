@@ -0,0 +1,64 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/nmvalera/solc-go/remote"
+)
+
+// DefaultAPIKeyHeader is the header holding API keys when APIKey is given none
+const DefaultAPIKeyHeader = remote.DefaultAPIKeyHeader
+
+// Authenticator authenticates a request, returning an error rejects it with 401
+type Authenticator func(r *http.Request) error
+
+// APIKey authenticates requests whose header (DefaultAPIKeyHeader if empty) holds a key accepted by validate
+func APIKey(header string, validate func(key string) bool) Authenticator {
+	if header == "" {
+		header = DefaultAPIKeyHeader
+	}
+	return func(r *http.Request) error {
+		key := r.Header.Get(header)
+		if key == "" {
+			return fmt.Errorf("missing %v header", header)
+		}
+		if !validate(key) {
+			return errors.New("invalid API key")
+		}
+		return nil
+	}
+}
+
+// ClientCertificate authenticates requests sent over TLS with a verified client certificate accepted by verify
+//
+// The TLS configuration must verify client certificates (see MutualTLSConfig), verify may be nil to accept any
+func ClientCertificate(verify func(cert *x509.Certificate) error) Authenticator {
+	return func(r *http.Request) error {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+			return errors.New("client certificate required")
+		}
+		if verify == nil {
+			return nil
+		}
+		return verify(r.TLS.VerifiedChains[0][0])
+	}
+}
+
+// MutualTLSConfig returns a server TLS configuration requiring client certificates signed by the PEM encoded caPEM
+//
+// Server certificates still have to be set, e.g. with http.Server.ListenAndServeTLS
+func MutualTLSConfig(caPEM []byte) (*tls.Config, error) {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, errors.New("no certificate found in client CA")
+	}
+	return &tls.Config{
+		ClientCAs:  pool,
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		MinVersion: tls.VersionTLS12,
+	}, nil
+}
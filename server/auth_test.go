@@ -0,0 +1,102 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKey(t *testing.T) {
+	s := New(&fakeSolc{version: "0.6.2+commit.bacdbe57.Emscripten.clang"})
+	s.Auth = APIKey("", func(key string) bool { return key == "secret" })
+
+	rec, res := do(t, s, http.MethodGet, "/versions", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, "Requests without key should be rejected")
+	assert.Equal(t, "missing X-API-Key header", res["error"])
+
+	req := httptest.NewRequest(http.MethodGet, "/versions", nil)
+	req.Header.Set(DefaultAPIKeyHeader, "wrong")
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, "Requests with an invalid key should be rejected")
+
+	req.Header.Set(DefaultAPIKeyHeader, "secret")
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code, "Requests with a valid key should be served")
+
+	rec, _ = do(t, s, http.MethodGet, "/health", "")
+	assert.Equal(t, http.StatusOK, rec.Code, "Health checks should not be authenticated")
+}
+
+// newCert creates a certificate signed by parent (self-signed if nil)
+func newCert(t *testing.T, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestClientCertificate(t *testing.T) {
+	ca, caKey, caPEM := newCert(t, "ca", nil, nil)
+	client, clientKey, _ := newCert(t, "client", ca, caKey)
+
+	config, err := MutualTLSConfig(caPEM)
+	require.NoError(t, err, "MutualTLSConfig should not error")
+	_, err = MutualTLSConfig([]byte("not a certificate"))
+	assert.Error(t, err, "Invalid CA should error")
+
+	s := New(&fakeSolc{version: "0.6.2+commit.bacdbe57.Emscripten.clang"})
+	s.Auth = ClientCertificate(func(cert *x509.Certificate) error {
+		if cert.Subject.CommonName != "client" {
+			return errors.New("unknown client")
+		}
+		return nil
+	})
+	srv := httptest.NewUnstartedServer(s)
+	srv.TLS = config
+	srv.StartTLS()
+	defer srv.Close()
+
+	transport := srv.Client().Transport.(*http.Transport).Clone()
+	transport.TLSClientConfig.Certificates = []tls.Certificate{{Certificate: [][]byte{client.Raw}, PrivateKey: clientKey}}
+	res, err := (&http.Client{Transport: transport}).Get(srv.URL + "/versions")
+	require.NoError(t, err, "Request with a client certificate should not error")
+	res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode, "Verified client should be served")
+
+	_, err = srv.Client().Get(srv.URL + "/versions")
+	assert.Error(t, err, "TLS handshake should fail without client certificate")
+
+	rec, _ := do(t, s, http.MethodGet, "/versions", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, "Requests without TLS should be rejected")
+}
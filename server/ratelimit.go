@@ -0,0 +1,48 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	solc "github.com/nmvalera/solc-go"
+)
+
+// ClientKey identifies the client of a request for rate limiting
+type ClientKey func(r *http.Request) string
+
+// RemoteIP is the ClientKey of the IP address a request comes from
+func RemoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// RateLimit is a middleware rejecting requests of clients over limiter with 429 and a Retry-After header
+//
+// key identifies clients, RemoteIP if nil (use a header key behind a proxy)
+func RateLimit(limiter *solc.RateLimiter, key ClientKey, next http.Handler) http.Handler {
+	if key == nil {
+		key = RemoteIP
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ok, retry := limiter.Allow(key(r))
+		if ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Retry-After is in whole seconds, round up so clients never retry too early
+		seconds := int64(math.Ceil(retry.Seconds()))
+		w.Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+		writeJSON(w, http.StatusTooManyRequests, map[string]interface{}{
+			"error":      fmt.Sprintf("rate limit exceeded, retry in %v", retry.Round(time.Millisecond)),
+			"retryAfter": seconds,
+		})
+	})
+}
@@ -0,0 +1,30 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	solc "github.com/nmvalera/solc-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	h := RateLimit(solc.NewRateLimiter(1, 2), nil, New(&fakeSolc{version: "0.6.2+commit.bacdbe57.Emscripten.clang"}))
+
+	for i := 0; i < 2; i++ {
+		rec, _ := do(t, h, http.MethodGet, "/health", "")
+		assert.Equal(t, http.StatusOK, rec.Code, "Requests within the burst should be allowed")
+	}
+	rec, res := do(t, h, http.MethodGet, "/health", "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code, "Requests over the burst should be limited")
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"), "Retry-After should be set in seconds")
+	assert.Equal(t, 1.0, res["retryAfter"])
+	assert.Contains(t, res["error"], "rate limit exceeded")
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	req.RemoteAddr = "10.0.0.2:1234"
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code, "Other clients should not be limited")
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	solc "github.com/nmvalera/solc-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemote(t *testing.T) {
+	compiler := &fakeSolc{version: "0.6.2+commit.bacdbe57.Emscripten.clang"}
+	srv := httptest.NewServer(New(compiler, &fakeSolc{version: "0.5.9+commit.e560f70d.Emscripten.clang"}))
+	defer srv.Close()
+
+	_, err := solc.NewRemote(srv.URL)
+	assert.Error(t, err, "Version should be required when several are served")
+	_, err = solc.NewRemote(srv.URL + "?version=0.4.0")
+	assert.Error(t, err, "Unknown version should error")
+
+	remote, err := solc.NewRemote(srv.URL+"?version=0.6.2", solc.WithHTTPClient(srv.Client()))
+	require.NoError(t, err, "NewRemote should not error")
+	assert.Equal(t, "0.6.2+commit.bacdbe57", remote.Version())
+
+	out, err := remote.Compile(&solc.Input{Sources: map[string]solc.SourceIn{"A.sol": solc.SourceIn{Content: "contract C {}"}}})
+	require.NoError(t, err, "Compile should not error")
+	assert.Equal(t, "0.6.2+commit.bacdbe57.Emscripten.clang", out.Contracts["A.sol"]["C"].Metadata)
+
+	compiler.err = solc.ErrTimeout
+	_, err = remote.Compile(&solc.Input{})
+	assert.Equal(t, solc.ErrTimeout, err, "Timeouts should be reported")
+
+	compiler.err = solc.ErrClosed
+	_, err = remote.Compile(&solc.Input{})
+	require.IsType(t, &solc.RemoteError{}, err)
+	assert.Equal(t, http.StatusServiceUnavailable, err.(*solc.RemoteError).StatusCode)
+
+	require.NoError(t, remote.Close())
+	_, err = remote.Compile(&solc.Input{})
+	assert.Equal(t, solc.ErrClosed, err, "Compile should error once closed")
+}
+
+func TestRemoteAPIKey(t *testing.T) {
+	s := New(&fakeSolc{version: "0.6.2+commit.bacdbe57.Emscripten.clang"})
+	s.Auth = APIKey("", func(key string) bool { return key == "secret" })
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	_, err := solc.NewRemote(srv.URL)
+	require.IsType(t, &solc.RemoteError{}, err, "Requests without key should be rejected")
+	assert.Equal(t, http.StatusUnauthorized, err.(*solc.RemoteError).StatusCode)
+
+	remote, err := solc.NewRemote(srv.URL, solc.WithAPIKey("", "secret"))
+	require.NoError(t, err, "NewRemote should not error with a valid key")
+	_, err = remote.Compile(&solc.Input{Sources: map[string]solc.SourceIn{"A.sol": solc.SourceIn{Content: "contract C {}"}}})
+	assert.NoError(t, err, "Compile should be authenticated")
+}
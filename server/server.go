@@ -0,0 +1,392 @@
+// Package server exposes solc compilers as an HTTP compilation service
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	solc "github.com/nmvalera/solc-go"
+)
+
+// DefaultMaxBodySize is the maximum size of a compile request body
+const DefaultMaxBodySize = 32 << 20
+
+// Server serves
+//
+//	POST /compile?version=<version>  standard JSON input in, standard JSON output out
+//	GET  /versions                   versions of the compilers served, and loaded by the registry if any
+//	GET  /healthz                    200 as long as every compiler is alive (also served as /health)
+//	GET  /readyz                     200 once every compiler is warmed up, until Shutdown
+//
+// The version may be short (0.6.2) or long (0.6.2+commit.bacdbe57), it can be omitted when serving a single compiler.
+// With a Registry, versions not served by the compilers, which may also be constraints (^0.6.0), are compiled by
+// the registry, and omitted versions are taken from the version pragmas of the sources.
+// Inputs over Limits are rejected with 413 and the exceeded limit, see RateLimit for per-client rate limits.
+// Compilations of untrusted inputs can be further bounded by a Policy per request (e.g. per tenant)
+type Server struct {
+	// Auth authenticates every request but health checks, requests are not authenticated if nil
+	Auth Authenticator
+
+	// MaxBodySize defaults to DefaultMaxBodySize
+	MaxBodySize int64
+
+	// Limits bound the inputs compiled, inputs over them are rejected with 413
+	Limits solc.InputLimits
+
+	// Policy returns the policy bounding the compilation of a request (see solc.CompilePolicy), compilations are
+	// only bounded by Limits if nil. Inputs over the policy are rejected with 413, or 400 for denied URL
+	// sources, compilations over its timeout with 504 and over its memory with 413
+	Policy func(r *http.Request) solc.CompilePolicy
+
+	// Registry compiles for the versions not served by the compilers of the server, downloading them on first use,
+	// unknown versions are rejected with 404 if nil
+	Registry *solc.Registry
+
+	compilers map[string]solc.Solc
+	versions  []string
+	mux       *http.ServeMux
+
+	// all holds each compiler once, aligned with versions
+	all []solc.Solc
+
+	state    sync.Mutex
+	draining bool
+	inflight int
+	drained  chan struct{}
+}
+
+// New creates a server dispatching compilations to compilers (typically warmed solc.Pool)
+func New(compilers ...solc.Solc) *Server {
+	s := &Server{
+		compilers: make(map[string]solc.Solc),
+		mux:       http.NewServeMux(),
+	}
+	for _, compiler := range compilers {
+		long := solc.LongVersion(compiler.Version())
+		s.compilers[long] = compiler
+		s.compilers[solc.ShortVersion(long)] = compiler
+		s.compilers[compiler.Version()] = compiler
+		s.all = append(s.all, compiler)
+	}
+	sort.Slice(s.all, func(i, j int) bool { return s.all[i].Version() < s.all[j].Version() })
+	for _, compiler := range s.all {
+		s.versions = append(s.versions, solc.LongVersion(compiler.Version()))
+	}
+
+	s.mux.HandleFunc("/compile", s.authenticated(s.handleCompile))
+	s.mux.HandleFunc("/versions", s.authenticated(s.handleVersions))
+	s.mux.HandleFunc("/health", s.handleHealth)
+	s.mux.HandleFunc("/healthz", s.handleHealth)
+	s.mux.HandleFunc("/readyz", s.handleReady)
+
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Shutdown fails readiness checks, rejects new compilations with 503 and waits for compilations
+// in progress to complete or ctx to be done
+//
+// It does not close the compilers nor the listener, call it before http.Server.Shutdown
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.state.Lock()
+	s.draining = true
+	if s.drained == nil {
+		s.drained = make(chan struct{})
+		if s.inflight == 0 {
+			close(s.drained)
+		}
+	}
+	drained := s.drained
+	s.state.Unlock()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// begin registers a compilation in progress, unless the server is shutting down
+func (s *Server) begin() bool {
+	s.state.Lock()
+	defer s.state.Unlock()
+	if s.draining {
+		return false
+	}
+	s.inflight++
+	return true
+}
+
+func (s *Server) end() {
+	s.state.Lock()
+	defer s.state.Unlock()
+	s.inflight--
+	if s.inflight == 0 && s.drained != nil {
+		close(s.drained)
+	}
+}
+
+func (s *Server) isDraining() bool {
+	s.state.Lock()
+	defer s.state.Unlock()
+	return s.draining
+}
+
+// authenticated rejects requests not authenticated by s.Auth before calling h
+func (s *Server) authenticated(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.Auth != nil {
+			if err := s.Auth(r); err != nil {
+				writeError(w, http.StatusUnauthorized, err)
+				return
+			}
+		}
+		h(w, r)
+	}
+}
+
+// compiler returns the compiler serving version, or nil if the registry compiles it
+func (s *Server) compiler(version string) (solc.Solc, error) {
+	if version == "" {
+		if len(s.versions) != 1 && s.Registry != nil {
+			return nil, nil
+		}
+		if len(s.versions) != 1 {
+			return nil, fmt.Errorf("version is required, available versions are %v", s.versions)
+		}
+		version = s.versions[0]
+	}
+	compiler, ok := s.compilers[version]
+	if !ok && s.Registry != nil {
+		return nil, nil
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown version %q", version)
+	}
+	return compiler, nil
+}
+
+func (s *Server) handleCompile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %v not allowed", r.Method))
+		return
+	}
+	if !s.begin() {
+		writeError(w, http.StatusServiceUnavailable, errors.New("server is shutting down"))
+		return
+	}
+	defer s.end()
+
+	// Versions never contain spaces, restore the + of long versions sent unescaped
+	version := strings.Replace(r.URL.Query().Get("version"), " ", "+", -1)
+	compiler, err := s.compiler(version)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	maxBodySize := s.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultMaxBodySize
+	}
+	input := &solc.Input{}
+	body := &countingBody{ReadCloser: r.Body}
+	err = json.NewDecoder(http.MaxBytesReader(w, body, maxBodySize)).Decode(input)
+	if err != nil && strings.Contains(err.Error(), "request body too large") {
+		// Chunked bodies have no length, report what was read before the limit
+		size := r.ContentLength
+		if size < 0 {
+			size = body.n
+		}
+		writeError(w, http.StatusRequestEntityTooLarge, &solc.SizeLimitError{Kind: "body", Size: int(size), Limit: int(maxBodySize)})
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid input: %v", err))
+		return
+	}
+	err = s.Limits.Check(input)
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, err)
+		return
+	}
+	ctx := r.Context()
+	if s.Policy != nil {
+		policy := s.Policy(r)
+		err = policy.Check(input)
+		if err != nil {
+			writeError(w, statusOf(err), err)
+			return
+		}
+		ctx = solc.ContextWithPolicy(ctx, policy)
+	}
+
+	if compiler == nil {
+		s.handleRegistryCompile(w, ctx, version, input)
+		return
+	}
+	out, err := solc.CompileContext(ctx, compiler, input)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, out)
+}
+
+// handleRegistryCompile compiles input with the registry version satisfying constraint, or the version pragmas of
+// the sources if empty
+func (s *Server) handleRegistryCompile(w http.ResponseWriter, ctx context.Context, constraint string, input *solc.Input) {
+	if constraint == "" {
+		var err error
+		constraint, err = solc.IntersectVersions(solc.VersionPragmas(input.Sources)...)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	if _, err := solc.MatchVersion(constraint, "0.0.0"); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	// Loaded first so versions that cannot be loaded are told apart from failing compilations
+	version, err := s.Registry.Load(ctx, constraint)
+	if err != nil {
+		writeError(w, loadStatus(err), err)
+		return
+	}
+
+	out, err := s.Registry.Compile(ctx, version, input)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// statusClientClosedRequest is the non standard status of requests cancelled by their client
+const statusClientClosedRequest = 499
+
+// loadStatus returns the status of a version the registry failed to load: 404 if no release satisfies it,
+// 502 if it could not be downloaded (e.g. network errors or checksum mismatches)
+func loadStatus(err error) int {
+	var noVersion *solc.NoVersionError
+	switch {
+	case errors.As(err, &noVersion):
+		return http.StatusNotFound
+	case err == solc.ErrClosed:
+		return http.StatusServiceUnavailable
+	case errors.Is(err, context.Canceled):
+		return statusClientClosedRequest
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusBadGateway
+}
+
+// handleVersions lists the versions served and, with a registry, those it has loaded. Releases the registry
+// would download on demand are not listed
+func (s *Server) handleVersions(w http.ResponseWriter, r *http.Request) {
+	versions := map[string][]string{"versions": s.versions}
+	if s.Registry != nil {
+		versions["registry"] = s.Registry.Versions()
+	}
+	writeJSON(w, http.StatusOK, versions)
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if len(s.versions) == 0 {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "no compiler"})
+		return
+	}
+	errs := make(map[string]string)
+	for i, compiler := range s.all {
+		if err := solc.Healthy(compiler); err != nil {
+			errs[s.versions[i]] = err.Error()
+		}
+	}
+	if len(errs) > 0 {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{"status": "unhealthy", "errors": errs})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	if s.isDraining() {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "shutting down"})
+		return
+	}
+	if len(s.versions) == 0 {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "no compiler"})
+		return
+	}
+	var pending []string
+	for i, compiler := range s.all {
+		if !solc.IsReady(compiler) {
+			pending = append(pending, s.versions[i])
+		}
+	}
+	if len(pending) > 0 {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{"status": "warming up", "pending": pending})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "ready", "versions": s.versions})
+}
+
+// statusOf maps compilation errors to HTTP statuses
+func statusOf(err error) int {
+	var sizeErr *solc.SizeLimitError
+	var memErr *solc.MemoryLimitError
+	var inputErr *solc.InputError
+	switch {
+	case errors.As(err, &sizeErr), errors.As(err, &memErr):
+		return http.StatusRequestEntityTooLarge
+	case errors.As(err, &inputErr):
+		return http.StatusBadRequest
+	case err == solc.ErrTimeout:
+		return http.StatusGatewayTimeout
+	case err == solc.ErrClosed, err == solc.ErrTerminated:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// countingBody counts the bytes read from a request body
+type countingBody struct {
+	io.ReadCloser
+	n int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+// writeError writes {"error": message}, with the exceeded limit, size and max for size limit errors
+func writeError(w http.ResponseWriter, status int, err error) {
+	var sizeErr *solc.SizeLimitError
+	if errors.As(err, &sizeErr) {
+		writeJSON(w, status, map[string]interface{}{"error": err.Error(), "limit": sizeErr.Kind, "size": sizeErr.Size, "max": sizeErr.Limit})
+		return
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
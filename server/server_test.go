@@ -0,0 +1,288 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	solc "github.com/nmvalera/solc-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSolc echoes the names of the sources it compiles
+type fakeSolc struct {
+	version string
+	err     error
+
+	unhealthy error
+	warming   bool
+	// block, if set, holds compilations until closed
+	block chan struct{}
+}
+
+func (s *fakeSolc) Healthy() error { return s.unhealthy }
+func (s *fakeSolc) IsReady() bool  { return !s.warming }
+
+func (s *fakeSolc) License() string                     { return "" }
+func (s *fakeSolc) Version() string                     { return s.version }
+func (s *fakeSolc) HeapStatistics() solc.HeapStatistics { return solc.HeapStatistics{} }
+func (s *fakeSolc) Close() error                        { return nil }
+func (s *fakeSolc) Compile(in *solc.Input) (*solc.Output, error) {
+	if s.block != nil {
+		<-s.block
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	out := &solc.Output{Contracts: make(map[string]map[string]solc.Contract)}
+	for name := range in.Sources {
+		out.Contracts[name] = map[string]solc.Contract{"C": solc.Contract{Metadata: s.version}}
+	}
+	return out, nil
+}
+
+func do(t *testing.T, h http.Handler, method, url, body string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	req := httptest.NewRequest(method, url, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var res map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res), "Response should be JSON")
+	return rec, res
+}
+
+func TestServer(t *testing.T) {
+	s := New(
+		&fakeSolc{version: "0.6.2+commit.bacdbe57.Emscripten.clang"},
+		&fakeSolc{version: "0.5.9+commit.e560f70d.Emscripten.clang"},
+	)
+	input := `{"language": "Solidity", "sources": {"A.sol": {"content": "contract C {}"}}}`
+
+	rec, res := do(t, s, http.MethodGet, "/versions", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []interface{}{"0.5.9+commit.e560f70d", "0.6.2+commit.bacdbe57"}, res["versions"])
+
+	rec, _ = do(t, s, http.MethodGet, "/health", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec, res = do(t, s, http.MethodPost, "/compile?version=0.5.9", input)
+	require.Equal(t, http.StatusOK, rec.Code, "Compile should succeed")
+	out := &solc.Output{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), out))
+	assert.Equal(t, "0.5.9+commit.e560f70d.Emscripten.clang", out.Contracts["A.sol"]["C"].Metadata, "Requested version should compile")
+
+	rec, _ = do(t, s, http.MethodPost, "/compile?version=0.6.2+commit.bacdbe57", input)
+	assert.Equal(t, http.StatusOK, rec.Code, "Long version should be accepted")
+
+	rec, res = do(t, s, http.MethodPost, "/compile", input)
+	assert.Equal(t, http.StatusNotFound, rec.Code, "Version should be required with several compilers")
+	assert.Contains(t, res["error"], "version is required")
+
+	rec, _ = do(t, s, http.MethodPost, "/compile?version=0.4.0", input)
+	assert.Equal(t, http.StatusNotFound, rec.Code, "Unknown version should not be found")
+
+	rec, _ = do(t, s, http.MethodPost, "/compile?version=0.6.2", "{")
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "Invalid input should be rejected")
+
+	rec, _ = do(t, s, http.MethodGet, "/compile?version=0.6.2", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestServerErrors(t *testing.T) {
+	compiler := &fakeSolc{version: "0.6.2+commit.bacdbe57.Emscripten.clang", err: solc.ErrTimeout}
+	s := New(compiler)
+
+	rec, _ := do(t, s, http.MethodPost, "/compile", `{}`)
+	assert.Equal(t, http.StatusGatewayTimeout, rec.Code, "Single compiler should be used by default")
+
+	compiler.err = &solc.SizeLimitError{Kind: "input"}
+	rec, _ = do(t, s, http.MethodPost, "/compile", `{}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	s.MaxBodySize = 4
+	rec, res := do(t, s, http.MethodPost, "/compile", `{"language": "Solidity"}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code, "Oversized body should be rejected")
+	assert.Equal(t, "body", res["limit"])
+	assert.Equal(t, float64(len(`{"language": "Solidity"}`)), res["size"])
+
+	req := httptest.NewRequest(http.MethodPost, "/compile", strings.NewReader(`{"language": "Solidity"}`))
+	req.ContentLength = -1
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res), "Response should be JSON")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code, "Oversized chunked body should be rejected")
+	assert.Greater(t, res["size"], 4.0, "Chunked body size should be the bytes read")
+
+	rec, _ = do(t, New(), http.MethodGet, "/health", "")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code, "Server without compiler should not be healthy")
+}
+
+func TestServerLimits(t *testing.T) {
+	s := New(&fakeSolc{version: "0.6.2+commit.bacdbe57.Emscripten.clang"})
+	s.Limits = solc.InputLimits{MaxSources: 1, MaxOutputs: 2}
+
+	rec, _ := do(t, s, http.MethodPost, "/compile", `{"sources": {"A.sol": {"content": "contract C {}"}}, "settings": {"outputSelection": {"*": {"*": ["abi", "evm.bytecode"]}}}}`)
+	assert.Equal(t, http.StatusOK, rec.Code, "Input within limits should compile")
+
+	rec, res := do(t, s, http.MethodPost, "/compile", `{"sources": {"A.sol": {"content": "contract A {}"}, "B.sol": {"content": "contract B {}"}}}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code, "Too many sources should be rejected")
+	assert.Equal(t, map[string]interface{}{"error": "solc: sources size 2 exceeds limit of 1", "limit": "sources", "size": 2.0, "max": 1.0}, res)
+
+	rec, res = do(t, s, http.MethodPost, "/compile", `{"sources": {"A.sol": {"content": "contract C {}"}}, "settings": {"outputSelection": {"*": {"*": ["abi", "evm.bytecode"], "": ["ast"]}}}}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code, "Too broad output selection should be rejected")
+	assert.Equal(t, "outputs", res["limit"])
+}
+
+func TestServerRegistry(t *testing.T) {
+	s := New(&fakeSolc{version: "0.6.2+commit.bacdbe57.Emscripten.clang"})
+	s.Registry = solc.NewRegistry(solc.RegistryConfig{Dir: "../solc-bin", Offline: true})
+	defer s.Registry.Close()
+	input := `{"language": "Solidity", "sources": {"A.sol": {"content": "pragma solidity ^0.5.0; contract C {}"}}, "settings": {"outputSelection": {"*": {"*": ["abi"]}}}}`
+
+	rec, _ := do(t, s, http.MethodPost, "/compile?version=0.5.9", input)
+	require.Equal(t, http.StatusOK, rec.Code, "Version not served should be compiled by the registry")
+	out := &solc.Output{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), out))
+	assert.Empty(t, out.Errors, "A.sol should compile with 0.5.9")
+	assert.Contains(t, out.Contracts["A.sol"], "C")
+	assert.Equal(t, []string{"0.5.9+commit.e560f70d"}, s.Registry.Versions(), "Registry should load the requested version")
+
+	rec, _ = do(t, s, http.MethodPost, "/compile", input)
+	assert.Equal(t, http.StatusOK, rec.Code, "Single compiler should still be used by default")
+
+	rec, _ = do(t, s, http.MethodPost, "/compile?version=^0.5.0", input)
+	assert.Equal(t, http.StatusOK, rec.Code, "Constraints should be routed to the registry")
+
+	rec, res := do(t, s, http.MethodPost, "/compile?version=^0.7.0", input)
+	assert.Equal(t, http.StatusNotFound, rec.Code, "Version missing from the registry should not be found")
+	assert.Contains(t, res["error"], "no compiler")
+
+	rec, _ = do(t, s, http.MethodPost, "/compile?version=abc", input)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "Invalid constraints should be rejected")
+
+	rec, res = do(t, s, http.MethodGet, "/versions", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []interface{}{"0.6.2+commit.bacdbe57"}, res["versions"])
+	assert.Equal(t, []interface{}{"0.5.9+commit.e560f70d"}, res["registry"], "Versions loaded by the registry should be listed")
+
+	registryOnly := New()
+	registryOnly.Registry = s.Registry
+	rec, _ = do(t, registryOnly, http.MethodPost, "/compile", input)
+	assert.Equal(t, http.StatusOK, rec.Code, "Omitted version should be taken from the version pragmas")
+}
+
+func TestServerRegistryErrors(t *testing.T) {
+	releases := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusInternalServerError)
+	}))
+	defer releases.Close()
+	s := New()
+	s.Registry = solc.NewRegistry(solc.RegistryConfig{Dir: "../solc-bin", ReleasesURL: releases.URL})
+	defer s.Registry.Close()
+	input := `{"language": "Solidity", "sources": {"A.sol": {"content": "contract C {}"}}}`
+
+	rec, _ := do(t, s, http.MethodPost, "/compile?version=^0.7.0", input)
+	assert.Equal(t, http.StatusBadGateway, rec.Code, "Releases failing to download should be a bad gateway")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodPost, "/compile?version=^0.7.0", strings.NewReader(input)).WithContext(ctx)
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, statusClientClosedRequest, rec.Code, "Cancelled requests should be told apart")
+}
+
+func TestServerPolicy(t *testing.T) {
+	compiler := &fakeSolc{version: "0.6.2+commit.bacdbe57.Emscripten.clang"}
+	s := New(compiler)
+	s.Policy = func(r *http.Request) solc.CompilePolicy {
+		if r.Header.Get("X-Tenant") == "trusted" {
+			return solc.CompilePolicy{}
+		}
+		return solc.CompilePolicy{InputLimits: solc.InputLimits{MaxSourceBytes: 16}, DenyURLs: true, Timeout: time.Second}
+	}
+
+	rec, _ := do(t, s, http.MethodPost, "/compile", `{"sources": {"A.sol": {"content": "contract C {}"}}}`)
+	assert.Equal(t, http.StatusOK, rec.Code, "Input within the policy should compile")
+
+	rec, res := do(t, s, http.MethodPost, "/compile", `{"sources": {"A.sol": {"content": "contract Large {}"}}}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code, "Input over the policy should be rejected")
+	assert.Equal(t, "source bytes", res["limit"])
+
+	rec, res = do(t, s, http.MethodPost, "/compile", `{"sources": {"A.sol": {"urls": ["/etc/passwd"]}}}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "URL sources should be denied")
+	assert.Contains(t, res["error"], "URL sources are denied")
+
+	req := httptest.NewRequest(http.MethodPost, "/compile", strings.NewReader(`{"sources": {"A.sol": {"urls": ["A.sol"]}}}`))
+	req.Header.Set("X-Tenant", "trusted")
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code, "Policies should apply per request")
+
+	compiler.err = &solc.MemoryLimitError{Usage: 2, Limit: 1}
+	rec, _ = do(t, s, http.MethodPost, "/compile", `{"sources": {"A.sol": {"content": "contract C {}"}}}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code, "Compilations over the memory of the policy should be rejected")
+}
+
+func TestServerHealth(t *testing.T) {
+	healthy := &fakeSolc{version: "0.6.2+commit.bacdbe57.Emscripten.clang"}
+	lazy := &fakeSolc{version: "0.5.9+commit.e560f70d.Emscripten.clang", warming: true}
+	s := New(healthy, lazy)
+
+	rec, _ := do(t, s, http.MethodGet, "/healthz", "")
+	assert.Equal(t, http.StatusOK, rec.Code, "Live compilers should be healthy")
+
+	rec, res := do(t, s, http.MethodGet, "/readyz", "")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code, "Warming compilers should not be ready")
+	assert.Equal(t, []interface{}{"0.5.9+commit.e560f70d"}, res["pending"])
+
+	lazy.warming = false
+	rec, res = do(t, s, http.MethodGet, "/readyz", "")
+	assert.Equal(t, http.StatusOK, rec.Code, "Warmed up compilers should be ready")
+	assert.Equal(t, []interface{}{"0.5.9+commit.e560f70d", "0.6.2+commit.bacdbe57"}, res["versions"])
+
+	healthy.unhealthy = errors.New("out of memory")
+	rec, res = do(t, s, http.MethodGet, "/healthz", "")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code, "Dead compilers should be unhealthy")
+	assert.Equal(t, map[string]interface{}{"0.6.2+commit.bacdbe57": "out of memory"}, res["errors"])
+
+	rec, _ = do(t, New(), http.MethodGet, "/readyz", "")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code, "Server without compilers should not be ready")
+}
+
+func TestServerShutdown(t *testing.T) {
+	compiler := &fakeSolc{version: "0.6.2+commit.bacdbe57.Emscripten.clang", block: make(chan struct{})}
+	s := New(compiler)
+	input := `{"language": "Solidity", "sources": {"A.sol": {"content": "contract C {}"}}}`
+
+	done := make(chan int)
+	go func() {
+		req := httptest.NewRequest(http.MethodPost, "/compile", strings.NewReader(input))
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		done <- rec.Code
+	}()
+	for !func() bool { s.state.Lock(); defer s.state.Unlock(); return s.inflight == 1 }() {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, s.Shutdown(ctx), "Shutdown should wait for compilations in progress")
+
+	rec, _ := do(t, s, http.MethodGet, "/readyz", "")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code, "Draining server should not be ready")
+	rec, _ = do(t, s, http.MethodPost, "/compile", input)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code, "Draining server should reject compilations")
+	rec, _ = do(t, s, http.MethodGet, "/healthz", "")
+	assert.Equal(t, http.StatusOK, rec.Code, "Draining server should stay alive")
+
+	close(compiler.block)
+	assert.Equal(t, http.StatusOK, <-done, "Compilation in progress should complete")
+	assert.NoError(t, s.Shutdown(context.Background()), "Shutdown should not error once drained")
+}
@@ -0,0 +1,133 @@
+package solc
+
+import (
+	"context"
+	"sync"
+)
+
+// shared holds the process-wide compilers returned by Get, by long version
+var shared = struct {
+	mux       sync.Mutex
+	instances map[string]*sharedInstance
+}{instances: make(map[string]*sharedInstance)}
+
+// sharedInstance is a compiler shared by every Get of a version
+type sharedInstance struct {
+	version string
+
+	// ready is closed once solc or err is set
+	ready chan struct{}
+	solc  Solc
+	err   error
+
+	refs int
+}
+
+// sharedSolc is a reference to a shared instance, closing it releases the reference
+type sharedSolc struct {
+	instance *sharedInstance
+
+	mux    sync.Mutex
+	closed bool
+}
+
+// Get returns the compiler of SOLC_BIN_DIR with the highest version satisfying version (e.g. "0.6.2" or "^0.5.0")
+//
+// Compilers are created on first use and shared by the whole process. Each returned Solc must be
+// closed, the shared compiler is closed once every reference is
+func Get(version string) (Solc, error) {
+	binary, err := ResolveBinary(SOLC_BIN_DIR, version)
+	if err != nil {
+		return nil, err
+	}
+
+	shared.mux.Lock()
+	instance, ok := shared.instances[binary.Version]
+	if !ok {
+		instance = &sharedInstance{version: binary.Version, ready: make(chan struct{})}
+		shared.instances[binary.Version] = instance
+	}
+	instance.refs++
+	shared.mux.Unlock()
+
+	if !ok {
+		instance.solc, instance.err = NewFromFile(binary.Path)
+		close(instance.ready)
+	}
+	<-instance.ready
+	if instance.err != nil {
+		releaseShared(instance)
+		return nil, instance.err
+	}
+
+	return &sharedSolc{instance: instance}, nil
+}
+
+// releaseShared drops a reference to instance, closing it with the last one
+func releaseShared(instance *sharedInstance) error {
+	shared.mux.Lock()
+	instance.refs--
+	last := instance.refs == 0
+	if last {
+		delete(shared.instances, instance.version)
+	}
+	shared.mux.Unlock()
+
+	if last && instance.solc != nil {
+		return instance.solc.Close()
+	}
+	return nil
+}
+
+func (s *sharedSolc) get() (Solc, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.closed {
+		return nil, ErrClosed
+	}
+	return s.instance.solc, nil
+}
+
+func (s *sharedSolc) License() string {
+	solc, err := s.get()
+	if err != nil {
+		return ""
+	}
+	return solc.License()
+}
+
+// Version remains available after Close
+func (s *sharedSolc) Version() string {
+	return s.instance.solc.Version()
+}
+
+func (s *sharedSolc) Compile(input *Input) (*Output, error) {
+	return s.CompileContext(context.Background(), input)
+}
+
+func (s *sharedSolc) CompileContext(ctx context.Context, input *Input) (*Output, error) {
+	solc, err := s.get()
+	if err != nil {
+		return nil, err
+	}
+	return CompileContext(ctx, solc, input)
+}
+
+func (s *sharedSolc) HeapStatistics() HeapStatistics {
+	solc, err := s.get()
+	if err != nil {
+		return HeapStatistics{}
+	}
+	return solc.HeapStatistics()
+}
+
+// Close releases the reference, it is safe to call it several times
+func (s *sharedSolc) Close() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	return releaseShared(s.instance)
+}
@@ -0,0 +1,46 @@
+package solc
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	var wg sync.WaitGroup
+	refs := make([]Solc, 4)
+	errs := make([]error, len(refs))
+	for i := range refs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			refs[i], errs[i] = Get("0.6.2")
+		}(i)
+	}
+	wg.Wait()
+	for i := range refs {
+		require.NoError(t, errs[i], "Get should not error")
+	}
+	assert.Len(t, shared.instances, 1, "Concurrent Get should share a single compiler")
+	assert.Equal(t, "0.6.2+commit.bacdbe57.Emscripten.clang", refs[0].Version())
+
+	for _, ref := range refs[1:] {
+		require.NoError(t, ref.Close(), "Close should not error")
+	}
+	require.NoError(t, refs[1].Close(), "Closing twice should not error")
+	_, err := refs[1].Compile(NewInput().AddSource("One.sol", "contract One {}"))
+	assert.Equal(t, ErrClosed, err, "Closed reference should not compile")
+
+	out, err := refs[0].Compile(NewInput().AddSource("One.sol", "pragma solidity ^0.6.1; contract One {}"))
+	require.NoError(t, err, "Open reference should compile")
+	assert.Empty(t, out.Errors, "One.sol should compile")
+
+	require.NoError(t, refs[0].Close(), "Close should not error")
+	assert.Empty(t, shared.instances, "Compiler should be released with the last reference")
+	assert.NoError(t, CheckLeaks(), "Released compiler should be closed")
+
+	_, err = Get("^0.7.0")
+	assert.Error(t, err, "Get should error for a missing version")
+}
@@ -0,0 +1,110 @@
+package solc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+
+	"github.com/nmvalera/solc-go/types"
+)
+
+// SignatureExt is the extension of detached signature files (see WriteSignature)
+const SignatureExt = ".sig"
+
+// ErrInvalidSignature is returned when a signature does not verify
+var ErrInvalidSignature = errors.New("solc: invalid signature")
+
+// Sign returns a detached signature of the deterministic JSON of v (see MarshalDeterministic), typically
+// an *Artifact or a *BuildInfo, which is the content of the files they write
+//
+// Ed25519 keys sign the JSON, ECDSA (ASN.1 signature) and RSA (PKCS #1 v1.5) keys its SHA-256
+func Sign(v interface{}, key crypto.Signer) ([]byte, error) {
+	b, err := types.MarshalDeterministic(v)
+	if err != nil {
+		return nil, err
+	}
+	return signBytes(b, key)
+}
+
+// Verify checks signature is a signature of the deterministic JSON of v by the private key of pub
+func Verify(v interface{}, signature []byte, pub crypto.PublicKey) error {
+	b, err := types.MarshalDeterministic(v)
+	if err != nil {
+		return err
+	}
+	return verifyBytes(b, signature, pub)
+}
+
+// WriteSignature signs the content of file (e.g. written by Artifact.WriteFile) and writes the base64
+// encoded signature next to it, into <file>.sig, and returns the signature file path
+func WriteSignature(file string, key crypto.Signer) (string, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	signature, err := signBytes(b, key)
+	if err != nil {
+		return "", err
+	}
+	err = ioutil.WriteFile(file+SignatureExt, []byte(base64.StdEncoding.EncodeToString(signature)), 0644)
+	if err != nil {
+		return "", err
+	}
+	return file + SignatureExt, nil
+}
+
+// VerifySignature checks the signature <file>.sig written by WriteSignature
+func VerifySignature(file string, pub crypto.PublicKey) error {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	encoded, err := ioutil.ReadFile(file + SignatureExt)
+	if err != nil {
+		return err
+	}
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	return verifyBytes(b, signature, pub)
+}
+
+func signBytes(b []byte, key crypto.Signer) ([]byte, error) {
+	if _, ok := key.Public().(ed25519.PublicKey); ok {
+		return key.Sign(rand.Reader, b, crypto.Hash(0))
+	}
+	digest := sha256.Sum256(b)
+	return key.Sign(rand.Reader, digest[:], crypto.SHA256)
+}
+
+func verifyBytes(b, signature []byte, pub crypto.PublicKey) error {
+	digest := sha256.Sum256(b)
+	valid := false
+	switch pub := pub.(type) {
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(pub, b, signature)
+	case *ecdsa.PublicKey:
+		var sig struct{ R, S *big.Int }
+		rest, err := asn1.Unmarshal(signature, &sig)
+		valid = err == nil && len(rest) == 0 && ecdsa.Verify(pub, digest[:], sig.R, sig.S)
+	case *rsa.PublicKey:
+		valid = rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature) == nil
+	default:
+		return fmt.Errorf("solc: unsupported public key %T", pub)
+	}
+	if !valid {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package solc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign(t *testing.T) {
+	dir, err := ioutil.TempDir("", "solc-artifacts")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	_, otherKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	out := &Output{Contracts: map[string]map[string]Contract{"One.sol": {"One": {EVM: EVM{Bytecode: Bytecode{Object: "6080"}}}}}}
+	artifact := NewArtifacts(out)[0]
+	file, err := artifact.WriteFile(dir)
+	require.NoError(t, err, "WriteFile should not error")
+
+	for _, key := range []crypto.Signer{edKey, ecKey, rsaKey} {
+		signature, err := Sign(artifact, key)
+		require.NoError(t, err, "Sign should not error")
+		assert.NoError(t, Verify(artifact, signature, key.Public()), "Signature should verify")
+
+		sigFile, err := WriteSignature(file, key)
+		require.NoError(t, err, "WriteSignature should not error")
+		assert.Equal(t, file+".sig", sigFile)
+		assert.NoError(t, VerifySignature(file, key.Public()), "Signature of the written artifact should verify")
+
+		assert.Equal(t, ErrInvalidSignature, Verify(artifact, signature, otherKey.Public()), "Signature by another key should not verify")
+	}
+
+	signature, err := Sign(artifact, edKey)
+	require.NoError(t, err, "Sign should not error")
+	artifact.Bytecode = "0x6081"
+	assert.Equal(t, ErrInvalidSignature, Verify(artifact, signature, edKey.Public()), "Modified artifacts should not verify")
+
+	require.NoError(t, ioutil.WriteFile(file, []byte("{}"), 0644))
+	assert.Equal(t, ErrInvalidSignature, VerifySignature(file, rsaKey.Public()), "Modified files should not verify")
+}
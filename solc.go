@@ -1,22 +1,18 @@
 package solc
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"path"
+	"runtime"
 	"strings"
 	"sync"
+	"time"
 
 	"rogchap.com/v8go"
 )
 
-type Solc interface {
-	License() string
-	Version() string
-	Compile(input *Input) (*Output, error)
-	Close()
-}
-
 type baseSolc struct {
 	isolate *v8go.Isolate
 	ctx     *v8go.Context
@@ -27,17 +23,50 @@ type baseSolc struct {
 	version *v8go.Value
 	license *v8go.Value
 	compile *v8go.Value
+	// compilePtr is compile taking a pointer to an input in the emscripten heap (see inputValue)
+	compilePtr *v8go.Value
+	// reset frees the allocations of the last compilation, nil for versions not exporting solidity_reset
+	reset *v8go.Value
+
+	opts *options
+
+	// version string resolved at initialization
+	fullVersion string
+
+	// terminated is set once an execution has been terminated, leaving the emscripten module in an unusable state
+	terminated bool
+
+	// exhausted is set once a compilation exceeded the memory of its policy, the instance should be replaced
+	exhausted *MemoryLimitError
+
+	closed bool
+
+	// console holds the lines printed during the last compilation (see WithConsoleCapture)
+	console []string
+
+	// profile holds the timings of the last compilation (see WithProfiling)
+	profile *CompileProfile
+
+	stats *statsRecorder
 }
 
 // New creates a new Solc binding using the underlying soljonjs emscripten binary
-func New(soljsonjs string) (Solc, error) {
-	return new(soljsonjs)
+func New(soljsonjs string, opts ...Option) (Solc, error) {
+	return new(soljsonjs, opts...)
 }
 
-func new(soljsonjs string) (*baseSolc, error) {
+func new(soljsonjs string, opts ...Option) (*baseSolc, error) {
+	o, err := newOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	_, span := o.startSpan(context.Background(), SpanInit)
+
 	// Create v8go JS execution context
 	isolate, err := v8go.NewIsolate()
 	if err != nil {
+		span.End(err)
 		return nil, err
 	}
 	ctx, _ := v8go.NewContext(isolate)
@@ -47,19 +76,45 @@ func new(soljsonjs string) (*baseSolc, error) {
 		mux:     &sync.Mutex{},
 		isolate: isolate,
 		ctx:     ctx,
+		opts:    o,
+		stats:   newStatsRecorder(),
 	}
 
 	// Initialize solc
 	err = solc.init(soljsonjs)
 	if err != nil {
+		solc.Close()
+		err = wrapJSError(PhaseInit, err)
+		span.End(err)
 		return nil, err
 	}
+	span.SetAttribute(AttrVersion, solc.fullVersion)
+	span.End(nil)
+	o.emit(Event{Kind: EventInit, Version: solc.fullVersion, Duration: time.Since(start)})
+	solc.drainConsole()
+
+	trackInstance(solc)
+	if o.finalizer {
+		runtime.SetFinalizer(solc, (*baseSolc).Close)
+	}
 
 	return solc, nil
 }
 
 func (solc *baseSolc) init(soljsonjs string) error {
+	// Capture console output, only when a logger or console capture is set
+	if solc.opts.capturesConsole() {
+		_, err := solc.ctx.RunScript(consoleScript, "console.js")
+		if err != nil {
+			return err
+		}
+	}
+
 	// Execute solcjson.js script
+	//
+	// This accounts for most of the initialization time. The v8go binding in use only exposes RunScript,
+	// without access to v8 code cache (ScriptCompiler::CachedData), so compiled code can not be persisted
+	// between instances until the binding is upgraded
 	_, err := solc.ctx.RunScript(soljsonjs, "soljson.js")
 	if err != nil {
 		return err
@@ -97,59 +152,205 @@ func (solc *baseSolc) init(soljsonjs string) error {
 		return err
 	}
 
+	// Bind the heap helpers used to transfer large inputs
+	err = solc.bindHeap()
+	if err != nil {
+		return err
+	}
+
+	// Bind reset function (since 0.6.0)
+	if solc.opts.reset && strings.Contains(soljsonjs, "_solidity_reset") {
+		solc.reset, err = solc.ctx.RunScript("Module.cwrap('solidity_reset', null, [])", "wrap_reset.js")
+		if err != nil {
+			return err
+		}
+	}
+
+	val, err := solc.version.Call(solc.ctx, nil)
+	if err != nil {
+		return wrapJSError(PhaseVersion, err)
+	}
+	solc.fullVersion = val.String()
+
 	return nil
 }
 
-func (solc *baseSolc) Close() {
+func (solc *baseSolc) Close() error {
 	solc.mux.Lock()
-	defer solc.mux.Lock()
+	defer solc.mux.Unlock()
+	if solc.closed {
+		return nil
+	}
+	solc.closed = true
+	untrackInstance(solc)
+	runtime.SetFinalizer(solc, nil)
 	solc.ctx.Close()
 	solc.isolate.Close()
+	return nil
 }
 
 func (solc *baseSolc) License() string {
 	if solc.license != nil {
 		solc.mux.Lock()
-		defer solc.mux.Lock()
-		val, _ := solc.license.Call(solc.ctx, nil)
+		defer solc.mux.Unlock()
+		if solc.closed {
+			return ""
+		}
+		val, err := solc.license.Call(solc.ctx, nil)
+		if err != nil {
+			solc.opts.logf("%v", wrapJSError(PhaseLicense, err))
+			return ""
+		}
 		return val.String()
 	}
 	return ""
 }
 
+// Version remains available after Close
 func (solc *baseSolc) Version() string {
-	if solc.version != nil {
-		solc.mux.Lock()
-		defer solc.mux.Lock()
-		val, _ := solc.version.Call(solc.ctx, nil)
-		return val.String()
-	}
-	return ""
+	return solc.fullVersion
 }
 
 func (solc *baseSolc) Compile(input *Input) (*Output, error) {
-	// Marshal Solc Compiler Input
-	b, err := json.Marshal(input)
-	if err != nil {
+	return solc.CompileContext(context.Background(), input)
+}
+
+// CompileContext is like Compile, ctx parents the compilation span (see WithTracer)
+func (solc *baseSolc) CompileContext(ctx context.Context, input *Input) (*Output, error) {
+	_, span := solc.opts.startSpan(ctx, SpanCompile)
+	span.SetAttribute(AttrVersion, solc.fullVersion)
+	span.SetAttribute(AttrSources, len(input.Sources))
+
+	if err := solc.checkInput(ctx, input); err != nil {
+		span.End(err)
 		return nil, err
 	}
 
-	// Run Compilation
-	solc.mux.Lock()
-	defer solc.mux.Unlock()
+	var coverage *CoverageMap
+	if solc.opts.coverage {
+		var err error
+		input, coverage, err = solc.instrument(ctx, input, span)
+		if err != nil {
+			span.End(err)
+			return nil, err
+		}
+	}
+
+	if len(solc.opts.analyzers) > 0 {
+		input = withAST(input)
+	}
+
+	out, err := solc.compileCached(ctx, input, span)
+	if err == nil && len(solc.opts.analyzers) > 0 {
+		out, err = solc.opts.analyze(out)
+	}
+	if err == nil && coverage != nil {
+		// Outputs may be shared (e.g. by caches), the coverage map is attached to a copy
+		covered := *out
+		covered.Coverage = coverage
+		out = &covered
+	}
+	span.End(err)
+
+	return out, err
+}
+
+// checkInput runs the checks of input preceding its compilation: validation, strict settings, output budget
+// and the compile policy of ctx
+func (solc *baseSolc) checkInput(ctx context.Context, input *Input) error {
+	if solc.opts.validate {
+		if err := input.Validate(); err != nil {
+			return err
+		}
+	}
+	if solc.opts.strict {
+		if err := checkSettings(solc.fullVersion, input); err != nil {
+			return err
+		}
+	}
+	if err := CheckOutputBudget(input, solc.opts.outputBudget); err != nil {
+		if solc.opts.strict {
+			return err
+		}
+		solc.opts.emit(Event{Kind: EventOutputBudget, Version: solc.fullVersion, Sources: len(input.Sources), Err: err})
+	}
+
+	if policy, ok := policyFromContext(ctx); ok {
+		if err := policy.Check(input); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	val_in, err := solc.ctx.Create(string(b))
+// instrument instruments input for coverage (see Instrument), it returns input as is if it does not compile
+func (solc *baseSolc) instrument(ctx context.Context, input *Input, span Span) (*Input, *CoverageMap, error) {
+	out, err := solc.compileCached(ctx, astInput(input), span)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, e := range out.Errors {
+		if e.Severity == "error" {
+			return input, nil, nil
+		}
+	}
+	return Instrument(input, out)
+}
+
+func (solc *baseSolc) compileCached(ctx context.Context, input *Input, span Span) (*Output, error) {
+	cache := solc.opts.cache
+	if cache == nil {
+		return solc.run(ctx, input, span)
+	}
+
+	key := buildKey(solc.fullVersion, input)
+	out, err := cache.Get(key)
+	if err == nil && out != nil {
+		solc.stats.cacheHit()
+		solc.opts.emit(Event{Kind: EventCacheHit, Version: solc.fullVersion, Sources: len(input.Sources), Key: key})
+		span.SetAttribute(AttrCached, true)
+		return out, nil
+	}
+	solc.opts.emit(Event{Kind: EventCacheMiss, Version: solc.fullVersion, Sources: len(input.Sources), Key: key})
+	span.SetAttribute(AttrCached, false)
+
+	out, err = solc.run(ctx, input, span)
 	if err != nil {
 		return nil, err
 	}
-	val_one, _ := solc.ctx.Create(1)
-	val_out, err := solc.compile.Call(solc.ctx, nil, val_in, val_one, val_one)
+
+	err = cache.Put(key, out)
 	if err != nil {
 		return nil, err
 	}
 
+	return out, nil
+}
+
+// run compiles input in the v8 context
+func (solc *baseSolc) run(ctx context.Context, input *Input, span Span) (*Output, error) {
 	out := &Output{}
-	err = json.Unmarshal([]byte(val_out.String()), out)
+	err := solc.exec(ctx, input, span, func(val *v8go.Value) error {
+		start := time.Now()
+		b, err := solc.outputBytes(val)
+		if err != nil {
+			return err
+		}
+		span.SetAttribute(AttrOutputBytes, len(b))
+		solc.stats.output(len(b))
+		if solc.opts.console {
+			out.Console = solc.console
+		}
+		if solc.profile == nil {
+			return json.Unmarshal(b, out)
+		}
+		solc.profile.Transfer += time.Since(start)
+		start = time.Now()
+		err = json.Unmarshal(b, out)
+		solc.profile.Unmarshal = time.Since(start)
+		out.Profile = solc.profile
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -157,17 +358,187 @@ func (solc *baseSolc) Compile(input *Input) (*Output, error) {
 	return out, nil
 }
 
-func NewFromFile(file string) (Solc, error) {
+// exec compiles input and passes the output JS string to decode, while still holding the instance
+//
+// The timeout and memory limit of the policy of ctx apply (see ContextWithPolicy)
+func (solc *baseSolc) exec(ctx context.Context, input *Input, span Span, decode func(*v8go.Value) error) error {
+	// Marshal Solc Compiler Input
+	start := time.Now()
+	b, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	marshal := time.Since(start)
+	return solc.execJSON(ctx, b, len(input.Sources), span, func(val *v8go.Value) error {
+		if solc.profile != nil {
+			solc.profile.Marshal = marshal
+		}
+		return decode(val)
+	})
+}
+
+// execJSON is like exec for a standard JSON input passed as is to the compiler
+func (solc *baseSolc) execJSON(ctx context.Context, b []byte, sources int, span Span, decode func(*v8go.Value) error) error {
+	policy, _ := policyFromContext(ctx)
+
+	span.SetAttribute(AttrInputBytes, len(b))
+	if limit := solc.opts.maxInputSize; limit > 0 && len(b) > limit {
+		return &SizeLimitError{Kind: "input", Size: len(b), Limit: limit}
+	}
+
+	// Run Compilation
+	solc.mux.Lock()
+	defer solc.mux.Unlock()
+
+	if solc.closed {
+		return ErrClosed
+	}
+	if solc.terminated {
+		return ErrTerminated
+	}
+
+	err := solc.checkMemory()
+	if err == nil {
+		err = solc.checkMemoryLimit(policy.MaxMemory)
+	}
+	if err != nil {
+		return err
+	}
+
+	solc.opts.emit(Event{Kind: EventCompileStart, Version: solc.fullVersion, Sources: sources})
+	start := time.Now()
+	timeout := solc.opts.timeout
+	if policy.Timeout > 0 && (timeout <= 0 || policy.Timeout < timeout) {
+		timeout = policy.Timeout
+	}
+	stop := solc.watchdog(timeout)
+
+	solc.profile = nil
+	if solc.opts.profile {
+		solc.profile = &CompileProfile{}
+	}
+	compile, val_in, free, err := solc.inputValue(b)
+	if err != nil {
+		stop()
+		return err
+	}
+	// No import callback (null function pointer), so missing sources are reported in the output
+	val_null, _ := solc.ctx.Create(0)
+	call := time.Now()
+	val_out, err := compile.Call(solc.ctx, nil, val_in, val_null, val_null)
+	if solc.profile != nil {
+		solc.profile.Transfer = call.Sub(start)
+		solc.profile.Call = time.Since(call)
+	}
+	if timedOut := stop(); timedOut {
+		solc.terminated = true
+		err = ErrTimeout
+	} else if err != nil {
+		err = wrapJSError(PhaseCompile, err)
+	} else {
+		err = solc.checkOutputSize(val_out)
+	}
+	elapsed := time.Since(start)
+	solc.stats.compiled(elapsed, len(b), err)
+	solc.opts.emit(Event{Kind: EventCompile, Version: solc.fullVersion, Sources: sources, Duration: elapsed, Err: err})
+	solc.console = nil
+	if !solc.terminated {
+		free()
+		solc.console = solc.drainConsole()
+		// The output has been copied to a JS string so the memory solc holds it in can be freed
+		if resetErr := solc.resetMemory(); err == nil {
+			err = resetErr
+		}
+		// The emscripten memory does not shrink, the instance is over the policy until replaced
+		if memErr, ok := solc.checkMemoryLimit(policy.MaxMemory).(*MemoryLimitError); ok {
+			solc.exhausted = memErr
+			if err == nil {
+				err = memErr
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return decode(val_out)
+}
+
+// resetMemory frees the internal allocations of solc, the emscripten heap does not shrink but is reused
+func (solc *baseSolc) resetMemory() error {
+	if solc.reset == nil {
+		return nil
+	}
+	_, err := solc.reset.Call(solc.ctx, nil)
+	if err != nil {
+		return wrapJSError(PhaseReset, err)
+	}
+	return nil
+}
+
+// checkOutputSize measures the output inside v8 so oversized outputs are never copied into Go memory
+func (solc *baseSolc) checkOutputSize(out *v8go.Value) error {
+	limit := solc.opts.maxOutputSize
+	if limit <= 0 {
+		return nil
+	}
+
+	size, err := solc.jsLength(out)
+	if err != nil {
+		return err
+	}
+	if size > limit {
+		return &SizeLimitError{Kind: "output", Size: size, Limit: limit}
+	}
+	return nil
+}
+
+// watchdog terminates JS execution once timeout elapses, if positive
+//
+// The returned stop function disarms the watchdog and indicates whether execution got terminated
+func (solc *baseSolc) watchdog(timeout time.Duration) (stop func() bool) {
+	if timeout <= 0 {
+		return func() bool { return false }
+	}
+
+	var (
+		mux      sync.Mutex
+		stopped  bool
+		timedOut bool
+	)
+	timer := time.AfterFunc(timeout, func() {
+		mux.Lock()
+		defer mux.Unlock()
+		if !stopped {
+			timedOut = true
+			solc.isolate.TerminateExecution()
+		}
+	})
+
+	return func() bool {
+		mux.Lock()
+		defer mux.Unlock()
+		stopped = true
+		timer.Stop()
+		return timedOut
+	}
+}
+
+func NewFromFile(file string, opts ...Option) (Solc, error) {
 	soljson, err := ioutil.ReadFile(file)
 	if err != nil {
 		return nil, err
 	}
 
-	return New(string(soljson))
+	return New(string(soljson), opts...)
 }
 
+// SOLC_BIN_DIR is the directory of the soljson binaries of Get
 const SOLC_BIN_DIR = "./solc-bin"
 
+// Solc6_2_0 returns a new 0.6.2 compiler and panics if it can not be created
+//
+// Deprecated: use Get("0.6.2"), which returns errors and shares the compiler
 func Solc6_2_0() Solc {
 	solc, err := NewFromFile(path.Join(SOLC_BIN_DIR, "soljson-v0.6.2+commit.bacdbe57.js"))
 	if err != nil {
@@ -177,6 +548,9 @@ func Solc6_2_0() Solc {
 	return solc
 }
 
+// Solc5_9_0 returns a new 0.5.9 compiler and panics if it can not be created
+//
+// Deprecated: use Get("0.5.9"), which returns errors and shares the compiler
 func Solc5_9_0() Solc {
 	solc, err := NewFromFile(path.Join(SOLC_BIN_DIR, "soljson-v0.5.9+commit.e560f70d.js"))
 	if err != nil {
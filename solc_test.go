@@ -2,6 +2,7 @@ package solc
 
 import (
 	"fmt"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -27,6 +28,16 @@ type testCase struct {
 	expectRes res
 }
 
+func TestMain(m *testing.M) {
+	EnableLeakDetection()
+	code := m.Run()
+	if err := CheckLeaks(); err != nil {
+		fmt.Println(err)
+		code = 1
+	}
+	os.Exit(code)
+}
+
 func TestSolc(t *testing.T) {
 	tests := []testCase{
 		// Solc 0.6.2 with pragma ^0.6.1
@@ -125,6 +136,7 @@ func testSolc(t *testing.T, test testCase) {
 	// Read Solsjon file
 	solc, err := NewFromFile(fmt.Sprintf("./solc-bin/soljson-v%v.js", test.commit))
 	require.NoError(t, err, "Creating Solc from valid solc emscripten binary should not error")
+	defer solc.Close()
 
 	// Test License and Version methods
 	assert.Greater(t, len(solc.License()), 10, "License should be valid")
@@ -216,3 +228,115 @@ func testSolc(t *testing.T, test testCase) {
 		}
 	}
 }
+
+func TestMemoryLimit(t *testing.T) {
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js", WithMemoryLimit(1024))
+	require.NoError(t, err, "Creating Solc from valid solc emscripten binary should not error")
+	defer solc.Close()
+
+	stats := solc.HeapStatistics()
+	assert.Greater(t, stats.ModuleMemory, uint64(0), "Emscripten memory size should be reported")
+	assert.Greater(t, stats.Usage(), uint64(1024), "Memory usage should be reported")
+
+	_, err = solc.Compile(&Input{Language: "Solidity"})
+	require.IsType(t, &MemoryLimitError{}, err, "Compile should error once memory limit is exceeded")
+}
+
+func TestClose(t *testing.T) {
+	solc, err := NewFromFile("./solc-bin/soljson-v0.5.9+commit.e560f70d.js")
+	require.NoError(t, err, "Creating Solc from valid solc emscripten binary should not error")
+
+	require.Error(t, CheckLeaks(), "Open compiler should be reported as leaked")
+	require.NoError(t, solc.Close(), "Close should not error")
+	require.NoError(t, solc.Close(), "Closing twice should not error")
+	require.NoError(t, CheckLeaks(), "Closed compiler should not be reported as leaked")
+
+	_, err = solc.Compile(&Input{Language: "Solidity"})
+	assert.Equal(t, ErrClosed, err, "Compile should error once closed")
+	assert.Equal(t, "", solc.License(), "License should be empty once closed")
+	assert.Equal(t, "0.5.9+commit.e560f70d.Emscripten.clang", solc.Version(), "Version should remain available once closed")
+}
+
+func TestJSError(t *testing.T) {
+	_, err := New("throw new Error('broken soljson')")
+	require.IsType(t, &JSError{}, err, "Init exception should be a JSError")
+	jsErr := err.(*JSError)
+	assert.Equal(t, PhaseInit, jsErr.Phase, "Phase should be init")
+	assert.Contains(t, jsErr.Message, "broken soljson", "Message should be the JS exception message")
+	assert.Contains(t, fmt.Sprintf("%+v", err), "soljson.js", "Stack trace should locate the exception")
+	assert.Equal(t, jsErr.Error(), fmt.Sprintf("%d", err), "Other verbs should print the message")
+}
+
+func TestSizeLimits(t *testing.T) {
+	in := &Input{
+		Language: "Solidity",
+		Sources: map[string]SourceIn{
+			"One.sol": SourceIn{Content: "pragma solidity ^0.6.1; contract One { function one() public pure returns (uint) { return 1; } }"},
+		},
+		Settings: Settings{
+			OutputSelection: map[string]map[string][]string{"*": map[string][]string{"*": []string{"*"}}},
+		},
+	}
+
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js", WithMaxInputSize(100))
+	require.NoError(t, err, "Creating Solc from valid solc emscripten binary should not error")
+	_, err = solc.Compile(in)
+	require.IsType(t, &SizeLimitError{}, err, "Compile should error on oversized input")
+	assert.Equal(t, "input", err.(*SizeLimitError).Kind)
+	solc.Close()
+
+	solc, err = NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js", WithMaxOutputSize(1000))
+	require.NoError(t, err, "Creating Solc from valid solc emscripten binary should not error")
+	defer solc.Close()
+	_, err = solc.Compile(in)
+	require.IsType(t, &SizeLimitError{}, err, "Compile should error on oversized output")
+	assert.Equal(t, "output", err.(*SizeLimitError).Kind)
+	assert.Greater(t, err.(*SizeLimitError).Size, 1000)
+}
+
+func TestMissingImport(t *testing.T) {
+	for _, file := range []string{"./solc-bin/soljson-v0.6.2+commit.bacdbe57.js", "./solc-bin/soljson-v0.5.9+commit.e560f70d.js"} {
+		solc, err := NewFromFile(file)
+		require.NoError(t, err, "Creating Solc from valid solc emscripten binary should not error")
+
+		out, err := solc.Compile(&Input{
+			Language: "Solidity",
+			Sources:  map[string]SourceIn{"One.sol": SourceIn{Content: "import \"./Missing.sol\"; contract One {}"}},
+		})
+		require.NoError(t, err, "Compile should report missing imports in the output")
+		require.Len(t, out.Errors, 1, "Missing import should be reported")
+		assert.Equal(t, "error", out.Errors[0].Severity)
+		assert.Contains(t, out.Errors[0].Message, "not found")
+
+		// solc calls no import callback, so the instance survives missing imports
+		out, err = solc.Compile(NewInput().AddSource("Two.sol", "contract Two {}"))
+		require.NoError(t, err, "Compile should not error after a missing import")
+		assert.Empty(t, firstError(out))
+		assert.Contains(t, out.Contracts["Two.sol"], "Two")
+		solc.Close()
+	}
+}
+
+func TestMemoryReset(t *testing.T) {
+	in := NewInput().AddSource("One.sol", "pragma solidity ^0.6.2; contract One {}")
+
+	solc, err := new(mustReadFile(t, "./solc-bin/soljson-v0.6.2+commit.bacdbe57.js"))
+	require.NoError(t, err, "Creating Solc from valid solc emscripten binary should not error")
+	defer solc.Close()
+	require.NotNil(t, solc.reset, "solidity_reset should be bound by default")
+	for i := 0; i < 3; i++ {
+		out, err := solc.Compile(in)
+		require.NoError(t, err, "Compile should not error after a reset")
+		assert.Contains(t, out.Contracts["One.sol"], "One")
+	}
+
+	solc, err = new(mustReadFile(t, "./solc-bin/soljson-v0.6.2+commit.bacdbe57.js"), WithMemoryReset(false))
+	require.NoError(t, err)
+	defer solc.Close()
+	assert.Nil(t, solc.reset, "solidity_reset should not be bound when disabled")
+
+	solc, err = new(mustReadFile(t, "./solc-bin/soljson-v0.5.9+commit.e560f70d.js"))
+	require.NoError(t, err)
+	defer solc.Close()
+	assert.Nil(t, solc.reset, "solidity_reset should not be bound before 0.6.0")
+}
@@ -0,0 +1,263 @@
+// Package solcmock provides an in-memory solc.Solc returning scripted outputs, to unit test code handling
+// compilations without v8 nor soljson binaries
+package solcmock
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	solc "github.com/nmvalera/solc-go"
+)
+
+// DefaultVersion is the version of mocks unless set with WithVersion
+const DefaultVersion = "0.6.2+commit.bacdbe57.Emscripten.clang"
+
+// Match selects the inputs a scripted response applies to (see Solc.On)
+type Match func(input *solc.Input) bool
+
+// HasSource matches inputs holding the source unit name
+func HasSource(name string) Match {
+	return func(input *solc.Input) bool {
+		_, ok := input.Sources[name]
+		return ok
+	}
+}
+
+// Contains matches inputs with a source containing s
+func Contains(s string) Match {
+	return func(input *solc.Input) bool {
+		for _, source := range input.Sources {
+			if strings.Contains(source.Content, s) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+type response struct {
+	out *solc.Output
+	err error
+}
+
+type rule struct {
+	match Match
+	response
+}
+
+// Solc is a mock compiler, safe for concurrent use
+//
+// Compilations return, in order of precedence, the next queued response (see Return and Fail), the
+// response of the first matching rule (see On), or the default response (see Default), an empty output
+// unless set. Outputs are returned as is, not copied. Every input is recorded (see Inputs)
+type Solc struct {
+	mux      sync.Mutex
+	version  string
+	license  string
+	delay    time.Duration
+	queue    []response
+	rules    []rule
+	fallback response
+	inputs   []*solc.Input
+	healthy  error
+	closed   bool
+}
+
+// New creates a mock of version DefaultVersion compiling every input to an empty output
+func New() *Solc {
+	return &Solc{version: DefaultVersion, fallback: response{out: &solc.Output{}}}
+}
+
+// WithVersion sets the version returned by Version
+func (s *Solc) WithVersion(version string) *Solc {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.version = version
+	return s
+}
+
+// WithLicense sets the license returned by License
+func (s *Solc) WithLicense(license string) *Solc {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.license = license
+	return s
+}
+
+// WithDelay makes compilations take d, compilations through CompileContext stop once their context is done
+func (s *Solc) WithDelay(d time.Duration) *Solc {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.delay = d
+	return s
+}
+
+// Return queues out as the output of the next compilation
+func (s *Solc) Return(out *solc.Output) *Solc {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.queue = append(s.queue, response{out: out})
+	return s
+}
+
+// Fail queues err as the error of the next compilation
+func (s *Solc) Fail(err error) *Solc {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.queue = append(s.queue, response{err: err})
+	return s
+}
+
+// On makes compilations of inputs matching match return out and err, unless a response is queued
+func (s *Solc) On(match Match, out *solc.Output, err error) *Solc {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.rules = append(s.rules, rule{match: match, response: response{out: out, err: err}})
+	return s
+}
+
+// Default sets the response of compilations neither queued nor matching a rule
+func (s *Solc) Default(out *solc.Output, err error) *Solc {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.fallback = response{out: out, err: err}
+	return s
+}
+
+// SetHealthy sets the error returned by Healthy, nil for a healthy compiler
+func (s *Solc) SetHealthy(err error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.healthy = err
+}
+
+// Inputs returns the inputs compiled so far, in order
+func (s *Solc) Inputs() []*solc.Input {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return append([]*solc.Input{}, s.inputs...)
+}
+
+// LastInput returns the input last compiled, nil if none was
+func (s *Solc) LastInput() *solc.Input {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if len(s.inputs) == 0 {
+		return nil
+	}
+	return s.inputs[len(s.inputs)-1]
+}
+
+// Calls returns the number of compilations so far
+func (s *Solc) Calls() int {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return len(s.inputs)
+}
+
+// Reset forgets the recorded inputs and the queued responses
+func (s *Solc) Reset() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.inputs = nil
+	s.queue = nil
+}
+
+func (s *Solc) License() string {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.license
+}
+
+func (s *Solc) Version() string {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.version
+}
+
+func (s *Solc) HeapStatistics() solc.HeapStatistics {
+	return solc.HeapStatistics{}
+}
+
+// Healthy returns the error set with SetHealthy, or solc.ErrClosed once closed
+func (s *Solc) Healthy() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.closed {
+		return solc.ErrClosed
+	}
+	return s.healthy
+}
+
+// Close makes further compilations fail with solc.ErrClosed
+func (s *Solc) Close() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.closed = true
+	return nil
+}
+
+// Compile records input and returns the scripted response
+func (s *Solc) Compile(input *solc.Input) (*solc.Output, error) {
+	return s.CompileContext(context.Background(), input)
+}
+
+// CompileContext is like Compile but returns ctx.Err() if ctx is done before the delay elapsed (see WithDelay)
+func (s *Solc) CompileContext(ctx context.Context, input *solc.Input) (*solc.Output, error) {
+	s.mux.Lock()
+	if s.closed {
+		s.mux.Unlock()
+		return nil, solc.ErrClosed
+	}
+	s.inputs = append(s.inputs, input)
+	r := s.respond(input)
+	delay := s.delay
+	s.mux.Unlock()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return r.out, r.err
+}
+
+// respond returns the response to input, consuming the first queued one if any
+func (s *Solc) respond(input *solc.Input) response {
+	if len(s.queue) > 0 {
+		r := s.queue[0]
+		s.queue = s.queue[1:]
+		return r
+	}
+	for _, rule := range s.rules {
+		if rule.match(input) {
+			return rule.response
+		}
+	}
+	return s.fallback
+}
+
+// ErrorOutput returns an output reporting a compilation error, how solc reports invalid sources
+func ErrorOutput(errorType, message string) *solc.Output {
+	return &solc.Output{Errors: []solc.Error{{
+		Type:             errorType,
+		Component:        "general",
+		Severity:         "error",
+		Message:          message,
+		FormattedMessage: errorType + ": " + message,
+	}}}
+}
+
+// ContractOutput returns an output holding contract name of file (e.g. with canned ABI and bytecode)
+func ContractOutput(file, name string, contract solc.Contract) *solc.Output {
+	return &solc.Output{
+		Sources:   map[string]solc.SourceOut{file: {}},
+		Contracts: map[string]map[string]solc.Contract{file: {name: contract}},
+	}
+}
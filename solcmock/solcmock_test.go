@@ -0,0 +1,85 @@
+package solcmock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	solc "github.com/nmvalera/solc-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ solc.Solc = New()
+
+func TestSolc(t *testing.T) {
+	failure := errors.New("failure")
+	contract := ContractOutput("A.sol", "A", solc.Contract{EVM: solc.EVM{Bytecode: solc.Bytecode{Object: "6080"}}})
+	invalid := ErrorOutput("ParserError", "Expected ';' but got '}'")
+	mock := New().
+		WithVersion("0.8.30+commit.73712a01.Emscripten.clang").
+		On(HasSource("A.sol"), contract, nil).
+		On(Contains("broken"), invalid, nil)
+	assert.Equal(t, "0.8.30+commit.73712a01.Emscripten.clang", mock.Version())
+
+	a := solc.NewInput().AddSource("A.sol", "contract A {}")
+	out, err := mock.Compile(a)
+	require.NoError(t, err, "Compile should not error")
+	assert.Equal(t, contract, out, "Matching rule should respond")
+
+	out, err = mock.Compile(solc.NewInput().AddSource("B.sol", "broken"))
+	require.NoError(t, err, "Compile should not error")
+	assert.Equal(t, "error", out.Errors[0].Severity, "Compilation errors should be reported in the output")
+
+	out, err = mock.Compile(solc.NewInput())
+	require.NoError(t, err, "Compile should not error")
+	assert.Equal(t, &solc.Output{}, out, "Default output should be empty")
+
+	mock.Fail(failure).Return(invalid)
+	_, err = mock.Compile(a)
+	assert.Equal(t, failure, err, "Queued error should come first")
+	out, _ = mock.Compile(a)
+	assert.Equal(t, invalid, out, "Queued output should come before rules")
+	out, _ = mock.Compile(a)
+	assert.Equal(t, contract, out, "Rules should apply once the queue is empty")
+
+	assert.Equal(t, 6, mock.Calls())
+	assert.Len(t, mock.Inputs(), 6, "Inputs should be recorded")
+	assert.Equal(t, a, mock.LastInput())
+	mock.Reset()
+	assert.Nil(t, mock.LastInput(), "Reset should forget inputs")
+
+	mock.Default(nil, solc.ErrTimeout)
+	_, err = mock.Compile(solc.NewInput())
+	assert.Equal(t, solc.ErrTimeout, err, "Default error should be returned")
+
+	mock.SetHealthy(solc.ErrTerminated)
+	assert.Equal(t, solc.ErrTerminated, solc.Healthy(mock))
+	require.NoError(t, mock.Close())
+	assert.Equal(t, solc.ErrClosed, solc.Healthy(mock))
+	_, err = mock.Compile(a)
+	assert.Equal(t, solc.ErrClosed, err, "Closed mock should not compile")
+}
+
+func TestSolcDelay(t *testing.T) {
+	mock := New().WithDelay(time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := solc.CompileContext(ctx, mock, solc.NewInput())
+	assert.Equal(t, context.DeadlineExceeded, err, "Compilation should stop with its context")
+}
+
+func TestSolcPool(t *testing.T) {
+	mock := New()
+	pool, err := solc.NewPool(func() (solc.Solc, error) { return mock, nil }, 2)
+	require.NoError(t, err, "NewPool should not error")
+	defer pool.Close()
+
+	_, err = pool.CompileAll(context.Background(), []*solc.Input{
+		solc.NewInput().AddSource("A.sol", "contract A {}"),
+		solc.NewInput().AddSource("B.sol", "contract B {}"),
+	}, 0)
+	require.NoError(t, err, "CompileAll should not error")
+	assert.Equal(t, 2, mock.Calls(), "Pool should compile with the mock")
+}
@@ -0,0 +1,90 @@
+package solc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+
+	"rogchap.com/v8go"
+)
+
+// jsonCompiler is implemented by compilers accepting a standard JSON input as is
+type jsonCompiler interface {
+	compileJSON(input []byte) ([]byte, error)
+}
+
+// CompileJSON compiles a standard JSON input and returns the standard JSON output
+//
+// Compilers created by New pass input and output through untouched, like solc --standard-json.
+// Other compilers round-trip them through Input and Output, dropping the fields these types do not declare
+func CompileJSON(solc Solc, input []byte) ([]byte, error) {
+	if c, ok := solc.(jsonCompiler); ok {
+		return c.compileJSON(input)
+	}
+
+	in := &Input{}
+	err := json.Unmarshal(input, in)
+	if err != nil {
+		// Report invalid inputs the way solc does
+		return json.Marshal(&Output{Errors: []Error{{
+			Component:        "general",
+			Type:             "JSONError",
+			Severity:         "error",
+			Message:          "Error parsing input JSON: " + err.Error(),
+			FormattedMessage: "Error parsing input JSON: " + err.Error() + "\n",
+		}}})
+	}
+
+	out, err := solc.Compile(in)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(out)
+}
+
+// ServeStandardJSON reads a standard JSON input from r and writes the output to w, like solc --standard-json
+func ServeStandardJSON(solc Solc, r io.Reader, w io.Writer) error {
+	input, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	out, err := CompileJSON(solc, input)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(out, '\n'))
+	return err
+}
+
+func (solc *baseSolc) compileJSON(input []byte) ([]byte, error) {
+	// Only used to report the number of sources, solc reports invalid inputs itself
+	var in struct {
+		Sources map[string]json.RawMessage `json:"sources"`
+	}
+	_ = json.Unmarshal(input, &in)
+
+	_, span := solc.opts.startSpan(context.Background(), SpanCompile)
+	span.SetAttribute(AttrVersion, solc.fullVersion)
+	span.SetAttribute(AttrSources, len(in.Sources))
+
+	var out []byte
+	err := solc.execJSON(context.Background(), input, len(in.Sources), span, func(val *v8go.Value) error {
+		var err error
+		out, err = solc.outputBytes(val)
+		if err != nil {
+			return err
+		}
+		span.SetAttribute(AttrOutputBytes, len(out))
+		solc.stats.output(len(out))
+		return nil
+	})
+	span.End(err)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
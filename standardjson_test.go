@@ -0,0 +1,25 @@
+package solc
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeStandardJSON(t *testing.T) {
+	compiler := &fakeSolc{output: &Output{Contracts: map[string]map[string]Contract{"A.sol": {"A": {Metadata: "{}"}}}}}
+
+	var out bytes.Buffer
+	err := ServeStandardJSON(compiler, strings.NewReader(`{"language": "Solidity"}`), &out)
+	require.NoError(t, err, "ServeStandardJSON should not error")
+	assert.True(t, strings.HasPrefix(out.String(), `{"contracts":{"A.sol":{"A":{"metadata":"{}"`), "Output should be standard JSON")
+	assert.True(t, strings.HasSuffix(out.String(), "\n"), "Output should end with a newline")
+
+	b, err := CompileJSON(compiler, []byte("{"))
+	require.NoError(t, err, "Invalid input should be reported in the output")
+	assert.Contains(t, string(b), `"type":"JSONError"`)
+	assert.Equal(t, 1, compiler.compiles, "Invalid input should not be compiled")
+}
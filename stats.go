@@ -0,0 +1,248 @@
+package solc
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Stats are compilation counters of a compiler since its creation
+type Stats struct {
+	Since time.Time
+
+	// Compiles counts the compilations run by the compiler, Failures the ones that errored
+	// (outputs reporting compilation errors are not failures)
+	Compiles uint64
+	Failures uint64
+
+	// CacheHits counts compilations served by the cache (see WithCache), they are not in Compiles
+	CacheHits uint64
+
+	// BytesIn and BytesOut are the sizes of the standard JSON inputs and outputs
+	BytesIn  uint64
+	BytesOut uint64
+
+	// Restarts counts the instances replaced by pools and recycling compilers
+	Restarts uint64
+
+	// P50 and P95 are percentiles of the durations of the latest compilations
+	P50 time.Duration
+	P95 time.Duration
+}
+
+// Throughput returns the number of compilations per second since the creation of the compiler
+func (s Stats) Throughput() float64 {
+	elapsed := time.Since(s.Since).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Compiles) / elapsed
+}
+
+// StatsOf returns the stats of solc, the zero Stats if it does not collect any
+//
+// Compilers created by New, pools, recycling, lazy and shared compilers collect stats
+func StatsOf(solc Solc) Stats {
+	if c, ok := solc.(interface{ Stats() Stats }); ok {
+		return c.Stats()
+	}
+	return Stats{}
+}
+
+// statsCollector is implemented by compilers able to add their stats to a recorder
+type statsCollector interface {
+	collectStats(r *statsRecorder)
+}
+
+// statsSamples is the number of latest compilation durations percentiles are computed on
+const statsSamples = 1024
+
+// statsRecorder accumulates stats, safe for concurrent use
+type statsRecorder struct {
+	mux   sync.Mutex
+	stats Stats
+
+	// durations is a ring of the latest samples, n is the number of samples ever recorded
+	durations [statsSamples]time.Duration
+	n         int
+}
+
+func newStatsRecorder() *statsRecorder {
+	return &statsRecorder{stats: Stats{Since: time.Now()}}
+}
+
+// compiled records a compilation that ran in the isolate
+func (r *statsRecorder) compiled(d time.Duration, in int, err error) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.stats.Compiles++
+	if err != nil {
+		r.stats.Failures++
+	}
+	r.stats.BytesIn += uint64(in)
+	r.sample(d)
+}
+
+func (r *statsRecorder) output(out int) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.stats.BytesOut += uint64(out)
+}
+
+func (r *statsRecorder) cacheHit() {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.stats.CacheHits++
+}
+
+func (r *statsRecorder) restarted() {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.stats.Restarts++
+}
+
+func (r *statsRecorder) sample(d time.Duration) {
+	r.durations[r.n%statsSamples] = d
+	r.n++
+}
+
+// merge adds the counters and samples of other to r, Since is the earliest of both
+func (r *statsRecorder) merge(other *statsRecorder) {
+	other.mux.Lock()
+	stats := other.stats
+	samples := other.samples()
+	other.mux.Unlock()
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if r.stats.Since.IsZero() || stats.Since.Before(r.stats.Since) {
+		r.stats.Since = stats.Since
+	}
+	r.stats.Compiles += stats.Compiles
+	r.stats.Failures += stats.Failures
+	r.stats.CacheHits += stats.CacheHits
+	r.stats.BytesIn += stats.BytesIn
+	r.stats.BytesOut += stats.BytesOut
+	r.stats.Restarts += stats.Restarts
+	for _, d := range samples {
+		r.sample(d)
+	}
+}
+
+// samples returns the recorded durations, it must be called while holding the lock
+func (r *statsRecorder) samples() []time.Duration {
+	if r.n < statsSamples {
+		return append([]time.Duration{}, r.durations[:r.n]...)
+	}
+	return append([]time.Duration{}, r.durations[:]...)
+}
+
+func (r *statsRecorder) snapshot() Stats {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	stats := r.stats
+	samples := r.samples()
+	if len(samples) > 0 {
+		sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+		stats.P50 = samples[(len(samples)-1)*50/100]
+		stats.P95 = samples[(len(samples)-1)*95/100]
+	}
+	return stats
+}
+
+// collect returns the stats collected by c
+func collect(c statsCollector) Stats {
+	r := &statsRecorder{}
+	c.collectStats(r)
+	return r.snapshot()
+}
+
+func (solc *baseSolc) collectStats(r *statsRecorder) {
+	r.merge(solc.stats)
+}
+
+// Stats returns the counters of the instance
+func (solc *baseSolc) Stats() Stats {
+	return solc.stats.snapshot()
+}
+
+func (p *Pool) collectStats(r *statsRecorder) {
+	p.mux.Lock()
+	all := append([]Solc{}, p.all...)
+	p.mux.Unlock()
+
+	r.merge(p.retired)
+	for _, instance := range all {
+		if c, ok := instance.(statsCollector); ok {
+			c.collectStats(r)
+		}
+	}
+}
+
+// Stats sums the counters of the instances of the pool, replaced ones included
+func (p *Pool) Stats() Stats {
+	return collect(p)
+}
+
+func (solc *recyclingSolc) collectStats(r *statsRecorder) {
+	solc.mux.Lock()
+	defer solc.mux.Unlock()
+	r.merge(solc.retired)
+	if c, ok := solc.current.(statsCollector); ok {
+		c.collectStats(r)
+	}
+}
+
+// Stats waits for a running compilation to complete, like HeapStatistics
+func (solc *recyclingSolc) Stats() Stats {
+	return collect(solc)
+}
+
+func (l *LazySolc) collectStats(r *statsRecorder) {
+	if !l.IsReady() {
+		return
+	}
+	if c, ok := l.solc.(statsCollector); ok {
+		c.collectStats(r)
+	}
+}
+
+// Stats are zero until the compiler is initialized
+func (l *LazySolc) Stats() Stats {
+	return collect(l)
+}
+
+func (s *sharedSolc) collectStats(r *statsRecorder) {
+	solc, err := s.get()
+	if err != nil {
+		return
+	}
+	if c, ok := solc.(statsCollector); ok {
+		c.collectStats(r)
+	}
+}
+
+// Stats are the ones of the process-wide compiler, shared by every reference
+func (s *sharedSolc) Stats() Stats {
+	return collect(s)
+}
+
+// Stats returns the stats of each loaded version, by long version
+//
+// Stats of evicted versions are dropped with them
+func (r *Registry) Stats() map[string]Stats {
+	r.mux.Lock()
+	pools := make(map[string]*Pool)
+	for version, entry := range r.entries {
+		if entry.pool != nil {
+			pools[version] = entry.pool
+		}
+	}
+	r.mux.Unlock()
+
+	stats := make(map[string]Stats)
+	for version, pool := range pools {
+		stats[version] = pool.Stats()
+	}
+	return stats
+}
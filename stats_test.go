@@ -0,0 +1,69 @@
+package solc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	solc, err := NewFromFile("./solc-bin/soljson-v0.5.9+commit.e560f70d.js")
+	require.NoError(t, err, "Creating compiler should not error")
+	defer solc.Close()
+
+	_, err = CompileSource(solc, "One.sol", "pragma solidity ^0.5.9; contract One {}")
+	require.NoError(t, err, "Compile should not error")
+
+	stats := StatsOf(solc)
+	assert.Equal(t, uint64(1), stats.Compiles)
+	assert.Equal(t, uint64(0), stats.Failures)
+	assert.Greater(t, stats.BytesIn, uint64(0), "Input size should be counted")
+	assert.Greater(t, stats.BytesOut, uint64(0), "Output size should be counted")
+	assert.Greater(t, int64(stats.P50), int64(0), "Duration should be sampled")
+	assert.Greater(t, stats.Throughput(), 0.0)
+
+	assert.Equal(t, Stats{}, StatsOf(&fakeSolc{}), "Compiler without stats should report none")
+}
+
+func TestStatsRecorder(t *testing.T) {
+	r := newStatsRecorder()
+	for i := 1; i <= 100; i++ {
+		r.compiled(time.Duration(i)*time.Millisecond, 10, nil)
+	}
+	r.compiled(time.Second, 10, assert.AnError)
+	r.output(5)
+
+	stats := r.snapshot()
+	assert.Equal(t, uint64(101), stats.Compiles)
+	assert.Equal(t, uint64(1), stats.Failures)
+	assert.Equal(t, uint64(1010), stats.BytesIn)
+	assert.Equal(t, uint64(5), stats.BytesOut)
+	assert.Equal(t, 51*time.Millisecond, stats.P50)
+	assert.Equal(t, 96*time.Millisecond, stats.P95)
+
+	for i := 0; i < statsSamples; i++ {
+		r.compiled(time.Millisecond, 0, nil)
+	}
+	assert.Equal(t, time.Millisecond, r.snapshot().P95, "Percentiles should only account for the latest samples")
+
+	merged := &statsRecorder{}
+	merged.merge(r)
+	merged.merge(newStatsRecorder())
+	assert.Equal(t, r.stats.Since, merged.snapshot().Since, "Merged stats should start at the earliest")
+	assert.Equal(t, r.snapshot().Compiles, merged.snapshot().Compiles)
+}
+
+func TestPoolStats(t *testing.T) {
+	p, err := NewPool(func() (Solc, error) {
+		return &crashSolc{fakeSolc: fakeSolc{version: "0.6.2"}}, nil
+	}, 1)
+	require.NoError(t, err, "NewPool should not error")
+	defer p.Close()
+
+	_, _ = p.Compile(echoInputs("crash")[0])
+	_, err = p.Compile(echoInputs("a")[0])
+	require.NoError(t, err, "Compile should not error")
+	assert.Equal(t, uint64(1), p.Stats().Restarts, "Replaced instance should be counted")
+}
@@ -0,0 +1,296 @@
+package solc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"time"
+
+	"rogchap.com/v8go"
+)
+
+// streamChunkSize is the number of UTF-16 code units copied out of v8 at once
+var streamChunkSize = 1 << 20
+
+// ContractFunc receives the contracts of a streamed output one at a time
+type ContractFunc func(file, name string, contract *Contract) error
+
+// DecodeOutput decodes a standard JSON output from r incrementally
+//
+// When fn is not nil contracts are passed to it as they are decoded instead of being
+// kept in the returned output, so only one contract is held in memory at a time
+func DecodeOutput(r io.Reader, fn ContractFunc) (*Output, error) {
+	dec := json.NewDecoder(r)
+	out := &Output{}
+
+	err := expectDelim(dec, '{')
+	if err != nil {
+		return nil, err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok {
+		case "errors":
+			err = dec.Decode(&out.Errors)
+		case "sources":
+			err = dec.Decode(&out.Sources)
+		case "contracts":
+			if fn == nil {
+				err = dec.Decode(&out.Contracts)
+			} else {
+				err = decodeContracts(dec, fn)
+			}
+		default:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return out, expectDelim(dec, '}')
+}
+
+func decodeContracts(dec *json.Decoder, fn ContractFunc) error {
+	err := expectDelim(dec, '{')
+	if err != nil {
+		return err
+	}
+	for dec.More() {
+		file, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		err = expectDelim(dec, '{')
+		if err != nil {
+			return err
+		}
+		for dec.More() {
+			name, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			contract := &Contract{}
+			err = dec.Decode(contract)
+			if err != nil {
+				return err
+			}
+			err = fn(file.(string), name.(string), contract)
+			if err != nil {
+				return err
+			}
+		}
+		err = expectDelim(dec, '}')
+		if err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return fmt.Errorf("solc: invalid output, expected %v got %v", delim, tok)
+	}
+	return nil
+}
+
+// streamCompiler is implemented by compilers able to stream their output
+type streamCompiler interface {
+	compileStream(ctx context.Context, input *Input, fn ContractFunc) (*Output, error)
+}
+
+// CompileStream is like CompileStreamContext with the background context
+func CompileStream(solc Solc, input *Input, fn ContractFunc) (*Output, error) {
+	return CompileStreamContext(context.Background(), solc, input, fn)
+}
+
+// CompileStreamContext compiles input and passes the contracts of the output to fn one at a time
+//
+// The returned output is the output without its contracts. Compilers created by New check input like
+// CompileContext, copy the output out of v8 in chunks to a temporary file then decode it incrementally,
+// bypassing any cache, and call fn once the compiler is released so fn may use it. Compilers with
+// analyzers or coverage, and others, fall back to CompileContext
+func CompileStreamContext(ctx context.Context, solc Solc, input *Input, fn ContractFunc) (*Output, error) {
+	if s, ok := solc.(streamCompiler); ok {
+		return s.compileStream(ctx, input, fn)
+	}
+	out, err := CompileContext(ctx, solc, input)
+	if err != nil {
+		return nil, err
+	}
+	return streamContracts(out, fn)
+}
+
+// streamContracts passes the contracts of out to fn sorted by file and name, and returns out without them
+func streamContracts(out *Output, fn ContractFunc) (*Output, error) {
+	stripped := *out
+	stripped.Contracts = nil
+	files := make([]string, 0, len(out.Contracts))
+	for file := range out.Contracts {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		names := make([]string, 0, len(out.Contracts[file]))
+		for name := range out.Contracts[file] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			contract := out.Contracts[file][name]
+			err := fn(file, name, &contract)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &stripped, nil
+}
+
+func (solc *baseSolc) compileStream(ctx context.Context, input *Input, fn ContractFunc) (*Output, error) {
+	if len(solc.opts.analyzers) > 0 || solc.opts.coverage {
+		// Both need the whole output
+		out, err := solc.CompileContext(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		return streamContracts(out, fn)
+	}
+
+	_, span := solc.opts.startSpan(ctx, SpanCompile)
+	span.SetAttribute(AttrVersion, solc.fullVersion)
+	span.SetAttribute(AttrSources, len(input.Sources))
+	if err := solc.checkInput(ctx, input); err != nil {
+		span.End(err)
+		return nil, err
+	}
+
+	// The output is spilled to a temporary file while the compiler is locked and decoded from it once
+	// released, so fn may use the compiler without the whole output being held in memory
+	f, err := ioutil.TempFile("", "solc-output-*.json")
+	if err != nil {
+		span.End(err)
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	var (
+		console []string
+		profile *CompileProfile
+	)
+	err = solc.exec(ctx, input, span, func(val *v8go.Value) error {
+		start := time.Now()
+		r, err := solc.newJSReader(val)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+
+		_, err = io.Copy(f, r)
+		span.SetAttribute(AttrOutputBytes, r.read)
+		solc.stats.output(r.read)
+		if solc.opts.console {
+			console = solc.console
+		}
+		if solc.profile != nil {
+			solc.profile.Transfer += time.Since(start)
+			profile = solc.profile
+		}
+		return err
+	})
+	if err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		span.End(err)
+		return nil, err
+	}
+
+	start := time.Now()
+	out, err := DecodeOutput(bufio.NewReader(f), fn)
+	if out != nil {
+		out.Console = console
+		if profile != nil {
+			profile.Unmarshal = time.Since(start)
+			out.Profile = profile
+		}
+	}
+	span.End(err)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// jsReader reads a JS string held in the v8 context chunk by chunk
+type jsReader struct {
+	solc *baseSolc
+	buf  []byte
+	eof  bool
+	read int
+}
+
+func (solc *baseSolc) newJSReader(val *v8go.Value) (*jsReader, error) {
+	global := solc.ctx.Global()
+	err := global.Set("__solc_output", val)
+	if err != nil {
+		return nil, err
+	}
+	_, err = solc.ctx.RunScript("__solc_cursor = 0", "stream.js")
+	if err != nil {
+		return nil, err
+	}
+	return &jsReader{solc: solc}, nil
+}
+
+// readChunkScript returns the next chunk of the output, never splitting a surrogate pair
+const readChunkScript = `(function(n) {
+	var s = __solc_output, o = __solc_cursor, e = Math.min(o + n, s.length);
+	if (e < s.length) {
+		var c = s.charCodeAt(e - 1);
+		if (c >= 0xD800 && c <= 0xDBFF) e--;
+	}
+	__solc_cursor = e;
+	return s.substring(o, e);
+})(%v)`
+
+func (r *jsReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.eof {
+			return 0, io.EOF
+		}
+		val, err := r.solc.ctx.RunScript(fmt.Sprintf(readChunkScript, streamChunkSize), "stream.js")
+		if err != nil {
+			return 0, err
+		}
+		r.buf = []byte(val.String())
+		r.eof = len(r.buf) == 0
+		r.read += len(r.buf)
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// Close releases the output held in the v8 context
+func (r *jsReader) Close() error {
+	_, err := r.solc.ctx.RunScript("__solc_output = undefined; __solc_cursor = undefined", "stream.js")
+	return err
+}
@@ -0,0 +1,111 @@
+package solc
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeOutput(t *testing.T) {
+	raw := `{
+		"errors": [{"severity": "warning", "message": "unused"}],
+		"sources": {"A.sol": {"id": 0}},
+		"contracts": {"A.sol": {"A": {"metadata": "a"}, "B": {"metadata": "b"}}},
+		"unknown": {"ignored": [1, 2]}
+	}`
+
+	out, err := DecodeOutput(strings.NewReader(raw), nil)
+	require.NoError(t, err, "DecodeOutput should not error")
+	assert.Len(t, out.Errors, 1)
+	assert.Equal(t, "b", out.Contracts["A.sol"]["B"].Metadata)
+
+	var names []string
+	out, err = DecodeOutput(strings.NewReader(raw), func(file, name string, contract *Contract) error {
+		names = append(names, file+":"+name+":"+contract.Metadata)
+		return nil
+	})
+	require.NoError(t, err, "DecodeOutput should not error")
+	assert.Equal(t, []string{"A.sol:A:a", "A.sol:B:b"}, names, "Contracts should be streamed in order")
+	assert.Nil(t, out.Contracts, "Streamed contracts should not be kept")
+	assert.Equal(t, "unused", out.Errors[0].Message)
+
+	_, err = DecodeOutput(strings.NewReader(`{"contracts": [`), nil)
+	assert.Error(t, err, "DecodeOutput should error on truncated output")
+}
+
+func TestCompileStream(t *testing.T) {
+	defer func(size int) { streamChunkSize = size }(streamChunkSize)
+	streamChunkSize = 7
+
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err, "Creating Solc from valid solc emscripten binary should not error")
+	defer solc.Close()
+
+	in := &Input{
+		Language: "Solidity",
+		Sources: map[string]SourceIn{
+			"One.sol": SourceIn{Content: "pragma solidity ^0.6.1; contract One { /// @notice one\n function one() public pure returns (uint) { return 1; } } contract Two {}"},
+		},
+		Settings: Settings{
+			OutputSelection: map[string]map[string][]string{"*": map[string][]string{"*": []string{"*"}}},
+		},
+	}
+	expected, err := solc.Compile(in)
+	require.NoError(t, err, "Compile should not error")
+
+	streamed := make(map[string]Contract)
+	out, err := CompileStream(solc, in, func(file, name string, contract *Contract) error {
+		streamed[name] = *contract
+		return nil
+	})
+	require.NoError(t, err, "CompileStream should not error")
+	assert.Equal(t, expected.Errors, out.Errors)
+	assert.Equal(t, expected.Contracts["One.sol"], map[string]Contract(streamed), "Streamed contracts should match compiled ones")
+
+	// Contracts are passed once the compiler is released
+	_, err = CompileStream(solc, in, func(file, name string, contract *Contract) error {
+		_, err := solc.Compile(NewInput().AddSource("Three.sol", "contract Three {}"))
+		return err
+	})
+	require.NoError(t, err, "fn should be able to use the compiler")
+	wrapped := DetectMisuse(solc)
+	_, err = CompileStream(wrapped, in, func(file, name string, contract *Contract) error {
+		_, err := wrapped.Compile(NewInput().AddSource("Three.sol", "contract Three {}"))
+		return err
+	})
+	require.NoError(t, err, "Using the compiler from fn should not be a misuse")
+}
+
+func TestCompileStreamProfile(t *testing.T) {
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js", WithProfiling(), WithConsoleCapture())
+	require.NoError(t, err, "Creating Solc from valid solc emscripten binary should not error")
+	defer solc.Close()
+
+	in := NewInput().AddSource("A.sol", "contract A {}")
+	expected, err := solc.Compile(in)
+	require.NoError(t, err, "Compile should not error")
+
+	out, err := CompileStream(solc, in, func(string, string, *Contract) error { return nil })
+	require.NoError(t, err, "CompileStream should not error")
+	require.NotNil(t, out.Profile, "Streamed outputs should be profiled like compiled ones")
+	assert.Greater(t, int64(out.Profile.Call), int64(0))
+	assert.Greater(t, int64(out.Profile.Unmarshal), int64(0))
+	assert.Equal(t, expected.Console, out.Console)
+}
+
+func TestCompileStreamChecks(t *testing.T) {
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js", WithValidation())
+	require.NoError(t, err, "Creating Solc from valid solc emscripten binary should not error")
+	defer solc.Close()
+
+	_, err = CompileStream(solc, &Input{Language: "Vyper"}, func(string, string, *Contract) error { return nil })
+	assert.Error(t, err, "Invalid inputs should be rejected")
+
+	ctx := ContextWithPolicy(context.Background(), CompilePolicy{InputLimits: InputLimits{MaxSources: 1}})
+	in := NewInput().AddSource("A.sol", "contract A {}").AddSource("B.sol", "contract B {}")
+	_, err = CompileStreamContext(ctx, solc, in, func(string, string, *Contract) error { return nil })
+	assert.Error(t, err, "Inputs over the policy of the context should be rejected")
+}
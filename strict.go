@@ -0,0 +1,107 @@
+package solc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nmvalera/solc-go/types"
+)
+
+// UnsupportedSettingsError is returned in strict mode when an input sets settings the compiler ignores (see WithStrictSettings)
+type UnsupportedSettingsError struct {
+	Version string
+
+	// Settings are the JSON paths of the unsupported settings followed by the version introducing them
+	Settings []string
+}
+
+func (e *UnsupportedSettingsError) Error() string {
+	return fmt.Sprintf("solc: settings unsupported by %v: %v", ShortVersion(e.Version), strings.Join(e.Settings, ", "))
+}
+
+// setting is a setting introduced in a compiler version
+type setting struct {
+	path  string
+	since string
+	set   func(s *Settings) bool
+}
+
+// versionedSettings lists the settings older compilers silently ignore, with the first version honouring them
+var versionedSettings = []setting{
+	{"optimizer.details", "0.5.5", func(s *Settings) bool { return s.Optimizer.Details != nil }},
+	{"optimizer.details.inliner", "0.8.5", func(s *Settings) bool {
+		return s.Optimizer.Details != nil && s.Optimizer.Details.Inliner != nil
+	}},
+	{"optimizer.details.yulDetails", "0.5.9", func(s *Settings) bool {
+		return s.Optimizer.Details != nil && s.Optimizer.Details.YulDetails != nil
+	}},
+	{"optimizer.details.yulDetails.optimizerSteps", "0.7.2", func(s *Settings) bool {
+		return s.Optimizer.Details != nil && s.Optimizer.Details.YulDetails != nil && s.Optimizer.Details.YulDetails.OptimizerSteps != ""
+	}},
+	{"viaIR", "0.7.5", func(s *Settings) bool { return s.ViaIR }},
+	{"metadata.bytecodeHash", "0.6.0", func(s *Settings) bool { return s.Metadata != nil && s.Metadata.BytecodeHash != "" }},
+	{"eofVersion", "0.8.29", func(s *Settings) bool { return s.EOFVersion != 0 }},
+}
+
+// outputsSince maps selectable outputs to the first version producing them, outputs
+// missing from the map are produced by every supported compiler
+var outputsSince = map[string]string{
+	"storageLayout": "0.5.13",
+	"ir":            "0.5.13",
+	"irOptimized":   "0.6.0",
+	"evm.deployedBytecode.immutableReferences": "0.6.5",
+	"evm.bytecode.generatedSources":            "0.8.0",
+	"evm.deployedBytecode.generatedSources":    "0.8.0",
+	"evm.bytecode.functionDebugData":           "0.8.3",
+	"evm.deployedBytecode.functionDebugData":   "0.8.3",
+	"irAst":                                    "0.8.20",
+	"irOptimizedAst":                           "0.8.20",
+	"transientStorageLayout":                   "0.8.27",
+}
+
+// UnsupportedSettings returns the settings of in that compiler version ignores, as JSON paths
+// followed by the version introducing them (e.g. "settings.viaIR (>=0.7.5)")
+func UnsupportedSettings(version string, in *Input) ([]string, error) {
+	if _, err := types.CompareVersions(version, version); err != nil {
+		return nil, err
+	}
+	before := func(since string) bool {
+		return versionLess(version, since)
+	}
+
+	var unsupported []string
+	for _, s := range versionedSettings {
+		if s.set(&in.Settings) && before(s.since) {
+			unsupported = append(unsupported, fmt.Sprintf("settings.%v (>=%v)", s.path, s.since))
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, contracts := range in.Settings.OutputSelection {
+		for _, outputs := range contracts {
+			for _, output := range outputs {
+				since, ok := outputsSince[output]
+				if ok && !seen[output] && before(since) {
+					seen[output] = true
+					unsupported = append(unsupported, fmt.Sprintf("settings.outputSelection %v (>=%v)", output, since))
+				}
+			}
+		}
+	}
+	sort.Strings(unsupported)
+
+	return unsupported, nil
+}
+
+// checkSettings returns an *UnsupportedSettingsError if compiler version ignores settings of in
+func checkSettings(version string, in *Input) error {
+	unsupported, err := UnsupportedSettings(version, in)
+	if err != nil {
+		return err
+	}
+	if len(unsupported) > 0 {
+		return &UnsupportedSettingsError{Version: version, Settings: unsupported}
+	}
+	return nil
+}
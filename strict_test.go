@@ -0,0 +1,54 @@
+package solc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnsupportedSettings(t *testing.T) {
+	yes := true
+	in := NewInput().AddSource("One.sol", "contract One {}").SetViaIR(true)
+	in.Settings.Optimizer.Details = &OptimizerDetails{Inliner: &yes}
+	in.Settings.OutputSelection.Select("*", "*", "storageLayout").Select("One.sol", "One", "storageLayout")
+
+	unsupported, err := UnsupportedSettings("0.5.9+commit.e560f70d.Emscripten.clang", in)
+	require.NoError(t, err, "UnsupportedSettings should not error")
+	assert.Equal(t, []string{
+		"settings.optimizer.details.inliner (>=0.8.5)",
+		"settings.outputSelection storageLayout (>=0.5.13)",
+		"settings.viaIR (>=0.7.5)",
+	}, unsupported, "Settings introduced after 0.5.9 should be reported once")
+
+	unsupported, err = UnsupportedSettings("0.8.5", in)
+	require.NoError(t, err, "UnsupportedSettings should not error")
+	assert.Empty(t, unsupported, "0.8.5 should support every setting")
+
+	_, err = UnsupportedSettings("latest", in)
+	assert.Error(t, err, "Invalid version should error")
+}
+
+func TestWithStrictSettings(t *testing.T) {
+	solc, err := NewFromFile("./solc-bin/soljson-v0.5.9+commit.e560f70d.js", WithStrictSettings())
+	require.NoError(t, err, "Creating Solc from valid solc emscripten binary should not error")
+	defer solc.Close()
+
+	in := NewInput().AddSource("One.sol", "pragma solidity ^0.5.2; contract One {}")
+	out, err := solc.Compile(in)
+	require.NoError(t, err, "Compile should not error on supported settings")
+	assert.Empty(t, out.Errors, "One.sol should compile")
+
+	in.Settings.OutputSelection.Select("*", "*", "storageLayout")
+	_, err = solc.Compile(in)
+	require.IsType(t, &UnsupportedSettingsError{}, err, "Compile should error on unsupported settings")
+	assert.EqualError(t, err, "solc: settings unsupported by 0.5.9: settings.outputSelection storageLayout (>=0.5.13)")
+}
+
+func TestUnsupportedSettingsEOF(t *testing.T) {
+	in := NewInput().AddSource("One.sol", "contract One {}").SetEVMVersion("osaka")
+	in.Settings.EOFVersion = EOFVersion1
+	unsupported, err := UnsupportedSettings("0.8.28", in)
+	assert.NoError(t, err, "UnsupportedSettings should not error")
+	assert.Equal(t, []string{"settings.eofVersion (>=0.8.29)"}, unsupported, "EOF should be gated on 0.8.29")
+}
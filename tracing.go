@@ -0,0 +1,64 @@
+package solc
+
+import (
+	"context"
+)
+
+// Attributes set on the spans started by compilers
+const (
+	AttrVersion       = "solc.version"
+	AttrSources       = "solc.sources"
+	AttrInputBytes    = "solc.input_bytes"
+	AttrOutputBytes   = "solc.output_bytes"
+	AttrCached        = "solc.cached"
+	AttrDownloadBytes = "solc.download_bytes"
+)
+
+// Span names
+const (
+	SpanInit    = "solc.init"
+	SpanCompile = "solc.compile"
+
+	// SpanDownload is started by registries around the download of a compiler release
+	SpanDownload = "solc.download"
+)
+
+// Tracer starts spans around compiler operations
+//
+// It is a minimal hook so tracing backends (e.g. OpenTelemetry, see contrib/otel) stay optional dependencies
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is an operation traced by a Tracer
+type Span interface {
+	SetAttribute(key string, value interface{})
+
+	// End completes the span, err is the error the operation failed with if any
+	End(err error)
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(key string, value interface{}) {}
+func (noopSpan) End(err error)                              {}
+
+func (o *options) startSpan(ctx context.Context, name string) (context.Context, Span) {
+	if o.tracer == nil {
+		return ctx, noopSpan{}
+	}
+	return o.tracer.Start(ctx, name)
+}
+
+// contextCompiler is implemented by compilers accepting a context, used to parent spans
+type contextCompiler interface {
+	CompileContext(ctx context.Context, input *Input) (*Output, error)
+}
+
+// CompileContext compiles input passing ctx to solc if it accepts one (like compilers created by New and Pool)
+func CompileContext(ctx context.Context, solc Solc, input *Input) (*Output, error) {
+	if c, ok := solc.(contextCompiler); ok {
+		return c.CompileContext(ctx, input)
+	}
+	return solc.Compile(input)
+}
@@ -0,0 +1,63 @@
+package solc
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedSpan struct {
+	name   string
+	parent interface{}
+	attrs  map[string]interface{}
+	ended  bool
+	err    error
+}
+
+func (s *recordedSpan) SetAttribute(key string, value interface{}) { s.attrs[key] = value }
+func (s *recordedSpan) End(err error)                              { s.ended, s.err = true, err }
+
+type recordingTracer struct {
+	mux   sync.Mutex
+	spans []*recordedSpan
+}
+
+type parentKey struct{}
+
+func (tr *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	tr.mux.Lock()
+	defer tr.mux.Unlock()
+	span := &recordedSpan{name: name, parent: ctx.Value(parentKey{}), attrs: make(map[string]interface{})}
+	tr.spans = append(tr.spans, span)
+	return context.WithValue(ctx, parentKey{}, name), span
+}
+
+func TestTracing(t *testing.T) {
+	tracer := &recordingTracer{}
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js", WithTracer(tracer))
+	require.NoError(t, err, "Creating Solc from valid solc emscripten binary should not error")
+	defer solc.Close()
+
+	require.Len(t, tracer.spans, 1, "Initialization should be traced")
+	assert.Equal(t, SpanInit, tracer.spans[0].name)
+	assert.True(t, tracer.spans[0].ended, "Init span should be ended")
+	assert.Equal(t, "0.6.2+commit.bacdbe57.Emscripten.clang", tracer.spans[0].attrs[AttrVersion])
+
+	ctx := context.WithValue(context.Background(), parentKey{}, "request")
+	in := &Input{Language: "Solidity", Sources: map[string]SourceIn{"One.sol": SourceIn{Content: "contract One {}"}}}
+	_, err = CompileContext(ctx, solc, in)
+	require.NoError(t, err, "Compile should not error")
+
+	require.Len(t, tracer.spans, 2, "Compilation should be traced")
+	span := tracer.spans[1]
+	assert.Equal(t, SpanCompile, span.name)
+	assert.Equal(t, "request", span.parent, "Compile span should be parented by the context")
+	assert.True(t, span.ended, "Compile span should be ended")
+	assert.NoError(t, span.err)
+	assert.Equal(t, 1, span.attrs[AttrSources])
+	assert.Greater(t, span.attrs[AttrInputBytes], 0)
+	assert.Greater(t, span.attrs[AttrOutputBytes], 0)
+}
@@ -0,0 +1,127 @@
+package solc
+
+import (
+	"fmt"
+	"io/ioutil"
+	"unicode/utf8"
+
+	"rogchap.com/v8go"
+)
+
+// transferThreshold is the size (in bytes for inputs, UTF-16 code units for outputs) above which
+// standard JSON crosses the v8 boundary in chunks of streamChunkSize instead of a single string
+var transferThreshold = 16 << 20
+
+// heapScript binds the emscripten heap helpers used to write inputs in chunks, soljson exports
+// them since at least 0.5.0
+const heapScript = `(function(global) {
+	if (typeof Module._malloc !== 'function' || typeof Module._free !== 'function' || typeof Module.stringToUTF8 !== 'function') {
+		return false;
+	}
+	global.__solc_malloc = Module._malloc;
+	global.__solc_free = Module._free;
+	global.__solc_write = function(ptr, n) { Module.stringToUTF8(__solc_chunk, ptr, n + 1); __solc_chunk = undefined; };
+	return true;
+})(this)`
+
+// bindHeap binds the compile function taking a pointer to an input written in the emscripten heap,
+// compilePtr is left nil if the module does not export the heap helpers
+func (solc *baseSolc) bindHeap() error {
+	val, err := solc.ctx.RunScript(heapScript, "heap.js")
+	if err != nil {
+		return err
+	}
+	if val.String() != "true" {
+		return nil
+	}
+	solc.compilePtr, err = solc.ctx.RunScript("Module.cwrap('solidity_compile', 'string', ['number', 'number', 'number'])", "wrap_compile_ptr.js")
+	return err
+}
+
+// inputValue creates the JS value of input passed to the compile function
+//
+// Large inputs are written into the emscripten heap chunk by chunk so no single string holds them
+// in v8, free must then be called once compilation completes
+func (solc *baseSolc) inputValue(input []byte) (compile, val *v8go.Value, free func(), err error) {
+	if len(input) <= transferThreshold || solc.compilePtr == nil || !utf8.Valid(input) {
+		val, err = solc.ctx.Create(string(input))
+		return solc.compile, val, func() {}, err
+	}
+
+	ptr, err := solc.ctx.RunScript(fmt.Sprintf("__solc_malloc(%v)", len(input)+1), "heap.js")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	addr := ptr.Int64()
+	if addr == 0 {
+		return nil, nil, nil, fmt.Errorf("solc: could not allocate %v bytes in the emscripten heap", len(input)+1)
+	}
+	free = func() {
+		_, _ = solc.ctx.RunScript(fmt.Sprintf("__solc_free(%v)", addr), "heap.js")
+	}
+
+	global := solc.ctx.Global()
+	for off := 0; off < len(input); {
+		end := off + streamChunkSize
+		if end >= len(input) {
+			end = len(input)
+		} else {
+			// Never split a UTF-8 sequence, stringToUTF8 would write a replacement character
+			for end > off && !utf8.RuneStart(input[end]) {
+				end--
+			}
+		}
+
+		chunk, err := solc.ctx.Create(string(input[off:end]))
+		if err == nil {
+			err = global.Set("__solc_chunk", chunk)
+		}
+		if err == nil {
+			_, err = solc.ctx.RunScript(fmt.Sprintf("__solc_write(%v, %v)", addr+int64(off), end-off), "heap.js")
+		}
+		if err != nil {
+			free()
+			return nil, nil, nil, err
+		}
+		off = end
+	}
+
+	val, err = solc.ctx.Create(addr)
+	if err != nil {
+		free()
+		return nil, nil, nil, err
+	}
+	return solc.compilePtr, val, free, nil
+}
+
+// outputBytes copies an output JS string out of v8, in chunks if it is large
+func (solc *baseSolc) outputBytes(val *v8go.Value) ([]byte, error) {
+	length, err := solc.jsLength(val)
+	if err != nil {
+		return nil, err
+	}
+	if length <= transferThreshold {
+		return []byte(val.String()), nil
+	}
+
+	r, err := solc.newJSReader(val)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+// jsLength returns the length of a JS string in UTF-16 code units, without copying it out of v8
+func (solc *baseSolc) jsLength(val *v8go.Value) (int, error) {
+	global := solc.ctx.Global()
+	err := global.Set("__solc_output", val)
+	if err != nil {
+		return 0, err
+	}
+	length, err := solc.ctx.RunScript("(function() { var l = __solc_output.length; __solc_output = undefined; return l; })()", "output_size.js")
+	if err != nil {
+		return 0, err
+	}
+	return int(length.Int64()), nil
+}
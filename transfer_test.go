@@ -0,0 +1,35 @@
+package solc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunkedTransfer(t *testing.T) {
+	in := NewInput().AddSource("One.sol", "pragma solidity >=0.5.9; // héllo €\ncontract One { function one() public pure returns (uint) { return 1; } }")
+
+	for _, file := range []string{"./solc-bin/soljson-v0.5.9+commit.e560f70d.js", "./solc-bin/soljson-v0.6.2+commit.bacdbe57.js"} {
+		solc, err := new(mustReadFile(t, file))
+		require.NoError(t, err, "Creating Solc from valid solc emscripten binary should not error")
+		defer solc.Close()
+		require.NotNil(t, solc.compilePtr, "Heap helpers should be bound")
+
+		expected, err := solc.Compile(in)
+		require.NoError(t, err, "Compile should not error")
+
+		func() {
+			defer func(threshold, size int) { transferThreshold, streamChunkSize = threshold, size }(transferThreshold, streamChunkSize)
+			transferThreshold, streamChunkSize = 10, 7
+
+			out, err := solc.Compile(in)
+			require.NoError(t, err, "Chunked compile should not error")
+			assert.Equal(t, expected, out, "Chunked transfer should not alter the output")
+		}()
+
+		out, err := solc.Compile(in)
+		require.NoError(t, err, "Compile should not error after a chunked transfer")
+		assert.Equal(t, expected, out)
+	}
+}
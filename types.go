@@ -0,0 +1,181 @@
+package solc
+
+import (
+	"encoding/json"
+
+	"github.com/nmvalera/solc-go/types"
+)
+
+// The standard JSON input and output, the Solc interface and the helpers on them are declared in package
+// types, which does not depend on v8
+
+type (
+	ABIEntry               = types.ABIEntry
+	ABIParameter           = types.ABIParameter
+	Address                = types.Address
+	AmbiguousContractError = types.AmbiguousContractError
+	BinaryVersionError     = types.BinaryVersionError
+	BuildInfo              = types.BuildInfo
+	Bytecode               = types.Bytecode
+	CompilationUnit        = types.CompilationUnit
+	CompileProfile         = types.CompileProfile
+	CompilerStep           = types.CompilerStep
+	Contract               = types.Contract
+	ContractID             = types.ContractID
+	CoverageKind           = types.CoverageKind
+	CoverageMap            = types.CoverageMap
+	CoverageMarker         = types.CoverageMarker
+	DiagnosticSeverity     = types.DiagnosticSeverity
+	EVM                    = types.EVM
+	EWASM                  = types.EWASM
+	Error                  = types.Error
+	Finding                = types.Finding
+	HeapStatistics         = types.HeapStatistics
+	Input                  = types.Input
+	InputError             = types.InputError
+	LayoutChange           = types.LayoutChange
+	LinkReference          = types.LinkReference
+	MetadataSettings       = types.MetadataSettings
+	Optimizer              = types.Optimizer
+	OptimizerDetails       = types.OptimizerDetails
+	Output                 = types.Output
+	OutputSelection        = types.OutputSelection
+	Remapping              = types.Remapping
+	SelectorMatch          = types.SelectorMatch
+	SelectorRegistry       = types.SelectorRegistry
+	Settings               = types.Settings
+	Solc                   = types.Solc
+	SourceIn               = types.SourceIn
+	SourceLocation         = types.SourceLocation
+	SourceOut              = types.SourceOut
+	StorageLayout          = types.StorageLayout
+	StorageLocation        = types.StorageLocation
+	StorageSlot            = types.StorageSlot
+	StorageType            = types.StorageType
+	VersionConflictError   = types.VersionConflictError
+	YulDetails             = types.YulDetails
+)
+
+const (
+	BinaryVersion        = types.BinaryVersion
+	BuildInfoFormat      = types.BuildInfoFormat
+	ChangeMoved          = types.ChangeMoved
+	ChangeRelocated      = types.ChangeRelocated
+	ChangeRemoved        = types.ChangeRemoved
+	ChangeRetyped        = types.ChangeRetyped
+	CoverageBranch       = types.CoverageBranch
+	CoverageFunction     = types.CoverageFunction
+	CoverageStatement    = types.CoverageStatement
+	DefaultOptimizerRuns = types.DefaultOptimizerRuns
+	EOFMinEVMVersion     = types.EOFMinEVMVersion
+	EOFVersion1          = types.EOFVersion1
+	LocationStorage      = types.LocationStorage
+	LocationTransient    = types.LocationTransient
+	SeverityError        = types.SeverityError
+	SeverityInformation  = types.SeverityInformation
+	SeverityWarning      = types.SeverityWarning
+	StepAnalysis         = types.StepAnalysis
+	StepCodegen          = types.StepCodegen
+	StepOptimizer        = types.StepOptimizer
+	StepParsing          = types.StepParsing
+)
+
+var (
+	BytecodeHashes = types.BytecodeHashes
+	DefaultOutputs = types.DefaultOutputs
+	EVMVersions    = types.EVMVersions
+	ErrClosed      = types.ErrClosed
+	ErrTerminated  = types.ErrTerminated
+	ErrTimeout     = types.ErrTimeout
+	Languages      = types.Languages
+)
+
+// CompareStorageLayouts is types.CompareStorageLayouts
+func CompareStorageLayouts(old, new *Contract) []LayoutChange {
+	return types.CompareStorageLayouts(old, new)
+}
+
+// DefaultEVMVersion is types.DefaultEVMVersion
+func DefaultEVMVersion(version string) (string, error) {
+	return types.DefaultEVMVersion(version)
+}
+
+// HexToAddress is types.HexToAddress
+func HexToAddress(s string) (Address, error) {
+	return types.HexToAddress(s)
+}
+
+// IntersectVersions is types.IntersectVersions
+func IntersectVersions(constraints ...string) (string, error) {
+	return types.IntersectVersions(constraints...)
+}
+
+// LongVersion is types.LongVersion
+func LongVersion(version string) string {
+	return types.LongVersion(version)
+}
+
+// MatchVersion is types.MatchVersion
+func MatchVersion(constraint, version string) (bool, error) {
+	return types.MatchVersion(constraint, version)
+}
+
+// NewBuildInfo is types.NewBuildInfo
+func NewBuildInfo(version string, input *Input, output *Output) (*BuildInfo, error) {
+	return types.NewBuildInfo(version, input, output)
+}
+
+// NewInput is types.NewInput
+func NewInput() *Input {
+	return types.NewInput()
+}
+
+// NormalizeOutput is types.NormalizeOutput
+func NormalizeOutput(out *Output, roots ...string) (*Output, error) {
+	return types.NormalizeOutput(out, roots...)
+}
+
+// NormalizeSourceName is types.NormalizeSourceName
+func NormalizeSourceName(name string) string {
+	return types.NormalizeSourceName(name)
+}
+
+// ParseABI is types.ParseABI
+func ParseABI(abi []json.RawMessage) ([]ABIEntry, error) {
+	return types.ParseABI(abi)
+}
+
+// ParseContractID is types.ParseContractID
+func ParseContractID(s string) (ContractID, error) {
+	return types.ParseContractID(s)
+}
+
+// ParseRemapping is types.ParseRemapping
+func ParseRemapping(s string) (Remapping, error) {
+	return types.ParseRemapping(s)
+}
+
+// ReadBuildInfo is types.ReadBuildInfo
+func ReadBuildInfo(file string) (*BuildInfo, error) {
+	return types.ReadBuildInfo(file)
+}
+
+// ResolveVersion is types.ResolveVersion
+func ResolveVersion(constraint string, versions []string) (string, error) {
+	return types.ResolveVersion(constraint, versions)
+}
+
+// SelectAll is types.SelectAll
+func SelectAll(outputs ...string) OutputSelection {
+	return types.SelectAll(outputs...)
+}
+
+// SelectContract is types.SelectContract
+func SelectContract(file, contract string, outputs ...string) OutputSelection {
+	return types.SelectContract(file, contract, outputs...)
+}
+
+// ShortVersion is types.ShortVersion
+func ShortVersion(version string) string {
+	return types.ShortVersion(version)
+}
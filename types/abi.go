@@ -0,0 +1,82 @@
+package types
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+)
+
+// ABIEntry is a function, constructor, event, error, fallback or receive entry of a contract ABI
+type ABIEntry struct {
+	Type            string         `json:"type"`
+	Name            string         `json:"name,omitempty"`
+	Inputs          []ABIParameter `json:"inputs,omitempty"`
+	Outputs         []ABIParameter `json:"outputs,omitempty"`
+	StateMutability string         `json:"stateMutability,omitempty"`
+	Anonymous       bool           `json:"anonymous,omitempty"`
+
+	// Constant and Payable give the mutability of functions in ABIs of solc < 0.5.0, which have no StateMutability
+	Constant bool `json:"constant,omitempty"`
+	Payable  bool `json:"payable,omitempty"`
+}
+
+// ABIParameter is an input or output of an ABI entry
+type ABIParameter struct {
+	Name         string         `json:"name"`
+	Type         string         `json:"type"`
+	InternalType string         `json:"internalType,omitempty"`
+	Components   []ABIParameter `json:"components,omitempty"`
+	Indexed      bool           `json:"indexed,omitempty"`
+}
+
+// ParseABI decodes the ABI of a contract
+func ParseABI(abi []json.RawMessage) ([]ABIEntry, error) {
+	entries := make([]ABIEntry, len(abi))
+	for i, raw := range abi {
+		err := json.Unmarshal(raw, &entries[i])
+		if err != nil {
+			return nil, err
+		}
+		// Entries of the legacy ABI format have no type
+		if entries[i].Type == "" {
+			entries[i].Type = "function"
+		}
+	}
+	return entries, nil
+}
+
+// CanonicalType returns the canonical type of the parameter, expanding tuples (e.g. (uint256,address)[])
+func (p ABIParameter) CanonicalType() string {
+	if !strings.HasPrefix(p.Type, "tuple") {
+		return p.Type
+	}
+	types := make([]string, len(p.Components))
+	for i, c := range p.Components {
+		types[i] = c.CanonicalType()
+	}
+	return "(" + strings.Join(types, ",") + ")" + strings.TrimPrefix(p.Type, "tuple")
+}
+
+// Signature returns the canonical signature of the entry (e.g. transfer(address,uint256))
+func (e ABIEntry) Signature() string {
+	types := make([]string, len(e.Inputs))
+	for i, input := range e.Inputs {
+		types[i] = input.CanonicalType()
+	}
+	return e.Name + "(" + strings.Join(types, ",") + ")"
+}
+
+// Selector returns the 0x prefixed hex selector of a function or error (4 bytes) or the topic of an event (32 bytes)
+//
+// It returns "" for other entries
+func (e ABIEntry) Selector() string {
+	hash := Keccak256([]byte(e.Signature()))
+	switch e.Type {
+	case "function", "error":
+		return "0x" + hex.EncodeToString(hash[:4])
+	case "event":
+		return "0x" + hex.EncodeToString(hash)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,30 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestABISelectors(t *testing.T) {
+	abi := []json.RawMessage{
+		json.RawMessage(`{"type": "function", "name": "transfer", "inputs": [{"name": "to", "type": "address"}, {"name": "value", "type": "uint256"}]}`),
+		json.RawMessage(`{"type": "event", "name": "Transfer", "inputs": [{"name": "from", "type": "address", "indexed": true}, {"name": "to", "type": "address", "indexed": true}, {"name": "value", "type": "uint256"}]}`),
+		json.RawMessage(`{"type": "error", "name": "Unauthorized", "inputs": [{"name": "user", "type": "tuple[]", "components": [{"name": "a", "type": "address"}, {"name": "b", "type": "uint8[2]"}]}]}`),
+		json.RawMessage(`{"type": "constructor", "inputs": []}`),
+		json.RawMessage(`{"name": "one", "inputs": []}`),
+	}
+	entries, err := ParseABI(abi)
+	require.NoError(t, err, "ParseABI should not error")
+	require.Len(t, entries, 5)
+
+	assert.Equal(t, "transfer(address,uint256)", entries[0].Signature())
+	assert.Equal(t, "0xa9059cbb", entries[0].Selector())
+	assert.Equal(t, "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", entries[1].Selector(), "Event selector should be the topic")
+	assert.Equal(t, "Unauthorized((address,uint8[2])[])", entries[2].Signature(), "Tuples should be expanded")
+	assert.Equal(t, "", entries[3].Selector(), "Constructors have no selector")
+	assert.Equal(t, "function", entries[4].Type, "Legacy entries should default to functions")
+	assert.Equal(t, "0x901717d1", entries[4].Selector())
+}
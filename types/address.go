@@ -0,0 +1,43 @@
+package types
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Address is a 20 bytes account address (layout compatible with go-ethereum's common.Address)
+type Address [20]byte
+
+// HexToAddress parses a hex encoded address, with or without 0x prefix
+func HexToAddress(s string) (Address, error) {
+	var a Address
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if len(s) != 2*len(a) {
+		return a, fmt.Errorf("invalid address %q: expected %v hex characters", s, 2*len(a))
+	}
+	_, err := hex.Decode(a[:], []byte(s))
+	if err != nil {
+		return a, fmt.Errorf("invalid address %q: %v", s, err)
+	}
+	return a, nil
+}
+
+// Hex returns the 0x prefixed lower case hex encoding of the address
+func (a Address) Hex() string {
+	return "0x" + hex.EncodeToString(a[:])
+}
+
+func (a Address) String() string {
+	return a.Hex()
+}
+
+func Keccak256(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
@@ -0,0 +1,112 @@
+package types
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BuildInfoFormat is the Hardhat build-info format emitted by this package
+const BuildInfoFormat = "hh-sol-build-info-1"
+
+// BuildInfo is a Hardhat compatible build-info document (artifacts/build-info/<id>.json)
+type BuildInfo struct {
+	Format          string  `json:"_format"`
+	ID              string  `json:"id"`
+	SolcVersion     string  `json:"solcVersion"`
+	SolcLongVersion string  `json:"solcLongVersion"`
+	Input           *Input  `json:"input"`
+	Output          *Output `json:"output"`
+}
+
+// NewBuildInfo creates the build-info of a compilation
+//
+// version is the compiler version as returned by Solc.Version()
+func NewBuildInfo(version string, input *Input, output *Output) (*BuildInfo, error) {
+	longVersion := LongVersion(version)
+	info := &BuildInfo{
+		Format:          BuildInfoFormat,
+		SolcVersion:     ShortVersion(longVersion),
+		SolcLongVersion: longVersion,
+		Input:           input,
+		Output:          output,
+	}
+
+	// Like Hardhat, the id is a non cryptographic hash of everything but the output
+	b, err := json.Marshal(struct {
+		Format          string `json:"_format"`
+		SolcVersion     string `json:"solcVersion"`
+		SolcLongVersion string `json:"solcLongVersion"`
+		Input           *Input `json:"input"`
+	}{info.Format, info.SolcVersion, info.SolcLongVersion, info.Input})
+	if err != nil {
+		return nil, err
+	}
+	sum := md5.Sum(b)
+	info.ID = hex.EncodeToString(sum[:])
+
+	return info, nil
+}
+
+// LongVersion strips the platform suffix of a compiler version (e.g. "0.6.2+commit.bacdbe57.Emscripten.clang" becomes "0.6.2+commit.bacdbe57")
+func LongVersion(version string) string {
+	i := strings.Index(version, "+commit.")
+	if i < 0 {
+		return version
+	}
+	rest := version[i+len("+commit."):]
+	if j := strings.Index(rest, "."); j >= 0 {
+		rest = rest[:j]
+	}
+	return version[:i] + "+commit." + rest
+}
+
+// ShortVersion returns the semver part of a compiler version (e.g. "0.6.2+commit.bacdbe57" becomes "0.6.2")
+func ShortVersion(version string) string {
+	if i := strings.IndexAny(version, "+-"); i >= 0 {
+		return version[:i]
+	}
+	return version
+}
+
+// WriteFile writes the build-info into <artifactsDir>/build-info/<id>.json and returns the file path
+func (info *BuildInfo) WriteFile(artifactsDir string) (string, error) {
+	dir := filepath.Join(artifactsDir, "build-info")
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := MarshalDeterministic(info)
+	if err != nil {
+		return "", err
+	}
+
+	file := filepath.Join(dir, info.ID+".json")
+	err = ioutil.WriteFile(file, b, 0644)
+	if err != nil {
+		return "", err
+	}
+
+	return file, nil
+}
+
+// ReadBuildInfo reads a build-info file
+func ReadBuildInfo(file string) (*BuildInfo, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &BuildInfo{}
+	err = json.Unmarshal(b, info)
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
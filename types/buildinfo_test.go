@@ -0,0 +1,39 @@
+package types
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildInfo(t *testing.T) {
+	dir, err := ioutil.TempDir("", "solc-artifacts")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	in := &Input{
+		Language: "Solidity",
+		Sources:  map[string]SourceIn{"One.sol": SourceIn{Content: "contract One {}"}},
+	}
+	out := &Output{Sources: map[string]SourceOut{"One.sol": SourceOut{ID: 0}}}
+
+	info, err := NewBuildInfo("0.6.2+commit.bacdbe57.Emscripten.clang", in, out)
+	require.NoError(t, err, "NewBuildInfo should not error")
+	assert.Equal(t, BuildInfoFormat, info.Format)
+	assert.Equal(t, "0.6.2", info.SolcVersion, "Short version should be correct")
+	assert.Equal(t, "0.6.2+commit.bacdbe57", info.SolcLongVersion, "Long version should be correct")
+	assert.Len(t, info.ID, 32, "Id should be an md5 hex digest")
+
+	file, err := info.WriteFile(dir)
+	require.NoError(t, err, "WriteFile should not error")
+	assert.Equal(t, filepath.Join(dir, "build-info", info.ID+".json"), file, "Build-info path should be correct")
+
+	read, err := ReadBuildInfo(file)
+	require.NoError(t, err, "ReadBuildInfo should not error")
+	assert.Equal(t, info.ID, read.ID)
+	assert.Equal(t, "contract One {}", read.Input.Sources["One.sol"].Content)
+}
@@ -0,0 +1,64 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CompilerStep is a step of a compilation inside the compiler
+type CompilerStep string
+
+const (
+	// StepParsing parses the sources
+	StepParsing CompilerStep = "parsing"
+
+	// StepAnalysis resolves names and checks types, it includes StepParsing for compilers not stopping after it
+	StepAnalysis CompilerStep = "analysis"
+
+	// StepCodegen generates the outputs selected, bytecode included
+	StepCodegen CompilerStep = "codegen"
+
+	// StepOptimizer optimizes the generated code, when the optimizer is enabled
+	StepOptimizer CompilerStep = "optimizer"
+)
+
+// compilerSteps are the compiler steps in execution order
+var compilerSteps = []CompilerStep{StepParsing, StepAnalysis, StepCodegen, StepOptimizer}
+
+// CompileProfile breaks down the duration of a compilation
+type CompileProfile struct {
+	// Marshal is the duration of encoding the input to standard JSON
+	Marshal time.Duration
+
+	// Transfer is the duration of copying the input into v8 and the output out of it
+	Transfer time.Duration
+
+	// Call is the duration of running the compiler
+	Call time.Duration
+
+	// Unmarshal is the duration of decoding the standard JSON output
+	Unmarshal time.Duration
+
+	// Steps break Call down by compiler step, they are only measured by solc.ProfileCompile
+	Steps map[CompilerStep]time.Duration
+}
+
+// Total returns the duration of the compilation
+func (p *CompileProfile) Total() time.Duration {
+	return p.Marshal + p.Transfer + p.Call + p.Unmarshal
+}
+
+func (p *CompileProfile) String() string {
+	call := p.Call.String()
+	if len(p.Steps) > 0 {
+		var steps []string
+		for _, step := range compilerSteps {
+			if d, ok := p.Steps[step]; ok {
+				steps = append(steps, fmt.Sprintf("%v %v", step, d))
+			}
+		}
+		call += " (" + strings.Join(steps, ", ") + ")"
+	}
+	return fmt.Sprintf("marshal %v, transfer %v, call %v, unmarshal %v", p.Marshal, p.Transfer, call, p.Unmarshal)
+}
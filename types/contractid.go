@@ -0,0 +1,82 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ContractID identifies a contract by its source unit name and contract name, formatted as a
+// fully qualified name ("src/A.sol:Token")
+//
+// An ID with no File is a bare contract name, as accepted where names are unambiguous
+type ContractID struct {
+	File string
+	Name string
+}
+
+// ParseContractID parses a fully qualified name (path:Name) or a bare contract name
+//
+// Source unit names may contain colons so the contract name is what follows the last one
+func ParseContractID(s string) (ContractID, error) {
+	i := strings.LastIndex(s, ":")
+	if i < 0 {
+		if s == "" {
+			return ContractID{}, fmt.Errorf("solc: empty contract name")
+		}
+		return ContractID{Name: s}, nil
+	}
+	id := ContractID{File: s[:i], Name: s[i+1:]}
+	if id.File == "" || id.Name == "" {
+		return ContractID{}, fmt.Errorf("solc: invalid contract %q, expected path:Name", s)
+	}
+	return id, nil
+}
+
+// String returns the fully qualified name, or the bare name if the ID has no File
+func (id ContractID) String() string {
+	if id.File == "" {
+		return id.Name
+	}
+	return id.File + ":" + id.Name
+}
+
+// IsQualified indicates whether the ID has a File
+func (id ContractID) IsQualified() bool {
+	return id.File != ""
+}
+
+// Matches indicates whether the ID designates the contract name of file, bare IDs match contracts of any file
+func (id ContractID) Matches(file, name string) bool {
+	return id.Name == name && (id.File == "" || id.File == file)
+}
+
+func (id ContractID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+func (id *ContractID) UnmarshalText(b []byte) error {
+	parsed, err := ParseContractID(string(b))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// ContractIDs returns the IDs of the contracts of the output, sorted
+func (out *Output) ContractIDs() []ContractID {
+	var ids []ContractID
+	for file, contracts := range out.Contracts {
+		for name := range contracts {
+			ids = append(ids, ContractID{File: file, Name: name})
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if ids[i].File != ids[j].File {
+			return ids[i].File < ids[j].File
+		}
+		return ids[i].Name < ids[j].Name
+	})
+	return ids
+}
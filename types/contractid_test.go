@@ -0,0 +1,43 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContractID(t *testing.T) {
+	id, err := ParseContractID("src/A.sol:Token")
+	require.NoError(t, err, "Fully qualified name should parse")
+	assert.Equal(t, ContractID{File: "src/A.sol", Name: "Token"}, id)
+	assert.True(t, id.IsQualified())
+	assert.Equal(t, "src/A.sol:Token", id.String())
+
+	id, err = ParseContractID("c:/A.sol:Token")
+	require.NoError(t, err, "Colons in paths should parse")
+	assert.Equal(t, "c:/A.sol", id.File)
+
+	id, err = ParseContractID("Token")
+	require.NoError(t, err, "Bare name should parse")
+	assert.False(t, id.IsQualified())
+	assert.Equal(t, "Token", id.String())
+	assert.True(t, id.Matches("any.sol", "Token"), "Bare name should match any file")
+	assert.False(t, ContractID{File: "a.sol", Name: "Token"}.Matches("b.sol", "Token"))
+
+	for _, invalid := range []string{"", "A.sol:", ":Token"} {
+		_, err = ParseContractID(invalid)
+		assert.Error(t, err, "%q should not parse", invalid)
+	}
+
+	b, err := json.Marshal(map[ContractID]ContractID{{File: "A.sol", Name: "A"}: {Name: "B"}})
+	require.NoError(t, err, "IDs should marshal")
+	assert.Equal(t, `{"A.sol:A":"B"}`, string(b))
+	var decoded map[ContractID]ContractID
+	require.NoError(t, json.Unmarshal(b, &decoded), "IDs should unmarshal")
+	assert.Equal(t, ContractID{Name: "B"}, decoded[ContractID{File: "A.sol", Name: "A"}])
+
+	out := &Output{Contracts: map[string]map[string]Contract{"B.sol": {"B": {}}, "A.sol": {"Z": {}, "A": {}}}}
+	assert.Equal(t, []ContractID{{"A.sol", "A"}, {"A.sol", "Z"}, {"B.sol", "B"}}, out.ContractIDs())
+}
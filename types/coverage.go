@@ -0,0 +1,40 @@
+package types
+
+// CoverageKind is the kind of code a coverage marker stands for
+type CoverageKind string
+
+const (
+	// CoverageFunction markers stand for the bodies of functions and modifiers
+	CoverageFunction CoverageKind = "function"
+
+	// CoverageStatement markers stand for statements
+	CoverageStatement CoverageKind = "statement"
+
+	// CoverageBranch markers stand for the true and false branches of if statements, implicit else included
+	CoverageBranch CoverageKind = "branch"
+)
+
+// CoverageMarker is a marker injected in the source by solc.Instrument
+type CoverageMarker struct {
+	// ID is the 0x prefixed 32 bytes value instrumented code pushes and pops when executing the marked code
+	ID   string       `json:"id"`
+	Kind CoverageKind `json:"kind"`
+
+	// Name is the name of functions and modifiers (constructor, fallback and receive for special functions)
+	Name string `json:"name,omitempty"`
+
+	// Block numbers the if statements of a file, Branch is 0 for their true branch and 1 for their false one
+	Block  int `json:"block,omitempty"`
+	Branch int `json:"branch,omitempty"`
+
+	// SourceLocation and Line locate the marked code in the source before instrumentation
+	SourceLocation SourceLocation `json:"sourceLocation"`
+	Line           int            `json:"line"`
+}
+
+// CoverageMap maps the markers of an instrumented input to the code they stand for, it encodes to JSON as
+// is to be saved along instrumented builds
+type CoverageMap struct {
+	// Markers are sorted by file then location
+	Markers []CoverageMarker `json:"markers"`
+}
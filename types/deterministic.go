@@ -0,0 +1,43 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// MarshalDeterministic encodes the output to JSON identically for identical outputs
+//
+// Files, contracts and the keys of every object, including those of raw JSON fields
+// such as ABIs and ASTs, are sorted. The JSON is indented with two spaces, does not escape
+// HTML characters and ends with a newline, so artifacts can be diffed and hashed
+func (out *Output) MarshalDeterministic() ([]byte, error) {
+	return MarshalDeterministic(out)
+}
+
+// MarshalDeterministic encodes v to JSON like Output.MarshalDeterministic, re-encoding it through generic
+// values so raw messages get their keys sorted too
+func MarshalDeterministic(v interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// Numbers are kept as written, a float64 would round large integers
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var generic interface{}
+	err = dec.Decode(&generic)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	err = enc.Encode(generic)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,74 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalDeterministic(t *testing.T) {
+	out := &Output{Contracts: map[string]map[string]Contract{
+		"B.sol": {"B": {UserDoc: json.RawMessage(`{"notice":"a < b","methods":{}}`)}},
+		"A.sol": {
+			"Two": {ABI: []json.RawMessage{json.RawMessage(`{"type":"function","name":"two","value":123456789012345678901234567890}`)}},
+			"One": {Metadata: "one"},
+		},
+	}}
+
+	b, err := out.MarshalDeterministic()
+	require.NoError(t, err, "MarshalDeterministic should not error")
+	assert.Equal(t, `{
+  "contracts": {
+    "A.sol": {
+      "One": {
+        "evm": {
+          "bytecode": {},
+          "deployedBytecode": {}
+        },
+        "ewasm": {},
+        "metadata": "one"
+      },
+      "Two": {
+        "abi": [
+          {
+            "name": "two",
+            "type": "function",
+            "value": 123456789012345678901234567890
+          }
+        ],
+        "evm": {
+          "bytecode": {},
+          "deployedBytecode": {}
+        },
+        "ewasm": {}
+      }
+    },
+    "B.sol": {
+      "B": {
+        "evm": {
+          "bytecode": {},
+          "deployedBytecode": {}
+        },
+        "ewasm": {},
+        "userdoc": {
+          "methods": {},
+          "notice": "a < b"
+        }
+      }
+    }
+  }
+}
+`, string(b), "Keys of raw messages should be sorted and numbers kept")
+
+	for i := 0; i < 10; i++ {
+		again, err := out.MarshalDeterministic()
+		require.NoError(t, err)
+		assert.Equal(t, b, again, "Encoding should be stable")
+	}
+
+	decoded := &Output{}
+	require.NoError(t, json.Unmarshal(b, decoded), "Deterministic encoding should decode")
+	assert.Equal(t, "one", decoded.Contracts["A.sol"]["One"].Metadata)
+}
@@ -0,0 +1,139 @@
+package types
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+)
+
+// binaryMagic prefixes binary encoded outputs and build-infos, followed by the format version
+var binaryMagic = []byte("solc")
+
+// BinaryVersion is the version of the binary encoding written by MarshalBinary
+//
+// It is bumped whenever the encoded types change incompatibly, older versions fail to decode
+// with a *BinaryVersionError so caches can treat them as misses
+const BinaryVersion = 1
+
+// BinaryVersionError is returned when decoding data written with another binary encoding version
+type BinaryVersionError struct {
+	Version int
+}
+
+func (e *BinaryVersionError) Error() string {
+	return fmt.Sprintf("solc: unsupported binary encoding version %v (expected %v)", e.Version, BinaryVersion)
+}
+
+// outputGob has the fields of Output without its methods, so gob does not call MarshalBinary recursively
+type outputGob Output
+
+// MarshalBinary encodes the output with gob, which is several times faster than JSON to read and write
+//
+// Empty slices and maps, raw JSON included, decode as nil
+func (out *Output) MarshalBinary() ([]byte, error) {
+	return marshalBinary((*outputGob)(out))
+}
+
+// UnmarshalBinary decodes an output encoded by MarshalBinary
+func (out *Output) UnmarshalBinary(b []byte) error {
+	*out = Output{}
+	return unmarshalBinary(b, (*outputGob)(out))
+}
+
+// buildInfoGob holds the input as JSON as gob does not preserve pointers to zero values (e.g. disabled optimizer details)
+type buildInfoGob struct {
+	Format          string
+	ID              string
+	SolcVersion     string
+	SolcLongVersion string
+	Input           []byte
+	Output          *Output
+}
+
+// MarshalBinary encodes the build-info like Output.MarshalBinary
+func (info *BuildInfo) MarshalBinary() ([]byte, error) {
+	input, err := json.Marshal(info.Input)
+	if err != nil {
+		return nil, err
+	}
+	return marshalBinary(&buildInfoGob{
+		Format:          info.Format,
+		ID:              info.ID,
+		SolcVersion:     info.SolcVersion,
+		SolcLongVersion: info.SolcLongVersion,
+		Input:           input,
+		Output:          info.Output,
+	})
+}
+
+// UnmarshalBinary decodes a build-info encoded by MarshalBinary
+func (info *BuildInfo) UnmarshalBinary(b []byte) error {
+	g := &buildInfoGob{}
+	err := unmarshalBinary(b, g)
+	if err != nil {
+		return err
+	}
+
+	*info = BuildInfo{
+		Format:          g.Format,
+		ID:              g.ID,
+		SolcVersion:     g.SolcVersion,
+		SolcLongVersion: g.SolcLongVersion,
+		Output:          g.Output,
+	}
+	if len(g.Input) > 0 && string(g.Input) != "null" {
+		info.Input = &Input{}
+		return json.Unmarshal(g.Input, info.Input)
+	}
+	return nil
+}
+
+func marshalBinary(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(binaryMagic)
+	buf.WriteByte(BinaryVersion)
+	err := gob.NewEncoder(&buf).Encode(v)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func unmarshalBinary(b []byte, v interface{}) error {
+	if !isBinary(b) {
+		return fmt.Errorf("solc: not binary encoded")
+	}
+	if version := int(b[len(binaryMagic)]); version != BinaryVersion {
+		return &BinaryVersionError{Version: version}
+	}
+	return gob.NewDecoder(bytes.NewReader(b[len(binaryMagic)+1:])).Decode(v)
+}
+
+func isBinary(b []byte) bool {
+	return len(b) > len(binaryMagic) && bytes.HasPrefix(b, binaryMagic)
+}
+
+// UnmarshalOutput decodes an output encoded by MarshalBinary or to JSON, as cache entries written by earlier
+// versions are
+//
+// Outputs of another binary encoding version decode as nil with a nil error, so caches treat them as misses
+func UnmarshalOutput(b []byte) (*Output, error) {
+	out := &Output{}
+	if !isBinary(b) {
+		err := json.Unmarshal(b, out)
+		if err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+
+	err := out.UnmarshalBinary(b)
+	if _, ok := err.(*BinaryVersionError); ok {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
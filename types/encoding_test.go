@@ -0,0 +1,57 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalBinary(t *testing.T) {
+	out := &Output{
+		Errors:  []Error{{Severity: "warning", Message: "unused", SourceLocation: SourceLocation{File: "A.sol", Start: 1, End: 2}}},
+		Sources: map[string]SourceOut{"A.sol": {ID: 1, AST: json.RawMessage(`{"nodeType":"SourceUnit"}`)}},
+		Contracts: map[string]map[string]Contract{"A.sol": {"A": {
+			ABI: []json.RawMessage{json.RawMessage(`{"type":"function"}`)},
+			EVM: EVM{
+				Bytecode:          Bytecode{Object: "6080", LinkReferences: map[string]map[string][]LinkReference{"L.sol": {"L": {{Start: 1, Length: 20}}}}},
+				MethodIdentifiers: map[string]string{"f()": "26121ff0"},
+			},
+			StorageLayout: &StorageLayout{Storage: []StorageSlot{{Label: "x", Slot: "0", Type: "t_uint256"}}, Types: map[string]*StorageType{"t_uint256": {Label: "uint256", NumberOfBytes: "32"}}},
+		}}},
+	}
+
+	b, err := out.MarshalBinary()
+	require.NoError(t, err, "MarshalBinary should not error")
+	decoded := &Output{}
+	require.NoError(t, decoded.UnmarshalBinary(b), "UnmarshalBinary should not error")
+	assert.Equal(t, out, decoded, "Output should round trip")
+
+	b[len(binaryMagic)] = BinaryVersion + 1
+	assert.Equal(t, &BinaryVersionError{Version: BinaryVersion + 1}, decoded.UnmarshalBinary(b), "Other versions should not decode")
+	cached, err := UnmarshalOutput(b)
+	assert.NoError(t, err)
+	assert.Nil(t, cached, "Entries of other versions should be cache misses")
+
+	cached, err = UnmarshalOutput([]byte(`{"errors": [{"message": "legacy"}]}`))
+	require.NoError(t, err, "JSON entries should still be read")
+	assert.Equal(t, "legacy", cached.Errors[0].Message)
+
+	assert.Error(t, decoded.UnmarshalBinary([]byte("{}")), "JSON should not decode as binary")
+}
+
+func TestBuildInfoMarshalBinary(t *testing.T) {
+	disabled := false
+	in := NewInput().AddSource("A.sol", "contract A {}")
+	in.Settings.Optimizer.Details = &OptimizerDetails{Yul: &disabled}
+	info, err := NewBuildInfo("0.6.2+commit.bacdbe57.Emscripten.clang", in, &Output{Errors: []Error{{Message: "m"}}})
+	require.NoError(t, err, "NewBuildInfo should not error")
+
+	b, err := info.MarshalBinary()
+	require.NoError(t, err, "MarshalBinary should not error")
+	decoded := &BuildInfo{}
+	require.NoError(t, decoded.UnmarshalBinary(b), "UnmarshalBinary should not error")
+	assert.Equal(t, info, decoded, "Build-info should round trip")
+	require.NotNil(t, decoded.Input.Settings.Optimizer.Details.Yul, "Disabled optimizer details should be kept")
+}
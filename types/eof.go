@@ -0,0 +1,33 @@
+package types
+
+import (
+	"encoding/hex"
+	"strings"
+)
+
+// EOFVersion1 is the EVM Object Format version selected by Settings.EOFVersion
+const EOFVersion1 = 1
+
+// EOFMinEVMVersion is the oldest EVM version EOF can target
+const EOFMinEVMVersion = "osaka"
+
+// eofMagic starts every EOF container
+const eofMagic = "ef00"
+
+// EOFVersion returns the EOF version of the bytecode object or 0 for legacy bytecode
+func (b Bytecode) EOFVersion() int {
+	obj := strings.ToLower(strings.TrimPrefix(b.Object, "0x"))
+	if !strings.HasPrefix(obj, eofMagic) || len(obj) < len(eofMagic)+2 {
+		return 0
+	}
+	version, err := hex.DecodeString(obj[len(eofMagic) : len(eofMagic)+2])
+	if err != nil {
+		return 0
+	}
+	return int(version[0])
+}
+
+// IsEOF indicates whether the bytecode object is an EOF container
+func (b Bytecode) IsEOF() bool {
+	return b.EOFVersion() != 0
+}
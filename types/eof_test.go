@@ -0,0 +1,27 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEOFVersion(t *testing.T) {
+	assert.Equal(t, 1, Bytecode{Object: "ef000101000402000100010400000000800000fe"}.EOFVersion(), "EOF container should be detected")
+	assert.True(t, Bytecode{Object: "0xEF0001"}.IsEOF(), "Prefixed upper case EOF container should be detected")
+	assert.False(t, Bytecode{Object: "6080604052"}.IsEOF(), "Legacy bytecode should not be EOF")
+	assert.False(t, Bytecode{Object: "ef00"}.IsEOF(), "Truncated header should not be EOF")
+	assert.False(t, Bytecode{}.IsEOF(), "Empty bytecode should not be EOF")
+}
+
+func TestValidateEOF(t *testing.T) {
+	in := NewInput().AddSource("One.sol", "contract One {}").SetEVMVersion("osaka")
+	in.Settings.EOFVersion = EOFVersion1
+	assert.NoError(t, in.Validate(), "EOF targeting osaka should be valid")
+
+	in.SetEVMVersion("cancun")
+	assert.EqualError(t, in.Validate(), "solc: invalid input: settings.eofVersion: EOF requires evmVersion osaka or later, got cancun")
+
+	in.Settings.EOFVersion = 2
+	assert.EqualError(t, in.Validate(), "solc: invalid input: settings.eofVersion: unknown EOF version 2, expected 1")
+}
@@ -0,0 +1,21 @@
+package types
+
+// DiagnosticSeverity is the severity of a diagnostic, as in LSP
+type DiagnosticSeverity int
+
+const (
+	SeverityError       DiagnosticSeverity = 1
+	SeverityWarning     DiagnosticSeverity = 2
+	SeverityInformation DiagnosticSeverity = 3
+)
+
+// Finding is an issue reported by a solc.Analyzer
+type Finding struct {
+	// Analyzer is the name of the analyzer reporting the finding
+	Analyzer string `json:"analyzer"`
+
+	Contract       ContractID         `json:"contract"`
+	Severity       DiagnosticSeverity `json:"severity"`
+	Message        string             `json:"message"`
+	SourceLocation SourceLocation     `json:"sourceLocation"`
+}
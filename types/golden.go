@@ -0,0 +1,80 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// metadataPatterns match the metadata solc appends to bytecode (ipfs, bzzr1, bzzr0 and none hashes),
+// with the hash and compiler version replaced by zeros in metadataReplacements
+var (
+	metadataPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(a264697066735822)[0-9a-f]{68}(64736f6c6343)[0-9a-f]{6}(0033)`),
+		regexp.MustCompile(`(a265627a7a72315820)[0-9a-f]{64}(64736f6c6343)[0-9a-f]{6}(0032)`),
+		regexp.MustCompile(`(a165627a7a72305820)[0-9a-f]{64}(0029)`),
+		regexp.MustCompile(`(a164736f6c6343)[0-9a-f]{6}(000a)`),
+	}
+	metadataReplacements = []string{
+		"${1}" + strings.Repeat("0", 68) + "${2}000000${3}",
+		"${1}" + strings.Repeat("0", 64) + "${2}000000${3}",
+		"${1}" + strings.Repeat("0", 64) + "${2}",
+		"${1}000000${2}",
+	}
+)
+
+// NormalizeOutput returns a copy of out fit for golden file comparisons, which do not break with every
+// change of compiler patch version or of the directory compiled from
+//
+// The hash and compiler version of the metadata appended to bytecode are zeroed, wherever the metadata
+// is (contracts created with new embed theirs), opcodes and the metadata output are dropped and roots
+// (absolute directories, e.g. a temporary project directory) are removed from paths, messages and ASTs.
+// See MarshalGolden for a sorted encoding
+func NormalizeOutput(out *Output, roots ...string) (*Output, error) {
+	b, err := json.Marshal(out)
+	if err != nil {
+		return nil, err
+	}
+	for i, pattern := range metadataPatterns {
+		b = pattern.ReplaceAll(b, []byte(metadataReplacements[i]))
+	}
+	for _, root := range roots {
+		root = strings.TrimSuffix(root, "/")
+		if root == "" {
+			continue
+		}
+		// Paths appear JSON encoded, with backslashes escaped
+		encoded, _ := json.Marshal(root)
+		escaped := encoded[1 : len(encoded)-1]
+		b = bytes.Replace(b, append(escaped, '/'), nil, -1)
+		b = bytes.Replace(b, escaped, nil, -1)
+	}
+
+	normalized := &Output{}
+	err = json.Unmarshal(b, normalized)
+	if err != nil {
+		return nil, err
+	}
+	for _, contracts := range normalized.Contracts {
+		for name, contract := range contracts {
+			contract.Metadata = ""
+			contract.EVM.Bytecode.Opcodes = ""
+			contract.EVM.DeployedBytecode.Opcodes = ""
+			contracts[name] = contract
+		}
+	}
+	normalized.Console = out.Console
+	normalized.Findings = out.Findings
+	normalized.Units = out.Units
+	return normalized, nil
+}
+
+// MarshalGolden encodes the normalized output (see NormalizeOutput) deterministically (see MarshalDeterministic)
+func (out *Output) MarshalGolden(roots ...string) ([]byte, error) {
+	normalized, err := NormalizeOutput(out, roots...)
+	if err != nil {
+		return nil, err
+	}
+	return normalized.MarshalDeterministic()
+}
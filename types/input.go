@@ -0,0 +1,204 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+)
+
+type Input struct {
+	Language string              `json:"language,omitempty"`
+	Sources  map[string]SourceIn `json:"sources,omitempty"`
+	Settings Settings            `json:"settings,omitempty"`
+}
+
+type SourceIn struct {
+	Keccak256 string `json:"keccak256,omitempty"`
+	Content   string `json:"content,omitempty"`
+
+	// URLs are read by the compiler if Content is not set, solc-go compilers have no callback reading them
+	URLs []string `json:"urls,omitempty"`
+}
+
+type Settings struct {
+	Remappings      []string        `json:"remappings,omitempty"`
+	Optimizer       Optimizer       `json:"optimizer,omitempty"`
+	EVMVersion      string          `json:"evmVersion,omitempty"`
+	ViaIR           bool            `json:"viaIR,omitempty"`
+	OutputSelection OutputSelection `json:"outputSelection,omitempty"`
+
+	// Libraries maps source files to library names to hex encoded addresses linked at compilation
+	Libraries map[string]map[string]string `json:"libraries,omitempty"`
+
+	// EOFVersion targets the EVM Object Format (only 1, experimental since 0.8.29) instead of legacy bytecode
+	EOFVersion int `json:"eofVersion,omitempty"`
+
+	// StopAfter stops compilation after the given step (only "parsing"), older compilers reject it
+	StopAfter string `json:"stopAfter,omitempty"`
+
+	// Metadata tunes the metadata JSON and the hash of it appended to bytecode
+	Metadata *MetadataSettings `json:"metadata,omitempty"`
+}
+
+// MetadataSettings tune the contract metadata
+type MetadataSettings struct {
+	// UseLiteralContent embeds the content of sources in metadata instead of their hashes and URLs
+	UseLiteralContent bool `json:"useLiteralContent,omitempty"`
+
+	// BytecodeHash is the hash of metadata appended to bytecode, ipfs (default), bzzr1 or none (solc >= 0.6.0)
+	BytecodeHash string `json:"bytecodeHash,omitempty"`
+
+	// AppendCBOR appends the CBOR encoded metadata (hash and compiler version) to bytecode, true if unset (solc >= 0.8.18)
+	AppendCBOR *bool `json:"appendCBOR,omitempty"`
+}
+
+type Optimizer struct {
+	Enabled bool              `json:"enabled,omitempty"`
+	Runs    int               `json:"runs,omitempty"`
+	Details *OptimizerDetails `json:"details,omitempty"`
+}
+
+// OptimizerDetails switch optimizer components individually, unset components keep the defaults of Enabled
+type OptimizerDetails struct {
+	Peephole          *bool       `json:"peephole,omitempty"`
+	Inliner           *bool       `json:"inliner,omitempty"`
+	JumpdestRemover   *bool       `json:"jumpdestRemover,omitempty"`
+	OrderLiterals     *bool       `json:"orderLiterals,omitempty"`
+	Deduplicate       *bool       `json:"deduplicate,omitempty"`
+	CSE               *bool       `json:"cse,omitempty"`
+	ConstantOptimizer *bool       `json:"constantOptimizer,omitempty"`
+	Yul               *bool       `json:"yul,omitempty"`
+	YulDetails        *YulDetails `json:"yulDetails,omitempty"`
+}
+
+// YulDetails tune the Yul optimizer
+type YulDetails struct {
+	StackAllocation *bool  `json:"stackAllocation,omitempty"`
+	OptimizerSteps  string `json:"optimizerSteps,omitempty"`
+}
+
+// DefaultOptimizerRuns is the number of optimizer runs solc assumes when none is set
+const DefaultOptimizerRuns = 200
+
+// DefaultOutputs are the outputs NewInput selects for every contract
+var DefaultOutputs = []string{"abi", "evm.bytecode", "evm.deployedBytecode", "evm.methodIdentifiers"}
+
+// NewInput returns a Solidity input selecting DefaultOutputs for every contract, with the optimizer enabled for DefaultOptimizerRuns
+//
+//	in := solc.NewInput().AddSource("One.sol", content).SetEVMVersion("istanbul")
+func NewInput() *Input {
+	return &Input{
+		Language: "Solidity",
+		Sources:  make(map[string]SourceIn),
+		Settings: Settings{
+			Optimizer:       Optimizer{Enabled: true, Runs: DefaultOptimizerRuns},
+			OutputSelection: SelectAll(DefaultOutputs...),
+		},
+	}
+}
+
+// AddSource adds a source named name and returns the input
+func (in *Input) AddSource(name, content string) *Input {
+	if in.Sources == nil {
+		in.Sources = make(map[string]SourceIn)
+	}
+	in.Sources[name] = SourceIn{Content: content}
+	return in
+}
+
+// AddSources adds sources and returns the input
+func (in *Input) AddSources(sources map[string]SourceIn) *Input {
+	if in.Sources == nil {
+		in.Sources = make(map[string]SourceIn)
+	}
+	for name, source := range sources {
+		in.Sources[name] = source
+	}
+	return in
+}
+
+// AddRemapping adds a remapping and returns the input
+func (in *Input) AddRemapping(remapping Remapping) *Input {
+	in.Settings.Remappings = append(in.Settings.Remappings, remapping.String())
+	return in
+}
+
+// SetOptimizer sets whether the optimizer is enabled and its runs and returns the input
+func (in *Input) SetOptimizer(enabled bool, runs int) *Input {
+	in.Settings.Optimizer.Enabled = enabled
+	in.Settings.Optimizer.Runs = runs
+	return in
+}
+
+// SetEVMVersion sets the target EVM version and returns the input
+func (in *Input) SetEVMVersion(version string) *Input {
+	in.Settings.EVMVersion = version
+	return in
+}
+
+// SetViaIR sets whether compilation goes through the Yul IR pipeline and returns the input
+func (in *Input) SetViaIR(viaIR bool) *Input {
+	in.Settings.ViaIR = viaIR
+	return in
+}
+
+// SetOutputSelection replaces the output selection and returns the input
+func (in *Input) SetOutputSelection(selection OutputSelection) *Input {
+	in.Settings.OutputSelection = selection
+	return in
+}
+
+// Hash returns a hex encoded SHA-256 of the canonical form of the input
+//
+// Inputs that solc compiles identically hash the same: language and optimizer runs defaults
+// are made explicit and output selections are sorted and deduplicated. Remappings are kept
+// in order as it breaks ties between them
+func (in *Input) Hash() string {
+	// encoding/json sorts map keys so the encoding of the canonical input is stable
+	b, _ := json.Marshal(in.canonical())
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
+
+func (in *Input) canonical() *Input {
+	c := *in
+	if c.Language == "" {
+		c.Language = "Solidity"
+	}
+	if c.Settings.Optimizer.Runs == 0 {
+		c.Settings.Optimizer.Runs = DefaultOptimizerRuns
+	}
+	if len(c.Settings.Remappings) == 0 {
+		c.Settings.Remappings = nil
+	}
+	if len(c.Sources) == 0 {
+		c.Sources = nil
+	}
+
+	if len(in.Settings.OutputSelection) == 0 {
+		c.Settings.OutputSelection = nil
+		return &c
+	}
+	c.Settings.OutputSelection = make(map[string]map[string][]string)
+	for file, contracts := range in.Settings.OutputSelection {
+		c.Settings.OutputSelection[file] = make(map[string][]string)
+		for contract, outputs := range contracts {
+			c.Settings.OutputSelection[file][contract] = sortedSet(outputs)
+		}
+	}
+	return &c
+}
+
+func sortedSet(values []string) []string {
+	set := make([]string, 0, len(values))
+	seen := make(map[string]bool)
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			set = append(set, v)
+		}
+	}
+	sort.Strings(set)
+	return set
+}
@@ -0,0 +1,63 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInputHash(t *testing.T) {
+	in := &Input{
+		Sources: map[string]SourceIn{"A.sol": SourceIn{Content: "contract A {}"}},
+		Settings: Settings{
+			OutputSelection: map[string]map[string][]string{"*": {"*": {"evm.bytecode", "abi", "abi"}}},
+		},
+	}
+	explicit := &Input{
+		Language: "Solidity",
+		Sources:  map[string]SourceIn{"A.sol": SourceIn{Content: "contract A {}"}},
+		Settings: Settings{
+			Optimizer:       Optimizer{Runs: DefaultOptimizerRuns},
+			OutputSelection: map[string]map[string][]string{"*": {"*": {"abi", "evm.bytecode"}}},
+		},
+	}
+	assert.Equal(t, in.Hash(), explicit.Hash(), "Inputs differing by defaults and selection order should hash the same")
+	assert.Equal(t, []string{"evm.bytecode", "abi", "abi"}, in.Settings.OutputSelection["*"]["*"], "Hash should not modify the input")
+
+	explicit.Settings.Optimizer.Enabled = true
+	assert.NotEqual(t, in.Hash(), explicit.Hash(), "Inputs with different settings should not hash the same")
+
+	remapped := &Input{Settings: Settings{Remappings: []string{"a=b", "c=d"}}}
+	reordered := &Input{Settings: Settings{Remappings: []string{"c=d", "a=b"}}}
+	assert.NotEqual(t, remapped.Hash(), reordered.Hash(), "Remappings order should be significant")
+}
+
+func TestNewInput(t *testing.T) {
+	in := NewInput()
+	assert.Equal(t, "Solidity", in.Language, "Language should default to Solidity")
+	assert.Equal(t, Optimizer{Enabled: true, Runs: DefaultOptimizerRuns}, in.Settings.Optimizer, "Optimizer should be enabled")
+	assert.Equal(t, OutputSelection{"*": {"*": DefaultOutputs}}, in.Settings.OutputSelection, "Default outputs should be selected")
+
+	in.AddSource("One.sol", "contract One {}").
+		AddSources(map[string]SourceIn{"Two.sol": {Content: "contract Two {}"}}).
+		AddRemapping(Remapping{Prefix: "@oz/", Target: "node_modules/@openzeppelin/"}).
+		SetOptimizer(false, 0).
+		SetEVMVersion("istanbul").
+		SetViaIR(true).
+		SetOutputSelection(SelectAll("abi"))
+	assert.Equal(t, &Input{
+		Language: "Solidity",
+		Sources: map[string]SourceIn{
+			"One.sol": {Content: "contract One {}"},
+			"Two.sol": {Content: "contract Two {}"},
+		},
+		Settings: Settings{
+			Remappings:      []string{"@oz/=node_modules/@openzeppelin/"},
+			EVMVersion:      "istanbul",
+			ViaIR:           true,
+			OutputSelection: OutputSelection{"*": {"*": {"abi"}}},
+		},
+	}, in, "Setters should update the input")
+
+	assert.Len(t, (&Input{}).AddSource("One.sol", "contract One {}").Sources, 1, "AddSource should allocate sources")
+}
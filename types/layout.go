@@ -0,0 +1,131 @@
+package types
+
+import (
+	"fmt"
+)
+
+// StorageLocation is where state variables live, either persistent or transient storage (EIP-1153)
+type StorageLocation string
+
+const (
+	LocationStorage   StorageLocation = "storage"
+	LocationTransient StorageLocation = "transient"
+)
+
+// Layout returns the layout of the state variables of the contract at location, nil if not selected
+func (c *Contract) Layout(location StorageLocation) *StorageLayout {
+	if location == LocationTransient {
+		return c.TransientStorageLayout
+	}
+	return c.StorageLayout
+}
+
+// Kinds of LayoutChange
+const (
+	// ChangeRemoved is a state variable missing from the new layout
+	ChangeRemoved = "removed"
+
+	// ChangeMoved is a state variable stored in a different slot or offset
+	ChangeMoved = "moved"
+
+	// ChangeRetyped is a state variable whose type changed
+	ChangeRetyped = "retyped"
+
+	// ChangeRelocated is a state variable moved between persistent and transient storage
+	ChangeRelocated = "relocated"
+)
+
+// LayoutChange is a change of the layout of a state variable that is unsafe for an upgrade
+type LayoutChange struct {
+	Kind string
+
+	// Contract and Label identify the state variable
+	Contract string
+	Label    string
+
+	// Location is the location in the old layout, NewLocation differs only for ChangeRelocated
+	Location    StorageLocation
+	NewLocation StorageLocation
+
+	Old *StorageSlot
+
+	// New is nil for ChangeRemoved
+	New *StorageSlot
+}
+
+func (c LayoutChange) String() string {
+	name := fmt.Sprintf("%v variable %v.%v", c.Location, c.Contract, c.Label)
+	switch c.Kind {
+	case ChangeRemoved:
+		return name + " removed"
+	case ChangeMoved:
+		return fmt.Sprintf("%v moved from slot %v offset %v to slot %v offset %v", name, c.Old.Slot, c.Old.Offset, c.New.Slot, c.New.Offset)
+	case ChangeRetyped:
+		return fmt.Sprintf("%v changed type from %v to %v", name, c.Old.Type, c.New.Type)
+	case ChangeRelocated:
+		return fmt.Sprintf("%v relocated to %v", name, c.NewLocation)
+	}
+	return name + " " + c.Kind
+}
+
+// CompareStorageLayouts returns the changes of the state variables of old in new, in the order of old
+//
+// Variables are matched by declaring contract and label within each location. Persistent and transient
+// slots are independent, so a variable moving between them is reported as ChangeRelocated whatever its slot.
+// Appended variables are not reported. Types are compared by label and size as type identifiers embed AST ids
+func CompareStorageLayouts(old, new *Contract) []LayoutChange {
+	var changes []LayoutChange
+	for _, location := range []StorageLocation{LocationStorage, LocationTransient} {
+		oldLayout := old.Layout(location)
+		if oldLayout == nil {
+			continue
+		}
+		other := LocationTransient
+		if location == LocationTransient {
+			other = LocationStorage
+		}
+
+		for i := range oldLayout.Storage {
+			o := &oldLayout.Storage[i]
+			change := LayoutChange{Contract: o.Contract, Label: o.Label, Location: location, NewLocation: location, Old: o}
+
+			n := findSlot(new.Layout(location), o)
+			relocated := findSlot(new.Layout(other), o)
+			switch {
+			case n == nil && relocated != nil:
+				change.Kind, change.NewLocation, change.New = ChangeRelocated, other, relocated
+			case n == nil:
+				change.Kind = ChangeRemoved
+			case n.Slot != o.Slot || n.Offset != o.Offset:
+				change.Kind, change.New = ChangeMoved, n
+			case !sameStorageType(oldLayout, o.Type, new.Layout(location), n.Type):
+				change.Kind, change.New = ChangeRetyped, n
+			default:
+				continue
+			}
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// findSlot returns the slot of layout declaring the same variable as slot
+func findSlot(layout *StorageLayout, slot *StorageSlot) *StorageSlot {
+	if layout == nil {
+		return nil
+	}
+	for i, s := range layout.Storage {
+		if s.Contract == slot.Contract && s.Label == slot.Label {
+			return &layout.Storage[i]
+		}
+	}
+	return nil
+}
+
+func sameStorageType(a *StorageLayout, aType string, b *StorageLayout, bType string) bool {
+	at, bt := a.Types[aType], b.Types[bType]
+	if at == nil || bt == nil {
+		return aType == bType
+	}
+	return at.Label == bt.Label && at.NumberOfBytes == bt.NumberOfBytes && at.Encoding == bt.Encoding
+}
@@ -0,0 +1,65 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareStorageLayouts(t *testing.T) {
+	types := map[string]*StorageType{
+		"t_uint256":     {Encoding: "inplace", Label: "uint256", NumberOfBytes: "32"},
+		"t_uint128":     {Encoding: "inplace", Label: "uint128", NumberOfBytes: "16"},
+		"t_bool":        {Encoding: "inplace", Label: "bool", NumberOfBytes: "1"},
+		"t_struct(S)12": {Encoding: "inplace", Label: "struct One.S", NumberOfBytes: "32"},
+		"t_struct(S)15": {Encoding: "inplace", Label: "struct One.S", NumberOfBytes: "32"},
+	}
+	old := &Contract{
+		StorageLayout: &StorageLayout{Types: types, Storage: []StorageSlot{
+			{Contract: "One.sol:One", Label: "total", Slot: "0", Type: "t_uint256"},
+			{Contract: "One.sol:One", Label: "paused", Slot: "1", Type: "t_bool"},
+			{Contract: "One.sol:One", Label: "s", Slot: "2", Type: "t_struct(S)12"},
+			{Contract: "One.sol:One", Label: "owner", Slot: "3", Type: "t_uint256"},
+			{Contract: "One.sol:One", Label: "lock", Slot: "4", Type: "t_bool"},
+		}},
+		TransientStorageLayout: &StorageLayout{Types: types, Storage: []StorageSlot{
+			{Contract: "One.sol:One", Label: "entered", Slot: "0", Type: "t_bool"},
+			{Contract: "One.sol:One", Label: "cache", Slot: "1", Type: "t_uint256"},
+		}},
+	}
+	new := &Contract{
+		StorageLayout: &StorageLayout{Types: types, Storage: []StorageSlot{
+			{Contract: "One.sol:One", Label: "total", Slot: "0", Type: "t_uint128"},
+			{Contract: "One.sol:One", Label: "paused", Slot: "1", Offset: 16, Type: "t_bool"},
+			{Contract: "One.sol:One", Label: "s", Slot: "2", Type: "t_struct(S)15"},
+			{Contract: "One.sol:One", Label: "appended", Slot: "5", Type: "t_uint256"},
+		}},
+		TransientStorageLayout: &StorageLayout{Types: types, Storage: []StorageSlot{
+			{Contract: "One.sol:One", Label: "lock", Slot: "0", Type: "t_bool"},
+			{Contract: "One.sol:One", Label: "entered", Slot: "1", Type: "t_bool"},
+			{Contract: "One.sol:One", Label: "cache", Slot: "1", Offset: 1, Type: "t_uint256"},
+		}},
+	}
+
+	var got []string
+	for _, change := range CompareStorageLayouts(old, new) {
+		got = append(got, change.String())
+	}
+	assert.Equal(t, []string{
+		"storage variable One.sol:One.total changed type from t_uint256 to t_uint128",
+		"storage variable One.sol:One.paused moved from slot 1 offset 0 to slot 1 offset 16",
+		"storage variable One.sol:One.owner removed",
+		"storage variable One.sol:One.lock relocated to transient",
+		"transient variable One.sol:One.entered moved from slot 0 offset 0 to slot 1 offset 0",
+		"transient variable One.sol:One.cache moved from slot 1 offset 0 to slot 1 offset 1",
+	}, got, "Changes should be reported per location in the order of the old layout")
+
+	assert.Empty(t, CompareStorageLayouts(old, old), "Identical layouts should not change")
+
+	noTransient := &Contract{StorageLayout: old.StorageLayout}
+	changes := CompareStorageLayouts(old, noTransient)
+	require.Len(t, changes, 2, "Transient variables missing from the new layout should be removed")
+	assert.Equal(t, ChangeRemoved, changes[0].Kind)
+	assert.Equal(t, LocationTransient, changes[0].Location)
+}
@@ -0,0 +1,62 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// AmbiguousContractError is returned by FindContract when several files define a contract of the name looked up
+type AmbiguousContractError struct {
+	Name  string
+	Files []string
+}
+
+func (e *AmbiguousContractError) Error() string {
+	return fmt.Sprintf("solc: contract %v is defined in several files (%v), use a fully qualified name", e.Name, strings.Join(e.Files, ", "))
+}
+
+// Contract returns the contract name of file
+//
+// The contract is a copy, modifying it does not modify the output
+func (out *Output) Contract(file, name string) (*Contract, bool) {
+	c, ok := out.Contracts[file][name]
+	if !ok {
+		return nil, false
+	}
+	return &c, true
+}
+
+// FindContract returns the contract named name and the file defining it
+//
+// name is either fully qualified (path/File.sol:Name) or a bare contract name, which must then
+// be defined by a single file or an *AmbiguousContractError is returned
+func (out *Output) FindContract(name string) (string, *Contract, error) {
+	id, err := ParseContractID(name)
+	if err != nil {
+		return "", nil, err
+	}
+	if id.IsQualified() {
+		c, ok := out.Contract(id.File, id.Name)
+		if !ok {
+			return "", nil, fmt.Errorf("solc: contract %v not found", id)
+		}
+		return id.File, c, nil
+	}
+
+	var files []string
+	for file, contracts := range out.Contracts {
+		if _, ok := contracts[id.Name]; ok {
+			files = append(files, file)
+		}
+	}
+	switch len(files) {
+	case 0:
+		return "", nil, fmt.Errorf("solc: contract %v not found", id)
+	case 1:
+		c, _ := out.Contract(files[0], id.Name)
+		return files[0], c, nil
+	}
+	sort.Strings(files)
+	return "", nil, &AmbiguousContractError{Name: id.Name, Files: files}
+}
@@ -0,0 +1,44 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindContract(t *testing.T) {
+	out := &Output{Contracts: map[string]map[string]Contract{
+		"src/A.sol":    {"Token": {Metadata: "a"}, "A": {Metadata: "aa"}},
+		"lib/B.sol":    {"Token": {Metadata: "b"}},
+		"c:/weird.sol": {"C": {Metadata: "c"}},
+	}}
+
+	c, ok := out.Contract("src/A.sol", "Token")
+	require.True(t, ok, "Contract should be found")
+	assert.Equal(t, "a", c.Metadata)
+	_, ok = out.Contract("src/A.sol", "Missing")
+	assert.False(t, ok, "Missing contract should not be found")
+
+	file, c, err := out.FindContract("A")
+	require.NoError(t, err, "Unique name should be found")
+	assert.Equal(t, "src/A.sol", file)
+	assert.Equal(t, "aa", c.Metadata)
+
+	file, c, err = out.FindContract("lib/B.sol:Token")
+	require.NoError(t, err, "Fully qualified name should be found")
+	assert.Equal(t, "lib/B.sol", file)
+	assert.Equal(t, "b", c.Metadata)
+
+	file, _, err = out.FindContract("c:/weird.sol:C")
+	require.NoError(t, err, "Paths with colons should be supported")
+	assert.Equal(t, "c:/weird.sol", file)
+
+	_, _, err = out.FindContract("Token")
+	assert.Equal(t, &AmbiguousContractError{Name: "Token", Files: []string{"lib/B.sol", "src/A.sol"}}, err, "Names defined twice should be ambiguous")
+
+	_, _, err = out.FindContract("Missing")
+	assert.Error(t, err, "Missing contract should error")
+	_, _, err = out.FindContract("src/A.sol:Missing")
+	assert.Error(t, err, "Missing contract should error")
+}
@@ -0,0 +1,116 @@
+package types
+
+import (
+	"encoding/json"
+)
+
+type Output struct {
+	Errors    []Error                        `json:"errors,omitempty"`
+	Sources   map[string]SourceOut           `json:"sources,omitempty"`
+	Contracts map[string]map[string]Contract `json:"contracts,omitempty"`
+
+	// Console holds the lines printed by the compiler while compiling (see solc.WithConsoleCapture)
+	Console []string `json:"-"`
+
+	// Findings are the issues reported by analyzers (see solc.WithAnalyzers)
+	Findings []Finding `json:"-"`
+
+	// Units are the compilation units of mixed Solidity and Yul builds (see solc.CompileMixed) or of
+	// builds split per unit (see solc.Pool.CompileUnits)
+	Units []CompilationUnit `json:"-"`
+
+	// Profile breaks down the duration of the compilation (see solc.WithProfiling and solc.ProfileCompile)
+	Profile *CompileProfile `json:"-"`
+
+	// Coverage maps the markers of the instrumented sources compiled (see solc.WithCoverage)
+	Coverage *CoverageMap `json:"-"`
+}
+
+type Error struct {
+	SourceLocation   SourceLocation `json:"sourceLocation,omitempty"`
+	Type             string         `json:"type,omitempty"`
+	ErrorCode        string         `json:"errorCode,omitempty"`
+	Component        string         `json:"component,omitempty"`
+	Severity         string         `json:"severity,omitempty"`
+	Message          string         `json:"message,omitempty"`
+	FormattedMessage string         `json:"formattedMessage,omitempty"`
+}
+
+type SourceLocation struct {
+	File  string `json:"file,omitempty"`
+	Start int    `json:"start,omitempty"`
+	End   int    `json:"end,omitempty"`
+}
+
+type SourceOut struct {
+	ID        int             `json:"id,omitempty"`
+	AST       json.RawMessage `json:"ast,omitempty"`
+	LegacyAST json.RawMessage `json:"legacyAST,omitempty"`
+}
+
+type Contract struct {
+	ABI           []json.RawMessage `json:"abi,omitempty"`
+	Metadata      string            `json:"metadata,omitempty"`
+	UserDoc       json.RawMessage   `json:"userdoc,omitempty"`
+	DevDoc        json.RawMessage   `json:"devdoc,omitempty"`
+	IR            string            `json:"ir,omitempty"`
+	IROptimized   string            `json:"irOptimized,omitempty"`
+	StorageLayout *StorageLayout    `json:"storageLayout,omitempty"`
+	EVM           EVM               `json:"evm,omitempty"`
+	EWASM         EWASM             `json:"ewasm,omitempty"`
+
+	// TransientStorageLayout is the layout of the transient state variables (solc >= 0.8.27)
+	TransientStorageLayout *StorageLayout `json:"transientStorageLayout,omitempty"`
+}
+
+// StorageLayout is the layout of the state variables of a contract (solc >= 0.5.13)
+type StorageLayout struct {
+	Storage []StorageSlot           `json:"storage"`
+	Types   map[string]*StorageType `json:"types"`
+}
+
+type StorageSlot struct {
+	AstID    int    `json:"astId"`
+	Contract string `json:"contract"`
+	Label    string `json:"label"`
+	Offset   int    `json:"offset"`
+	Slot     string `json:"slot"`
+	Type     string `json:"type"`
+}
+
+type StorageType struct {
+	Encoding      string        `json:"encoding"`
+	Label         string        `json:"label"`
+	NumberOfBytes string        `json:"numberOfBytes"`
+	Key           string        `json:"key,omitempty"`
+	Value         string        `json:"value,omitempty"`
+	Base          string        `json:"base,omitempty"`
+	Members       []StorageSlot `json:"members,omitempty"`
+}
+
+type EVM struct {
+	Assembly          string                       `json:"assembly,omitempty"`
+	LegacyAssembly    json.RawMessage              `json:"legacyAssembly,omitempty"`
+	Bytecode          Bytecode                     `json:"bytecode,omitempty"`
+	DeployedBytecode  Bytecode                     `json:"deployedBytecode,omitempty"`
+	MethodIdentifiers map[string]string            `json:"methodIdentifiers,omitempty"`
+	GasEstimates      map[string]map[string]string `json:"gasEstimates,omitempty"`
+}
+
+type Bytecode struct {
+	Object              string                                `json:"object,omitempty"`
+	Opcodes             string                                `json:"opcodes,omitempty"`
+	SourceMap           string                                `json:"sourceMap,omitempty"`
+	LinkReferences      map[string]map[string][]LinkReference `json:"linkReferences,omitempty"`
+	ImmutableReferences map[string][]LinkReference            `json:"immutableReferences,omitempty"`
+}
+
+type LinkReference struct {
+	Start  int `json:"start"`
+	Length int `json:"length"`
+}
+
+type EWASM struct {
+	Wast string `json:"wast,omitempty"`
+	Wasm string `json:"wasm,omitempty"`
+}
@@ -0,0 +1,83 @@
+package types
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// NormalizeSourceName returns name slash separated and cleaned (without ./ nor duplicate slashes), so sources
+// named on Windows (src\token\ERC20.sol) match those named elsewhere (src/token/ERC20.sol)
+//
+// A trailing slash, significant in remappings, is kept. Absolute and parent (../) paths stay so
+func NormalizeSourceName(name string) string {
+	if name == "" {
+		return name
+	}
+	normalized := path.Clean(strings.Replace(name, `\`, "/", -1))
+	if strings.HasSuffix(name, "/") || strings.HasSuffix(name, `\`) {
+		if normalized != "/" {
+			normalized += "/"
+		}
+	}
+	return normalized
+}
+
+// NormalizeRemapping normalizes the context, prefix and target of a remapping, invalid ones are kept as is
+func NormalizeRemapping(s string) string {
+	r, err := ParseRemapping(s)
+	if err != nil {
+		return s
+	}
+	r.Context, r.Prefix, r.Target = NormalizeSourceName(r.Context), NormalizeSourceName(r.Prefix), NormalizeSourceName(r.Target)
+	return r.String()
+}
+
+// NormalizeSourceNames normalizes (see NormalizeSourceName) the source names of the input, of its remappings,
+// libraries and output selection. It errors if sources have the same normalized name
+//
+// Imports are not rewritten: sources importing others with backslashes should be resolved accordingly
+// (see solc.NormalizeResolver) before normalizing
+func (in *Input) NormalizeSourceNames() error {
+	names := make([]string, 0, len(in.Sources))
+	for name := range in.Sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	sources := make(map[string]SourceIn, len(in.Sources))
+	normalizedFrom := make(map[string]string, len(in.Sources))
+	for _, name := range names {
+		normalized := NormalizeSourceName(name)
+		if other, ok := normalizedFrom[normalized]; ok {
+			return fmt.Errorf("solc: sources %q and %q have the same normalized name %q", other, name, normalized)
+		}
+		normalizedFrom[normalized] = name
+		sources[normalized] = in.Sources[name]
+	}
+	if in.Sources != nil {
+		in.Sources = sources
+	}
+
+	for i, r := range in.Settings.Remappings {
+		in.Settings.Remappings[i] = NormalizeRemapping(r)
+	}
+	if in.Settings.Libraries != nil {
+		libraries := make(map[string]map[string]string, len(in.Settings.Libraries))
+		for file, addresses := range in.Settings.Libraries {
+			libraries[NormalizeSourceName(file)] = addresses
+		}
+		in.Settings.Libraries = libraries
+	}
+	if in.Settings.OutputSelection != nil {
+		selection := make(OutputSelection, len(in.Settings.OutputSelection))
+		for file, contracts := range in.Settings.OutputSelection {
+			if file != "*" {
+				file = NormalizeSourceName(file)
+			}
+			selection[file] = contracts
+		}
+		in.Settings.OutputSelection = selection
+	}
+	return nil
+}
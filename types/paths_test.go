@@ -0,0 +1,41 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeSourceName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"":                     "",
+		"src/A.sol":            "src/A.sol",
+		`src\token\ERC20.sol`:  "src/token/ERC20.sol",
+		`.\src\A.sol`:          "src/A.sol",
+		"src//lib/./A.sol":     "src/lib/A.sol",
+		`..\lib\A.sol`:         "../lib/A.sol",
+		`C:\project\src\A.sol`: "C:/project/src/A.sol",
+		`lib\oz\`:              "lib/oz/",
+		"/":                    "/",
+	} {
+		assert.Equal(t, expected, NormalizeSourceName(name), "NormalizeSourceName(%q)", name)
+	}
+}
+
+func TestInputNormalizeSourceNames(t *testing.T) {
+	in := NewInput().
+		AddSource(`src\A.sol`, "a").
+		AddSource("src/B.sol", "b").
+		AddRemapping(Remapping{Prefix: `@oz\`, Target: `lib\oz\`}).
+		SetOutputSelection(OutputSelection{"*": {"*": {"abi"}}, `src\A.sol`: {"A": {"evm.bytecode"}}})
+	in.Settings.Libraries = map[string]map[string]string{`src\Lib.sol`: {"Lib": "0x0000000000000000000000000000000000000001"}}
+	require.NoError(t, in.NormalizeSourceNames(), "NormalizeSourceNames should not error")
+	assert.Equal(t, map[string]SourceIn{"src/A.sol": {Content: "a"}, "src/B.sol": {Content: "b"}}, in.Sources)
+	assert.Equal(t, []string{"@oz/=lib/oz/"}, in.Settings.Remappings)
+	assert.Equal(t, OutputSelection{"*": {"*": {"abi"}}, "src/A.sol": {"A": {"evm.bytecode"}}}, in.Settings.OutputSelection)
+	assert.Contains(t, in.Settings.Libraries, "src/Lib.sol")
+
+	in = NewInput().AddSource(`src\A.sol`, "a").AddSource("src/A.sol", "other a")
+	assert.Error(t, in.NormalizeSourceNames(), "Colliding sources should error")
+}
@@ -0,0 +1,71 @@
+package types
+
+// defaultEVMVersions are the EVM versions compilers target by default, with the first version defaulting to them
+var defaultEVMVersions = []struct {
+	since      string
+	evmVersion string
+}{
+	{"0.8.30", "prague"},
+	{"0.8.25", "cancun"},
+	{"0.8.20", "shanghai"},
+	{"0.8.18", "paris"},
+	{"0.8.7", "london"},
+	{"0.8.5", "berlin"},
+	{"0.5.14", "istanbul"},
+	{"0.5.5", "petersburg"},
+	{"0.4.21", "byzantium"},
+}
+
+// DefaultEVMVersion returns the EVM version compiler version targets when none is set, "" for compilers
+// older than 0.4.21 which have no evmVersion setting
+func DefaultEVMVersion(version string) (string, error) {
+	v, _, err := parseSemver(version)
+	if err != nil {
+		return "", err
+	}
+	for _, d := range defaultEVMVersions {
+		since, _, _ := parseSemver(d.since)
+		if !v.less(since) {
+			return d.evmVersion, nil
+		}
+	}
+	return "", nil
+}
+
+// ApplyVerificationProfile sets the settings third parties need to reproduce and verify the build of in with
+// compiler version and returns the input
+//
+// Metadata embeds the content of sources and is selected for every contract, its IPFS hash is appended
+// to bytecode (for solc >= 0.6.0, older compilers only append Swarm hashes). Optimizer runs and the EVM
+// version are made explicit, the latter to the default of the compiler unless set
+func (in *Input) ApplyVerificationProfile(version string) (*Input, error) {
+	evmVersion, err := DefaultEVMVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	v, _, _ := parseSemver(version)
+
+	metadata := MetadataSettings{UseLiteralContent: true}
+	if in.Settings.Metadata != nil {
+		metadata = *in.Settings.Metadata
+		metadata.UseLiteralContent = true
+	}
+	if v.less(semver{0, 6, 0}) {
+		metadata.BytecodeHash = ""
+	} else {
+		metadata.BytecodeHash = "ipfs"
+	}
+	in.Settings.Metadata = &metadata
+
+	if in.Settings.Optimizer.Runs == 0 {
+		in.Settings.Optimizer.Runs = DefaultOptimizerRuns
+	}
+	if in.Settings.EVMVersion == "" {
+		in.Settings.EVMVersion = evmVersion
+	}
+	if len(in.Settings.OutputSelection) == 0 {
+		in.Settings.OutputSelection = SelectAll(DefaultOutputs...)
+	}
+	in.Settings.OutputSelection = in.Settings.OutputSelection.Select("*", "*", "metadata")
+	return in, nil
+}
@@ -0,0 +1,36 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Remapping is an import remapping written context:prefix=target (see Settings.Remappings)
+type Remapping struct {
+	Context string
+	Prefix  string
+	Target  string
+}
+
+// ParseRemapping parses a remapping written [context:]prefix=target
+func ParseRemapping(s string) (Remapping, error) {
+	eq := strings.Index(s, "=")
+	if eq <= 0 {
+		return Remapping{}, fmt.Errorf("invalid remapping %q, expected [context:]prefix=target", s)
+	}
+	r := Remapping{Prefix: s[:eq], Target: s[eq+1:]}
+	if colon := strings.Index(r.Prefix, ":"); colon >= 0 {
+		r.Context, r.Prefix = r.Prefix[:colon], r.Prefix[colon+1:]
+	}
+	if r.Prefix == "" {
+		return Remapping{}, fmt.Errorf("invalid remapping %q, expected [context:]prefix=target", s)
+	}
+	return r, nil
+}
+
+func (r Remapping) String() string {
+	if r.Context != "" {
+		return r.Context + ":" + r.Prefix + "=" + r.Target
+	}
+	return r.Prefix + "=" + r.Target
+}
@@ -0,0 +1,110 @@
+package types
+
+import (
+	"strings"
+)
+
+// OutputSelection selects the outputs of each contract of each source file
+//
+// Files and contracts may be "*" to select all of them. The "" contract selects file level outputs (ast, legacyAST)
+type OutputSelection map[string]map[string][]string
+
+// SelectAll returns a selection of outputs for every contract of every file
+func SelectAll(outputs ...string) OutputSelection {
+	return OutputSelection{}.Select("*", "*", outputs...)
+}
+
+// SelectContract returns a selection of outputs for contract of file
+func SelectContract(file, contract string, outputs ...string) OutputSelection {
+	return OutputSelection{}.Select(file, contract, outputs...)
+}
+
+// Select adds outputs for contract of file and returns the selection, which is allocated if nil
+//
+//	selection = selection.Select("One.sol", "One", "abi", "evm.bytecode.object")
+func (s OutputSelection) Select(file, contract string, outputs ...string) OutputSelection {
+	if s == nil {
+		s = make(OutputSelection)
+	}
+	if s[file] == nil {
+		s[file] = make(map[string][]string)
+	}
+	for _, output := range outputs {
+		if !contains(s[file][contract], output) {
+			s[file][contract] = append(s[file][contract], output)
+		}
+	}
+	return s
+}
+
+// Selected indicates whether output of contract of file is selected, either directly, by a wildcard
+// or by a parent output (e.g. evm selects evm.bytecode.object)
+func (s OutputSelection) Selected(file, contract, output string) bool {
+	for _, f := range []string{file, "*"} {
+		for _, c := range []string{contract, "*"} {
+			if contract == "" && c == "*" {
+				// File level outputs are not selected by contract wildcards
+				continue
+			}
+			for _, selected := range s[f][c] {
+				if selected == "*" || selected == output || strings.HasPrefix(output, selected+".") {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Filter returns a copy of out restricted to files and to contracts (Name or path:Name)
+//
+// A nil files or contracts keeps all of them. Errors not attached to a removed file are kept.
+// Contracts and sources are shared with out
+func (out *Output) Filter(files, contracts []string) *Output {
+	keepFile := func(file string) bool { return files == nil || contains(files, file) }
+	keepContract := func(file, name string) bool {
+		return contracts == nil || contains(contracts, name) || contains(contracts, ContractID{File: file, Name: name}.String())
+	}
+
+	filtered := &Output{}
+	for _, err := range out.Errors {
+		if file := err.SourceLocation.File; file == "" || keepFile(file) {
+			filtered.Errors = append(filtered.Errors, err)
+		}
+	}
+	for file, source := range out.Sources {
+		if keepFile(file) {
+			if filtered.Sources == nil {
+				filtered.Sources = make(map[string]SourceOut)
+			}
+			filtered.Sources[file] = source
+		}
+	}
+	for file, fileContracts := range out.Contracts {
+		if !keepFile(file) {
+			continue
+		}
+		for name, contract := range fileContracts {
+			if !keepContract(file, name) {
+				continue
+			}
+			if filtered.Contracts == nil {
+				filtered.Contracts = make(map[string]map[string]Contract)
+			}
+			if filtered.Contracts[file] == nil {
+				filtered.Contracts[file] = make(map[string]Contract)
+			}
+			filtered.Contracts[file][name] = contract
+		}
+	}
+	return filtered
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
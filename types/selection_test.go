@@ -0,0 +1,53 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputSelection(t *testing.T) {
+	var s OutputSelection
+	s = s.Select("One.sol", "One", "abi", "evm.bytecode.object", "abi")
+	s = s.Select("*", "", "ast")
+	assert.Equal(t, OutputSelection{
+		"One.sol": {"One": {"abi", "evm.bytecode.object"}},
+		"*":       {"": {"ast"}},
+	}, s, "Outputs should be deduplicated")
+
+	assert.True(t, s.Selected("One.sol", "One", "abi"))
+	assert.False(t, s.Selected("One.sol", "Two", "abi"))
+	assert.True(t, s.Selected("Two.sol", "", "ast"), "File wildcards should select")
+	assert.False(t, s.Selected("Two.sol", "", "legacyAST"))
+
+	all := SelectAll("evm")
+	assert.Equal(t, OutputSelection{"*": {"*": {"evm"}}}, all)
+	assert.True(t, all.Selected("One.sol", "One", "evm.bytecode.object"), "Parent outputs should select children")
+	assert.False(t, all.Selected("One.sol", "", "ast"), "Contract wildcards should not select file level outputs")
+	assert.Equal(t, OutputSelection{"One.sol": {"One": {"abi"}}}, SelectContract("One.sol", "One", "abi"))
+}
+
+func TestOutputFilter(t *testing.T) {
+	out := &Output{
+		Errors: []Error{
+			{Message: "global"},
+			{Message: "one", SourceLocation: SourceLocation{File: "One.sol"}},
+			{Message: "two", SourceLocation: SourceLocation{File: "Two.sol"}},
+		},
+		Sources: map[string]SourceOut{"One.sol": {ID: 0}, "Two.sol": {ID: 1}},
+		Contracts: map[string]map[string]Contract{
+			"One.sol": {"One": {Metadata: "one"}, "Lib": {Metadata: "lib"}},
+			"Two.sol": {"Two": {Metadata: "two"}},
+		},
+	}
+
+	filtered := out.Filter([]string{"One.sol"}, []string{"One"})
+	assert.Equal(t, []Error{out.Errors[0], out.Errors[1]}, filtered.Errors, "Errors of removed files should be removed")
+	assert.Equal(t, map[string]SourceOut{"One.sol": {ID: 0}}, filtered.Sources)
+	assert.Equal(t, map[string]map[string]Contract{"One.sol": {"One": {Metadata: "one"}}}, filtered.Contracts)
+
+	filtered = out.Filter(nil, []string{"Two.sol:Two", "Lib"})
+	assert.Len(t, filtered.Sources, 2, "Nil files should keep every file")
+	assert.Equal(t, map[string]map[string]Contract{"One.sol": {"Lib": {Metadata: "lib"}}, "Two.sol": {"Two": {Metadata: "two"}}}, filtered.Contracts)
+	assert.Len(t, out.Contracts["One.sol"], 2, "Output should not be modified")
+}
@@ -0,0 +1,81 @@
+package types
+
+import (
+	"sort"
+	"strings"
+)
+
+// SelectorMatch is a function, error or event of a contract with a given selector
+type SelectorMatch struct {
+	Contract  ContractID `json:"contract"`
+	Type      string     `json:"type"`
+	Signature string     `json:"signature"`
+}
+
+// SelectorRegistry maps 0x prefixed lowercase selectors (4 bytes) and event topics (32 bytes) to the entries they stand for
+//
+// It encodes to JSON as is, to be exported and loaded back when decoding traces or calldata
+type SelectorRegistry map[string][]SelectorMatch
+
+// Add registers the selectors of the contracts of out, from their ABI or method identifiers
+func (r SelectorRegistry) Add(out *Output) error {
+	for _, id := range out.ContractIDs() {
+		c := out.Contracts[id.File][id.Name]
+		entries, err := ParseABI(c.ABI)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if selector := entry.Selector(); selector != "" {
+				r.AddMatch(selector, SelectorMatch{Contract: id, Type: entry.Type, Signature: entry.Signature()})
+			}
+		}
+		// Method identifiers identify functions when the ABI is not selected
+		for signature, selector := range c.EVM.MethodIdentifiers {
+			r.AddMatch("0x"+selector, SelectorMatch{Contract: id, Type: "function", Signature: signature})
+		}
+	}
+	return nil
+}
+
+// AddMatch adds match to the entries with selector, unless already there
+func (r SelectorRegistry) AddMatch(selector string, match SelectorMatch) {
+	selector = NormalizeSelector(selector)
+	for _, m := range r[selector] {
+		if m == match {
+			return
+		}
+	}
+	matches := append(r[selector], match)
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Contract != matches[j].Contract {
+			return matches[i].Contract.String() < matches[j].Contract.String()
+		}
+		return matches[i].Signature < matches[j].Signature
+	})
+	r[selector] = matches
+}
+
+// Lookup returns the entries with selector, which may be upper case or miss its 0x prefix
+func (r SelectorRegistry) Lookup(selector string) []SelectorMatch {
+	return r[NormalizeSelector(selector)]
+}
+
+// LookupSelector returns the functions, errors and events of the contracts of out with selector (e.g. 0x901717d1)
+func (out *Output) LookupSelector(selector string) ([]SelectorMatch, error) {
+	r := make(SelectorRegistry)
+	err := r.Add(out)
+	if err != nil {
+		return nil, err
+	}
+	return r.Lookup(selector), nil
+}
+
+// NormalizeSelector returns selector lower case and 0x prefixed
+func NormalizeSelector(selector string) string {
+	selector = strings.ToLower(selector)
+	if !strings.HasPrefix(selector, "0x") {
+		selector = "0x" + selector
+	}
+	return selector
+}
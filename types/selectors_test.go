@@ -0,0 +1,46 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupSelector(t *testing.T) {
+	out := &Output{Contracts: map[string]map[string]Contract{
+		"A.sol": {"A": {
+			ABI: []json.RawMessage{
+				json.RawMessage(`{"type":"function","name":"one","inputs":[]}`),
+				json.RawMessage(`{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256"}]}`),
+			},
+			EVM: EVM{MethodIdentifiers: map[string]string{"one()": "901717d1"}},
+		}},
+		"B.sol": {"B": {EVM: EVM{MethodIdentifiers: map[string]string{"one()": "901717d1"}}}},
+	}}
+
+	matches, err := out.LookupSelector("0x901717D1")
+	require.NoError(t, err, "LookupSelector should not error")
+	assert.Equal(t, []SelectorMatch{
+		{Contract: ContractID{File: "A.sol", Name: "A"}, Type: "function", Signature: "one()"},
+		{Contract: ContractID{File: "B.sol", Name: "B"}, Type: "function", Signature: "one()"},
+	}, matches, "Functions should be found across the build, once per contract")
+
+	matches, err = out.LookupSelector("ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	require.NoError(t, err)
+	require.Len(t, matches, 1, "Event topics should be found")
+	assert.Equal(t, "Transfer(address,address,uint256)", matches[0].Signature)
+
+	matches, err = out.LookupSelector("0xdeadbeef")
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+
+	registry := make(SelectorRegistry)
+	require.NoError(t, registry.Add(out), "Add should not error")
+	b, err := json.Marshal(registry)
+	require.NoError(t, err, "Registry should export")
+	loaded := make(SelectorRegistry)
+	require.NoError(t, json.Unmarshal(b, &loaded), "Registry should load")
+	assert.Equal(t, registry, loaded, "Registry should round trip")
+}
@@ -0,0 +1,51 @@
+// Package types declares the standard JSON input and output of solc and the Solc interface, without the v8
+// binding of the solc package so clients of remote compilers (see package remote) do not link v8
+package types
+
+import (
+	"errors"
+)
+
+var (
+	// ErrTimeout is returned by Compile when a compilation exceeds the configured timeout
+	ErrTimeout = errors.New("solc: compilation timed out")
+
+	// ErrTerminated is returned by Compile on an instance whose execution got terminated by a timeout
+	ErrTerminated = errors.New("solc: compiler terminated by a previous timeout")
+
+	// ErrClosed is returned when using a closed compiler
+	ErrClosed = errors.New("solc: compiler closed")
+)
+
+type Solc interface {
+	License() string
+	Version() string
+	Compile(input *Input) (*Output, error)
+	HeapStatistics() HeapStatistics
+
+	// Close releases the v8 isolate, it is safe to call it several times
+	Close() error
+}
+
+// HeapStatistics reports the memory used by the v8 isolate backing a compiler
+type HeapStatistics struct {
+	TotalHeapSize           uint64
+	TotalHeapSizeExecutable uint64
+	TotalPhysicalSize       uint64
+	TotalAvailableSize      uint64
+	UsedHeapSize            uint64
+	HeapSizeLimit           uint64
+	MallocedMemory          uint64
+	PeakMallocedMemory      uint64
+
+	// ExternalMemory includes the array buffers backing the emscripten memory
+	ExternalMemory uint64
+
+	// ModuleMemory is the size of the emscripten memory (it grows but never shrinks)
+	ModuleMemory uint64
+}
+
+// Usage is the memory accounted against a limit set with solc.WithMemoryLimit
+func (stats HeapStatistics) Usage() uint64 {
+	return stats.UsedHeapSize + stats.ExternalMemory
+}
@@ -0,0 +1,22 @@
+package types
+
+// CompilationUnit is an input compiled separately by solc.CompileMixed or solc.Pool.CompileUnits
+type CompilationUnit struct {
+	Language string
+
+	// Sources are the source unit names compiled together, source IDs and source maps of their
+	// output are relative to the unit
+	Sources []string
+}
+
+// Unit returns the compilation unit of the source file of a mixed or split build, nil if out has no unit for it
+func (out *Output) Unit(file string) *CompilationUnit {
+	for i, unit := range out.Units {
+		for _, name := range unit.Sources {
+			if name == file {
+				return &out.Units[i]
+			}
+		}
+	}
+	return nil
+}
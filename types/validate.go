@@ -0,0 +1,158 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// InputError is returned when an input is structurally invalid (see Input.Validate)
+type InputError struct {
+	// Problems describe each invalid field, prefixed with its JSON path
+	Problems []string
+}
+
+func (e *InputError) Error() string {
+	return fmt.Sprintf("solc: invalid input: %v", strings.Join(e.Problems, "; "))
+}
+
+// Languages are the input languages solc accepts
+var Languages = []string{"Solidity", "Yul", "SolidityAST", "EVMAssembly"}
+
+// EVMVersions are the EVM versions solc targets, oldest first (a compiler supports a subset of them)
+var EVMVersions = []string{
+	"homestead", "tangerineWhistle", "spuriousDragon", "byzantium", "constantinople", "petersburg",
+	"istanbul", "berlin", "london", "paris", "shanghai", "cancun", "prague", "osaka",
+}
+
+// BytecodeHashes are the hashes of metadata solc appends to bytecode
+var BytecodeHashes = []string{"ipfs", "bzzr1", "none"}
+
+var (
+	fileOutputs     = []string{"ast", "legacyAST"}
+	contractOutputs = []string{
+		"abi", "devdoc", "userdoc", "metadata", "ir", "irAst", "irOptimized", "irOptimizedAst",
+		"storageLayout", "transientStorageLayout", "evm", "evm.assembly", "evm.legacyAssembly",
+		"evm.bytecode", "evm.deployedBytecode", "evm.deployedBytecode.immutableReferences",
+		"evm.methodIdentifiers", "evm.gasEstimates", "ewasm", "ewasm.wast", "ewasm.wasm",
+	}
+	bytecodeOutputs = []string{"object", "opcodes", "sourceMap", "linkReferences", "generatedSources", "functionDebugData"}
+)
+
+// Validate checks the structure of the input, so mistakes solc would report as a single opaque
+// error (or silently ignore) are reported as an *InputError listing every problem
+//
+// Sources and remappings must be well formed, selected outputs and EVM version must be known
+// and library addresses valid. Validate does not check the sources compile
+func (in *Input) Validate() error {
+	var problems []string
+	problemf := func(format string, v ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, v...))
+	}
+
+	if in.Language != "" && !contains(Languages, in.Language) {
+		problemf("language: unknown language %q, expected one of %v", in.Language, strings.Join(Languages, ", "))
+	}
+
+	if len(in.Sources) == 0 {
+		problemf("sources: no source")
+	}
+	for name, source := range in.Sources {
+		if name == "" {
+			problemf("sources: empty source unit name")
+		}
+		if source.Content == "" && source.Keccak256 == "" && len(source.URLs) == 0 {
+			problemf("sources[%q]: no content", name)
+		}
+	}
+
+	for i, remapping := range in.Settings.Remappings {
+		if _, err := ParseRemapping(remapping); err != nil {
+			problemf("settings.remappings[%v]: %v", i, err)
+		}
+	}
+
+	if in.Settings.Optimizer.Runs < 0 {
+		problemf("settings.optimizer.runs: negative runs %v", in.Settings.Optimizer.Runs)
+	}
+
+	if v := in.Settings.EVMVersion; v != "" && !contains(EVMVersions, v) {
+		problemf("settings.evmVersion: unknown EVM version %q, expected one of %v", v, strings.Join(EVMVersions, ", "))
+	}
+
+	switch v := in.Settings.EOFVersion; {
+	case v != 0 && v != EOFVersion1:
+		problemf("settings.eofVersion: unknown EOF version %v, expected %v", v, EOFVersion1)
+	case v != 0 && in.Settings.EVMVersion != "" && evmVersionBefore(in.Settings.EVMVersion, EOFMinEVMVersion):
+		problemf("settings.eofVersion: EOF requires evmVersion %v or later, got %v", EOFMinEVMVersion, in.Settings.EVMVersion)
+	}
+
+	if m := in.Settings.Metadata; m != nil && m.BytecodeHash != "" && !contains(BytecodeHashes, m.BytecodeHash) {
+		problemf("settings.metadata.bytecodeHash: unknown hash %q, expected one of %v", m.BytecodeHash, strings.Join(BytecodeHashes, ", "))
+	}
+
+	if in.Settings.StopAfter != "" && in.Settings.StopAfter != "parsing" {
+		problemf("settings.stopAfter: unknown step %q, expected parsing", in.Settings.StopAfter)
+	}
+
+	for file, contracts := range in.Settings.OutputSelection {
+		for contract, outputs := range contracts {
+			for _, output := range outputs {
+				if !knownOutput(contract, output) {
+					problemf("settings.outputSelection[%q][%q]: unknown output %q", file, contract, output)
+				}
+			}
+		}
+	}
+
+	for file, libs := range in.Settings.Libraries {
+		for lib, addr := range libs {
+			if lib == "" {
+				problemf("settings.libraries[%q]: empty library name", file)
+			}
+			if _, err := HexToAddress(addr); err != nil {
+				problemf("settings.libraries[%q][%q]: %v", file, lib, err)
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		// Maps are iterated in random order
+		sort.Strings(problems)
+		return &InputError{Problems: problems}
+	}
+	return nil
+}
+
+// evmVersionBefore indicates whether EVM version v is known and older than w
+func evmVersionBefore(v, w string) bool {
+	i, j := -1, -1
+	for k, version := range EVMVersions {
+		if version == v {
+			i = k
+		}
+		if version == w {
+			j = k
+		}
+	}
+	return i >= 0 && i < j
+}
+
+// knownOutput indicates whether output can be selected for contract ("" for file level outputs)
+func knownOutput(contract, output string) bool {
+	if output == "*" {
+		return true
+	}
+	if contract == "" {
+		return contains(fileOutputs, output)
+	}
+	if contains(contractOutputs, output) {
+		return true
+	}
+	for _, prefix := range []string{"evm.bytecode.", "evm.deployedBytecode."} {
+		if strings.HasPrefix(output, prefix) && contains(bytecodeOutputs, strings.TrimPrefix(output, prefix)) {
+			return true
+		}
+	}
+	return false
+}
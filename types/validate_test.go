@@ -0,0 +1,49 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	in := NewInput().AddSource("One.sol", "contract One {}")
+	in.Settings.OutputSelection = in.Settings.OutputSelection.Select("*", "", "ast").Select("One.sol", "One", "evm.bytecode.object", "*")
+	in.Settings.Libraries = map[string]map[string]string{"Lib.sol": {"Lib": "0x00000000000000000000000000000000000000aa"}}
+	in.SetEVMVersion("istanbul").AddRemapping(Remapping{Prefix: "@oz/", Target: "lib/oz/"})
+	require.NoError(t, in.Validate(), "Valid input should not error")
+
+	invalid := &Input{
+		Language: "Vyper",
+		Sources:  map[string]SourceIn{"One.sol": {}},
+		Settings: Settings{
+			Remappings: []string{"=lib/"},
+			Optimizer:  Optimizer{Runs: -1},
+			EVMVersion: "frontier",
+			StopAfter:  "analysis",
+			Metadata:   &MetadataSettings{BytecodeHash: "sha3"},
+			OutputSelection: OutputSelection{"*": {
+				"*": {"abi", "evm.bytecode.objects"},
+				"":  {"abi"},
+			}},
+			Libraries: map[string]map[string]string{"Lib.sol": {"Lib": "0x1234"}},
+		},
+	}
+	err := invalid.Validate()
+	require.IsType(t, &InputError{}, err, "Invalid input should error")
+	assert.Equal(t, []string{
+		`language: unknown language "Vyper", expected one of Solidity, Yul, SolidityAST, EVMAssembly`,
+		`settings.evmVersion: unknown EVM version "frontier", expected one of homestead, tangerineWhistle, spuriousDragon, byzantium, constantinople, petersburg, istanbul, berlin, london, paris, shanghai, cancun, prague, osaka`,
+		`settings.libraries["Lib.sol"]["Lib"]: invalid address "1234": expected 40 hex characters`,
+		`settings.metadata.bytecodeHash: unknown hash "sha3", expected one of ipfs, bzzr1, none`,
+		`settings.optimizer.runs: negative runs -1`,
+		`settings.outputSelection["*"][""]: unknown output "abi"`,
+		`settings.outputSelection["*"]["*"]: unknown output "evm.bytecode.objects"`,
+		`settings.remappings[0]: invalid remapping "=lib/", expected [context:]prefix=target`,
+		`settings.stopAfter: unknown step "analysis", expected parsing`,
+		`sources["One.sol"]: no content`,
+	}, err.(*InputError).Problems, "Every problem should be reported")
+
+	assert.EqualError(t, (&Input{}).Validate(), "solc: invalid input: sources: no source", "Empty input should error")
+}
@@ -0,0 +1,353 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// semver is a major.minor.patch version
+type semver [3]int
+
+func (v semver) less(w semver) bool {
+	for i := range v {
+		if v[i] != w[i] {
+			return v[i] < w[i]
+		}
+	}
+	return false
+}
+
+func (v semver) String() string {
+	return fmt.Sprintf("%v.%v.%v", v[0], v[1], v[2])
+}
+
+// parseSemver parses a possibly partial version (e.g. 0.6, 0.6.x) also returning the number of parts set
+func parseSemver(s string) (v semver, parts int, err error) {
+	s = ShortVersion(strings.TrimPrefix(strings.TrimSpace(s), "v"))
+	if s == "" {
+		return v, 0, fmt.Errorf("solc: empty version")
+	}
+	for i, part := range strings.Split(s, ".") {
+		if i >= len(v) {
+			return v, 0, fmt.Errorf("solc: invalid version %q", s)
+		}
+		if part == "x" || part == "X" || part == "*" {
+			break
+		}
+		v[i], err = strconv.Atoi(part)
+		if err != nil || v[i] < 0 {
+			return v, 0, fmt.Errorf("solc: invalid version %q", s)
+		}
+		parts++
+	}
+	return v, parts, nil
+}
+
+// bump returns the smallest version above every version matching the first parts of v
+func (v semver) bump(parts int) semver {
+	switch parts {
+	case 0:
+		return semver{1 << 30}
+	case 1:
+		return semver{v[0] + 1}
+	case 2:
+		return semver{v[0], v[1] + 1}
+	}
+	return semver{v[0], v[1], v[2] + 1}
+}
+
+// versionRange is the set of versions in [low, high)
+type versionRange struct {
+	low, high         semver
+	lowIncl, highIncl bool
+	hasLow, hasHigh   bool
+}
+
+func (r versionRange) contains(v semver) bool {
+	if r.hasLow && (v.less(r.low) || (!r.lowIncl && v == r.low)) {
+		return false
+	}
+	if r.hasHigh && (r.high.less(v) || (!r.highIncl && v == r.high)) {
+		return false
+	}
+	return true
+}
+
+// intersect returns the versions in both r and s
+func (r versionRange) intersect(s versionRange) versionRange {
+	if s.hasLow && (!r.hasLow || r.low.less(s.low) || (r.low == s.low && !s.lowIncl)) {
+		r.low, r.lowIncl, r.hasLow = s.low, s.lowIncl, true
+	}
+	if s.hasHigh && (!r.hasHigh || s.high.less(r.high) || (r.high == s.high && !s.highIncl)) {
+		r.high, r.highIncl, r.hasHigh = s.high, s.highIncl, true
+	}
+	return r
+}
+
+func (r versionRange) empty() bool {
+	if !r.hasLow || !r.hasHigh {
+		return false
+	}
+	return r.high.less(r.low) || (r.low == r.high && !(r.lowIncl && r.highIncl))
+}
+
+// String formats the range as a constraint, a single version if it is the only one matching
+func (r versionRange) String() string {
+	if r.hasLow && r.hasHigh && r.lowIncl && !r.highIncl && r.high == r.low.bump(3) {
+		return r.low.String()
+	}
+	var comparators []string
+	if r.hasLow {
+		op := ">="
+		if !r.lowIncl {
+			op = ">"
+		}
+		comparators = append(comparators, op+r.low.String())
+	}
+	if r.hasHigh && r.high != (semver{}).bump(0) {
+		op := "<"
+		if r.highIncl {
+			op = "<="
+		}
+		comparators = append(comparators, op+r.high.String())
+	}
+	if len(comparators) == 0 {
+		return "*"
+	}
+	return strings.Join(comparators, " ")
+}
+
+// parseComparator parses a single comparator (e.g. ^0.6.1, >=0.5.0, 0.6.x) into a range
+func parseComparator(c string) (versionRange, error) {
+	op := ""
+	for _, prefix := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(c, prefix) {
+			op, c = prefix, c[len(prefix):]
+			break
+		}
+	}
+	v, parts, err := parseSemver(c)
+	if err != nil {
+		return versionRange{}, err
+	}
+
+	r := versionRange{}
+	switch op {
+	case "", "=":
+		r = versionRange{low: v, high: v.bump(parts), lowIncl: true, hasLow: true, hasHigh: true}
+	case ">=":
+		r = versionRange{low: v, lowIncl: true, hasLow: true}
+	case ">":
+		r = versionRange{low: v.bump(parts), lowIncl: true, hasLow: true}
+	case "<":
+		r = versionRange{high: v, hasHigh: true}
+	case "<=":
+		r = versionRange{high: v.bump(parts), hasHigh: true}
+	case "~":
+		// Allow patch changes, or minor ones if only the major is set
+		bumped := parts
+		if bumped > 2 {
+			bumped = 2
+		}
+		r = versionRange{low: v, high: v.bump(bumped), lowIncl: true, hasLow: true, hasHigh: true}
+	case "^":
+		// Bump the first non-zero part
+		switch {
+		case v[0] > 0 || parts == 1:
+			r.high = v.bump(1)
+		case v[1] > 0 || parts == 2:
+			r.high = v.bump(2)
+		default:
+			r.high = v.bump(3)
+		}
+		r.low, r.lowIncl, r.hasLow, r.hasHigh = v, true, true, true
+	}
+	return r, nil
+}
+
+// CompareVersions returns -1, 0 or 1 as version v is older than, the same as or newer than w, ignoring
+// their build metadata
+func CompareVersions(v, w string) (int, error) {
+	a, _, err := parseSemver(v)
+	if err != nil {
+		return 0, err
+	}
+	b, _, err := parseSemver(w)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case a.less(b):
+		return -1, nil
+	case b.less(a):
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// MatchVersion indicates whether version satisfies a solidity pragma constraint
+// (e.g. "^0.6.1", ">=0.5.0 <0.7.0", "0.5.0 - 0.6.2", "^0.5.0 || ^0.6.0")
+func MatchVersion(constraint, version string) (bool, error) {
+	v, parts, err := parseSemver(version)
+	if err != nil {
+		return false, err
+	}
+	if parts != 3 {
+		return false, fmt.Errorf("solc: incomplete version %q", version)
+	}
+
+	for _, alternative := range strings.Split(constraint, "||") {
+		ranges, err := parseComparators(alternative)
+		if err != nil {
+			return false, err
+		}
+		matched := true
+		for _, r := range ranges {
+			matched = matched && r.contains(v)
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// MatchVersions indicates whether version satisfies every constraint
+func MatchVersions(constraints []string, version string) (bool, error) {
+	for _, constraint := range constraints {
+		ok, err := MatchVersion(constraint, version)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func parseComparators(s string) ([]versionRange, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("solc: empty version constraint")
+	}
+
+	var ranges []versionRange
+	for i := 0; i < len(fields); i++ {
+		// Hyphen range, a - b is >=a <=b
+		if i+2 < len(fields) && fields[i+1] == "-" {
+			low, err := parseComparator(">=" + fields[i])
+			if err != nil {
+				return nil, err
+			}
+			high, err := parseComparator("<=" + fields[i+2])
+			if err != nil {
+				return nil, err
+			}
+			ranges = append(ranges, low, high)
+			i += 2
+			continue
+		}
+
+		// Operators may be separated from their version (e.g. ">= 0.5.0")
+		c := fields[i]
+		if strings.Trim(c, "<>=^~") == "" && i+1 < len(fields) {
+			c += fields[i+1]
+			i++
+		}
+		r, err := parseComparator(c)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// parseConstraint parses a constraint into the union of the ranges of its alternatives, empty ones dropped
+func parseConstraint(constraint string) ([]versionRange, error) {
+	var union []versionRange
+	for _, alternative := range strings.Split(constraint, "||") {
+		ranges, err := parseComparators(alternative)
+		if err != nil {
+			return nil, err
+		}
+		r := versionRange{}
+		for _, comparator := range ranges {
+			r = r.intersect(comparator)
+		}
+		if !r.empty() {
+			union = append(union, r)
+		}
+	}
+	return union, nil
+}
+
+// VersionConflictError is returned when no version satisfies every constraint
+type VersionConflictError struct {
+	Constraints []string
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("solc: no version satisfies every constraint of %q", strings.Join(e.Constraints, `", "`))
+}
+
+// IntersectVersions returns a constraint matching the versions satisfying every constraint, e.g.
+// ">=0.6.2 <0.7.0" for "^0.6.0" and ">=0.6.2 <0.8.0", or a *VersionConflictError if there is none
+//
+// Alternatives are kept (e.g. "^0.5.0 || ^0.6.0" and ">=0.5.5" gives ">=0.5.5 <0.6.0 || >=0.6.0 <0.7.0"),
+// no constraint gives "*"
+func IntersectVersions(constraints ...string) (string, error) {
+	union := []versionRange{{}}
+	for _, constraint := range constraints {
+		alternatives, err := parseConstraint(constraint)
+		if err != nil {
+			return "", err
+		}
+		var next []versionRange
+		for _, r := range union {
+			for _, alternative := range alternatives {
+				if i := r.intersect(alternative); !i.empty() {
+					next = append(next, i)
+				}
+			}
+		}
+		union = next
+	}
+	if len(union) == 0 {
+		return "", &VersionConflictError{Constraints: constraints}
+	}
+
+	sort.SliceStable(union, func(i, j int) bool { return union[i].low.less(union[j].low) })
+	var alternatives []string
+	for _, r := range union {
+		if s := r.String(); !contains(alternatives, s) {
+			alternatives = append(alternatives, s)
+		}
+	}
+	return strings.Join(alternatives, " || "), nil
+}
+
+// ResolveVersion returns the highest of versions satisfying constraint (see MatchVersion)
+func ResolveVersion(constraint string, versions []string) (string, error) {
+	var (
+		best    string
+		bestVer semver
+	)
+	for _, version := range versions {
+		ok, err := MatchVersion(constraint, version)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			continue
+		}
+		v, _, _ := parseSemver(version)
+		if best == "" || bestVer.less(v) {
+			best, bestVer = version, v
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("solc: no version satisfies %q among %v", constraint, versions)
+	}
+	return best, nil
+}
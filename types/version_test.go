@@ -0,0 +1,84 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchVersion(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		match      bool
+	}{
+		{"^0.6.1", "0.6.2", true},
+		{"^0.6.1", "0.6.0", false},
+		{"^0.6.1", "0.7.0", false},
+		{"^0.0.3", "0.0.4", false},
+		{"^1.2.0", "1.9.0", true},
+		{"~0.6.1", "0.6.9", true},
+		{"~0.6.1", "0.7.0", false},
+		{"~0", "0.9.0", true},
+		{"0.6.2", "0.6.2+commit.bacdbe57", true},
+		{"=0.6.2", "0.6.3", false},
+		{"0.6", "0.6.12", true},
+		{"0.6.x", "0.7.0", false},
+		{">=0.5.0 <0.7.0", "0.6.2", true},
+		{">=0.5.0 <0.7.0", "0.7.0", false},
+		{">= 0.5.0", "0.5.0", true},
+		{">0.5", "0.5.9", false},
+		{">0.5", "0.6.0", true},
+		{"<=0.5", "0.5.9", true},
+		{"0.5.0 - 0.6.2", "0.6.2", true},
+		{"0.5.0 - 0.6.2", "0.6.3", false},
+		{"^0.4.24 || ^0.6.0", "0.6.2", true},
+		{"^0.4.24 || ^0.6.0", "0.5.9", false},
+	}
+	for _, test := range tests {
+		match, err := MatchVersion(test.constraint, test.version)
+		require.NoError(t, err, "MatchVersion(%q, %q) should not error", test.constraint, test.version)
+		assert.Equal(t, test.match, match, "MatchVersion(%q, %q)", test.constraint, test.version)
+	}
+
+	_, err := MatchVersion("^0.6.a", "0.6.2")
+	assert.Error(t, err, "Invalid constraint should error")
+}
+
+func TestResolveVersion(t *testing.T) {
+	versions := []string{"0.5.9+commit.e560f70d", "0.6.2+commit.bacdbe57", "0.6.1+commit.e6f7d5a4"}
+
+	version, err := ResolveVersion("^0.6.0", versions)
+	require.NoError(t, err, "ResolveVersion should not error")
+	assert.Equal(t, "0.6.2+commit.bacdbe57", version, "Highest matching version should be resolved")
+
+	_, err = ResolveVersion("^0.7.0", versions)
+	assert.Error(t, err, "ResolveVersion should error when no version matches")
+}
+
+func TestIntersectVersions(t *testing.T) {
+	tests := []struct {
+		constraints []string
+		version     string
+	}{
+		{nil, "*"},
+		{[]string{"^0.6.0", ">=0.6.2 <0.8.0"}, ">=0.6.2 <0.7.0"},
+		{[]string{"^0.6.0", "0.6.2"}, "0.6.2"},
+		{[]string{"^0.5.0 || ^0.6.0", ">=0.5.5"}, ">=0.5.5 <0.6.0 || >=0.6.0 <0.7.0"},
+		{[]string{">=0.5.0", ">0.5"}, ">=0.6.0"},
+		{[]string{"<0.7.0", "<=0.6"}, "<0.7.0"},
+	}
+	for _, test := range tests {
+		version, err := IntersectVersions(test.constraints...)
+		require.NoError(t, err, "IntersectVersions(%q) should not error", test.constraints)
+		assert.Equal(t, test.version, version, "IntersectVersions(%q)", test.constraints)
+		_, err = MatchVersion(version, "0.6.2")
+		assert.NoError(t, err, "Intersection %q should be a valid constraint", version)
+	}
+
+	_, err := IntersectVersions("^0.5.0", "^0.6.0")
+	assert.IsType(t, &VersionConflictError{}, err, "Disjoint constraints should conflict")
+	_, err = IntersectVersions("^0.6.a")
+	assert.Error(t, err, "Invalid constraint should error")
+}
@@ -0,0 +1,131 @@
+package solc
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// CompilationUnits partitions the Solidity sources of in into units of sources importing each other,
+// directly or through shared dependencies, which compile independently of one another
+//
+// Imports are resolved with the remappings of in, imports of sources missing from in do not join units.
+// Units are sorted by their first source, sources by name
+func CompilationUnits(in *Input) []CompilationUnit {
+	g := importGraph(in)
+	parent := make(map[string]string)
+	for _, name := range g.Nodes {
+		parent[name] = name
+	}
+
+	var root func(name string) string
+	root = func(name string) string {
+		if parent[name] != name {
+			parent[name] = root(parent[name])
+		}
+		return parent[name]
+	}
+	for _, name := range g.Nodes {
+		for _, dep := range g.Edges[name] {
+			if _, ok := in.Sources[dep]; ok {
+				parent[root(dep)] = root(name)
+			}
+		}
+	}
+
+	var units []CompilationUnit
+	index := make(map[string]int)
+	for _, name := range g.Nodes {
+		r := root(name)
+		i, ok := index[r]
+		if !ok {
+			i = len(units)
+			index[r] = i
+			units = append(units, CompilationUnit{Language: "Solidity"})
+		}
+		units[i].Sources = append(units[i].Sources, name)
+	}
+	return units
+}
+
+// importGraph returns the import graph of the sources of in, imports resolved with its remappings
+//
+// Unlike DependencyGraph, imports of sources missing from in are edges but not nodes
+func importGraph(in *Input) *ImportGraph {
+	var remappings []Remapping
+	for _, s := range in.Settings.Remappings {
+		if r, err := ParseRemapping(s); err == nil {
+			remappings = append(remappings, r)
+		}
+	}
+
+	g := &ImportGraph{Edges: make(map[string][]string)}
+	for name := range in.Sources {
+		g.Nodes = append(g.Nodes, name)
+	}
+	sort.Strings(g.Nodes)
+	for _, name := range g.Nodes {
+		for _, imp := range ParseImports(in.Sources[name].Content) {
+			dep := remap(remappings, name, ImportPath(name, imp.Path))
+			if !contains(g.Edges[name], dep) {
+				g.Edges[name] = append(g.Edges[name], dep)
+			}
+		}
+		sort.Strings(g.Edges[name])
+	}
+	return g
+}
+
+// remap applies to path the remapping with the longest context matching from, then the longest prefix
+func remap(remappings []Remapping, from, path string) string {
+	var best *Remapping
+	for i, r := range remappings {
+		if !strings.HasPrefix(from, r.Context) || !strings.HasPrefix(path, r.Prefix) {
+			continue
+		}
+		if best == nil || len(r.Context) > len(best.Context) || (len(r.Context) == len(best.Context) && len(r.Prefix) > len(best.Prefix)) {
+			best = &remappings[i]
+		}
+	}
+	if best == nil {
+		return path
+	}
+	return best.Target + strings.TrimPrefix(path, best.Prefix)
+}
+
+// CompileUnits compiles the compilation units of in (see CompilationUnits) in parallel across the instances
+// of the pool and merges their outputs, errors in the order of Units which list the units
+//
+// Source IDs and source maps are relative to the unit of their source. Inputs of a single unit, or of
+// another language than Solidity, are compiled as a whole
+func (p *Pool) CompileUnits(ctx context.Context, in *Input) (*Output, error) {
+	var units []CompilationUnit
+	if in.Language == "Solidity" {
+		units = CompilationUnits(in)
+	}
+	if len(units) <= 1 {
+		return p.CompileContext(ctx, in)
+	}
+
+	inputs := make([]*Input, len(units))
+	for i, unit := range units {
+		inputs[i] = &Input{Language: in.Language, Sources: make(map[string]SourceIn), Settings: in.Settings}
+		for _, name := range unit.Sources {
+			inputs[i].Sources[name] = in.Sources[name]
+		}
+	}
+	outputs, err := p.CompileAll(ctx, inputs, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &Output{
+		Sources:   make(map[string]SourceOut),
+		Contracts: make(map[string]map[string]Contract),
+		Units:     units,
+	}
+	for _, unitOut := range outputs {
+		mergeUnit(out, unitOut)
+	}
+	return out, nil
+}
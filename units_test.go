@@ -0,0 +1,64 @@
+package solc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompilationUnits(t *testing.T) {
+	in := NewInput().
+		AddSource("src/A.sol", "import \"./B.sol\"; contract A {}").
+		AddSource("src/B.sol", "import \"@lib/L.sol\"; contract B {}").
+		AddSource("lib/L.sol", "contract L {}").
+		AddSource("src/C.sol", "import \"Missing.sol\"; contract C {}").
+		AddSource("src/D.sol", "import \"Missing.sol\"; contract D {}").
+		AddSource("other/E.sol", "import \"@lib/F.sol\"; contract E {}").
+		AddSource("other/F.sol", "contract F {}").
+		AddRemapping(Remapping{Prefix: "@lib/", Target: "lib/"}).
+		AddRemapping(Remapping{Context: "other", Prefix: "@lib/", Target: "other/"})
+
+	assert.Equal(t, []CompilationUnit{
+		{Language: "Solidity", Sources: []string{"lib/L.sol", "src/A.sol", "src/B.sol"}},
+		{Language: "Solidity", Sources: []string{"other/E.sol", "other/F.sol"}},
+		{Language: "Solidity", Sources: []string{"src/C.sol"}},
+		{Language: "Solidity", Sources: []string{"src/D.sol"}},
+	}, CompilationUnits(in), "Sources should be grouped by import graph components")
+}
+
+func TestPoolCompileUnits(t *testing.T) {
+	pool, err := NewPool(FileFactory("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js"), 2)
+	require.NoError(t, err, "NewPool should not error")
+	defer pool.Close()
+
+	in := NewInput().
+		AddSource("A.sol", "pragma solidity ^0.6.0;\nimport \"./B.sol\";\ncontract A is B {}").
+		AddSource("B.sol", "pragma solidity ^0.6.0;\ncontract B {}").
+		AddSource("C.sol", "pragma solidity ^0.6.0;\ncontract C {}").
+		SetOutputSelection(SelectAll("evm.bytecode.object"))
+	out, err := pool.CompileUnits(context.Background(), in)
+	require.NoError(t, err, "CompileUnits should not error")
+	assert.Empty(t, out.Errors, "Units should compile")
+	assert.Equal(t, []CompilationUnit{
+		{Language: "Solidity", Sources: []string{"A.sol", "B.sol"}},
+		{Language: "Solidity", Sources: []string{"C.sol"}},
+	}, out.Units)
+	assert.Equal(t, []string{"C.sol"}, out.Unit("C.sol").Sources, "Unit should be found")
+
+	whole, err := pool.CompileContext(context.Background(), in)
+	require.NoError(t, err, "CompileContext should not error")
+	// Source maps differ as source IDs are relative to units
+	for file, contracts := range whole.Contracts {
+		for name, contract := range contracts {
+			assert.Equal(t, contract.EVM.Bytecode.Object, out.Contracts[file][name].EVM.Bytecode.Object, "%v should compile as in a single unit", name)
+		}
+	}
+	assert.Len(t, out.Contracts, 3)
+
+	single := NewInput().AddSource("C.sol", "pragma solidity ^0.6.0;\ncontract C {}")
+	out, err = pool.CompileUnits(context.Background(), single)
+	require.NoError(t, err, "CompileUnits should not error")
+	assert.Nil(t, out.Units, "Single unit should be compiled as a whole")
+}
@@ -0,0 +1,330 @@
+package solc
+
+import (
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/nmvalera/solc-go/ast"
+	"github.com/nmvalera/solc-go/types"
+)
+
+// ProxyKind is an upgradeable proxy pattern
+type ProxyKind string
+
+const (
+	// ProxyUUPS proxies are upgraded by their implementation (ERC-1822)
+	ProxyUUPS ProxyKind = "uups"
+
+	// ProxyTransparent proxies are upgraded by their admin, calls of other accounts are always delegated
+	ProxyTransparent ProxyKind = "transparent"
+
+	// ProxyBeacon proxies get their implementation from a beacon shared by many proxies
+	ProxyBeacon ProxyKind = "beacon"
+)
+
+// Kinds of UpgradeIssue
+const (
+	// IssueConstructorWrite is a state variable written by a constructor, which runs on the implementation
+	// and not on the proxy storage
+	IssueConstructorWrite = "constructor-write"
+
+	// IssueInitialValue is a state variable initialized at declaration, which is a constructor write
+	IssueInitialValue = "initial-value"
+
+	// IssueNoInitializer is a contract with state variables and no initializer function
+	IssueNoInitializer = "no-initializer"
+
+	// IssueStorageGap is a storage gap not following the convention, a uint256[N] __gap variable
+	// declared last by its contract
+	IssueStorageGap = "storage-gap"
+
+	// IssueLayout is a storage layout change unsafe for an upgrade (see CompareStorageLayouts)
+	IssueLayout = "layout"
+
+	// IssueUpgradeFunction is an upgrade function missing from an UUPS implementation, or declared by a
+	// beacon implementation
+	IssueUpgradeFunction = "upgrade-function"
+
+	// IssueSelectorClash is a function of a transparent proxy implementation clashing with the admin
+	// functions of the proxy, calls of the admin would never reach it
+	IssueSelectorClash = "selector-clash"
+)
+
+// UpgradeIssue is a problem of an implementation contract reported by CheckUpgrade
+type UpgradeIssue struct {
+	Kind    string
+	Message string
+
+	// SourceLocation is the declaration at fault, zero for layout and ABI issues
+	SourceLocation SourceLocation
+
+	// Change is the layout change of IssueLayout issues
+	Change *LayoutChange
+}
+
+func (i UpgradeIssue) String() string {
+	return i.Message
+}
+
+var (
+	gapRe = regexp.MustCompile(`^uint256\[\d+\]$`)
+
+	// uupsFunctions are the upgrade functions of UUPS implementations, one of them must be exposed
+	uupsFunctions = []string{"upgradeTo(address)", "upgradeToAndCall(address,bytes)"}
+
+	// transparentFunctions are the admin functions of transparent proxies
+	transparentFunctions = []string{"admin()", "implementation()", "changeAdmin(address)", "upgradeTo(address)", "upgradeToAndCall(address,bytes)"}
+)
+
+// CheckUpgrade validates implementation contract name (see FindContract) of out for a proxy of kind
+//
+// Constructors must not write state variables, an initializer must be present if the contract has state,
+// storage gaps must follow the uint256[N] __gap convention and the functions must fit the proxy pattern.
+// If previous is set, its storage layout must be compatible with the one of the implementation, shrinking
+// a storage gap to make room for new variables is allowed.
+//
+// It requires the ast and abi outputs, and storageLayout of both contracts when previous is set
+func CheckUpgrade(out *Output, name string, kind ProxyKind, previous *Contract) ([]UpgradeIssue, error) {
+	switch kind {
+	case ProxyUUPS, ProxyTransparent, ProxyBeacon:
+	default:
+		return nil, fmt.Errorf("solc: unknown proxy kind %q", kind)
+	}
+
+	file, contract, err := out.FindContract(name)
+	if err != nil {
+		return nil, err
+	}
+	var units []*ast.Unit
+	for source, s := range out.Sources {
+		if len(s.AST) == 0 {
+			return nil, fmt.Errorf("solc: checking upgrades requires the ast output of %q", source)
+		}
+		unit, err := ast.Parse(s.AST)
+		if err != nil {
+			return nil, fmt.Errorf("solc: could not parse AST of %q: %w", source, err)
+		}
+		units = append(units, unit)
+	}
+	id, _ := ParseContractID(name)
+	var node *ast.Contract
+	for _, unit := range units {
+		if unit.AbsolutePath == file {
+			node = unit.Contract(id.Name)
+		}
+	}
+	if node == nil {
+		return nil, fmt.Errorf("solc: no AST for contract %v", name)
+	}
+
+	issues := stateIssues(ast.NewInheritanceGraph(units...).Linearized(node))
+
+	if previous != nil {
+		if previous.StorageLayout == nil || contract.StorageLayout == nil {
+			return nil, fmt.Errorf("solc: checking layout compatibility requires the storageLayout output")
+		}
+		for _, change := range CompareStorageLayouts(previous, contract) {
+			if change.Label == "__gap" && change.New != nil && gapEnd(previous, change.Old) == gapEnd(contract, change.New) && gapEnd(contract, change.New) >= 0 {
+				continue
+			}
+			change := change
+			issues = append(issues, UpgradeIssue{Kind: IssueLayout, Message: change.String(), Change: &change})
+		}
+	}
+
+	abiIssues, err := proxyIssues(kind, contract)
+	if err != nil {
+		return nil, err
+	}
+	return append(issues, abiIssues...), nil
+}
+
+// stateIssues checks the initialization of the state variables of a contract and its bases (linearized)
+func stateIssues(linearized []*ast.Contract) []UpgradeIssue {
+	var issues []UpgradeIssue
+	issuef := func(kind string, n *ast.Node, format string, v ...interface{}) {
+		unit := &ast.Unit{Node: n.Ancestor("SourceUnit")}
+		if unit.Node != nil {
+			unit.AbsolutePath = unit.StringAttr("absolutePath")
+		}
+		issues = append(issues, UpgradeIssue{Kind: kind, Message: fmt.Sprintf(format, v...), SourceLocation: NodeLocation(unit, n)})
+	}
+
+	// Immutable and constant variables live in the bytecode
+	vars := make(map[int]*ast.StateVariable)
+	for _, c := range linearized {
+		var mutable []*ast.StateVariable
+		for _, v := range c.StateVariables() {
+			if !v.Constant && v.StringAttr("mutability") != "immutable" {
+				mutable = append(mutable, v)
+				vars[v.ID] = v
+			}
+		}
+
+		for i, v := range mutable {
+			if v.Child("value") != nil {
+				issuef(IssueInitialValue, v.Node, "state variable %v.%v is initialized at declaration, initialize it in an initializer", c.Name, v.Name)
+			}
+			if v.Name != "__gap" {
+				continue
+			}
+			if !gapRe.MatchString(v.Type) {
+				issuef(IssueStorageGap, v.Node, "storage gap %v.__gap is %v, expected uint256[N]", c.Name, v.Type)
+			}
+			if i != len(mutable)-1 {
+				issuef(IssueStorageGap, v.Node, "storage gap %v.__gap is not the last state variable of %v", c.Name, c.Name)
+			}
+		}
+	}
+
+	initializer := false
+	for _, c := range linearized {
+		for _, f := range c.Functions() {
+			if f.Kind == "constructor" || f.BoolAttr("isConstructor") {
+				for _, w := range writtenVariables(f.Node) {
+					if v := vars[w.ref]; v != nil {
+						issuef(IssueConstructorWrite, w.node, "constructor of %v writes state variable %v", c.Name, v.Name)
+					}
+				}
+			} else if isInitializer(f) {
+				initializer = true
+			}
+		}
+	}
+	if !initializer && len(vars) > 0 {
+		issuef(IssueNoInitializer, linearized[0].Node, "%v has state variables and no initializer", linearized[0].Name)
+	}
+
+	return issues
+}
+
+type variableWrite struct {
+	node *ast.Node
+	ref  int
+}
+
+// writtenVariables returns the variables assigned, incremented, decremented or deleted in n
+func writtenVariables(n *ast.Node) []variableWrite {
+	var writes []variableWrite
+	var target func(expr *ast.Node)
+	target = func(expr *ast.Node) {
+		for expr != nil {
+			switch expr.NodeType {
+			case "IndexAccess", "IndexRangeAccess":
+				expr = expr.Child("baseExpression")
+			case "MemberAccess":
+				expr = expr.Child("expression")
+			case "TupleExpression":
+				for _, component := range expr.ChildList("components") {
+					target(component)
+				}
+				return
+			case "Identifier":
+				var ref int
+				if expr.Attr("referencedDeclaration", &ref) == nil {
+					writes = append(writes, variableWrite{node: expr, ref: ref})
+				}
+				return
+			default:
+				return
+			}
+		}
+	}
+
+	n.Walk(func(node *ast.Node) bool {
+		switch node.NodeType {
+		case "Assignment":
+			target(node.Child("leftHandSide"))
+		case "UnaryOperation":
+			switch node.StringAttr("operator") {
+			case "++", "--", "delete":
+				target(node.Child("subExpression"))
+			}
+		}
+		return true
+	})
+	return writes
+}
+
+// isInitializer indicates whether function f is an initialize function or uses an initializer modifier,
+// kind is not set by old compilers
+func isInitializer(f *ast.Function) bool {
+	if (f.Kind != "function" && f.Kind != "") || (f.Visibility != "public" && f.Visibility != "external") {
+		return false
+	}
+	if f.Name == "initialize" {
+		return true
+	}
+	for _, m := range f.ChildList("modifiers") {
+		if name := m.Child("modifierName").Name(); name == "initializer" || name == "reinitializer" {
+			return true
+		}
+	}
+	return false
+}
+
+// gapEnd returns the slot following a storage gap, -1 if unknown
+func gapEnd(c *Contract, gap *StorageSlot) int64 {
+	slot, err := strconv.ParseInt(gap.Slot, 10, 64)
+	if err != nil || c.StorageLayout == nil || c.StorageLayout.Types[gap.Type] == nil {
+		return -1
+	}
+	size, err := strconv.ParseInt(c.StorageLayout.Types[gap.Type].NumberOfBytes, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return slot + (size+31)/32
+}
+
+// proxyIssues checks the functions of an implementation against the proxy pattern
+func proxyIssues(kind ProxyKind, contract *Contract) ([]UpgradeIssue, error) {
+	entries, err := ParseABI(contract.ABI)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 && len(contract.ABI) == 0 {
+		return nil, fmt.Errorf("solc: checking upgrades requires the abi output")
+	}
+	functions := make(map[string]string)
+	for _, e := range entries {
+		if e.Type == "function" {
+			functions[e.Selector()] = e.Signature()
+		}
+	}
+	has := func(signature string) bool {
+		_, ok := functions[functionSelector(signature)]
+		return ok
+	}
+
+	var issues []UpgradeIssue
+	switch kind {
+	case ProxyUUPS:
+		if !has(uupsFunctions[0]) && !has(uupsFunctions[1]) {
+			issues = append(issues, UpgradeIssue{Kind: IssueUpgradeFunction, Message: "UUPS implementation has no upgradeTo or upgradeToAndCall function, the proxy could not be upgraded"})
+		}
+		if !has("proxiableUUID()") {
+			issues = append(issues, UpgradeIssue{Kind: IssueUpgradeFunction, Message: "UUPS implementation has no proxiableUUID function (ERC-1822)"})
+		}
+	case ProxyTransparent:
+		for _, signature := range transparentFunctions {
+			if clash, ok := functions[functionSelector(signature)]; ok {
+				issues = append(issues, UpgradeIssue{Kind: IssueSelectorClash, Message: fmt.Sprintf("function %v clashes with the %v admin function of transparent proxies", clash, signature)})
+			}
+		}
+	case ProxyBeacon:
+		for _, signature := range uupsFunctions {
+			if has(signature) {
+				issues = append(issues, UpgradeIssue{Kind: IssueUpgradeFunction, Message: fmt.Sprintf("beacon implementation declares %v, beacon proxies are upgraded through their beacon", signature)})
+			}
+		}
+	}
+	return issues, nil
+}
+
+// functionSelector returns the 0x prefixed selector of a canonical function signature
+func functionSelector(signature string) string {
+	hash := types.Keccak256([]byte(signature))
+	return "0x" + hex.EncodeToString(hash[:4])
+}
@@ -0,0 +1,130 @@
+package solc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const upgradeBase = `pragma solidity ^0.6.2;
+contract Initializable {
+    bool private initialized;
+    modifier initializer() { require(!initialized); initialized = true; _; }
+}
+`
+
+const implementationV1 = upgradeBase + `
+contract Base is Initializable {
+    uint256 public value;
+    uint256[49] private __gap;
+}
+contract Impl is Base {
+    address public owner;
+    function initialize() public initializer { owner = msg.sender; }
+    function upgradeTo(address impl) external {}
+    function proxiableUUID() external pure returns (bytes32) { return 0; }
+}
+`
+
+// Base shrinks its gap for a new variable, Impl writes its state from its constructor
+const implementationV2 = upgradeBase + `
+contract Base is Initializable {
+    uint256 public value;
+    uint256 public extra;
+    uint256[48] private __gap;
+}
+contract Impl is Base {
+    address public owner;
+    uint256 public count = 1;
+    mapping(address => uint256) balances;
+    constructor() public { owner = msg.sender; balances[msg.sender]++; }
+    function upgradeTo(address impl) external {}
+}
+`
+
+// Base inserts a variable without shrinking its gap and declares a misplaced one
+const implementationV3 = upgradeBase + `
+contract Base is Initializable {
+    uint256 public value;
+    uint256 public extra;
+    uint256[49] private __gap;
+    bool public flag;
+}
+contract Impl is Base {
+    address public owner;
+    function initialize() public initializer { owner = msg.sender; }
+    function admin() external view returns (address) { return owner; }
+}
+`
+
+func TestCheckUpgrade(t *testing.T) {
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js")
+	require.NoError(t, err, "Creating compiler should not error")
+	defer solc.Close()
+
+	compile := func(source string) *Output {
+		in := NewInput().AddSource("Impl.sol", source)
+		in.Settings.OutputSelection.Select("*", "", "ast")
+		in.Settings.OutputSelection.Select("*", "*", "abi", "storageLayout")
+		out, err := solc.Compile(in)
+		require.NoError(t, err, "Compile should not error")
+		require.Empty(t, out.Errors, "Compile should not report errors")
+		return out
+	}
+	kinds := func(issues []UpgradeIssue) []string {
+		var kinds []string
+		for _, issue := range issues {
+			kinds = append(kinds, issue.Kind+": "+issue.Message)
+		}
+		return kinds
+	}
+
+	v1 := compile(implementationV1)
+	issues, err := CheckUpgrade(v1, "Impl", ProxyUUPS, nil)
+	require.NoError(t, err, "CheckUpgrade should not error")
+	assert.Empty(t, issues, "Valid implementation should not have issues")
+
+	previous, _ := v1.Contract("Impl.sol", "Impl")
+	v2 := compile(implementationV2)
+	issues, err = CheckUpgrade(v2, "Impl.sol:Impl", ProxyUUPS, previous)
+	require.NoError(t, err, "CheckUpgrade should not error")
+	assert.Equal(
+		t,
+		[]string{
+			"initial-value: state variable Impl.count is initialized at declaration, initialize it in an initializer",
+			"constructor-write: constructor of Impl writes state variable owner",
+			"constructor-write: constructor of Impl writes state variable balances",
+			"no-initializer: Impl has state variables and no initializer",
+			"upgrade-function: UUPS implementation has no proxiableUUID function (ERC-1822)",
+		},
+		kinds(issues),
+		"Shrinking the gap should be allowed",
+	)
+	assert.Equal(t, "owner", implementationV2[issues[1].SourceLocation.Start:issues[1].SourceLocation.End], "Location should point at the write")
+
+	v3 := compile(implementationV3)
+	issues, err = CheckUpgrade(v3, "Impl", ProxyTransparent, previous)
+	require.NoError(t, err, "CheckUpgrade should not error")
+	assert.Equal(
+		t,
+		[]string{
+			"storage-gap: storage gap Base.__gap is not the last state variable of Base",
+			"layout: storage variable Impl.sol:Impl.__gap moved from slot 2 offset 0 to slot 3 offset 0",
+			"layout: storage variable Impl.sol:Impl.owner moved from slot 51 offset 0 to slot 52 offset 1",
+			"selector-clash: function admin() clashes with the admin() admin function of transparent proxies",
+		},
+		kinds(issues),
+		"Layout changes, misplaced gaps and clashes should be reported",
+	)
+	require.NotNil(t, issues[1].Change, "Layout issues should have their change")
+
+	issues, err = CheckUpgrade(v1, "Impl", ProxyBeacon, nil)
+	require.NoError(t, err, "CheckUpgrade should not error")
+	assert.Equal(t, []string{"upgrade-function: beacon implementation declares upgradeTo(address), beacon proxies are upgraded through their beacon"}, kinds(issues))
+
+	_, err = CheckUpgrade(v1, "Impl", "diamond", nil)
+	assert.Error(t, err, "Unknown proxy kind should error")
+	_, err = CheckUpgrade(v1, "Impl", ProxyUUPS, &Contract{})
+	assert.Error(t, err, "Missing storage layout should error")
+}
@@ -0,0 +1,21 @@
+package solc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithValidation(t *testing.T) {
+	solc, err := NewFromFile("./solc-bin/soljson-v0.6.2+commit.bacdbe57.js", WithValidation())
+	require.NoError(t, err, "Creating Solc from valid solc emscripten binary should not error")
+	defer solc.Close()
+
+	_, err = solc.Compile(&Input{Language: "Solidity"})
+	require.IsType(t, &InputError{}, err, "Compile should error on invalid input")
+
+	out, err := solc.Compile(NewInput().AddSource("One.sol", "pragma solidity ^0.6.1; contract One {}"))
+	require.NoError(t, err, "Compile should not error on valid input")
+	assert.Empty(t, out.Errors, "Valid input should compile")
+}
@@ -0,0 +1,150 @@
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	solc "github.com/nmvalera/solc-go"
+)
+
+// DefaultEtherscanURL is the URL of the Etherscan (multichain) API
+const DefaultEtherscanURL = "https://api.etherscan.io/v2/api"
+
+// DefaultPollInterval is the default interval between Etherscan verification status checks
+const DefaultPollInterval = 5 * time.Second
+
+// Etherscan verifies contracts on Etherscan, submitting the standard JSON input of requests
+type Etherscan struct {
+	// URL defaults to DefaultEtherscanURL
+	URL    string
+	APIKey string
+
+	// PollInterval defaults to DefaultPollInterval
+	PollInterval time.Duration
+
+	// Client defaults to http.DefaultClient
+	Client *http.Client
+}
+
+// etherscanResponse is the envelope of Etherscan API responses
+type etherscanResponse struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// text returns the result of responses with a string result, such as error messages
+func (r *etherscanResponse) text() string {
+	var text string
+	if json.Unmarshal(r.Result, &text) != nil {
+		return string(r.Result)
+	}
+	return text
+}
+
+// Verify submits req (verifysourcecode) and waits for Etherscan to process it (checkverifystatus)
+func (e *Etherscan) Verify(ctx context.Context, req *Request) (*Result, error) {
+	input, err := json.Marshal(req.Input)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"apikey":                {e.APIKey},
+		"module":                {"contract"},
+		"action":                {"verifysourcecode"},
+		"codeformat":            {"solidity-standard-json-input"},
+		"sourceCode":            {string(input)},
+		"contractaddress":       {req.Address.Hex()},
+		"contractname":          {req.Contract},
+		"compilerversion":       {"v" + solc.LongVersion(req.Version)},
+		"constructorArguements": {strings.TrimPrefix(req.ConstructorArguments, "0x")},
+	}
+	res, err := e.do(ctx, http.MethodPost, req.ChainID, form)
+	if err != nil {
+		return nil, err
+	}
+	if res.Status != "1" {
+		if strings.Contains(strings.ToLower(res.text()), "already verified") {
+			return &Result{Status: StatusVerified, Message: res.text()}, nil
+		}
+		return nil, &Error{Target: "etherscan", Message: res.text()}
+	}
+	guid := res.text()
+
+	interval := e.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	for {
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		res, err = e.do(ctx, http.MethodGet, req.ChainID, url.Values{
+			"apikey": {e.APIKey},
+			"module": {"contract"},
+			"action": {"checkverifystatus"},
+			"guid":   {guid},
+		})
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case strings.Contains(strings.ToLower(res.text()), "pending"):
+			continue
+		case res.Status == "1" || strings.Contains(strings.ToLower(res.text()), "already verified"):
+			return &Result{Status: StatusVerified, Message: res.text()}, nil
+		default:
+			return nil, &Error{Target: "etherscan", Message: res.text()}
+		}
+	}
+}
+
+func (e *Etherscan) do(ctx context.Context, method string, chainID uint64, params url.Values) (*etherscanResponse, error) {
+	u := e.URL
+	if u == "" {
+		u = DefaultEtherscanURL
+	}
+	u += "?chainid=" + strconv.FormatUint(chainID, 10)
+
+	var (
+		httpReq *http.Request
+		err     error
+	)
+	if method == http.MethodPost {
+		httpReq, err = http.NewRequest(method, u, strings.NewReader(params.Encode()))
+		if err == nil {
+			httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	} else {
+		httpReq, err = http.NewRequest(method, u+"&"+params.Encode(), nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	httpReq = httpReq.WithContext(ctx)
+
+	res, err := client(e.Client).Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, &Error{Target: "etherscan", Message: res.Status}
+	}
+
+	out := &etherscanResponse{}
+	err = json.NewDecoder(res.Body).Decode(out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
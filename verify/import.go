@@ -0,0 +1,244 @@
+package verify
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	solc "github.com/nmvalera/solc-go"
+)
+
+// StatusMismatch is the status of imported contracts whose recompiled code differs from the deployed one
+const StatusMismatch = "mismatch"
+
+// Import is a deployed contract recompiled from its Etherscan verified source
+type Import struct {
+	Address solc.Address
+	ChainID uint64
+
+	// Contract is the fully qualified name of the contract (e.g. contracts/One.sol:One)
+	Contract string
+
+	// Version is the long compiler version the contract got verified with
+	Version string
+
+	Input  *solc.Input
+	Output *solc.Output
+
+	// ConstructorArguments are the hex encoded constructor arguments of the verification
+	ConstructorArguments string
+
+	// Status is StatusPerfect if the recompiled runtime code is the deployed one, StatusPartial if they
+	// only differ by their metadata and StatusMismatch otherwise
+	Status string
+}
+
+// etherscanSource is a result of the getsourcecode action
+type etherscanSource struct {
+	SourceCode           string
+	ContractName         string
+	CompilerVersion      string
+	OptimizationUsed     string
+	Runs                 string
+	ConstructorArguments string
+	EVMVersion           string
+	Library              string
+}
+
+// ImportFromEtherscan imports the contract deployed at address on chain with the Etherscan API (see Etherscan.Import)
+func ImportFromEtherscan(ctx context.Context, chain uint64, address solc.Address, apiKey string, registry *solc.Registry) (*Import, error) {
+	e := &Etherscan{APIKey: apiKey}
+	return e.Import(ctx, chain, address, registry)
+}
+
+// Import downloads the verified source and settings of the contract deployed at address (getsourcecode),
+// recompiles them with the verified compiler version loaded by registry and compares the result with the
+// deployed code (eth_getCode)
+func (e *Etherscan) Import(ctx context.Context, chainID uint64, address solc.Address, registry *solc.Registry) (*Import, error) {
+	res, err := e.do(ctx, http.MethodGet, chainID, url.Values{
+		"apikey":  {e.APIKey},
+		"module":  {"contract"},
+		"action":  {"getsourcecode"},
+		"address": {address.Hex()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var sources []etherscanSource
+	if res.Status != "1" || json.Unmarshal(res.Result, &sources) != nil || len(sources) == 0 {
+		return nil, &Error{Target: "etherscan", Message: res.text()}
+	}
+	source := sources[0]
+	if source.SourceCode == "" {
+		return nil, &Error{Target: "etherscan", Message: fmt.Sprintf("contract %v is not verified", address.Hex())}
+	}
+
+	input, err := etherscanInput(&source)
+	if err != nil {
+		return nil, err
+	}
+	version := strings.TrimPrefix(source.CompilerVersion, "v")
+	output, err := registry.Compile(ctx, "="+solc.ShortVersion(version), input)
+	if err != nil {
+		return nil, err
+	}
+	for _, diag := range output.Errors {
+		if diag.Severity == "error" {
+			return nil, fmt.Errorf("recompiling %v failed: %v", source.ContractName, diag.Message)
+		}
+	}
+	file, contract, err := output.FindContract(source.ContractName)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err = e.do(ctx, http.MethodGet, chainID, url.Values{
+		"apikey":  {e.APIKey},
+		"module":  {"proxy"},
+		"action":  {"eth_getCode"},
+		"address": {address.Hex()},
+		"tag":     {"latest"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	deployed, err := hex.DecodeString(strings.TrimPrefix(res.text(), "0x"))
+	if err != nil {
+		return nil, &Error{Target: "etherscan", Message: res.text()}
+	}
+	status, err := compareCode(contract.EVM.DeployedBytecode, deployed)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Import{
+		Address:              address,
+		ChainID:              chainID,
+		Contract:             solc.ContractID{File: file, Name: source.ContractName}.String(),
+		Version:              version,
+		Input:                input,
+		Output:               output,
+		ConstructorArguments: source.ConstructorArguments,
+		Status:               status,
+	}, nil
+}
+
+// etherscanInput reconstructs the input of a verified source, which is either a single source file, a JSON
+// object of sources or a standard JSON input wrapped in an extra pair of braces
+func etherscanInput(source *etherscanSource) (*solc.Input, error) {
+	code := strings.TrimSpace(source.SourceCode)
+	if strings.HasPrefix(code, "{{") && strings.HasSuffix(code, "}}") {
+		input := &solc.Input{}
+		err := json.Unmarshal([]byte(code[1:len(code)-1]), input)
+		if err != nil {
+			return nil, fmt.Errorf("invalid standard JSON input: %v", err)
+		}
+		input.Settings.OutputSelection = solc.SelectAll("abi", "evm.bytecode", "evm.deployedBytecode", "metadata")
+		return input, nil
+	}
+
+	input := solc.NewInput()
+	input.Settings.OutputSelection = solc.SelectAll("abi", "evm.bytecode", "evm.deployedBytecode", "metadata")
+	if strings.HasPrefix(code, "{") {
+		err := json.Unmarshal([]byte(code), &input.Sources)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sources: %v", err)
+		}
+	} else {
+		input.AddSource(source.ContractName+".sol", source.SourceCode)
+	}
+
+	runs, _ := strconv.Atoi(source.Runs)
+	input.Settings.Optimizer = solc.Optimizer{Enabled: source.OptimizationUsed == "1", Runs: runs}
+	if source.EVMVersion != "" && !strings.EqualFold(source.EVMVersion, "default") {
+		input.Settings.EVMVersion = strings.ToLower(source.EVMVersion)
+	}
+
+	// Libraries are listed as Name:address pairs separated by semicolons
+	for _, library := range strings.Split(source.Library, ";") {
+		parts := strings.SplitN(strings.TrimSpace(library), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		address := parts[1]
+		if !strings.HasPrefix(address, "0x") {
+			address = "0x" + address
+		}
+		if input.Settings.Libraries == nil {
+			input.Settings.Libraries = make(map[string]map[string]string)
+		}
+		for _, file := range libraryFiles(input.Sources, parts[0]) {
+			if input.Settings.Libraries[file] == nil {
+				input.Settings.Libraries[file] = make(map[string]string)
+			}
+			input.Settings.Libraries[file][parts[0]] = address
+		}
+	}
+	return input, nil
+}
+
+// libraryFiles returns the sources declaring library name, or all of them if none does
+func libraryFiles(sources map[string]solc.SourceIn, name string) []string {
+	re := regexp.MustCompile(`\blibrary\s+` + regexp.QuoteMeta(name) + `\b`)
+	var declaring, all []string
+	for file, source := range sources {
+		all = append(all, file)
+		if re.MatchString(source.Content) {
+			declaring = append(declaring, file)
+		}
+	}
+	if len(declaring) > 0 {
+		return declaring
+	}
+	return all
+}
+
+// compareCode compares recompiled runtime bytecode with deployed code, ignoring the values of immutables
+// and the address libraries push to prevent calls other than delegatecalls
+func compareCode(compiled solc.Bytecode, deployed []byte) (string, error) {
+	code, err := hex.DecodeString(strings.TrimPrefix(compiled.Object, "0x"))
+	if err != nil {
+		return "", fmt.Errorf("recompiled bytecode is not linked: %v", err)
+	}
+
+	// Immutables precede the metadata, which may have another length
+	for _, refs := range compiled.ImmutableReferences {
+		for _, ref := range refs {
+			if ref.Start >= 0 && ref.Start+ref.Length <= len(code) && ref.Start+ref.Length <= len(deployed) {
+				copy(code[ref.Start:ref.Start+ref.Length], deployed[ref.Start:ref.Start+ref.Length])
+			}
+		}
+	}
+	// PUSH20 of a zero address replaced by the library address at deployment
+	if len(code) > 21 && len(deployed) > 21 && code[0] == 0x73 && bytes.Equal(code[1:21], make([]byte, 20)) {
+		copy(code[1:21], deployed[1:21])
+	}
+
+	if bytes.Equal(code, deployed) {
+		return StatusPerfect, nil
+	}
+	return compareWithoutMetadata(code, deployed), nil
+}
+
+// compareWithoutMetadata returns StatusPartial if code and deployed are equal without their metadata
+func compareWithoutMetadata(code, deployed []byte) string {
+	if bytes.Equal(stripMetadata(code), stripMetadata(deployed)) {
+		return StatusPartial
+	}
+	return StatusMismatch
+}
+
+func stripMetadata(code []byte) []byte {
+	if m, err := solc.ParseBytecodeMetadata(code); err != nil || m == nil {
+		return code
+	}
+	return code[:len(code)-int(binary.BigEndian.Uint16(code[len(code)-2:]))-2]
+}
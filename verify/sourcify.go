@@ -0,0 +1,95 @@
+package verify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DefaultSourcifyURL is the URL of the public Sourcify server
+const DefaultSourcifyURL = "https://sourcify.dev/server"
+
+// Sourcify verifies contracts on a Sourcify server
+type Sourcify struct {
+	// URL defaults to DefaultSourcifyURL
+	URL string
+
+	// Client defaults to http.DefaultClient
+	Client *http.Client
+}
+
+// Verify submits the metadata and sources of req (POST /verify)
+func (s *Sourcify) Verify(ctx context.Context, req *Request) (*Result, error) {
+	if req.Metadata == "" {
+		return nil, &Error{Target: "sourcify", Message: "missing contract metadata"}
+	}
+
+	files := map[string]string{"metadata.json": req.Metadata}
+	for name, source := range req.Input.Sources {
+		files[name] = source.Content
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"address":        req.Address.Hex(),
+		"chain":          strconv.FormatUint(req.ChainID, 10),
+		"files":          files,
+		"chosenContract": req.Contract,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	url := s.URL
+	if url == "" {
+		url = DefaultSourcifyURL
+	}
+	httpReq, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(url, "/")+"/verify", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq = httpReq.WithContext(ctx)
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	res, err := client(s.Client).Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var out struct {
+		Error  string `json:"error"`
+		Result []struct {
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		} `json:"result"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&out)
+	if err != nil && res.StatusCode == http.StatusOK {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK || out.Error != "" {
+		msg := out.Error
+		if msg == "" {
+			msg = res.Status
+		}
+		return nil, &Error{Target: "sourcify", Message: msg}
+	}
+	if len(out.Result) == 0 {
+		return nil, &Error{Target: "sourcify", Message: "empty result"}
+	}
+
+	result := out.Result[0]
+	if result.Status != StatusPerfect && result.Status != StatusPartial {
+		return nil, &Error{Target: "sourcify", Message: result.Message}
+	}
+	return &Result{Status: result.Status, Message: result.Message}, nil
+}
+
+func client(c *http.Client) *http.Client {
+	if c == nil {
+		return http.DefaultClient
+	}
+	return c
+}
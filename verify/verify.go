@@ -0,0 +1,87 @@
+// Package verify submits contract source verifications to Sourcify and Etherscan
+package verify
+
+import (
+	"context"
+	"fmt"
+
+	solc "github.com/nmvalera/solc-go"
+)
+
+// Statuses of successful verifications
+const (
+	// StatusPerfect is a full match, including metadata (Sourcify)
+	StatusPerfect = "perfect"
+
+	// StatusPartial is a match of the bytecode but not of the metadata (Sourcify)
+	StatusPartial = "partial"
+
+	// StatusVerified is a match as reported by Etherscan
+	StatusVerified = "verified"
+)
+
+// Request is a contract verification request
+type Request struct {
+	Address solc.Address
+	ChainID uint64
+
+	// Contract is the fully qualified name of the contract (e.g. contracts/One.sol:One)
+	Contract string
+
+	// Version is the compiler version the contract got compiled with
+	Version string
+
+	Input *solc.Input
+
+	// Metadata is the contract metadata output by the compiler, required by Sourcify
+	Metadata string
+
+	// ConstructorArguments are the hex encoded ABI encoded constructor arguments, used by Etherscan
+	ConstructorArguments string
+}
+
+// NewRequest creates the verification request of contract (path:Name) compiled from input into output
+func NewRequest(address solc.Address, chainID uint64, contract, version string, input *solc.Input, output *solc.Output) (*Request, error) {
+	id, err := solc.ParseContractID(contract)
+	if err == nil && !id.IsQualified() {
+		err = fmt.Errorf("invalid contract %q, expected path:Name", contract)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c, ok := output.Contract(id.File, id.Name)
+	if !ok {
+		return nil, fmt.Errorf("contract %q not found in compilation output", contract)
+	}
+
+	return &Request{
+		Address:  address,
+		ChainID:  chainID,
+		Contract: contract,
+		Version:  version,
+		Input:    input,
+		Metadata: c.Metadata,
+	}, nil
+}
+
+// Result is the result of a successful verification
+type Result struct {
+	// Status is one of StatusPerfect, StatusPartial or StatusVerified
+	Status  string
+	Message string
+}
+
+// Error is returned when a verification is rejected
+type Error struct {
+	Target  string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("verify: %v rejected verification: %v", e.Target, e.Message)
+}
+
+// Verifier submits verification requests
+type Verifier interface {
+	Verify(ctx context.Context, req *Request) (*Result, error)
+}
@@ -0,0 +1,153 @@
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	solc "github.com/nmvalera/solc-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRequest(t *testing.T) *Request {
+	address, err := solc.HexToAddress("0x00000000000000000000000000000000000000aa")
+	require.NoError(t, err)
+	input := &solc.Input{
+		Language: "Solidity",
+		Sources:  map[string]solc.SourceIn{"One.sol": solc.SourceIn{Content: "contract One {}"}},
+	}
+	output := &solc.Output{Contracts: map[string]map[string]solc.Contract{"One.sol": {"One": {Metadata: `{"version":1}`}}}}
+
+	req, err := NewRequest(address, 5, "One.sol:One", "0.6.2+commit.bacdbe57.Emscripten.clang", input, output)
+	require.NoError(t, err, "NewRequest should not error")
+	_, err = NewRequest(address, 5, "One.sol:Two", "", input, output)
+	require.Error(t, err, "NewRequest should error on unknown contract")
+
+	return req
+}
+
+func TestSourcify(t *testing.T) {
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/verify", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if body["chain"] == "5" {
+			w.Write([]byte(`{"result": [{"address": "0x00000000000000000000000000000000000000aa", "chainId": "5", "status": "perfect"}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": "unsupported chain"}`))
+	}))
+	defer srv.Close()
+
+	req := testRequest(t)
+	sourcify := &Sourcify{URL: srv.URL}
+	res, err := sourcify.Verify(context.Background(), req)
+	require.NoError(t, err, "Verify should not error")
+	assert.Equal(t, StatusPerfect, res.Status)
+	assert.Equal(t, "0x00000000000000000000000000000000000000aa", body["address"])
+	assert.Equal(t, map[string]interface{}{"metadata.json": `{"version":1}`, "One.sol": "contract One {}"}, body["files"])
+
+	req.ChainID = 1
+	_, err = sourcify.Verify(context.Background(), req)
+	require.IsType(t, &Error{}, err, "Rejections should be reported as Error")
+	assert.Equal(t, "unsupported chain", err.(*Error).Message)
+}
+
+func TestEtherscan(t *testing.T) {
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "5", r.Form.Get("chainid"))
+		switch r.Form.Get("action") {
+		case "verifysourcecode":
+			assert.Equal(t, "v0.6.2+commit.bacdbe57", r.Form.Get("compilerversion"))
+			assert.Equal(t, "One.sol:One", r.Form.Get("contractname"))
+			assert.Equal(t, "key", r.Form.Get("apikey"))
+			w.Write([]byte(`{"status": "1", "message": "OK", "result": "guid"}`))
+		case "checkverifystatus":
+			assert.Equal(t, "guid", r.Form.Get("guid"))
+			polls++
+			if polls < 2 {
+				w.Write([]byte(`{"status": "0", "message": "NOTOK", "result": "Pending in queue"}`))
+				return
+			}
+			w.Write([]byte(`{"status": "1", "message": "OK", "result": "Pass - Verified"}`))
+		}
+	}))
+	defer srv.Close()
+
+	etherscan := &Etherscan{URL: srv.URL, APIKey: "key", PollInterval: time.Millisecond}
+	res, err := etherscan.Verify(context.Background(), testRequest(t))
+	require.NoError(t, err, "Verify should not error")
+	assert.Equal(t, StatusVerified, res.Status)
+	assert.Equal(t, "Pass - Verified", res.Message)
+	assert.Equal(t, 2, polls, "Status should be polled until processed")
+}
+
+func TestImport(t *testing.T) {
+	const source = "pragma solidity ^0.6.2;\ncontract One {\n    uint256 public value;\n    constructor(uint256 v) public { value = v; }\n}\n"
+	registry := solc.NewRegistry(solc.RegistryConfig{Dir: "../solc-bin", Offline: true})
+	defer registry.Close()
+	in := solc.NewInput().AddSource("One.sol", source)
+	in.Settings.Optimizer = solc.Optimizer{Enabled: true, Runs: 200}
+	out, err := registry.Compile(context.Background(), "=0.6.2", in)
+	require.NoError(t, err, "Compile should not error")
+	runtime := out.Contracts["One.sol"]["One"].EVM.DeployedBytecode.Object
+
+	var sourceCode, code string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "0x00000000000000000000000000000000000000aa", r.URL.Query().Get("address"))
+		switch r.URL.Query().Get("action") {
+		case "getsourcecode":
+			result, _ := json.Marshal([]map[string]string{{
+				"SourceCode":           sourceCode,
+				"ContractName":         "One",
+				"CompilerVersion":      "v0.6.2+commit.bacdbe57",
+				"OptimizationUsed":     "1",
+				"Runs":                 "200",
+				"ConstructorArguments": "000000000000000000000000000000000000000000000000000000000000002a",
+				"EVMVersion":           "Default",
+			}})
+			w.Write([]byte(`{"status": "1", "message": "OK", "result": ` + string(result) + `}`))
+		case "eth_getCode":
+			w.Write([]byte(`{"jsonrpc": "2.0", "id": 1, "result": "0x` + code + `"}`))
+		}
+	}))
+	defer srv.Close()
+	address, _ := solc.HexToAddress("0x00000000000000000000000000000000000000aa")
+	etherscan := &Etherscan{URL: srv.URL, APIKey: "key"}
+
+	// The IPFS hash precedes 64736f6c6343000602 0033 ("solc": 0.6.2 and the metadata length)
+	metadata := len(runtime) - 22
+	standardJSON, _ := json.Marshal(in)
+	for _, test := range []struct {
+		sourceCode string
+		code       string
+		status     string
+	}{
+		{source, runtime, StatusPerfect},
+		{`{"One.sol": {"content": ` + strconv.Quote(source) + `}}`, runtime, StatusPerfect},
+		{"{" + string(standardJSON) + "}", runtime, StatusPerfect},
+		{source, runtime[:metadata-8] + "00000000" + runtime[metadata:], StatusPartial},
+		{source, "00" + runtime[2:], StatusMismatch},
+	} {
+		sourceCode, code = test.sourceCode, test.code
+		imported, err := etherscan.Import(context.Background(), 1, address, registry)
+		require.NoError(t, err, "Import should not error")
+		assert.Equal(t, test.status, imported.Status, "Recompiled code should be compared")
+		assert.Equal(t, "One.sol:One", imported.Contract)
+		assert.Equal(t, "0.6.2+commit.bacdbe57", imported.Version)
+		assert.True(t, imported.Input.Settings.Optimizer.Enabled, "Optimizer settings should be imported")
+		assert.Equal(t, "000000000000000000000000000000000000000000000000000000000000002a", imported.ConstructorArguments)
+	}
+
+	sourceCode = ""
+	_, err = etherscan.Import(context.Background(), 1, address, registry)
+	require.IsType(t, &Error{}, err, "Unverified contracts should error")
+}
@@ -0,0 +1,166 @@
+package solc
+
+import (
+	"context"
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultDebounce is the delay a Watcher waits for file events to settle before recompiling
+const DefaultDebounce = 100 * time.Millisecond
+
+// Watcher recompiles the sources of a directory each time one of them changes
+type Watcher struct {
+	Solc     Solc
+	Settings Settings
+
+	// Cache is optional, when set unchanged compilation units are served from it
+	Cache Cache
+
+	// Debounce defaults to DefaultDebounce
+	Debounce time.Duration
+
+	// Normalization applies to the sources loaded (see LoadDirNormalized)
+	Normalization SourceNormalization
+
+	// Resolver is optional, when set the imports of the sources missing from the directory (e.g. libraries
+	// of include paths or remapped ones) are loaded from it
+	Resolver ImportResolver
+}
+
+// Watch compiles the sources under root, then recompiles them on every change until ctx is done
+//
+// onResult is called after each compilation with the time it took, loading and resolving sources included
+func (w *Watcher) Watch(ctx context.Context, root string, onResult func(out *Output, err error, elapsed time.Duration)) error {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer fsw.Close()
+
+	err = watchDirs(fsw, root)
+	if err != nil {
+		return err
+	}
+
+	debounce := w.Debounce
+	if debounce == 0 {
+		debounce = DefaultDebounce
+	}
+
+	hashes := make(map[string][sha256.Size]byte)
+	w.compileChanged(root, hashes, onResult)
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case event, ok := <-fsw.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&fsnotify.Create != 0 {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					_ = watchDirs(fsw, event.Name)
+				}
+			}
+			if filepath.Ext(event.Name) == ".sol" || event.Op&(fsnotify.Remove|fsnotify.Rename) != 0 {
+				timer.Reset(debounce)
+			}
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return nil
+			}
+			onResult(nil, err, 0)
+		case <-timer.C:
+			w.compileChanged(root, hashes, onResult)
+		}
+	}
+}
+
+// compileChanged compiles sources under root if any of them changed since the last call
+func (w *Watcher) compileChanged(root string, hashes map[string][sha256.Size]byte, onResult func(*Output, error, time.Duration)) {
+	start := time.Now()
+	sources, err := w.load(root)
+	if err != nil {
+		onResult(nil, err, time.Since(start))
+		return
+	}
+
+	changed := len(sources) != len(hashes)
+	for name, source := range sources {
+		h := sha256.Sum256([]byte(source.Content))
+		if prev, ok := hashes[name]; !ok || prev != h {
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	for name := range hashes {
+		delete(hashes, name)
+	}
+	for name, source := range sources {
+		hashes[name] = sha256.Sum256([]byte(source.Content))
+	}
+
+	input := &Input{
+		Language: "Solidity",
+		Sources:  sources,
+		Settings: w.Settings,
+	}
+
+	var out *Output
+	if w.Cache != nil {
+		out, _, err = compileCached(w.Cache, w.Solc, input)
+	} else {
+		out, err = w.Solc.Compile(input)
+	}
+	onResult(out, err, time.Since(start))
+}
+
+// load loads the sources under root and the imports they resolve with the resolver of w
+func (w *Watcher) load(root string) (map[string]SourceIn, error) {
+	sources, err := LoadDirNormalized(root, w.Normalization)
+	if err != nil || w.Resolver == nil {
+		return sources, err
+	}
+	graph, err := DependencyGraph(sources, w.Resolver)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range graph.Nodes {
+		if _, ok := sources[name]; ok {
+			continue
+		}
+		content, err := w.Resolver.Resolve(name)
+		if err != nil {
+			return nil, err
+		}
+		sources[name] = SourceIn{Content: content}
+	}
+	return sources, nil
+}
+
+func watchDirs(fsw *fsnotify.Watcher, root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != root && skipDir(info.Name()) {
+			return filepath.SkipDir
+		}
+		return fsw.Add(path)
+	})
+}
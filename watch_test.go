@@ -0,0 +1,62 @@
+package solc
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "solc-watch")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "lib"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "lib", "One.sol"), []byte("contract One {}"), 0644))
+
+	sources, err := LoadDir(dir)
+	require.NoError(t, err, "LoadDir should not error")
+	assert.Equal(t, map[string]SourceIn{"lib/One.sol": SourceIn{Content: "contract One {}"}}, sources, "Sources should be loaded")
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "Main.sol"), []byte(`import "@lib/L.sol"; contract Main {}`), 0644))
+
+	compiler := &fakeSolc{output: &Output{}}
+	recorder := &recordingSolc{Solc: compiler}
+	w := &Watcher{Solc: recorder, Debounce: 10 * time.Millisecond, Resolver: SourcesResolver{"@lib/L.sol": {Content: "contract L {}"}}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	results := make(chan error, 10)
+	done := make(chan error)
+	go func() {
+		done <- w.Watch(ctx, dir, func(out *Output, err error, elapsed time.Duration) {
+			results <- err
+		})
+	}()
+
+	select {
+	case err := <-results:
+		require.NoError(t, err, "Initial compilation should not error")
+	case <-time.After(5 * time.Second):
+		t.Fatal("Initial compilation should run")
+	}
+	assert.Equal(t, [][]string{{"@lib/L.sol", "Main.sol", "lib/One.sol"}}, recorder.compiled, "Imports should be resolved")
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "lib", "Two.sol"), []byte("contract Two {}"), 0644))
+	select {
+	case <-results:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Change should trigger a compilation")
+	}
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-done, "Watch should stop on context cancellation")
+	assert.Equal(t, 2, compiler.compiles, "Compiler should have run twice")
+}